@@ -0,0 +1,85 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+// ccc serves the circuit capacity checker over RPC, so that geth can run it out of process
+// with --ccc.remote. It needs to be built with the circuit_capacity_checker build tag to use
+// libzkp, otherwise it serves the mock checker.
+package main
+
+import (
+	"flag"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/scroll-tech/go-ethereum/cmd/utils"
+	"github.com/scroll-tech/go-ethereum/log"
+	"github.com/scroll-tech/go-ethereum/rollup/circuitcapacitychecker"
+	"github.com/scroll-tech/go-ethereum/rpc"
+)
+
+func main() {
+	var (
+		httpAddr  = flag.String("http", "", "HTTP listen address, e.g. 127.0.0.1:8560")
+		ipcPath   = flag.String("ipc", "", "IPC socket path")
+		verbosity = flag.Int("verbosity", int(log.LvlInfo), "log verbosity (0-5)")
+	)
+	flag.Parse()
+
+	glogger := log.NewGlogHandler(log.StreamHandler(os.Stderr, log.TerminalFormat(false)))
+	glogger.Verbosity(log.Lvl(*verbosity))
+	log.Root().SetHandler(glogger)
+
+	if *httpAddr == "" && *ipcPath == "" {
+		utils.Fatalf("-http or -ipc is required")
+	}
+
+	server := rpc.NewServer()
+	service := circuitcapacitychecker.NewRPCService(func(lightMode bool) circuitcapacitychecker.Checker {
+		return circuitcapacitychecker.NewCircuitCapacityChecker(lightMode)
+	})
+	if err := server.RegisterName(circuitcapacitychecker.RPCNamespace, service); err != nil {
+		utils.Fatalf("Failed to register the circuit capacity checker service: %v", err)
+	}
+
+	if *ipcPath != "" {
+		// remove the socket left behind by a crashed process
+		os.Remove(*ipcPath)
+		listener, err := net.Listen("unix", *ipcPath)
+		if err != nil {
+			utils.Fatalf("Failed to listen on %s: %v", *ipcPath, err)
+		}
+		defer os.Remove(*ipcPath)
+		go server.ServeListener(listener)
+		log.Info("Circuit capacity checker listening", "ipc", *ipcPath)
+	}
+	if *httpAddr != "" {
+		listener, err := net.Listen("tcp", *httpAddr)
+		if err != nil {
+			utils.Fatalf("Failed to listen on %s: %v", *httpAddr, err)
+		}
+		go http.Serve(listener, server)
+		log.Info("Circuit capacity checker listening", "http", "http://"+listener.Addr().String())
+	}
+
+	sigc := make(chan os.Signal, 1)
+	signal.Notify(sigc, syscall.SIGINT, syscall.SIGTERM)
+	<-sigc
+	log.Info("Shutting down circuit capacity checker")
+	server.Stop()
+}
@@ -0,0 +1,196 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"fmt"
+	"time"
+
+	cli "gopkg.in/urfave/cli.v1"
+
+	"github.com/scroll-tech/go-ethereum/cmd/utils"
+	"github.com/scroll-tech/go-ethereum/core/rawdb"
+	"github.com/scroll-tech/go-ethereum/rollup/sync_service"
+)
+
+var (
+	l1MessageFromFlag = cli.Uint64Flag{
+		Name:  "from",
+		Usage: "Queue index of the first L1 message to export",
+	}
+	l1MessageToFlag = cli.Uint64Flag{
+		Name:  "to",
+		Usage: "Queue index of the last L1 message to export (default = highest synced queue index)",
+	}
+	l1MessageL1BlockFlag = cli.Uint64Flag{
+		Name:  "l1block",
+		Usage: "L1 block up to which the imported L1 messages are complete, the L1 message sync continues after it",
+	}
+
+	l1MessageNetworkFlags = []cli.Flag{
+		utils.DataDirFlag,
+		utils.AncientFlag,
+		utils.RopstenFlag,
+		utils.SepoliaFlag,
+		utils.RinkebyFlag,
+		utils.GoerliFlag,
+		utils.ScrollAlphaFlag,
+		utils.ScrollSepoliaFlag,
+		utils.ScrollFlag,
+	}
+
+	l1MessagesCommand = cli.Command{
+		Name:     "l1msgs",
+		Usage:    "A set of commands for managing the L1 messages in the database",
+		Category: "MISCELLANEOUS COMMANDS",
+		Subcommands: []cli.Command{
+			{
+				Name:      "export",
+				Usage:     "Export L1 messages into a file",
+				ArgsUsage: "<filename>",
+				Action:    utils.MigrateFlags(exportL1Messages),
+				Category:  "MISCELLANEOUS COMMANDS",
+				Flags:     append([]cli.Flag{l1MessageFromFlag, l1MessageToFlag}, l1MessageNetworkFlags...),
+				Description: `
+geth l1msgs export --from <queue index> --to <queue index> <filename>
+exports a contiguous range of L1 messages. Files ending with .jsonl hold one
+JSON-encoded transaction per line, including its hash, other files an RLP
+stream of L1 messages. If the file ends with .gz, the output is gzipped.`,
+			},
+			{
+				Name:      "import",
+				Usage:     "Import L1 messages from a file",
+				ArgsUsage: "<filename>",
+				Action:    utils.MigrateFlags(importL1Messages),
+				Category:  "MISCELLANEOUS COMMANDS",
+				Flags:     append([]cli.Flag{l1MessageL1BlockFlag}, l1MessageNetworkFlags...),
+				Description: `
+geth l1msgs import <filename>
+imports L1 messages exported by geth l1msgs export, overwriting the stored
+messages with the same queue indices. The hashes of JSON-encoded messages are
+checked against their contents. To seed a new node, pass the L1 block up to
+which the file is complete with --l1block, so that the L1 message sync
+continues after it instead of from the L1 deployment block.`,
+			},
+			{
+				Name:     "verify",
+				Usage:    "Verify the L1 messages in the database and reset the L1 message sync to the first invalid one",
+				Action:   utils.MigrateFlags(verifyL1Messages),
+				Category: "MISCELLANEOUS COMMANDS",
+				Flags:    l1MessageNetworkFlags,
+				Description: `
+geth l1msgs verify
+checks that the stored L1 messages have contiguous queue indices up to the
+highest synced queue index, that they can be decoded, and that the recomputed
+hashes of the messages included by the head block match the L2 chain. If it
+finds a gap or an invalid message, it deletes the messages from there on and
+resets the synced L1 height, so that the node syncs them from L1 again.`,
+			},
+		},
+	}
+)
+
+func exportL1Messages(ctx *cli.Context) error {
+	if len(ctx.Args()) < 1 {
+		utils.Fatalf("This command requires an argument.")
+	}
+
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	db := utils.MakeChainDatabase(ctx, stack, true)
+	defer db.Close()
+
+	first := ctx.Uint64(l1MessageFromFlag.Name)
+	last := rawdb.ReadHighestSyncedQueueIndex(db)
+	if ctx.IsSet(l1MessageToFlag.Name) {
+		last = ctx.Uint64(l1MessageToFlag.Name)
+	}
+	if first > last {
+		utils.Fatalf("Export error: first queue index %d is larger than last queue index %d", first, last)
+	}
+
+	start := time.Now()
+	if err := utils.ExportL1Messages(db, ctx.Args().First(), first, last); err != nil {
+		utils.Fatalf("Export error: %v", err)
+	}
+	fmt.Printf("Exported L1 messages %d - %d in %v\n", first, last, time.Since(start))
+	if synced := rawdb.ReadSyncedL1BlockNumber(db); synced != nil && last == rawdb.ReadHighestSyncedQueueIndex(db) {
+		fmt.Printf("The exported L1 messages are complete up to L1 block %d\n", *synced)
+	}
+	return nil
+}
+
+func importL1Messages(ctx *cli.Context) error {
+	if len(ctx.Args()) < 1 {
+		utils.Fatalf("This command requires an argument.")
+	}
+
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	db := utils.MakeChainDatabase(ctx, stack, false)
+	defer db.Close()
+
+	start := time.Now()
+	count, err := utils.ImportL1Messages(db, ctx.Args().First())
+	if err != nil {
+		utils.Fatalf("Import error: %v", err)
+	}
+	fmt.Printf("Imported %d L1 messages in %v, highest synced queue index: %d\n", count, time.Since(start), rawdb.ReadHighestSyncedQueueIndex(db))
+
+	if ctx.IsSet(l1MessageL1BlockFlag.Name) {
+		l1Block := ctx.Uint64(l1MessageL1BlockFlag.Name)
+		if synced := rawdb.ReadSyncedL1BlockNumber(db); synced == nil || *synced < l1Block {
+			rawdb.WriteSyncedL1BlockNumber(db, l1Block)
+			fmt.Printf("L1 message sync continues after L1 block %d\n", l1Block)
+		}
+	}
+	return nil
+}
+
+func verifyL1Messages(ctx *cli.Context) error {
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	db := utils.MakeChainDatabase(ctx, stack, false)
+	defer db.Close()
+
+	v, err := sync_service.VerifyL1Messages(db)
+	if err != nil {
+		utils.Fatalf("Failed to verify L1 messages: %v", err)
+	}
+
+	fmt.Printf("Stored L1 messages: %d, highest synced queue index: %d\n", v.NumL1Messages, v.HighestSyncedQueueIndex)
+	for _, gap := range v.Gaps {
+		fmt.Printf("Missing L1 messages: %d - %d\n", gap[0], gap[1])
+	}
+	for _, queueIndex := range v.Corrupted {
+		fmt.Printf("Corrupted L1 message: %d\n", queueIndex)
+	}
+	for _, queueIndex := range v.HashMismatches {
+		fmt.Printf("L1 message not found in the L2 chain by its recomputed hash: %d\n", queueIndex)
+	}
+
+	if v.FirstInvalidQueueIndex == nil {
+		fmt.Println("All L1 messages are valid")
+		return nil
+	}
+	restartBlock := sync_service.ResetL1MessageSync(db, *v.FirstInvalidQueueIndex, stack.Config().L1DeploymentBlock)
+	fmt.Printf("Deleted the L1 messages from queue index %d, the L1 message sync restarts after L1 block %d\n", *v.FirstInvalidQueueIndex, restartBlock)
+	return nil
+}
@@ -132,6 +132,10 @@ var (
 		utils.MinerNoVerifyFlag,
 		utils.MinerStoreSkippedTxTracesFlag,
 		utils.MinerMaxAccountsNumFlag,
+		utils.MinerTxOrderingFlag,
+		utils.MinerPriorityAddressesFlag,
+		utils.MinerPreconfirmationsFlag,
+		utils.MinerBundlesFlag,
 		utils.NATFlag,
 		utils.NoDiscoverFlag,
 		utils.DiscoveryV5Flag,
@@ -165,8 +169,34 @@ var (
 		utils.L1EndpointFlag,
 		utils.L1ConfirmationsFlag,
 		utils.L1DeploymentBlockFlag,
+		utils.L1BeaconEndpointFlag,
+		utils.L1HedgeDelayFlag,
+		utils.L1CrossCheckLogsFlag,
+		utils.L1CheckpointNumberFlag,
+		utils.L1CheckpointHashFlag,
 		utils.CircuitCapacityCheckEnabledFlag,
+		utils.CircuitCapacityCheckRemoteFlag,
+		utils.CircuitCapacityCheckRemoteTimeoutFlag,
+		utils.CircuitCapacityCheckRemoteCommandFlag,
+		utils.CircuitCapacityCheckMaxRowsFlag,
+		utils.CircuitCapacityVerifyEnabledFlag,
+		utils.CircuitCapacityVerifyWorkersFlag,
+		utils.CircuitCapacityVerifyQueueFlag,
+		utils.CircuitCapacityVerifySequencerFlag,
 		utils.RollupVerifyEnabledFlag,
+		utils.RollupProposerEnabledFlag,
+		utils.RollupProposerMaxBlocksPerChunkFlag,
+		utils.RollupProposerMaxTxsPerChunkFlag,
+		utils.RollupProposerMaxL1GasPerChunkFlag,
+		utils.RollupProposerMaxCalldataPerChunkFlag,
+		utils.RollupProposerMaxRowsPerChunkFlag,
+		utils.RollupProposerChunkTimeoutFlag,
+		utils.RollupProposerMaxChunksPerBatchFlag,
+		utils.RollupProposerMaxL1GasPerBatchFlag,
+		utils.RollupProposerMaxCalldataPerBatchFlag,
+		utils.RollupProposerBatchTimeoutFlag,
+		utils.RollupProposerMaxBlobSizeFlag,
+		utils.RollupWithdrawTrieFlag,
 	}
 
 	rpcFlags = []cli.Flag{
@@ -250,6 +280,10 @@ func init() {
 		utils.ShowDeprecated,
 		// See snapshot.go
 		snapshotCommand,
+		// See rollupcmd.go
+		rollupCommand,
+		// See l1msgscmd.go
+		l1MessagesCommand,
 	}
 	sort.Sort(cli.CommandsByName(app.Commands))
 
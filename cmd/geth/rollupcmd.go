@@ -0,0 +1,136 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/olekukonko/tablewriter"
+	cli "gopkg.in/urfave/cli.v1"
+
+	"github.com/scroll-tech/go-ethereum/cmd/utils"
+	"github.com/scroll-tech/go-ethereum/core/rawdb"
+	"github.com/scroll-tech/go-ethereum/rollup/rollup_sync_service"
+)
+
+var (
+	batchIndexFlag = cli.Uint64Flag{
+		Name:  "index",
+		Usage: "Index of the batch to verify",
+	}
+	commitTxFlag = cli.StringFlag{
+		Name:  "commit-tx",
+		Usage: "File containing the commit batch transaction, either as returned by eth_getTransactionByHash or RLP-encoded",
+	}
+	finalizeEventFlag = cli.StringFlag{
+		Name:  "finalize-event",
+		Usage: "File containing the FinalizeBatch event log of the batch, either a single log or the log list returned by eth_getLogs",
+	}
+
+	rollupCommand = cli.Command{
+		Name:     "rollup",
+		Usage:    "A set of commands for inspecting rollup batches",
+		Category: "MISCELLANEOUS COMMANDS",
+		Subcommands: []cli.Command{
+			{
+				Name:     "verify-batch",
+				Usage:    "Verify a finalized batch against the local chain without L1 access",
+				Action:   utils.MigrateFlags(verifyBatch),
+				Category: "MISCELLANEOUS COMMANDS",
+				Flags: []cli.Flag{
+					utils.DataDirFlag,
+					utils.AncientFlag,
+					utils.RopstenFlag,
+					utils.SepoliaFlag,
+					utils.RinkebyFlag,
+					utils.GoerliFlag,
+					utils.ScrollAlphaFlag,
+					utils.ScrollSepoliaFlag,
+					utils.ScrollFlag,
+					batchIndexFlag,
+					commitTxFlag,
+					finalizeEventFlag,
+				},
+				Description: `
+geth rollup verify-batch --index <batch index> --commit-tx <file> --finalize-event <file>
+recomputes the batch from the blocks in the local database and compares its state
+root, withdraw root and batch hash with the values finalized on L1. The chunks
+and the parent batch are decoded from the commit batch transaction, which is not
+needed for the genesis batch. The command exits with an error on any mismatch.
+`,
+			},
+		},
+	}
+)
+
+// verifyBatch recomputes a finalized batch from the local chain and prints a
+// per-field comparison with the batch finalized on L1.
+func verifyBatch(ctx *cli.Context) error {
+	if !ctx.IsSet(batchIndexFlag.Name) || !ctx.IsSet(finalizeEventFlag.Name) {
+		utils.Fatalf("This command requires --%s and --%s.", batchIndexFlag.Name, finalizeEventFlag.Name)
+	}
+	batchIndex := ctx.Uint64(batchIndexFlag.Name)
+
+	event, err := rollup_sync_service.ReadFinalizeBatchEvent(ctx.String(finalizeEventFlag.Name), batchIndex)
+	if err != nil {
+		utils.Fatalf("Failed to read finalize batch event: %v", err)
+	}
+	var commitTxData []byte
+	if batchIndex > 0 {
+		if !ctx.IsSet(commitTxFlag.Name) {
+			utils.Fatalf("This command requires --%s for non-genesis batches.", commitTxFlag.Name)
+		}
+		if commitTxData, err = rollup_sync_service.ReadCommitBatchTxData(ctx.String(commitTxFlag.Name)); err != nil {
+			utils.Fatalf("Failed to read commit batch transaction: %v", err)
+		}
+	}
+
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	chain, db := utils.MakeChain(ctx, stack)
+	v, err := rollup_sync_service.VerifyBatchOffline(chain, commitTxData, event)
+	if err != nil {
+		utils.Fatalf("Failed to verify batch %d: %v", batchIndex, err)
+	}
+
+	fmt.Printf("Batch %d: blocks %d - %d, total L1 messages popped %d\n\n", v.BatchIndex, v.StartBlockNumber, v.EndBlockNumber, v.FinalizedBatchMeta.TotalL1MessagePopped)
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Field", "L1", "Local", "Match"})
+	table.Append([]string{"state root", v.L1StateRoot.Hex(), v.L2StateRoot.Hex(), strconv.FormatBool(v.L1StateRoot == v.L2StateRoot)})
+	table.Append([]string{"withdraw root", v.L1WithdrawRoot.Hex(), v.L2WithdrawRoot.Hex(), strconv.FormatBool(v.L1WithdrawRoot == v.L2WithdrawRoot)})
+	table.Append([]string{"batch hash", v.L1BatchHash.Hex(), v.L2BatchHash.Hex(), strconv.FormatBool(v.L1BatchHash == v.L2BatchHash)})
+
+	// Compare the parent batch of the commit transaction with the one verified by the rollup sync service, if any.
+	if batchIndex > 0 {
+		if parent := rawdb.ReadFinalizedBatchMeta(db, batchIndex-1); parent != nil {
+			table.Append([]string{"parent batch hash", v.ParentBatchMeta.BatchHash.Hex(), parent.BatchHash.Hex(), strconv.FormatBool(v.ParentBatchMeta.BatchHash == parent.BatchHash)})
+			table.Append([]string{"parent total L1 messages popped", strconv.FormatUint(v.ParentBatchMeta.TotalL1MessagePopped, 10), strconv.FormatUint(parent.TotalL1MessagePopped, 10), strconv.FormatBool(v.ParentBatchMeta.TotalL1MessagePopped == parent.TotalL1MessagePopped)})
+		}
+	}
+	table.Render()
+
+	if !v.Match() {
+		return errors.New("local batch does not match the batch finalized on L1")
+	}
+	fmt.Println("\nLocal batch matches the batch finalized on L1")
+	return nil
+}
@@ -195,6 +195,10 @@ var AppHelpFlagGroups = []flags.FlagGroup{
 			utils.MinerNoVerifyFlag,
 			utils.MinerStoreSkippedTxTracesFlag,
 			utils.MinerMaxAccountsNumFlag,
+			utils.MinerTxOrderingFlag,
+			utils.MinerPriorityAddressesFlag,
+			utils.MinerPreconfirmationsFlag,
+			utils.MinerBundlesFlag,
 		},
 	},
 	{
@@ -20,6 +20,7 @@ package utils
 import (
 	"bufio"
 	"compress/gzip"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -350,6 +351,195 @@ func ExportPreimages(db ethdb.Database, fn string) error {
 	return nil
 }
 
+// isJSONLines reports whether an L1 message file holds one JSON-encoded transaction per line.
+func isJSONLines(fn string) bool {
+	return strings.HasSuffix(strings.TrimSuffix(fn, ".gz"), ".jsonl")
+}
+
+// ExportL1Messages exports the L1 messages with queue indices in [first, last] into the specified file,
+// truncating any data already present in the file. Files ending with .jsonl hold one JSON-encoded
+// transaction per line, other files an RLP stream of L1 messages. If the file ends with .gz, the output
+// is gzipped.
+func ExportL1Messages(db ethdb.Database, fn string, first, last uint64) error {
+	log.Info("Exporting L1 messages", "file", fn, "first", first, "last", last)
+
+	fh, err := os.OpenFile(fn, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, os.ModePerm)
+	if err != nil {
+		return err
+	}
+	defer fh.Close()
+
+	var writer io.Writer = fh
+	if strings.HasSuffix(fn, ".gz") {
+		writer = gzip.NewWriter(writer)
+		defer writer.(*gzip.Writer).Close()
+	}
+	jsonLines := isJSONLines(fn)
+
+	it := rawdb.IterateL1MessagesFrom(db, first)
+	defer it.Release()
+
+	next := first
+	for next <= last && it.Next() {
+		msg := it.L1Message()
+		if msg.QueueIndex != next {
+			return fmt.Errorf("missing L1 message, queue index: %d", next)
+		}
+		if jsonLines {
+			data, err := types.NewTx(&msg).MarshalJSON()
+			if err != nil {
+				return err
+			}
+			if _, err := writer.Write(append(data, '\n')); err != nil {
+				return err
+			}
+		} else if err := rlp.Encode(writer, &msg); err != nil {
+			return err
+		}
+		next++
+	}
+	if err := it.Error(); err != nil {
+		return err
+	}
+	if next <= last {
+		return fmt.Errorf("missing L1 message, queue index: %d", next)
+	}
+	log.Info("Exported L1 messages", "file", fn, "count", next-first)
+	return nil
+}
+
+// ImportL1Messages imports L1 messages exported by ExportL1Messages into the database and returns the
+// number of imported messages. The messages of the file must have contiguous queue indices, and the
+// hashes of JSON-encoded messages are checked against their contents. The whole file is checked before
+// any message is written, so nothing is imported from an invalid file. The highest synced queue index
+// is only advanced if the imported messages connect to the messages in the database.
+func ImportL1Messages(db ethdb.Database, fn string) (uint64, error) {
+	log.Info("Importing L1 messages", "file", fn)
+
+	// the first pass only decodes and checks the messages
+	first, count, err := readL1Messages(fn, func(*types.L1MessageTx) error { return nil })
+	if err != nil {
+		return 0, err
+	}
+
+	// WriteL1Message moves the highest synced queue index to each written message,
+	// so it is restored once all messages are written
+	highest := rawdb.ReadHighestSyncedQueueIndex(db)
+	batch := db.NewBatch()
+	_, _, err = readL1Messages(fn, func(msg *types.L1MessageTx) error {
+		rawdb.WriteL1Message(batch, *msg)
+		if batch.ValueSize() > ethdb.IdealBatchSize {
+			rawdb.WriteHighestSyncedQueueIndex(batch, highest)
+			if err := batch.Write(); err != nil {
+				return err
+			}
+			batch.Reset()
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if count > 0 {
+		last := first + count - 1
+		connected := first == 0 || (first-1 <= highest && rawdb.ReadL1MessageRLP(db, first-1) != nil)
+		if connected && last > highest {
+			highest = last
+		}
+	}
+	rawdb.WriteHighestSyncedQueueIndex(batch, highest)
+	if err := batch.Write(); err != nil {
+		return 0, err
+	}
+	log.Info("Imported L1 messages", "file", fn, "first", first, "count", count, "highest synced queue index", highest)
+	return count, nil
+}
+
+// readL1Messages decodes the L1 messages of a file written by ExportL1Messages, checks that their queue
+// indices are contiguous and passes them to fn. It returns the first queue index and the number of messages.
+func readL1Messages(fn string, process func(*types.L1MessageTx) error) (uint64, uint64, error) {
+	fh, err := os.Open(fn)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer fh.Close()
+
+	var reader io.Reader = bufio.NewReader(fh)
+	if strings.HasSuffix(fn, ".gz") {
+		if reader, err = gzip.NewReader(reader); err != nil {
+			return 0, 0, err
+		}
+	}
+
+	var next func() (*types.L1MessageTx, error)
+	if isJSONLines(fn) {
+		lines := bufio.NewReader(reader)
+		next = func() (*types.L1MessageTx, error) {
+			line, err := lines.ReadBytes('\n')
+			if err == io.EOF && len(line) > 0 {
+				err = nil
+			}
+			if err != nil {
+				return nil, err
+			}
+			return decodeL1MessageJSON(line)
+		}
+	} else {
+		stream := rlp.NewStream(reader, 0)
+		next = func() (*types.L1MessageTx, error) {
+			msg := new(types.L1MessageTx)
+			if err := stream.Decode(msg); err != nil {
+				return nil, err
+			}
+			return msg, nil
+		}
+	}
+
+	var first, count uint64
+	for {
+		msg, err := next()
+		if err == io.EOF {
+			break
+		}
+		if err == nil && count > 0 && msg.QueueIndex != first+count {
+			err = fmt.Errorf("non-contiguous L1 message, expected queue index: %d, got: %d", first+count, msg.QueueIndex)
+		}
+		if err == nil {
+			err = process(msg)
+		}
+		if err != nil {
+			return 0, 0, fmt.Errorf("failed to import L1 message %d, err: %w", count, err)
+		}
+		if count == 0 {
+			first = msg.QueueIndex
+		}
+		count++
+	}
+	return first, count, nil
+}
+
+// decodeL1MessageJSON decodes an L1 message transaction in its JSON-RPC encoding and checks its hash if present.
+func decodeL1MessageJSON(data []byte) (*types.L1MessageTx, error) {
+	var tx types.Transaction
+	if err := json.Unmarshal(data, &tx); err != nil {
+		return nil, err
+	}
+	if !tx.IsL1MessageTx() {
+		return nil, fmt.Errorf("unexpected transaction type: %d", tx.Type())
+	}
+	var dec struct {
+		Hash *common.Hash `json:"hash"`
+	}
+	if err := json.Unmarshal(data, &dec); err != nil {
+		return nil, err
+	}
+	if dec.Hash != nil && *dec.Hash != tx.Hash() {
+		return nil, fmt.Errorf("L1 message hash mismatch, expected: %s, recomputed: %s", dec.Hash.Hex(), tx.Hash().Hex())
+	}
+	return tx.AsL1MessageTx(), nil
+}
+
 // exportHeader is used in the export/import flow. When we do an export,
 // the first element we output is the exportHeader.
 // Whenever a backwards-incompatible change is made, the Version header
@@ -17,13 +17,17 @@
 package utils
 
 import (
+	"bytes"
 	"fmt"
+	"math/big"
 	"os"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/scroll-tech/go-ethereum/common"
 	"github.com/scroll-tech/go-ethereum/core/rawdb"
+	"github.com/scroll-tech/go-ethereum/core/types"
 	"github.com/scroll-tech/go-ethereum/rlp"
 )
 
@@ -196,3 +200,99 @@ func TestImportFutureFormat(t *testing.T) {
 		t.Fatalf("wrong error: %v", err)
 	}
 }
+
+func TestExportImportL1Messages(t *testing.T) {
+	for _, name := range []string{"l1msgs.rlp", "l1msgs.jsonl", "l1msgs.jsonl.gz"} {
+		t.Run(name, func(t *testing.T) {
+			testExportImportL1Messages(t, fmt.Sprintf("%v/%v", t.TempDir(), name))
+		})
+	}
+}
+
+func testExportImportL1Messages(t *testing.T, f string) {
+	to := common.HexToAddress("0x1234")
+	db := rawdb.NewMemoryDatabase()
+	for i := uint64(0); i < 100; i++ {
+		rawdb.WriteL1Message(db, types.L1MessageTx{QueueIndex: i, Gas: 100000, To: &to, Value: big.NewInt(int64(i)), Data: []byte{byte(i)}, Sender: to})
+	}
+	if err := ExportL1Messages(db, f, 10, 99); err != nil {
+		t.Fatal(err)
+	}
+	if err := ExportL1Messages(db, f+".missing", 90, 100); err == nil {
+		t.Fatal("expected error when exporting missing L1 messages")
+	}
+
+	// importing without the preceding messages does not advance the highest synced queue index
+	db2 := rawdb.NewMemoryDatabase()
+	count, err := ImportL1Messages(db2, f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 90 {
+		t.Fatalf("imported L1 message count mismatch: have %d, want %d", count, 90)
+	}
+	if highest := rawdb.ReadHighestSyncedQueueIndex(db2); highest != 0 {
+		t.Fatalf("highest synced queue index mismatch: have %d, want %d", highest, 0)
+	}
+	for i := uint64(10); i < 100; i++ {
+		if have, want := rawdb.ReadL1MessageRLP(db2, i), rawdb.ReadL1MessageRLP(db, i); !bytes.Equal(have, want) {
+			t.Fatalf("L1 message %d mismatch: have %x, want %x", i, have, want)
+		}
+	}
+
+	// once the preceding messages exist, the imported messages become synced
+	for i := uint64(0); i < 10; i++ {
+		rawdb.WriteL1Message(db2, *rawdb.ReadL1Message(db, i))
+	}
+	if _, err := ImportL1Messages(db2, f); err != nil {
+		t.Fatal(err)
+	}
+	if highest := rawdb.ReadHighestSyncedQueueIndex(db2); highest != 99 {
+		t.Fatalf("highest synced queue index mismatch: have %d, want %d", highest, 99)
+	}
+}
+
+func TestImportL1MessagesHashMismatch(t *testing.T) {
+	f := fmt.Sprintf("%v/l1msgs.jsonl", t.TempDir())
+	data := `{"type":"0x7e","queueIndex":"0x0","gas":"0x1","to":"0x0000000000000000000000000000000000001234","value":"0x0","input":"0x","sender":"0x0000000000000000000000000000000000001234","hash":"0x0000000000000000000000000000000000000000000000000000000000000001"}` + "\n"
+	if err := os.WriteFile(f, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	db := rawdb.NewMemoryDatabase()
+	if _, err := ImportL1Messages(db, f); err == nil || !strings.Contains(err.Error(), "hash mismatch") {
+		t.Fatalf("expected hash mismatch error, got %v", err)
+	}
+	if msg := rawdb.ReadL1Message(db, 0); msg != nil {
+		t.Fatal("L1 message with mismatching hash was imported")
+	}
+}
+
+func TestImportL1MessagesInvalidFile(t *testing.T) {
+	to := common.HexToAddress("0x1234")
+	db := rawdb.NewMemoryDatabase()
+	for _, i := range []uint64{0, 1, 3} {
+		rawdb.WriteL1Message(db, types.L1MessageTx{QueueIndex: i, Gas: 100000, To: &to, Value: big.NewInt(0), Sender: to})
+	}
+	f := fmt.Sprintf("%v/l1msgs.rlp", t.TempDir())
+	fh, err := os.Create(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, i := range []uint64{0, 1, 3} {
+		if err := rlp.Encode(fh, rawdb.ReadL1Message(db, i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	fh.Close()
+
+	// the valid messages before the gap are not imported either
+	db2 := rawdb.NewMemoryDatabase()
+	if _, err := ImportL1Messages(db2, f); err == nil || !strings.Contains(err.Error(), "non-contiguous") {
+		t.Fatalf("expected non-contiguous L1 message error, got %v", err)
+	}
+	for _, i := range []uint64{0, 1, 3} {
+		if msg := rawdb.ReadL1Message(db2, i); msg != nil {
+			t.Fatalf("L1 message %d of an invalid file was imported", i)
+		}
+	}
+}
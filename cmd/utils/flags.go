@@ -73,6 +73,8 @@ import (
 	"github.com/scroll-tech/go-ethereum/p2p/nat"
 	"github.com/scroll-tech/go-ethereum/p2p/netutil"
 	"github.com/scroll-tech/go-ethereum/params"
+	"github.com/scroll-tech/go-ethereum/rollup/circuitcapacitychecker"
+	"github.com/scroll-tech/go-ethereum/rollup/sync_service"
 	"github.com/scroll-tech/go-ethereum/rollup/tracing"
 	"github.com/scroll-tech/go-ethereum/rpc"
 )
@@ -505,6 +507,23 @@ var (
 		Usage: "Maximum number of accounts that miner will fetch the pending transactions of when building a new block",
 		Value: math.MaxInt,
 	}
+	MinerTxOrderingFlag = cli.StringFlag{
+		Name:  "miner.txordering",
+		Usage: "Ordering policy of pending L2 transactions (price, fifo, roundrobin, priority), only price commits local transactions first",
+		Value: miner.OrderingPrice,
+	}
+	MinerPriorityAddressesFlag = cli.StringFlag{
+		Name:  "miner.priorityaddrs",
+		Usage: "Comma separated list of senders whose transactions are included first by the priority ordering policy",
+	}
+	MinerPreconfirmationsFlag = cli.BoolFlag{
+		Name:  "miner.preconfirmations",
+		Usage: "Sign and stream preconfirmations of the transactions committed into the pending block (clique only)",
+	}
+	MinerBundlesFlag = cli.BoolFlag{
+		Name:  "miner.bundles",
+		Usage: "Accept transaction bundles over eth_sendBundle",
+	}
 	// Account settings
 	UnlockedAccountFlag = cli.StringFlag{
 		Name:  "unlock",
@@ -825,7 +844,7 @@ var (
 	// L1Settings
 	L1EndpointFlag = cli.StringFlag{
 		Name:  "l1.endpoint",
-		Usage: "Endpoint of L1 HTTP-RPC server",
+		Usage: "Endpoint of L1 HTTP-RPC or WebSocket server (WebSocket enables push-based L1 event ingestion), or a comma-separated list of endpoints in order of preference",
 	}
 	L1ConfirmationsFlag = cli.StringFlag{
 		Name:  "l1.confirmations",
@@ -835,12 +854,68 @@ var (
 		Name:  "l1.sync.startblock",
 		Usage: "L1 block height to start syncing from. Should be set to the L1 message queue deployment block number.",
 	}
+	L1BeaconEndpointFlag = cli.StringFlag{
+		Name:  "l1.beacon.endpoint",
+		Usage: "Endpoint of L1 beacon node API, used to verify the blobs of committed batches",
+	}
+	L1HedgeDelayFlag = cli.DurationFlag{
+		Name:  "l1.hedgedelay",
+		Usage: "Delay after which slow L1 requests are also sent to the next L1 endpoint (0 = disabled)",
+	}
+	L1CrossCheckLogsFlag = cli.BoolFlag{
+		Name:  "l1.crosschecklogs",
+		Usage: "Only accept L1 logs if two L1 endpoints return the same logs (requires at least 2 L1 endpoints)",
+	}
+	L1CheckpointNumberFlag = cli.Uint64Flag{
+		Name:  "l1.checkpoint.number",
+		Usage: "Number of a trusted L1 block, must not be later than the L1 sync start block on a fresh node",
+	}
+	L1CheckpointHashFlag = cli.StringFlag{
+		Name:  "l1.checkpoint.hash",
+		Usage: "Hash of the trusted L1 block, enables verifying L1 messages against L1 block headers and receipts",
+	}
 
 	// Circuit capacity check settings
 	CircuitCapacityCheckEnabledFlag = cli.BoolFlag{
 		Name:  "ccc",
 		Usage: "Enable circuit capacity check during block validation",
 	}
+	CircuitCapacityCheckRemoteFlag = cli.StringFlag{
+		Name:  "ccc.remote",
+		Usage: "RPC endpoint of an out-of-process circuit capacity checker (ccc), used instead of the in-process one",
+	}
+	CircuitCapacityCheckRemoteTimeoutFlag = cli.DurationFlag{
+		Name:  "ccc.remote.timeout",
+		Usage: "Timeout of a call to the out-of-process circuit capacity checker",
+		Value: circuitcapacitychecker.DefaultRemoteTimeout,
+	}
+	CircuitCapacityCheckRemoteCommandFlag = cli.StringFlag{
+		Name:  "ccc.remote.cmd",
+		Usage: "Command that starts the out-of-process circuit capacity checker, the node restarts it if it exits",
+	}
+	CircuitCapacityCheckMaxRowsFlag = cli.Uint64Flag{
+		Name:  "ccc.maxrows",
+		Usage: "Row limit of each sub-circuit of the circuit capacity checker, used for row share metrics and estimates",
+		Value: ethconfig.Defaults.Miner.CircuitCapacityChecker.MaxRows,
+	}
+	CircuitCapacityVerifyEnabledFlag = cli.BoolFlag{
+		Name:  "ccc.verify",
+		Usage: "Recompute the row consumption of imported blocks in the background and compare it with the sequencer's",
+	}
+	CircuitCapacityVerifyWorkersFlag = cli.IntFlag{
+		Name:  "ccc.verify.workers",
+		Usage: "Number of blocks whose row consumption is recomputed concurrently",
+		Value: ethconfig.Defaults.RowConsumptionVerifier.Workers,
+	}
+	CircuitCapacityVerifyQueueFlag = cli.IntFlag{
+		Name:  "ccc.verify.queue",
+		Usage: "Number of imported blocks waiting for row consumption verification, further blocks are not verified",
+		Value: ethconfig.Defaults.RowConsumptionVerifier.QueueSize,
+	}
+	CircuitCapacityVerifySequencerFlag = cli.StringFlag{
+		Name:  "ccc.verify.sequencer",
+		Usage: "RPC endpoint of the sequencer to fetch the row consumption of blocks from (default: local database)",
+	}
 
 	// Rollup verify service settings
 	RollupVerifyEnabledFlag = cli.BoolFlag{
@@ -848,6 +923,73 @@ var (
 		Usage: "Enable verification of batch consistency between L1 and L2 in rollup",
 	}
 
+	// Rollup proposer settings
+	RollupProposerEnabledFlag = cli.BoolFlag{
+		Name:  "rollup.proposer",
+		Usage: "Enable the in-node proposer that groups L2 blocks into chunks and batches",
+	}
+	RollupProposerMaxBlocksPerChunkFlag = cli.Uint64Flag{
+		Name:  "rollup.proposer.chunk.maxblocks",
+		Usage: "Maximum number of blocks in a proposed chunk (0 = no limit)",
+		Value: ethconfig.Defaults.Proposer.MaxBlockNumPerChunk,
+	}
+	RollupProposerMaxTxsPerChunkFlag = cli.Uint64Flag{
+		Name:  "rollup.proposer.chunk.maxtxs",
+		Usage: "Maximum number of transactions in a proposed chunk (0 = no limit)",
+		Value: ethconfig.Defaults.Proposer.MaxTxNumPerChunk,
+	}
+	RollupProposerMaxL1GasPerChunkFlag = cli.Uint64Flag{
+		Name:  "rollup.proposer.chunk.maxl1gas",
+		Usage: "Maximum estimated L1 commit gas of a proposed chunk (0 = no limit)",
+		Value: ethconfig.Defaults.Proposer.MaxL1CommitGasPerChunk,
+	}
+	RollupProposerMaxCalldataPerChunkFlag = cli.Uint64Flag{
+		Name:  "rollup.proposer.chunk.maxcalldata",
+		Usage: "Maximum estimated L1 commit calldata size of a proposed chunk (0 = no limit)",
+		Value: ethconfig.Defaults.Proposer.MaxL1CommitCalldataSizePerChunk,
+	}
+	RollupProposerMaxRowsPerChunkFlag = cli.Uint64Flag{
+		Name:  "rollup.proposer.chunk.maxrows",
+		Usage: "Maximum row consumption of any sub-circuit in a proposed chunk, requires row consumption of blocks (0 = no limit)",
+		Value: ethconfig.Defaults.Proposer.MaxRowConsumptionPerChunk,
+	}
+	RollupProposerChunkTimeoutFlag = cli.DurationFlag{
+		Name:  "rollup.proposer.chunk.timeout",
+		Usage: "Age of the first block after which a chunk is proposed even if it is not full (0 = never)",
+		Value: ethconfig.Defaults.Proposer.ChunkTimeout,
+	}
+	RollupProposerMaxChunksPerBatchFlag = cli.Uint64Flag{
+		Name:  "rollup.proposer.batch.maxchunks",
+		Usage: "Maximum number of chunks in a proposed batch, further limited by the codec (0 = codec limit)",
+		Value: ethconfig.Defaults.Proposer.MaxChunkNumPerBatch,
+	}
+	RollupProposerMaxL1GasPerBatchFlag = cli.Uint64Flag{
+		Name:  "rollup.proposer.batch.maxl1gas",
+		Usage: "Maximum estimated L1 commit gas of a proposed batch (0 = no limit)",
+		Value: ethconfig.Defaults.Proposer.MaxL1CommitGasPerBatch,
+	}
+	RollupProposerMaxCalldataPerBatchFlag = cli.Uint64Flag{
+		Name:  "rollup.proposer.batch.maxcalldata",
+		Usage: "Maximum estimated L1 commit calldata size of a proposed batch (0 = no limit)",
+		Value: ethconfig.Defaults.Proposer.MaxL1CommitCalldataSizePerBatch,
+	}
+	RollupProposerBatchTimeoutFlag = cli.DurationFlag{
+		Name:  "rollup.proposer.batch.timeout",
+		Usage: "Age of the first block after which a batch is proposed even if it is not full (0 = never)",
+		Value: ethconfig.Defaults.Proposer.BatchTimeout,
+	}
+	RollupProposerMaxBlobSizeFlag = cli.Uint64Flag{
+		Name:  "rollup.proposer.maxblobsize",
+		Usage: "Maximum estimated blob size of a proposed chunk or batch (0 = no limit)",
+		Value: ethconfig.Defaults.Proposer.MaxBlobSize,
+	}
+
+	// Withdraw trie indexer settings
+	RollupWithdrawTrieFlag = cli.BoolFlag{
+		Name:  "rollup.withdrawtrie",
+		Usage: "Enable the withdraw trie indexer that serves withdrawal proofs",
+	}
+
 	// Max block range for `eth_getLogs` method
 	MaxBlockRangeFlag = cli.Int64Flag{
 		Name:  "rpc.getlogs.maxrange",
@@ -1355,6 +1497,23 @@ func setL1(ctx *cli.Context, cfg *node.Config) {
 	if ctx.GlobalIsSet(L1DeploymentBlockFlag.Name) {
 		cfg.L1DeploymentBlock = ctx.GlobalUint64(L1DeploymentBlockFlag.Name)
 	}
+	if ctx.GlobalIsSet(L1BeaconEndpointFlag.Name) {
+		cfg.L1BeaconEndpoint = ctx.GlobalString(L1BeaconEndpointFlag.Name)
+	}
+	if ctx.GlobalIsSet(L1HedgeDelayFlag.Name) {
+		cfg.L1HedgeDelay = ctx.GlobalDuration(L1HedgeDelayFlag.Name)
+	}
+	if ctx.GlobalIsSet(L1CrossCheckLogsFlag.Name) {
+		cfg.L1CrossCheckLogs = ctx.GlobalBool(L1CrossCheckLogsFlag.Name)
+	}
+	if ctx.GlobalIsSet(L1CheckpointNumberFlag.Name) {
+		cfg.L1CheckpointNumber = ctx.GlobalUint64(L1CheckpointNumberFlag.Name)
+	}
+	if ctx.GlobalIsSet(L1CheckpointHashFlag.Name) {
+		if err := cfg.L1CheckpointHash.UnmarshalText([]byte(ctx.GlobalString(L1CheckpointHashFlag.Name))); err != nil {
+			Fatalf("Invalid value for flag %s: %v", L1CheckpointHashFlag.Name, err)
+		}
+	}
 }
 
 func setSmartCard(ctx *cli.Context, cfg *node.Config) {
@@ -1527,6 +1686,27 @@ func setMiner(ctx *cli.Context, cfg *miner.Config) {
 	if ctx.GlobalIsSet(MinerMaxAccountsNumFlag.Name) {
 		cfg.MaxAccountsNum = ctx.GlobalInt(MinerMaxAccountsNumFlag.Name)
 	}
+	if ctx.GlobalIsSet(MinerTxOrderingFlag.Name) {
+		cfg.TxOrdering = ctx.GlobalString(MinerTxOrderingFlag.Name)
+	}
+	if ctx.GlobalIsSet(MinerPriorityAddressesFlag.Name) {
+		cfg.PriorityAddresses = nil
+		for _, addr := range strings.Split(ctx.GlobalString(MinerPriorityAddressesFlag.Name), ",") {
+			if addr = strings.TrimSpace(addr); !common.IsHexAddress(addr) {
+				Fatalf("Invalid priority address: %s", addr)
+			}
+			cfg.PriorityAddresses = append(cfg.PriorityAddresses, common.HexToAddress(addr))
+		}
+	}
+	if ctx.GlobalIsSet(MinerPreconfirmationsFlag.Name) {
+		cfg.Preconfirmations = ctx.GlobalBool(MinerPreconfirmationsFlag.Name)
+	}
+	if ctx.GlobalIsSet(MinerBundlesFlag.Name) {
+		cfg.Bundles = ctx.GlobalBool(MinerBundlesFlag.Name)
+	}
+	if _, err := miner.NewOrderingPolicy(cfg.TxOrdering, cfg.PriorityAddresses); err != nil {
+		Fatalf("Invalid --%s: %v", MinerTxOrderingFlag.Name, err)
+	}
 	if ctx.GlobalIsSet(LegacyMinerGasTargetFlag.Name) {
 		log.Warn("The generic --miner.gastarget flag is deprecated and will be removed in the future!")
 	}
@@ -1559,6 +1739,30 @@ func setCircuitCapacityCheck(ctx *cli.Context, cfg *ethconfig.Config) {
 	if ctx.GlobalIsSet(CircuitCapacityCheckEnabledFlag.Name) {
 		cfg.CheckCircuitCapacity = ctx.GlobalBool(CircuitCapacityCheckEnabledFlag.Name)
 	}
+	if ctx.GlobalIsSet(CircuitCapacityCheckRemoteFlag.Name) {
+		cfg.Miner.CircuitCapacityChecker.Endpoint = ctx.GlobalString(CircuitCapacityCheckRemoteFlag.Name)
+	}
+	if ctx.GlobalIsSet(CircuitCapacityCheckRemoteTimeoutFlag.Name) {
+		cfg.Miner.CircuitCapacityChecker.Timeout = ctx.GlobalDuration(CircuitCapacityCheckRemoteTimeoutFlag.Name)
+	}
+	if ctx.GlobalIsSet(CircuitCapacityCheckRemoteCommandFlag.Name) {
+		cfg.Miner.CircuitCapacityChecker.Command = strings.Fields(ctx.GlobalString(CircuitCapacityCheckRemoteCommandFlag.Name))
+	}
+	if ctx.GlobalIsSet(CircuitCapacityCheckMaxRowsFlag.Name) {
+		cfg.Miner.CircuitCapacityChecker.MaxRows = ctx.GlobalUint64(CircuitCapacityCheckMaxRowsFlag.Name)
+	}
+	if ctx.GlobalIsSet(CircuitCapacityVerifyEnabledFlag.Name) {
+		cfg.VerifyRowConsumption = ctx.GlobalBool(CircuitCapacityVerifyEnabledFlag.Name)
+	}
+	if ctx.GlobalIsSet(CircuitCapacityVerifyWorkersFlag.Name) {
+		cfg.RowConsumptionVerifier.Workers = ctx.GlobalInt(CircuitCapacityVerifyWorkersFlag.Name)
+	}
+	if ctx.GlobalIsSet(CircuitCapacityVerifyQueueFlag.Name) {
+		cfg.RowConsumptionVerifier.QueueSize = ctx.GlobalInt(CircuitCapacityVerifyQueueFlag.Name)
+	}
+	if ctx.GlobalIsSet(CircuitCapacityVerifySequencerFlag.Name) {
+		cfg.RowConsumptionVerifier.Sequencer = ctx.GlobalString(CircuitCapacityVerifySequencerFlag.Name)
+	}
 }
 
 func setEnableRollupVerify(ctx *cli.Context, cfg *ethconfig.Config) {
@@ -1567,6 +1771,51 @@ func setEnableRollupVerify(ctx *cli.Context, cfg *ethconfig.Config) {
 	}
 }
 
+func setRollupProposer(ctx *cli.Context, cfg *ethconfig.Config) {
+	if ctx.GlobalIsSet(RollupProposerEnabledFlag.Name) {
+		cfg.EnableProposer = ctx.GlobalBool(RollupProposerEnabledFlag.Name)
+	}
+	if ctx.GlobalIsSet(RollupProposerMaxBlocksPerChunkFlag.Name) {
+		cfg.Proposer.MaxBlockNumPerChunk = ctx.GlobalUint64(RollupProposerMaxBlocksPerChunkFlag.Name)
+	}
+	if ctx.GlobalIsSet(RollupProposerMaxTxsPerChunkFlag.Name) {
+		cfg.Proposer.MaxTxNumPerChunk = ctx.GlobalUint64(RollupProposerMaxTxsPerChunkFlag.Name)
+	}
+	if ctx.GlobalIsSet(RollupProposerMaxL1GasPerChunkFlag.Name) {
+		cfg.Proposer.MaxL1CommitGasPerChunk = ctx.GlobalUint64(RollupProposerMaxL1GasPerChunkFlag.Name)
+	}
+	if ctx.GlobalIsSet(RollupProposerMaxCalldataPerChunkFlag.Name) {
+		cfg.Proposer.MaxL1CommitCalldataSizePerChunk = ctx.GlobalUint64(RollupProposerMaxCalldataPerChunkFlag.Name)
+	}
+	if ctx.GlobalIsSet(RollupProposerMaxRowsPerChunkFlag.Name) {
+		cfg.Proposer.MaxRowConsumptionPerChunk = ctx.GlobalUint64(RollupProposerMaxRowsPerChunkFlag.Name)
+	}
+	if ctx.GlobalIsSet(RollupProposerChunkTimeoutFlag.Name) {
+		cfg.Proposer.ChunkTimeout = ctx.GlobalDuration(RollupProposerChunkTimeoutFlag.Name)
+	}
+	if ctx.GlobalIsSet(RollupProposerMaxChunksPerBatchFlag.Name) {
+		cfg.Proposer.MaxChunkNumPerBatch = ctx.GlobalUint64(RollupProposerMaxChunksPerBatchFlag.Name)
+	}
+	if ctx.GlobalIsSet(RollupProposerMaxL1GasPerBatchFlag.Name) {
+		cfg.Proposer.MaxL1CommitGasPerBatch = ctx.GlobalUint64(RollupProposerMaxL1GasPerBatchFlag.Name)
+	}
+	if ctx.GlobalIsSet(RollupProposerMaxCalldataPerBatchFlag.Name) {
+		cfg.Proposer.MaxL1CommitCalldataSizePerBatch = ctx.GlobalUint64(RollupProposerMaxCalldataPerBatchFlag.Name)
+	}
+	if ctx.GlobalIsSet(RollupProposerBatchTimeoutFlag.Name) {
+		cfg.Proposer.BatchTimeout = ctx.GlobalDuration(RollupProposerBatchTimeoutFlag.Name)
+	}
+	if ctx.GlobalIsSet(RollupProposerMaxBlobSizeFlag.Name) {
+		cfg.Proposer.MaxBlobSize = ctx.GlobalUint64(RollupProposerMaxBlobSizeFlag.Name)
+	}
+}
+
+func setEnableWithdrawTrieIndexer(ctx *cli.Context, cfg *ethconfig.Config) {
+	if ctx.GlobalIsSet(RollupWithdrawTrieFlag.Name) {
+		cfg.EnableWithdrawTrieIndexer = ctx.GlobalBool(RollupWithdrawTrieFlag.Name)
+	}
+}
+
 func setMaxBlockRange(ctx *cli.Context, cfg *ethconfig.Config) {
 	if ctx.GlobalIsSet(MaxBlockRangeFlag.Name) {
 		cfg.MaxBlockRange = ctx.GlobalInt64(MaxBlockRangeFlag.Name)
@@ -1642,6 +1891,8 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *ethconfig.Config) {
 	setLes(ctx, cfg)
 	setCircuitCapacityCheck(ctx, cfg)
 	setEnableRollupVerify(ctx, cfg)
+	setRollupProposer(ctx, cfg)
+	setEnableWithdrawTrieIndexer(ctx, cfg)
 	setMaxBlockRange(ctx, cfg)
 
 	// Cap the cache allowance and tune the garbage collector
@@ -1920,18 +2171,7 @@ func RegisterEthService(stack *node.Node, cfg *ethconfig.Config) (ethapi.Backend
 
 	// initialize L1 client for sync service
 	// note: we need to do this here to avoid circular dependency
-	l1EndpointUrl := stack.Config().L1Endpoint
-	var l1Client *ethclient.Client
-
-	if l1EndpointUrl != "" {
-		var err error
-		l1Client, err = ethclient.Dial(l1EndpointUrl)
-		if err != nil {
-			Fatalf("Unable to connect to L1 endpoint at %v: %v", l1EndpointUrl, err)
-		}
-
-		log.Info("Initialized L1 client", "endpoint", l1EndpointUrl)
-	}
+	l1Client := newL1Client(stack.Config())
 
 	backend, err := eth.New(stack, cfg, l1Client)
 	if err != nil {
@@ -1948,6 +2188,42 @@ func RegisterEthService(stack *node.Node, cfg *ethconfig.Config) (ethapi.Backend
 	return backend.APIBackend, backend
 }
 
+// newL1Client connects to the configured L1 endpoints. If more than one endpoint
+// is configured, requests are spread over them with failover.
+func newL1Client(cfg *node.Config) sync_service.EthClient {
+	if cfg.L1Endpoint == "" {
+		return nil
+	}
+
+	var endpoints []sync_service.MultiClientEndpoint
+	for _, url := range strings.Split(cfg.L1Endpoint, ",") {
+		url = strings.TrimSpace(url)
+		if url == "" {
+			continue
+		}
+		client, err := ethclient.Dial(url)
+		if err != nil {
+			Fatalf("Unable to connect to L1 endpoint at %v: %v", url, err)
+		}
+		log.Info("Initialized L1 client", "endpoint", url)
+		endpoints = append(endpoints, sync_service.MultiClientEndpoint{URL: url, Client: client})
+	}
+
+	// keep using a single endpoint directly unless multi-endpoint features are requested
+	if len(endpoints) == 1 && !cfg.L1CrossCheckLogs {
+		return endpoints[0].Client
+	}
+
+	client, err := sync_service.NewMultiClient(endpoints, sync_service.MultiClientConfig{
+		HedgeDelay:     cfg.L1HedgeDelay,
+		CrossCheckLogs: cfg.L1CrossCheckLogs,
+	})
+	if err != nil {
+		Fatalf("Unable to initialize L1 client: %v", err)
+	}
+	return client
+}
+
 // RegisterEthStatsService configures the Ethereum Stats daemon and adds it to
 // the given node.
 func RegisterEthStatsService(stack *node.Node, backend ethapi.Backend, url string) {
@@ -52,10 +52,11 @@ type BlockValidator struct {
 	engine consensus.Engine    // Consensus engine used for validating
 
 	// circuit capacity checker related fields
-	checkCircuitCapacity   bool                                           // whether enable circuit capacity check
-	cMu                    sync.Mutex                                     // mutex for circuit capacity checker
-	tracer                 tracerWrapper                                  // scroll tracer wrapper
-	circuitCapacityChecker *circuitcapacitychecker.CircuitCapacityChecker // circuit capacity checker instance
+	checkCircuitCapacity   bool                           // whether enable circuit capacity check
+	cMu                    sync.Mutex                     // mutex for circuit capacity checker
+	tracer                 tracerWrapper                  // scroll tracer wrapper
+	circuitCapacityChecker circuitcapacitychecker.Checker // circuit capacity checker instance
+	maxRows                uint64                         // row limit of each sub-circuit, for metrics
 }
 
 // NewBlockValidator returns a new block validator which is safe for re-use
@@ -72,11 +73,12 @@ type tracerWrapper interface {
 	CreateTraceEnvAndGetBlockTrace(*params.ChainConfig, ChainContext, consensus.Engine, ethdb.Database, *state.StateDB, *types.Block, *types.Block, bool) (*types.BlockTrace, error)
 }
 
-func (v *BlockValidator) SetupTracerAndCircuitCapacityChecker(tracer tracerWrapper) {
+func (v *BlockValidator) SetupTracerAndCircuitCapacityChecker(tracer tracerWrapper, remote circuitcapacitychecker.RemoteConfig) {
 	v.checkCircuitCapacity = true
 	v.tracer = tracer
-	v.circuitCapacityChecker = circuitcapacitychecker.NewCircuitCapacityChecker(true)
-	log.Info("new CircuitCapacityChecker in BlockValidator", "ID", v.circuitCapacityChecker.ID)
+	v.circuitCapacityChecker = circuitcapacitychecker.New(remote, true)
+	v.maxRows = remote.MaxRows
+	log.Info("new CircuitCapacityChecker in BlockValidator", "ID", v.circuitCapacityChecker.ID())
 }
 
 // ValidateBody validates the given block's uncles and verifies the block
@@ -127,12 +129,13 @@ func (v *BlockValidator) ValidateBody(block *types.Block) error {
 		}
 		log.Trace(
 			"Validator write block row consumption",
-			"id", v.circuitCapacityChecker.ID,
+			"id", v.circuitCapacityChecker.ID(),
 			"number", block.NumberU64(),
 			"hash", block.Hash().String(),
 			"rowConsumption", rowConsumption,
 		)
 		rawdb.WriteBlockRowConsumption(v.bc.db, block.Hash(), rowConsumption)
+		circuitcapacitychecker.UpdateRowConsumptionMetrics(rowConsumption, v.maxRows)
 	}
 	return nil
 }
@@ -203,7 +206,7 @@ func (v *BlockValidator) ValidateL1Messages(block *types.Block) error {
 			l1msg := it.L1Message()
 			skippedTx := types.NewTx(&l1msg)
 			log.Debug("Skipped L1 message", "queueIndex", index, "tx", skippedTx.Hash().String(), "block", blockHash.String())
-			rawdb.WriteSkippedTransaction(v.bc.db, skippedTx, nil, "unknown", block.NumberU64(), &blockHash)
+			v.bc.WriteSkippedTransaction(skippedTx, nil, rawdb.SkipReason{Code: rawdb.SkipReasonL1MessageSkipped, Message: "unknown"}, block.NumberU64(), &blockHash)
 		}
 
 		queueIndex = txQueueIndex + 1
@@ -308,7 +311,7 @@ func (v *BlockValidator) validateCircuitRowConsumption(block *types.Block) (*typ
 
 	log.Trace(
 		"Validator apply ccc for block",
-		"id", v.circuitCapacityChecker.ID,
+		"id", v.circuitCapacityChecker.ID(),
 		"number", block.NumberU64(),
 		"hash", block.Hash().String(),
 		"len(txs)", block.Transactions().Len(),
@@ -328,13 +331,13 @@ func (v *BlockValidator) validateCircuitRowConsumption(block *types.Block) (*typ
 
 	cccStartTime := time.Now()
 	v.circuitCapacityChecker.Reset()
-	log.Trace("Validator reset ccc", "id", v.circuitCapacityChecker.ID)
+	log.Trace("Validator reset ccc", "id", v.circuitCapacityChecker.ID())
 	rc, err := v.circuitCapacityChecker.ApplyBlock(traces)
 	validateCccTimer.Update(time.Since(cccStartTime))
 
 	log.Trace(
 		"Validator apply ccc for block result",
-		"id", v.circuitCapacityChecker.ID,
+		"id", v.circuitCapacityChecker.ID(),
 		"number", block.NumberU64(),
 		"hash", block.Hash().String(),
 		"len(txs)", block.Transactions().Len(),
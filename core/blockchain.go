@@ -91,6 +91,8 @@ var (
 	blockPrefetchExecuteTimer   = metrics.NewRegisteredTimer("chain/prefetch/executes", nil)
 	blockPrefetchInterruptMeter = metrics.NewRegisteredMeter("chain/prefetch/interrupts", nil)
 
+	skippedTxEventDroppedCounter = metrics.NewRegisteredCounter("chain/skippedtx/dropped", nil)
+
 	errInsertionInterrupted = errors.New("insertion is interrupted")
 	errChainStopped         = errors.New("blockchain is stopped")
 )
@@ -102,6 +104,7 @@ const (
 	txLookupCacheLimit  = 1024
 	maxFutureBlocks     = 256
 	maxTimeFutureBlocks = 30
+	skippedTxQueueSize  = 1024
 	TriesInMemory       = 128
 
 	// BlockChainVersion ensures that an incompatible database forces a resync from scratch.
@@ -196,6 +199,8 @@ type BlockChain struct {
 	chainHeadFeed event.Feed
 	logsFeed      event.Feed
 	blockProcFeed event.Feed
+	skippedTxFeed event.Feed
+	skippedTxs    chan SkippedTxEvent // skipped tx events waiting to be sent to skippedTxFeed
 	scope         event.SubscriptionScope
 	genesisBlock  *types.Block
 
@@ -264,6 +269,7 @@ func NewBlockChain(db ethdb.Database, cacheConfig *CacheConfig, chainConfig *par
 			Zktrie:    chainConfig.Scroll.ZktrieEnabled(),
 		}),
 		quit:           make(chan struct{}),
+		skippedTxs:     make(chan SkippedTxEvent, skippedTxQueueSize),
 		chainmu:        syncx.NewClosableMutex(),
 		shouldPreserve: shouldPreserve,
 		bodyCache:      bodyCache,
@@ -423,6 +429,10 @@ func NewBlockChain(db ethdb.Database, cacheConfig *CacheConfig, chainConfig *par
 	bc.wg.Add(1)
 	go bc.futureBlocksLoop()
 
+	// Start skipped tx event sender.
+	bc.wg.Add(1)
+	go bc.skippedTxLoop()
+
 	// Start tx indexer/unindexer.
 	if txLookupLimit != nil {
 		bc.txLookupLimit = *txLookupLimit
@@ -1451,6 +1461,38 @@ func (bc *BlockChain) writeBlockWithState(block *types.Block, receipts []*types.
 	return status, nil
 }
 
+// WriteSkippedTransaction stores a transaction skipped in the block with the given number and posts a
+// SkippedTxEvent asynchronously. blockHash is nil if the transaction was skipped while building the block.
+func (bc *BlockChain) WriteSkippedTransaction(tx *types.Transaction, traces *types.BlockTrace, reason rawdb.SkipReason, blockNumber uint64, blockHash *common.Hash) {
+	rawdb.WriteSkippedTransaction(bc.db, tx, traces, reason, blockNumber, blockHash)
+
+	sender, err := types.Sender(types.LatestSigner(bc.chainConfig), tx)
+	if err != nil {
+		log.Warn("Failed to recover sender of skipped transaction", "tx", tx.Hash().String(), "err", err)
+	}
+	// the event is dropped rather than blocking the caller, which is usually the worker, on slow subscribers
+	select {
+	case bc.skippedTxs <- SkippedTxEvent{Tx: tx, Sender: sender, Reason: reason, BlockNumber: blockNumber, BlockHash: blockHash}:
+	default:
+		log.Warn("Dropping skipped transaction event", "tx", tx.Hash().String())
+		skippedTxEventDroppedCounter.Inc(1)
+	}
+}
+
+// skippedTxLoop sends the queued skipped tx events to the subscribers.
+func (bc *BlockChain) skippedTxLoop() {
+	defer bc.wg.Done()
+
+	for {
+		select {
+		case ev := <-bc.skippedTxs:
+			bc.skippedTxFeed.Send(ev)
+		case <-bc.quit:
+			return
+		}
+	}
+}
+
 // addFutureBlock checks if the block is within the max allowed window to get
 // accepted for future processing, and returns an error if the block is too far
 // ahead and was not added.
@@ -383,6 +383,11 @@ func (bc *BlockChain) SubscribeLogsEvent(ch chan<- []*types.Log) event.Subscript
 	return bc.scope.Track(bc.logsFeed.Subscribe(ch))
 }
 
+// SubscribeSkippedTxEvent registers a subscription of SkippedTxEvent.
+func (bc *BlockChain) SubscribeSkippedTxEvent(ch chan<- SkippedTxEvent) event.Subscription {
+	return bc.scope.Track(bc.skippedTxFeed.Subscribe(ch))
+}
+
 // SubscribeBlockProcessingEvent registers a subscription of bool where true means
 // block processing has started while false means it has stopped.
 func (bc *BlockChain) SubscribeBlockProcessingEvent(ch chan<- bool) event.Subscription {
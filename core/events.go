@@ -18,6 +18,7 @@ package core
 
 import (
 	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/core/rawdb"
 	"github.com/scroll-tech/go-ethereum/core/types"
 )
 
@@ -44,3 +45,12 @@ type ChainHeadEvent struct{ Block *types.Block }
 
 // NewL1MsgsEvent is posted when we receive some new messages from L1.
 type NewL1MsgsEvent struct{ Count int }
+
+// SkippedTxEvent is posted when a transaction is skipped by the sequencer.
+type SkippedTxEvent struct {
+	Tx          *types.Transaction
+	Sender      common.Address
+	Reason      rawdb.SkipReason
+	BlockNumber uint64
+	BlockHash   *common.Hash // nil if the transaction was skipped while building the block
+}
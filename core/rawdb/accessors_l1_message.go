@@ -107,6 +107,14 @@ func WriteL1Messages(db ethdb.KeyValueWriter, l1Msgs []types.L1MessageTx) {
 	}
 }
 
+// DeleteL1Message removes an L1 message from the database.
+// Note: The caller is responsible for updating the highest synced queue index.
+func DeleteL1Message(db ethdb.KeyValueWriter, queueIndex uint64) {
+	if err := db.Delete(L1MessageKey(queueIndex)); err != nil {
+		log.Crit("Failed to delete L1 message", "queueIndex", queueIndex, "err", err)
+	}
+}
+
 // ReadL1MessageRLP retrieves an L1 message in its raw RLP database encoding.
 func ReadL1MessageRLP(db ethdb.Reader, queueIndex uint64) rlp.RawValue {
 	data, err := db.Get(L1MessageKey(queueIndex))
@@ -197,6 +205,11 @@ func (it *L1MessageIterator) L1Message() types.L1MessageTx {
 	return l1Msg
 }
 
+// L1MessageRLP returns the current L1 message in its raw RLP database encoding.
+func (it *L1MessageIterator) L1MessageRLP() rlp.RawValue {
+	return common.CopyBytes(it.inner.Value())
+}
+
 // Release releases the associated resources.
 func (it *L1MessageIterator) Release() {
 	it.inner.Release()
@@ -275,3 +288,96 @@ func ReadFirstQueueIndexNotInL2Block(db ethdb.Reader, l2BlockHash common.Hash) *
 	queueIndex := binary.BigEndian.Uint64(data)
 	return &queueIndex
 }
+
+// L1MessageSyncCheckpoint records the hash of a processed L1 block and the
+// number of L1 messages synced up to and including this block. It is used
+// to detect L1 reorgs and to roll back the L1 messages past the fork point.
+type L1MessageSyncCheckpoint struct {
+	L1BlockNumber uint64
+	L1BlockHash   common.Hash
+	NumL1Messages uint64
+}
+
+// WriteL1MessageSyncCheckpoint writes an L1 message sync checkpoint to the database.
+func WriteL1MessageSyncCheckpoint(db ethdb.KeyValueWriter, checkpoint *L1MessageSyncCheckpoint) {
+	bytes, err := rlp.EncodeToBytes(checkpoint)
+	if err != nil {
+		log.Crit("Failed to RLP encode L1 message sync checkpoint", "err", err)
+	}
+	if err := db.Put(l1MessageSyncCheckpointKey(checkpoint.L1BlockNumber), bytes); err != nil {
+		log.Crit("Failed to store L1 message sync checkpoint", "L1BlockNumber", checkpoint.L1BlockNumber, "err", err)
+	}
+}
+
+// DeleteL1MessageSyncCheckpoint removes the L1 message sync checkpoint of an L1 block from the database.
+func DeleteL1MessageSyncCheckpoint(db ethdb.KeyValueWriter, l1BlockNumber uint64) {
+	if err := db.Delete(l1MessageSyncCheckpointKey(l1BlockNumber)); err != nil {
+		log.Crit("Failed to delete L1 message sync checkpoint", "L1BlockNumber", l1BlockNumber, "err", err)
+	}
+}
+
+// ReadL1MessageSyncCheckpoints retrieves all L1 message sync checkpoints, ordered by L1 block number.
+func ReadL1MessageSyncCheckpoints(db ethdb.Iteratee) []*L1MessageSyncCheckpoint {
+	it := db.NewIterator(l1MessageSyncCheckpointPrefix, nil)
+	defer it.Release()
+
+	var checkpoints []*L1MessageSyncCheckpoint
+	for it.Next() {
+		if len(it.Key()) != len(l1MessageSyncCheckpointPrefix)+8 {
+			continue
+		}
+		checkpoint := new(L1MessageSyncCheckpoint)
+		if err := rlp.DecodeBytes(it.Value(), checkpoint); err != nil {
+			log.Crit("Invalid L1 message sync checkpoint RLP", "key", it.Key(), "err", err)
+		}
+		checkpoints = append(checkpoints, checkpoint)
+	}
+	if err := it.Error(); err != nil {
+		log.Crit("Failed to read L1 message sync checkpoints", "err", err)
+	}
+	return checkpoints
+}
+
+// VerifiedL1Block is the latest L1 block whose header has been verified to descend from a trusted checkpoint.
+type VerifiedL1Block struct {
+	Number uint64
+	Hash   common.Hash
+}
+
+// WriteLastVerifiedL1Block writes the latest verified L1 block to the database.
+func WriteLastVerifiedL1Block(db ethdb.KeyValueWriter, block *VerifiedL1Block) {
+	bytes, err := rlp.EncodeToBytes(block)
+	if err != nil {
+		log.Crit("Failed to RLP encode verified L1 block", "err", err)
+	}
+	if err := db.Put(lastVerifiedL1BlockKey, bytes); err != nil {
+		log.Crit("Failed to store verified L1 block", "number", block.Number, "err", err)
+	}
+}
+
+// ReadLastVerifiedL1Block retrieves the latest verified L1 block.
+func ReadLastVerifiedL1Block(db ethdb.Reader) *VerifiedL1Block {
+	data, err := db.Get(lastVerifiedL1BlockKey)
+	if err != nil && isNotFoundErr(err) {
+		return nil
+	}
+	if err != nil {
+		log.Crit("Failed to read verified L1 block from database", "err", err)
+	}
+	if len(data) == 0 {
+		return nil
+	}
+
+	block := new(VerifiedL1Block)
+	if err := rlp.DecodeBytes(data, block); err != nil {
+		log.Crit("Invalid verified L1 block RLP", "data", data, "err", err)
+	}
+	return block
+}
+
+// DeleteLastVerifiedL1Block removes the latest verified L1 block from the database.
+func DeleteLastVerifiedL1Block(db ethdb.KeyValueWriter) {
+	if err := db.Delete(lastVerifiedL1BlockKey); err != nil {
+		log.Crit("Failed to delete verified L1 block", "err", err)
+	}
+}
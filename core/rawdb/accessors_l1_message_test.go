@@ -157,3 +157,62 @@ func TestIterationStopsAtMaxQueueIndex(t *testing.T) {
 		t.Fatal("Invalid length", "expected", 3, "got", len(got))
 	}
 }
+
+func TestL1MessageSyncCheckpoints(t *testing.T) {
+	db := NewMemoryDatabase()
+
+	if checkpoints := ReadL1MessageSyncCheckpoints(db); len(checkpoints) != 0 {
+		t.Fatal("Expected no checkpoints", "got", len(checkpoints))
+	}
+
+	// write out of order, read in order of L1 block number
+	for _, number := range []uint64{300, 100, 200} {
+		WriteL1MessageSyncCheckpoint(db, &L1MessageSyncCheckpoint{
+			L1BlockNumber: number,
+			L1BlockHash:   common.BigToHash(new(big.Int).SetUint64(number)),
+			NumL1Messages: number / 10,
+		})
+	}
+	WriteL1Messages(db, []types.L1MessageTx{newL1MessageTx(0)})
+
+	checkpoints := ReadL1MessageSyncCheckpoints(db)
+	if len(checkpoints) != 3 {
+		t.Fatal("Checkpoint count mismatch", "expected", 3, "got", len(checkpoints))
+	}
+	for i, number := range []uint64{100, 200, 300} {
+		if checkpoints[i].L1BlockNumber != number || checkpoints[i].NumL1Messages != number/10 || checkpoints[i].L1BlockHash != common.BigToHash(new(big.Int).SetUint64(number)) {
+			t.Fatal("Checkpoint mismatch", "index", i, "got", checkpoints[i])
+		}
+	}
+
+	DeleteL1MessageSyncCheckpoint(db, 200)
+	if checkpoints := ReadL1MessageSyncCheckpoints(db); len(checkpoints) != 2 || checkpoints[1].L1BlockNumber != 300 {
+		t.Fatal("Checkpoint was not deleted")
+	}
+
+	DeleteL1Message(db, 0)
+	if msg := ReadL1Message(db, 0); msg != nil {
+		t.Fatal("L1 message was not deleted")
+	}
+}
+
+func TestLastVerifiedL1Block(t *testing.T) {
+	db := NewMemoryDatabase()
+
+	if block := ReadLastVerifiedL1Block(db); block != nil {
+		t.Fatal("Verified L1 block exists in new database", "block", block)
+	}
+
+	want := &VerifiedL1Block{Number: 100, Hash: common.Hash{1}}
+	WriteLastVerifiedL1Block(db, want)
+
+	got := ReadLastVerifiedL1Block(db)
+	if got == nil || *got != *want {
+		t.Fatal("Verified L1 block mismatch", "expected", want, "got", got)
+	}
+
+	DeleteLastVerifiedL1Block(db)
+	if block := ReadLastVerifiedL1Block(db); block != nil {
+		t.Fatal("Verified L1 block was not deleted", "block", block)
+	}
+}
@@ -0,0 +1,139 @@
+package rawdb
+
+import (
+	"bytes"
+	"math/big"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/ethdb"
+	"github.com/scroll-tech/go-ethereum/log"
+	"github.com/scroll-tech/go-ethereum/rlp"
+)
+
+// ProposedChunk is a chunk of consecutive L2 blocks proposed by the in-node proposer.
+type ProposedChunk struct {
+	Index                      uint64
+	StartBlockNumber           uint64
+	EndBlockNumber             uint64
+	TotalL1MessagePoppedBefore uint64
+	TotalL1MessagePopped       uint64 // total number of L1 messages popped before and in this chunk.
+	Hash                       common.Hash
+	CodecVersion               uint64
+}
+
+// ProposedBatch is a batch of consecutive chunks proposed by the in-node proposer.
+type ProposedBatch struct {
+	Index                      uint64
+	StartChunkIndex            uint64
+	EndChunkIndex              uint64
+	StartBlockNumber           uint64
+	EndBlockNumber             uint64
+	TotalL1MessagePoppedBefore uint64
+	TotalL1MessagePopped       uint64 // total number of L1 messages popped before and in this batch.
+	ParentBatchHash            common.Hash
+	Hash                       common.Hash
+	Header                     []byte // the encoded batch header.
+	CodecVersion               uint64
+}
+
+// WriteProposedChunk stores a proposed chunk in the database.
+func WriteProposedChunk(db ethdb.KeyValueWriter, chunk *ProposedChunk) {
+	value, err := rlp.EncodeToBytes(chunk)
+	if err != nil {
+		log.Crit("failed to RLP encode proposed chunk", "chunk index", chunk.Index, "err", err)
+	}
+	if err := db.Put(proposedChunkKey(chunk.Index), value); err != nil {
+		log.Crit("failed to store proposed chunk", "chunk index", chunk.Index, "value", value, "err", err)
+	}
+}
+
+// ReadProposedChunk retrieves a proposed chunk from the database.
+// It returns nil if no chunk was proposed with the given index.
+func ReadProposedChunk(db ethdb.Reader, chunkIndex uint64) *ProposedChunk {
+	data, err := db.Get(proposedChunkKey(chunkIndex))
+	if err != nil && isNotFoundErr(err) {
+		return nil
+	}
+	if err != nil {
+		log.Crit("failed to read proposed chunk from database", "chunk index", chunkIndex, "err", err)
+	}
+
+	chunk := new(ProposedChunk)
+	if err := rlp.Decode(bytes.NewReader(data), chunk); err != nil {
+		log.Crit("Invalid proposed chunk RLP", "chunk index", chunkIndex, "data", data, "err", err)
+	}
+	return chunk
+}
+
+// WriteProposedBatch stores a proposed batch in the database.
+func WriteProposedBatch(db ethdb.KeyValueWriter, batch *ProposedBatch) {
+	value, err := rlp.EncodeToBytes(batch)
+	if err != nil {
+		log.Crit("failed to RLP encode proposed batch", "batch index", batch.Index, "err", err)
+	}
+	if err := db.Put(proposedBatchKey(batch.Index), value); err != nil {
+		log.Crit("failed to store proposed batch", "batch index", batch.Index, "value", value, "err", err)
+	}
+}
+
+// ReadProposedBatch retrieves a proposed batch from the database.
+// It returns nil if no batch was proposed with the given index.
+func ReadProposedBatch(db ethdb.Reader, batchIndex uint64) *ProposedBatch {
+	data, err := db.Get(proposedBatchKey(batchIndex))
+	if err != nil && isNotFoundErr(err) {
+		return nil
+	}
+	if err != nil {
+		log.Crit("failed to read proposed batch from database", "batch index", batchIndex, "err", err)
+	}
+
+	batch := new(ProposedBatch)
+	if err := rlp.Decode(bytes.NewReader(data), batch); err != nil {
+		log.Crit("Invalid proposed batch RLP", "batch index", batchIndex, "data", data, "err", err)
+	}
+	return batch
+}
+
+// WriteLastProposedChunkIndex stores the index of the latest proposed chunk in the database.
+func WriteLastProposedChunkIndex(db ethdb.KeyValueWriter, chunkIndex uint64) {
+	value := big.NewInt(0).SetUint64(chunkIndex).Bytes()
+	if err := db.Put(lastProposedChunkIndexKey, value); err != nil {
+		log.Crit("failed to store last proposed chunk index", "chunk index", chunkIndex, "value", value, "err", err)
+	}
+}
+
+// ReadLastProposedChunkIndex fetches the index of the latest proposed chunk from the database.
+func ReadLastProposedChunkIndex(db ethdb.Reader) *uint64 {
+	return readProposerIndex(db, lastProposedChunkIndexKey)
+}
+
+// WriteLastProposedBatchIndex stores the index of the latest proposed batch in the database.
+func WriteLastProposedBatchIndex(db ethdb.KeyValueWriter, batchIndex uint64) {
+	value := big.NewInt(0).SetUint64(batchIndex).Bytes()
+	if err := db.Put(lastProposedBatchIndexKey, value); err != nil {
+		log.Crit("failed to store last proposed batch index", "batch index", batchIndex, "value", value, "err", err)
+	}
+}
+
+// ReadLastProposedBatchIndex fetches the index of the latest proposed batch from the database.
+func ReadLastProposedBatchIndex(db ethdb.Reader) *uint64 {
+	return readProposerIndex(db, lastProposedBatchIndexKey)
+}
+
+func readProposerIndex(db ethdb.Reader, key []byte) *uint64 {
+	data, err := db.Get(key)
+	if err != nil && isNotFoundErr(err) {
+		return nil
+	}
+	if err != nil {
+		log.Crit("failed to read proposer index from database", "key", key, "err", err)
+	}
+
+	number := new(big.Int).SetBytes(data)
+	if !number.IsUint64() {
+		log.Crit("unexpected proposer index in database", "key", key, "data", data, "number", number)
+	}
+
+	index := number.Uint64()
+	return &index
+}
@@ -0,0 +1,63 @@
+package rawdb
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/scroll-tech/go-ethereum/common"
+)
+
+func TestProposedChunksAndBatches(t *testing.T) {
+	db := NewMemoryDatabase()
+
+	if chunk := ReadProposedChunk(db, 0); chunk != nil {
+		t.Fatal("Expected nil for non-existing chunk", "got", chunk)
+	}
+	if batch := ReadProposedBatch(db, 0); batch != nil {
+		t.Fatal("Expected nil for non-existing batch", "got", batch)
+	}
+	if index := ReadLastProposedChunkIndex(db); index != nil {
+		t.Fatal("Expected nil for non-existing last chunk index", "got", *index)
+	}
+	if index := ReadLastProposedBatchIndex(db); index != nil {
+		t.Fatal("Expected nil for non-existing last batch index", "got", *index)
+	}
+
+	chunks := []*ProposedChunk{
+		{Index: 0, StartBlockNumber: 0, EndBlockNumber: 0, Hash: common.BytesToHash([]byte("chunk0"))},
+		{Index: 1, StartBlockNumber: 1, EndBlockNumber: 10, TotalL1MessagePoppedBefore: 0, TotalL1MessagePopped: 5, Hash: common.BytesToHash([]byte("chunk1")), CodecVersion: 1},
+	}
+	for _, chunk := range chunks {
+		WriteProposedChunk(db, chunk)
+		WriteLastProposedChunkIndex(db, chunk.Index)
+	}
+	for _, chunk := range chunks {
+		if got := ReadProposedChunk(db, chunk.Index); !reflect.DeepEqual(got, chunk) {
+			t.Fatal("Proposed chunk mismatch", "expected", chunk, "got", got)
+		}
+	}
+	if index := ReadLastProposedChunkIndex(db); index == nil || *index != 1 {
+		t.Fatal("Last proposed chunk index mismatch", "expected", 1, "got", index)
+	}
+
+	batch := &ProposedBatch{
+		Index:                1,
+		StartChunkIndex:      1,
+		EndChunkIndex:        1,
+		StartBlockNumber:     1,
+		EndBlockNumber:       10,
+		TotalL1MessagePopped: 5,
+		ParentBatchHash:      common.BytesToHash([]byte("batch0")),
+		Hash:                 common.BytesToHash([]byte("batch1")),
+		Header:               []byte{1, 2, 3},
+		CodecVersion:         1,
+	}
+	WriteProposedBatch(db, batch)
+	WriteLastProposedBatchIndex(db, batch.Index)
+	if got := ReadProposedBatch(db, batch.Index); !reflect.DeepEqual(got, batch) {
+		t.Fatal("Proposed batch mismatch", "expected", batch, "got", got)
+	}
+	if index := ReadLastProposedBatchIndex(db); index == nil || *index != 1 {
+		t.Fatal("Last proposed batch index mismatch", "expected", 1, "got", index)
+	}
+}
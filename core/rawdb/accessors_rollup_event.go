@@ -2,9 +2,11 @@ package rawdb
 
 import (
 	"bytes"
+	"encoding/binary"
 	"math/big"
 
 	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/crypto/kzg4844"
 	"github.com/scroll-tech/go-ethereum/ethdb"
 	"github.com/scroll-tech/go-ethereum/log"
 	"github.com/scroll-tech/go-ethereum/rlp"
@@ -24,6 +26,13 @@ type FinalizedBatchMeta struct {
 	WithdrawRoot         common.Hash
 }
 
+// CommittedBatchMeta holds metadata for batches committed to L1.
+type CommittedBatchMeta struct {
+	BatchHash     common.Hash
+	L1BlockNumber uint64      // the L1 block that includes the commit transaction.
+	L1TxHash      common.Hash // the hash of the commit transaction.
+}
+
 // WriteRollupEventSyncedL1BlockNumber stores the latest synced L1 block number related to rollup events in the database.
 func WriteRollupEventSyncedL1BlockNumber(db ethdb.KeyValueWriter, l1BlockNumber uint64) {
 	value := big.NewInt(0).SetUint64(l1BlockNumber).Bytes()
@@ -101,6 +110,14 @@ func WriteFinalizedBatchMeta(db ethdb.KeyValueWriter, batchIndex uint64, finaliz
 	}
 }
 
+// DeleteFinalizedBatchMeta removes the metadata of a finalized batch from the database.
+// Note: This is only used to roll back batches finalized in L1 blocks that were reorged out.
+func DeleteFinalizedBatchMeta(db ethdb.KeyValueWriter, batchIndex uint64) {
+	if err := db.Delete(batchMetaKey(batchIndex)); err != nil {
+		log.Crit("failed to delete finalized batch metadata", "batch index", batchIndex, "err", err)
+	}
+}
+
 // ReadFinalizedBatchMeta fetches the metadata of a finalized batch from the database.
 func ReadFinalizedBatchMeta(db ethdb.Reader, batchIndex uint64) *FinalizedBatchMeta {
 	data, err := db.Get(batchMetaKey(batchIndex))
@@ -144,3 +161,259 @@ func ReadFinalizedL2BlockNumber(db ethdb.Reader) *uint64 {
 	finalizedL2BlockNumber := number.Uint64()
 	return &finalizedL2BlockNumber
 }
+
+// WriteCommittedBatchMeta stores the metadata of a committed batch in the database.
+func WriteCommittedBatchMeta(db ethdb.KeyValueWriter, batchIndex uint64, committedBatchMeta *CommittedBatchMeta) {
+	value, err := rlp.EncodeToBytes(committedBatchMeta)
+	if err != nil {
+		log.Crit("failed to RLP encode committed batch metadata", "batch index", batchIndex, "committed batch meta", committedBatchMeta, "err", err)
+	}
+	if err := db.Put(committedBatchMetaKey(batchIndex), value); err != nil {
+		log.Crit("failed to store committed batch metadata", "batch index", batchIndex, "value", value, "err", err)
+	}
+}
+
+// DeleteCommittedBatchMeta removes the metadata of a reverted committed batch from the database.
+func DeleteCommittedBatchMeta(db ethdb.KeyValueWriter, batchIndex uint64) {
+	if err := db.Delete(committedBatchMetaKey(batchIndex)); err != nil {
+		log.Crit("failed to delete committed batch metadata", "batch index", batchIndex, "err", err)
+	}
+}
+
+// ReadCommittedBatchMeta fetches the metadata of a committed batch from the database.
+func ReadCommittedBatchMeta(db ethdb.Reader, batchIndex uint64) *CommittedBatchMeta {
+	data, err := db.Get(committedBatchMetaKey(batchIndex))
+	if err != nil && isNotFoundErr(err) {
+		return nil
+	}
+	if err != nil {
+		log.Crit("failed to read committed batch metadata from database", "batch index", batchIndex, "err", err)
+	}
+
+	cbm := new(CommittedBatchMeta)
+	if err := rlp.Decode(bytes.NewReader(data), cbm); err != nil {
+		log.Crit("Invalid CommittedBatchMeta RLP", "batch index", batchIndex, "data", data, "err", err)
+	}
+	return cbm
+}
+
+// WriteCommittedL2BlockNumber stores the highest L2 block number committed to L1 in the database.
+func WriteCommittedL2BlockNumber(db ethdb.KeyValueWriter, l2BlockNumber uint64) {
+	value := big.NewInt(0).SetUint64(l2BlockNumber).Bytes()
+	if err := db.Put(committedL2BlockNumberKey, value); err != nil {
+		log.Crit("failed to store committed L2 block number for rollup event", "L2 block number", l2BlockNumber, "value", value, "err", err)
+	}
+}
+
+// ReadCommittedL2BlockNumber fetches the highest L2 block number committed to L1 from the database.
+func ReadCommittedL2BlockNumber(db ethdb.Reader) *uint64 {
+	data, err := db.Get(committedL2BlockNumberKey)
+	if err != nil && isNotFoundErr(err) {
+		return nil
+	}
+	if err != nil {
+		log.Crit("failed to read committed L2 block number from database", "key", committedL2BlockNumberKey, "err", err)
+	}
+
+	number := new(big.Int).SetBytes(data)
+	if !number.IsUint64() {
+		log.Crit("unexpected committed L2 block number in database", "data", data, "number", number)
+	}
+
+	committedL2BlockNumber := number.Uint64()
+	return &committedL2BlockNumber
+}
+
+// WriteLastFinalizedBatchIndex stores the index of the latest finalized batch in the database.
+func WriteLastFinalizedBatchIndex(db ethdb.KeyValueWriter, batchIndex uint64) {
+	value := big.NewInt(0).SetUint64(batchIndex).Bytes()
+	if err := db.Put(lastFinalizedBatchIndexKey, value); err != nil {
+		log.Crit("failed to store last finalized batch index", "batch index", batchIndex, "value", value, "err", err)
+	}
+}
+
+// ReadLastFinalizedBatchIndex fetches the index of the latest finalized batch from the database.
+func ReadLastFinalizedBatchIndex(db ethdb.Reader) *uint64 {
+	data, err := db.Get(lastFinalizedBatchIndexKey)
+	if err != nil && isNotFoundErr(err) {
+		return nil
+	}
+	if err != nil {
+		log.Crit("failed to read last finalized batch index from database", "err", err)
+	}
+
+	number := new(big.Int).SetBytes(data)
+	if !number.IsUint64() {
+		log.Crit("unexpected last finalized batch index in database", "data", data, "number", number)
+	}
+
+	batchIndex := number.Uint64()
+	return &batchIndex
+}
+
+// WriteBatchEndBlockNumber indexes a committed batch by the number of its last L2 block.
+func WriteBatchEndBlockNumber(db ethdb.KeyValueWriter, l2BlockNumber uint64, batchIndex uint64) {
+	if err := db.Put(batchEndBlockNumberKey(l2BlockNumber), encodeBigEndian(batchIndex)); err != nil {
+		log.Crit("failed to store batch end block number", "L2 block number", l2BlockNumber, "batch index", batchIndex, "err", err)
+	}
+}
+
+// DeleteBatchEndBlockNumber removes the index entry of a reverted batch from the database.
+func DeleteBatchEndBlockNumber(db ethdb.KeyValueWriter, l2BlockNumber uint64) {
+	if err := db.Delete(batchEndBlockNumberKey(l2BlockNumber)); err != nil {
+		log.Crit("failed to delete batch end block number", "L2 block number", l2BlockNumber, "err", err)
+	}
+}
+
+// IndexBatchEndBlockNumbers indexes the stored batches by the number of their last L2 block, which fills
+// in the index for batches committed before it existed. It only runs once per database and returns the
+// number of batches indexed.
+func IndexBatchEndBlockNumbers(db ethdb.Database) int {
+	if has, err := db.Has(batchEndBlockNumberIndexedKey); err != nil {
+		log.Crit("failed to read batch end block number index status", "err", err)
+	} else if has {
+		return 0
+	}
+
+	it := db.NewIterator(batchChunkRangesPrefix, nil)
+	defer it.Release()
+
+	batch := db.NewBatch()
+	indexed := 0
+	for it.Next() {
+		if len(it.Key()) != len(batchChunkRangesPrefix)+8 {
+			continue
+		}
+		batchIndex := binary.BigEndian.Uint64(it.Key()[len(batchChunkRangesPrefix):])
+		var chunkBlockRanges []*ChunkBlockRange
+		if err := rlp.DecodeBytes(it.Value(), &chunkBlockRanges); err != nil {
+			log.Crit("Invalid ChunkBlockRange RLP", "batch index", batchIndex, "data", it.Value(), "err", err)
+		}
+		if len(chunkBlockRanges) == 0 {
+			continue
+		}
+		WriteBatchEndBlockNumber(batch, chunkBlockRanges[len(chunkBlockRanges)-1].EndBlockNumber, batchIndex)
+		indexed++
+		if batch.ValueSize() > ethdb.IdealBatchSize {
+			if err := batch.Write(); err != nil {
+				log.Crit("failed to store batch end block numbers", "err", err)
+			}
+			batch.Reset()
+		}
+	}
+	if err := it.Error(); err != nil {
+		log.Crit("failed to iterate batch chunk ranges", "err", err)
+	}
+	if err := batch.Put(batchEndBlockNumberIndexedKey, []byte{1}); err != nil {
+		log.Crit("failed to store batch end block number index status", "err", err)
+	}
+	if err := batch.Write(); err != nil {
+		log.Crit("failed to store batch end block numbers", "err", err)
+	}
+	return indexed
+}
+
+// ReadBatchIndexByBlockNumber returns the index of the first committed batch that ends at or after the given L2 block,
+// or nil if there is no such batch. Since batches are contiguous, this is the batch containing the block, if the block
+// is not before the first indexed batch.
+func ReadBatchIndexByBlockNumber(db ethdb.Iteratee, l2BlockNumber uint64) *uint64 {
+	it := db.NewIterator(batchEndBlockNumberPrefix, encodeBigEndian(l2BlockNumber))
+	defer it.Release()
+
+	for it.Next() {
+		if len(it.Key()) != len(batchEndBlockNumberPrefix)+8 || len(it.Value()) != 8 {
+			continue
+		}
+		batchIndex := binary.BigEndian.Uint64(it.Value())
+		return &batchIndex
+	}
+	if err := it.Error(); err != nil {
+		log.Crit("failed to read batch index by block number", "L2 block number", l2BlockNumber, "err", err)
+	}
+	return nil
+}
+
+// WriteBatchBlob stores the verified blob of a committed batch in the database.
+// The blob is kept until the batch is finalized or reverted.
+func WriteBatchBlob(db ethdb.KeyValueWriter, batchIndex uint64, blob *kzg4844.Blob) {
+	if err := db.Put(batchBlobKey(batchIndex), blob[:]); err != nil {
+		log.Crit("failed to store batch blob", "batch index", batchIndex, "err", err)
+	}
+}
+
+// ReadBatchBlob retrieves the verified blob of a committed batch from the database.
+// It returns nil if no blob is stored for the given batch index.
+func ReadBatchBlob(db ethdb.Reader, batchIndex uint64) *kzg4844.Blob {
+	data, err := db.Get(batchBlobKey(batchIndex))
+	if err != nil && isNotFoundErr(err) {
+		return nil
+	}
+	if err != nil {
+		log.Crit("failed to read batch blob from database", "batch index", batchIndex, "err", err)
+	}
+
+	var blob kzg4844.Blob
+	if len(data) != len(blob) {
+		log.Crit("unexpected batch blob length in database", "batch index", batchIndex, "length", len(data))
+	}
+	copy(blob[:], data)
+	return &blob
+}
+
+// DeleteBatchBlob removes the blob of a batch from the database.
+func DeleteBatchBlob(db ethdb.KeyValueWriter, batchIndex uint64) {
+	if err := db.Delete(batchBlobKey(batchIndex)); err != nil {
+		log.Crit("failed to delete batch blob", "batch index", batchIndex, "err", err)
+	}
+}
+
+// RollupEventSyncCheckpoint records the hash of a processed L1 block and the rollup
+// state after processing all rollup events up to and including this block.
+// It is used to detect L1 reorgs and to roll back the batches past the fork point.
+type RollupEventSyncCheckpoint struct {
+	L1BlockNumber           uint64
+	L1BlockHash             common.Hash
+	LastCommittedBatchIndex uint64
+	LastFinalizedBatchIndex uint64
+	FinalizedL2BlockNumber  uint64
+}
+
+// WriteRollupEventSyncCheckpoint stores a rollup event sync checkpoint in the database.
+func WriteRollupEventSyncCheckpoint(db ethdb.KeyValueWriter, checkpoint *RollupEventSyncCheckpoint) {
+	value, err := rlp.EncodeToBytes(checkpoint)
+	if err != nil {
+		log.Crit("failed to RLP encode rollup event sync checkpoint", "L1 block number", checkpoint.L1BlockNumber, "err", err)
+	}
+	if err := db.Put(rollupEventSyncCheckpointKey(checkpoint.L1BlockNumber), value); err != nil {
+		log.Crit("failed to store rollup event sync checkpoint", "L1 block number", checkpoint.L1BlockNumber, "err", err)
+	}
+}
+
+// DeleteRollupEventSyncCheckpoint removes the rollup event sync checkpoint of an L1 block from the database.
+func DeleteRollupEventSyncCheckpoint(db ethdb.KeyValueWriter, l1BlockNumber uint64) {
+	if err := db.Delete(rollupEventSyncCheckpointKey(l1BlockNumber)); err != nil {
+		log.Crit("failed to delete rollup event sync checkpoint", "L1 block number", l1BlockNumber, "err", err)
+	}
+}
+
+// ReadRollupEventSyncCheckpoints fetches all rollup event sync checkpoints, ordered by L1 block number.
+func ReadRollupEventSyncCheckpoints(db ethdb.Iteratee) []*RollupEventSyncCheckpoint {
+	it := db.NewIterator(rollupEventSyncCheckpointPrefix, nil)
+	defer it.Release()
+
+	var checkpoints []*RollupEventSyncCheckpoint
+	for it.Next() {
+		if len(it.Key()) != len(rollupEventSyncCheckpointPrefix)+8 {
+			continue
+		}
+		checkpoint := new(RollupEventSyncCheckpoint)
+		if err := rlp.DecodeBytes(it.Value(), checkpoint); err != nil {
+			log.Crit("Invalid RollupEventSyncCheckpoint RLP", "key", it.Key(), "err", err)
+		}
+		checkpoints = append(checkpoints, checkpoint)
+	}
+	if err := it.Error(); err != nil {
+		log.Crit("failed to read rollup event sync checkpoints", "err", err)
+	}
+	return checkpoints
+}
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/crypto/kzg4844"
 )
 
 func TestWriteRollupEventSyncedL1BlockNumber(t *testing.T) {
@@ -184,3 +185,178 @@ func TestBatchChunkRanges(t *testing.T) {
 	// delete non-existing value: ensure the delete operation handles non-existing values without errors.
 	DeleteBatchChunkRanges(db, uint64(len(chunks)+1))
 }
+
+func TestBatchBlob(t *testing.T) {
+	db := NewMemoryDatabase()
+
+	// read non-existing value
+	if got := ReadBatchBlob(db, 1); got != nil {
+		t.Fatal("Expected nil for non-existing value")
+	}
+
+	var blob kzg4844.Blob
+	copy(blob[1:], []byte("blob payload"))
+	WriteBatchBlob(db, 1, &blob)
+
+	got := ReadBatchBlob(db, 1)
+	if got == nil || *got != blob {
+		t.Fatal("Mismatch in read blob", "batch index", 1)
+	}
+
+	// delete: finalize or revert batch
+	DeleteBatchBlob(db, 1)
+	if got := ReadBatchBlob(db, 1); got != nil {
+		t.Fatal("Blob was not deleted", "batch index", 1)
+	}
+}
+
+func TestRollupEventSyncCheckpoints(t *testing.T) {
+	db := NewMemoryDatabase()
+
+	if checkpoints := ReadRollupEventSyncCheckpoints(db); len(checkpoints) != 0 {
+		t.Fatal("Expected no checkpoints", "got", len(checkpoints))
+	}
+
+	expected := []*RollupEventSyncCheckpoint{
+		{L1BlockNumber: 100, L1BlockHash: common.BytesToHash([]byte("block100")), LastCommittedBatchIndex: 1, LastFinalizedBatchIndex: 0, FinalizedL2BlockNumber: 0},
+		{L1BlockNumber: 200, L1BlockHash: common.BytesToHash([]byte("block200")), LastCommittedBatchIndex: 3, LastFinalizedBatchIndex: 1, FinalizedL2BlockNumber: 10},
+		{L1BlockNumber: 300, L1BlockHash: common.BytesToHash([]byte("block300")), LastCommittedBatchIndex: 4, LastFinalizedBatchIndex: 3, FinalizedL2BlockNumber: 30},
+	}
+	for _, checkpoint := range expected {
+		WriteRollupEventSyncCheckpoint(db, checkpoint)
+	}
+
+	checkpoints := ReadRollupEventSyncCheckpoints(db)
+	if len(checkpoints) != len(expected) {
+		t.Fatal("Checkpoint count mismatch", "expected", len(expected), "got", len(checkpoints))
+	}
+	for i := range expected {
+		if *checkpoints[i] != *expected[i] {
+			t.Fatal("Checkpoint mismatch", "expected", expected[i], "got", checkpoints[i])
+		}
+	}
+
+	DeleteRollupEventSyncCheckpoint(db, 300)
+	if checkpoints := ReadRollupEventSyncCheckpoints(db); len(checkpoints) != 2 {
+		t.Fatal("Checkpoint was not deleted")
+	}
+
+	WriteFinalizedBatchMeta(db, 1, &FinalizedBatchMeta{BatchHash: common.BytesToHash([]byte("batch1"))})
+	DeleteFinalizedBatchMeta(db, 1)
+	if meta := ReadFinalizedBatchMeta(db, 1); meta != nil {
+		t.Fatal("Finalized batch meta was not deleted")
+	}
+}
+
+func TestLastFinalizedBatchIndex(t *testing.T) {
+	db := NewMemoryDatabase()
+
+	if index := ReadLastFinalizedBatchIndex(db); index != nil {
+		t.Fatal("Expected nil for non-existing value", "got", *index)
+	}
+
+	for _, batchIndex := range []uint64{0, 1, 1 << 8, 1 << 32} {
+		WriteLastFinalizedBatchIndex(db, batchIndex)
+		if got := ReadLastFinalizedBatchIndex(db); got == nil || *got != batchIndex {
+			t.Fatal("Batch index mismatch", "expected", batchIndex, "got", got)
+		}
+	}
+}
+
+func TestBatchIndexByBlockNumber(t *testing.T) {
+	db := NewMemoryDatabase()
+
+	if index := ReadBatchIndexByBlockNumber(db, 0); index != nil {
+		t.Fatal("Expected nil for non-existing value", "got", *index)
+	}
+
+	// batch 0 ends at block 0, batch 1 at block 10, batch 2 at block 300
+	WriteBatchEndBlockNumber(db, 0, 0)
+	WriteBatchEndBlockNumber(db, 10, 1)
+	WriteBatchEndBlockNumber(db, 300, 2)
+
+	tests := []struct {
+		blockNumber uint64
+		batchIndex  uint64
+	}{
+		{0, 0},
+		{1, 1},
+		{10, 1},
+		{11, 2},
+		{256, 2},
+		{300, 2},
+	}
+	for _, test := range tests {
+		got := ReadBatchIndexByBlockNumber(db, test.blockNumber)
+		if got == nil || *got != test.batchIndex {
+			t.Fatal("Batch index mismatch", "block number", test.blockNumber, "expected", test.batchIndex, "got", got)
+		}
+	}
+
+	if index := ReadBatchIndexByBlockNumber(db, 301); index != nil {
+		t.Fatal("Expected nil for block after the last batch", "got", *index)
+	}
+
+	DeleteBatchEndBlockNumber(db, 300)
+	if index := ReadBatchIndexByBlockNumber(db, 11); index != nil {
+		t.Fatal("Expected nil after deleting the last batch", "got", *index)
+	}
+}
+
+func TestIndexBatchEndBlockNumbers(t *testing.T) {
+	db := NewMemoryDatabase()
+
+	// batches 1 and 2 were committed before the index existed, batch 3 is already indexed
+	WriteBatchChunkRanges(db, 1, []*ChunkBlockRange{{1, 5}, {6, 10}})
+	WriteBatchChunkRanges(db, 2, []*ChunkBlockRange{{11, 20}})
+	WriteBatchChunkRanges(db, 3, []*ChunkBlockRange{{21, 30}})
+	WriteBatchEndBlockNumber(db, 30, 3)
+
+	if indexed := IndexBatchEndBlockNumbers(db); indexed != 3 {
+		t.Fatal("Indexed batches mismatch", "expected", 3, "got", indexed)
+	}
+	for blockNumber, batchIndex := range map[uint64]uint64{1: 1, 10: 1, 11: 2, 20: 2, 25: 3} {
+		got := ReadBatchIndexByBlockNumber(db, blockNumber)
+		if got == nil || *got != batchIndex {
+			t.Fatal("Batch index mismatch", "block number", blockNumber, "expected", batchIndex, "got", got)
+		}
+	}
+
+	// the index is only backfilled once
+	WriteBatchChunkRanges(db, 4, []*ChunkBlockRange{{31, 40}})
+	if indexed := IndexBatchEndBlockNumbers(db); indexed != 0 {
+		t.Fatal("Expected no batches to be indexed again", "got", indexed)
+	}
+}
+
+func TestCommittedBatchMeta(t *testing.T) {
+	db := NewMemoryDatabase()
+
+	if meta := ReadCommittedBatchMeta(db, 1); meta != nil {
+		t.Fatal("Expected nil for non-existing value", "got", meta)
+	}
+	if number := ReadCommittedL2BlockNumber(db); number != nil {
+		t.Fatal("Expected nil for non-existing value", "got", *number)
+	}
+
+	expected := &CommittedBatchMeta{
+		BatchHash:     common.BytesToHash([]byte("batch1")),
+		L1BlockNumber: 100,
+		L1TxHash:      common.BytesToHash([]byte("tx1")),
+	}
+	WriteCommittedBatchMeta(db, 1, expected)
+	if got := ReadCommittedBatchMeta(db, 1); got == nil || *got != *expected {
+		t.Fatal("Committed batch meta mismatch", "expected", expected, "got", got)
+	}
+	DeleteCommittedBatchMeta(db, 1)
+	if meta := ReadCommittedBatchMeta(db, 1); meta != nil {
+		t.Fatal("Committed batch meta was not deleted")
+	}
+
+	for _, number := range []uint64{0, 1, 1 << 16, 1 << 32} {
+		WriteCommittedL2BlockNumber(db, number)
+		if got := ReadCommittedL2BlockNumber(db); got == nil || *got != number {
+			t.Fatal("Committed L2 block number mismatch", "expected", number, "got", got)
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/binary"
 	"encoding/json"
+	"fmt"
 	"math/big"
 	"sync"
 
@@ -46,6 +47,55 @@ func ReadNumSkippedTransactions(db ethdb.Reader) uint64 {
 	return number.Uint64()
 }
 
+// SkipReasonCode is the enumerated reason why a transaction was skipped.
+type SkipReasonCode uint64
+
+const (
+	SkipReasonUnknown                SkipReasonCode = iota // not recorded, e.g. for transactions skipped by older versions
+	SkipReasonRowConsumptionOverflow                       // the transaction alone exceeds the row limit of a sub-circuit
+	SkipReasonL1MessageSkipped                             // an L1 message skipped for another reason, e.g. its gas limit
+	SkipReasonTraceFailure                                 // tracing or executing the transaction failed
+	SkipReasonUnknownCCCError                              // the circuit capacity checker failed with an unknown error
+)
+
+var skipReasonCodeNames = []string{
+	SkipReasonUnknown:                "unknown",
+	SkipReasonRowConsumptionOverflow: "row_consumption_overflow",
+	SkipReasonL1MessageSkipped:       "l1_message_skipped",
+	SkipReasonTraceFailure:           "trace_failure",
+	SkipReasonUnknownCCCError:        "unknown_ccc_error",
+}
+
+func (c SkipReasonCode) String() string {
+	if c < SkipReasonCode(len(skipReasonCodeNames)) {
+		return skipReasonCodeNames[c]
+	}
+	return fmt.Sprintf("SkipReasonCode(%d)", uint64(c))
+}
+
+// MarshalText encodes the code as its name.
+func (c SkipReasonCode) MarshalText() ([]byte, error) {
+	return []byte(c.String()), nil
+}
+
+// UnmarshalText decodes the name of a code.
+func (c *SkipReasonCode) UnmarshalText(input []byte) error {
+	for code, name := range skipReasonCodeNames {
+		if name == string(input) {
+			*c = SkipReasonCode(code)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown skip reason code: %s", input)
+}
+
+// SkipReason describes why a transaction was skipped.
+type SkipReason struct {
+	Code       SkipReasonCode
+	SubCircuit string // the sub-circuit whose row limit is exceeded, only set for SkipReasonRowConsumptionOverflow
+	Message    string // free-form details
+}
+
 // SkippedTransaction stores the transaction object, along with the skip reason and block context.
 type SkippedTransaction struct {
 	// Tx is the skipped transaction.
@@ -82,16 +132,29 @@ type SkippedTransactionV2 struct {
 
 	// BlockHash is the hash of the block in which this transaction was skipped or nil.
 	BlockHash *common.Hash
+
+	// ReasonCode is the enumerated skip reason, SkipReasonUnknown for transactions skipped by older versions.
+	ReasonCode SkipReasonCode `rlp:"optional"`
+
+	// SubCircuit is the sub-circuit whose row limit the transaction exceeds, if any.
+	SubCircuit string `rlp:"optional"`
 }
 
 // writeSkippedTransaction writes a skipped transaction to the database.
-func writeSkippedTransaction(db ethdb.KeyValueWriter, tx *types.Transaction, traces *types.BlockTrace, reason string, blockNumber uint64, blockHash *common.Hash) {
+func writeSkippedTransaction(db ethdb.KeyValueWriter, tx *types.Transaction, traces *types.BlockTrace, reason SkipReason, blockNumber uint64, blockHash *common.Hash) {
 	var err error
 	// workaround: RLP decoding fails if this is nil
 	if blockHash == nil {
 		blockHash = &common.Hash{}
 	}
-	stx := SkippedTransactionV2{Tx: tx, Reason: reason, BlockNumber: blockNumber, BlockHash: blockHash}
+	stx := SkippedTransactionV2{
+		Tx:          tx,
+		Reason:      reason.Message,
+		BlockNumber: blockNumber,
+		BlockHash:   blockHash,
+		ReasonCode:  reason.Code,
+		SubCircuit:  reason.SubCircuit,
+	}
 	if traces != nil {
 		if stx.TracesBytes, err = json.Marshal(traces); err != nil {
 			log.Crit("Failed to json marshal skipped transaction", "hash", tx.Hash().String(), "err", err)
@@ -180,7 +243,7 @@ func ReadSkippedTransactionHash(db ethdb.Reader, index uint64) *common.Hash {
 
 // WriteSkippedTransaction writes a skipped transaction to the database and also updates the count and lookup index.
 // Note: The lookup index and count will include duplicates if there are chain reorgs.
-func WriteSkippedTransaction(db ethdb.Database, tx *types.Transaction, traces *types.BlockTrace, reason string, blockNumber uint64, blockHash *common.Hash) {
+func WriteSkippedTransaction(db ethdb.Database, tx *types.Transaction, traces *types.BlockTrace, reason SkipReason, blockNumber uint64, blockHash *common.Hash) {
 	// this method is not accessed concurrently, but just to be sure...
 	mu.Lock()
 	defer mu.Unlock()
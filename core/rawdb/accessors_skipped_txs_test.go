@@ -7,6 +7,7 @@ import (
 
 	"github.com/scroll-tech/go-ethereum/common"
 	"github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/rlp"
 )
 
 func TestReadWriteNumSkippedTransactions(t *testing.T) {
@@ -44,11 +45,55 @@ func newTestTransaction(queueIndex uint64) *types.Transaction {
 func TestReadWriteSkippedTransactionNoIndex(t *testing.T) {
 	tx := newTestTransaction(123)
 	db := NewMemoryDatabase()
-	writeSkippedTransaction(db, tx, nil, "random reason", 1, &common.Hash{1})
+	writeSkippedTransaction(db, tx, nil, SkipReason{Code: SkipReasonRowConsumptionOverflow, SubCircuit: "evm", Message: "random reason"}, 1, &common.Hash{1})
 	got := ReadSkippedTransaction(db, tx.Hash())
 	if got == nil || got.Tx.Hash() != tx.Hash() || got.Reason != "random reason" || got.BlockNumber != 1 || got.BlockHash == nil || *got.BlockHash != (common.Hash{1}) {
 		t.Fatal("Skipped transaction mismatch", "got", got)
 	}
+	if got.ReasonCode != SkipReasonRowConsumptionOverflow || got.SubCircuit != "evm" {
+		t.Fatal("Skip reason mismatch", "code", got.ReasonCode, "subCircuit", got.SubCircuit)
+	}
+}
+
+func TestReadSkippedTransactionWithoutReasonCode(t *testing.T) {
+	tx := newTestTransaction(123)
+	db := NewMemoryDatabase()
+	// V2 layout before the reason code was added
+	stx := struct {
+		Tx          *types.Transaction
+		TracesBytes []byte
+		Reason      string
+		BlockNumber uint64
+		BlockHash   *common.Hash
+	}{Tx: tx, Reason: "random reason", BlockNumber: 1, BlockHash: &common.Hash{1}}
+	data, err := rlp.EncodeToBytes(stx)
+	if err != nil {
+		t.Fatal("Failed to encode skipped transaction", "err", err)
+	}
+	if err := db.Put(SkippedTransactionKey(tx.Hash()), data); err != nil {
+		t.Fatal("Failed to store skipped transaction", "err", err)
+	}
+	got := ReadSkippedTransaction(db, tx.Hash())
+	if got == nil || got.Reason != "random reason" || got.ReasonCode != SkipReasonUnknown || got.SubCircuit != "" {
+		t.Fatal("Skipped transaction mismatch", "got", got)
+	}
+}
+
+func TestSkipReasonCodeText(t *testing.T) {
+	for code := SkipReasonUnknown; code <= SkipReasonUnknownCCCError; code++ {
+		text, err := code.MarshalText()
+		if err != nil {
+			t.Fatal("Failed to marshal skip reason code", "code", code, "err", err)
+		}
+		var decoded SkipReasonCode
+		if err := decoded.UnmarshalText(text); err != nil || decoded != code {
+			t.Fatal("Skip reason code mismatch", "expected", code, "got", decoded, "err", err)
+		}
+	}
+	var code SkipReasonCode
+	if err := code.UnmarshalText([]byte("random reason")); err == nil {
+		t.Fatal("Expected error for unknown skip reason code")
+	}
 }
 
 func TestReadSkippedTransactionV1AsV2(t *testing.T) {
@@ -64,7 +109,7 @@ func TestReadSkippedTransactionV1AsV2(t *testing.T) {
 func TestReadWriteSkippedTransaction(t *testing.T) {
 	tx := newTestTransaction(123)
 	db := NewMemoryDatabase()
-	WriteSkippedTransaction(db, tx, nil, "random reason", 1, &common.Hash{1})
+	WriteSkippedTransaction(db, tx, nil, SkipReason{Code: SkipReasonL1MessageSkipped, Message: "random reason"}, 1, &common.Hash{1})
 	got := ReadSkippedTransaction(db, tx.Hash())
 	if got == nil || got.Tx.Hash() != tx.Hash() || got.Reason != "random reason" || got.BlockNumber != 1 || got.BlockHash == nil || *got.BlockHash != (common.Hash{1}) {
 		t.Fatal("Skipped transaction mismatch", "got", got)
@@ -88,7 +133,7 @@ func TestSkippedTransactionConcurrentUpdate(t *testing.T) {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			WriteSkippedTransaction(db, tx, nil, "random reason", 1, &common.Hash{1})
+			WriteSkippedTransaction(db, tx, nil, SkipReason{Code: SkipReasonL1MessageSkipped, Message: "random reason"}, 1, &common.Hash{1})
 		}()
 	}
 	wg.Wait()
@@ -110,12 +155,12 @@ func TestIterateSkippedTransactions(t *testing.T) {
 	}
 
 	for _, tx := range txs {
-		WriteSkippedTransaction(db, tx, nil, "random reason", 1, &common.Hash{1})
+		WriteSkippedTransaction(db, tx, nil, SkipReason{Code: SkipReasonL1MessageSkipped, Message: "random reason"}, 1, &common.Hash{1})
 	}
 
 	// simulate skipped L2 tx that's not included in the index
 	l2tx := newTestTransaction(6)
-	writeSkippedTransaction(db, l2tx, nil, "random reason", 1, &common.Hash{1})
+	writeSkippedTransaction(db, l2tx, nil, SkipReason{Code: SkipReasonL1MessageSkipped, Message: "random reason"}, 1, &common.Hash{1})
 
 	it := IterateSkippedTransactionsFrom(db, 2)
 	defer it.Release()
@@ -0,0 +1,151 @@
+package rawdb
+
+import (
+	"bytes"
+	"math/big"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/ethdb"
+	"github.com/scroll-tech/go-ethereum/log"
+	"github.com/scroll-tech/go-ethereum/rlp"
+)
+
+// WithdrawMessage is a message appended to the withdraw trie of the L2MessageQueue contract.
+type WithdrawMessage struct {
+	MessageHash common.Hash
+	BlockNumber uint64 // the L2 block that appended the message
+	BlockHash   common.Hash
+}
+
+// WithdrawTrieProgress is the indexing progress of the withdraw trie.
+type WithdrawTrieProgress struct {
+	BlockNumber uint64      // the last indexed L2 block
+	BlockHash   common.Hash // the hash of the last indexed L2 block, used to detect reorgs
+	NumMessages uint64      // the number of messages appended up to and including the last indexed block
+}
+
+// WriteWithdrawMessage stores a withdraw message by its index in the withdraw trie.
+func WriteWithdrawMessage(db ethdb.KeyValueWriter, index uint64, msg *WithdrawMessage) {
+	value, err := rlp.EncodeToBytes(msg)
+	if err != nil {
+		log.Crit("failed to RLP encode withdraw message", "index", index, "err", err)
+	}
+	if err := db.Put(withdrawMessageKey(index), value); err != nil {
+		log.Crit("failed to store withdraw message", "index", index, "value", value, "err", err)
+	}
+}
+
+// ReadWithdrawMessage retrieves a withdraw message by its index in the withdraw trie.
+func ReadWithdrawMessage(db ethdb.Reader, index uint64) *WithdrawMessage {
+	data, err := db.Get(withdrawMessageKey(index))
+	if err != nil && isNotFoundErr(err) {
+		return nil
+	}
+	if err != nil {
+		log.Crit("failed to read withdraw message from database", "index", index, "err", err)
+	}
+
+	msg := new(WithdrawMessage)
+	if err := rlp.Decode(bytes.NewReader(data), msg); err != nil {
+		log.Crit("Invalid withdraw message RLP", "index", index, "data", data, "err", err)
+	}
+	return msg
+}
+
+// DeleteWithdrawMessage removes a withdraw message from the database.
+func DeleteWithdrawMessage(db ethdb.KeyValueWriter, index uint64) {
+	if err := db.Delete(withdrawMessageKey(index)); err != nil {
+		log.Crit("failed to delete withdraw message", "index", index, "err", err)
+	}
+}
+
+// WriteWithdrawMessageIndex stores the index of a withdraw message by its hash.
+func WriteWithdrawMessageIndex(db ethdb.KeyValueWriter, messageHash common.Hash, index uint64) {
+	value := big.NewInt(0).SetUint64(index).Bytes()
+	if err := db.Put(withdrawMessageIndexKey(messageHash), value); err != nil {
+		log.Crit("failed to store withdraw message index", "message hash", messageHash.Hex(), "index", index, "err", err)
+	}
+}
+
+// ReadWithdrawMessageIndex retrieves the index of a withdraw message by its hash.
+func ReadWithdrawMessageIndex(db ethdb.Reader, messageHash common.Hash) *uint64 {
+	data, err := db.Get(withdrawMessageIndexKey(messageHash))
+	if err != nil && isNotFoundErr(err) {
+		return nil
+	}
+	if err != nil {
+		log.Crit("failed to read withdraw message index from database", "message hash", messageHash.Hex(), "err", err)
+	}
+
+	number := new(big.Int).SetBytes(data)
+	if !number.IsUint64() {
+		log.Crit("unexpected withdraw message index in database", "message hash", messageHash.Hex(), "data", data, "number", number)
+	}
+
+	index := number.Uint64()
+	return &index
+}
+
+// DeleteWithdrawMessageIndex removes the index of a withdraw message from the database.
+func DeleteWithdrawMessageIndex(db ethdb.KeyValueWriter, messageHash common.Hash) {
+	if err := db.Delete(withdrawMessageIndexKey(messageHash)); err != nil {
+		log.Crit("failed to delete withdraw message index", "message hash", messageHash.Hex(), "err", err)
+	}
+}
+
+// WriteWithdrawTrieNode stores the hash of a complete subtree of the withdraw trie.
+func WriteWithdrawTrieNode(db ethdb.KeyValueWriter, height uint8, position uint64, hash common.Hash) {
+	if err := db.Put(withdrawTrieNodeKey(height, position), hash.Bytes()); err != nil {
+		log.Crit("failed to store withdraw trie node", "height", height, "position", position, "err", err)
+	}
+}
+
+// ReadWithdrawTrieNode retrieves the hash of a complete subtree of the withdraw trie.
+func ReadWithdrawTrieNode(db ethdb.Reader, height uint8, position uint64) *common.Hash {
+	data, err := db.Get(withdrawTrieNodeKey(height, position))
+	if err != nil && isNotFoundErr(err) {
+		return nil
+	}
+	if err != nil {
+		log.Crit("failed to read withdraw trie node from database", "height", height, "position", position, "err", err)
+	}
+
+	hash := common.BytesToHash(data)
+	return &hash
+}
+
+// DeleteWithdrawTrieNode removes a withdraw trie node from the database.
+func DeleteWithdrawTrieNode(db ethdb.KeyValueWriter, height uint8, position uint64) {
+	if err := db.Delete(withdrawTrieNodeKey(height, position)); err != nil {
+		log.Crit("failed to delete withdraw trie node", "height", height, "position", position, "err", err)
+	}
+}
+
+// WriteWithdrawTrieProgress stores the indexing progress of the withdraw trie.
+func WriteWithdrawTrieProgress(db ethdb.KeyValueWriter, progress *WithdrawTrieProgress) {
+	value, err := rlp.EncodeToBytes(progress)
+	if err != nil {
+		log.Crit("failed to RLP encode withdraw trie progress", "err", err)
+	}
+	if err := db.Put(withdrawTrieProgressKey, value); err != nil {
+		log.Crit("failed to store withdraw trie progress", "value", value, "err", err)
+	}
+}
+
+// ReadWithdrawTrieProgress retrieves the indexing progress of the withdraw trie.
+// It returns nil if the withdraw trie was never indexed.
+func ReadWithdrawTrieProgress(db ethdb.Reader) *WithdrawTrieProgress {
+	data, err := db.Get(withdrawTrieProgressKey)
+	if err != nil && isNotFoundErr(err) {
+		return nil
+	}
+	if err != nil {
+		log.Crit("failed to read withdraw trie progress from database", "err", err)
+	}
+
+	progress := new(WithdrawTrieProgress)
+	if err := rlp.Decode(bytes.NewReader(data), progress); err != nil {
+		log.Crit("Invalid withdraw trie progress RLP", "data", data, "err", err)
+	}
+	return progress
+}
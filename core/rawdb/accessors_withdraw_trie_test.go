@@ -0,0 +1,58 @@
+package rawdb
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/scroll-tech/go-ethereum/common"
+)
+
+func TestWithdrawTrie(t *testing.T) {
+	db := NewMemoryDatabase()
+
+	if msg := ReadWithdrawMessage(db, 0); msg != nil {
+		t.Fatal("Expected nil for non-existing withdraw message", "got", msg)
+	}
+	if progress := ReadWithdrawTrieProgress(db); progress != nil {
+		t.Fatal("Expected nil for non-existing withdraw trie progress", "got", progress)
+	}
+
+	msg := &WithdrawMessage{MessageHash: common.BytesToHash([]byte("msg")), BlockNumber: 10, BlockHash: common.BytesToHash([]byte("block"))}
+	WriteWithdrawMessage(db, 3, msg)
+	WriteWithdrawMessageIndex(db, msg.MessageHash, 3)
+	if got := ReadWithdrawMessage(db, 3); !reflect.DeepEqual(got, msg) {
+		t.Fatal("Withdraw message mismatch", "expected", msg, "got", got)
+	}
+	if index := ReadWithdrawMessageIndex(db, msg.MessageHash); index == nil || *index != 3 {
+		t.Fatal("Withdraw message index mismatch", "expected", 3, "got", index)
+	}
+
+	// nodes at different heights with the same position do not collide
+	WriteWithdrawTrieNode(db, 1, 5, common.BytesToHash([]byte("node1")))
+	WriteWithdrawTrieNode(db, 2, 5, common.BytesToHash([]byte("node2")))
+	if hash := ReadWithdrawTrieNode(db, 1, 5); hash == nil || *hash != common.BytesToHash([]byte("node1")) {
+		t.Fatal("Withdraw trie node mismatch", "got", hash)
+	}
+	if hash := ReadWithdrawTrieNode(db, 2, 5); hash == nil || *hash != common.BytesToHash([]byte("node2")) {
+		t.Fatal("Withdraw trie node mismatch", "got", hash)
+	}
+
+	progress := &WithdrawTrieProgress{BlockNumber: 10, BlockHash: msg.BlockHash, NumMessages: 4}
+	WriteWithdrawTrieProgress(db, progress)
+	if got := ReadWithdrawTrieProgress(db); !reflect.DeepEqual(got, progress) {
+		t.Fatal("Withdraw trie progress mismatch", "expected", progress, "got", got)
+	}
+
+	DeleteWithdrawMessage(db, 3)
+	DeleteWithdrawMessageIndex(db, msg.MessageHash)
+	DeleteWithdrawTrieNode(db, 1, 5)
+	if msg := ReadWithdrawMessage(db, 3); msg != nil {
+		t.Fatal("Expected nil for deleted withdraw message", "got", msg)
+	}
+	if index := ReadWithdrawMessageIndex(db, msg.MessageHash); index != nil {
+		t.Fatal("Expected nil for deleted withdraw message index", "got", *index)
+	}
+	if hash := ReadWithdrawTrieNode(db, 1, 5); hash != nil {
+		t.Fatal("Expected nil for deleted withdraw trie node", "got", hash)
+	}
+}
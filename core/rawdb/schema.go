@@ -109,12 +109,33 @@ var (
 	l1MessagePrefix                   = []byte("L1") // l1MessagePrefix + queueIndex (uint64 big endian) -> L1MessageTx
 	firstQueueIndexNotInL2BlockPrefix = []byte("q")  // firstQueueIndexNotInL2BlockPrefix + L2 block hash -> enqueue index
 	highestSyncedQueueIndexKey        = []byte("HighestSyncedQueueIndex")
+	l1MessageSyncCheckpointPrefix     = []byte("S-cp") // l1MessageSyncCheckpointPrefix + L1 block number (uint64 big endian) -> L1MessageSyncCheckpoint
+	lastVerifiedL1BlockKey            = []byte("LastVerifiedL1Block")
 
 	// Scroll rollup event store
 	rollupEventSyncedL1BlockNumberKey = []byte("R-LastRollupEventSyncedL1BlockNumber")
 	batchChunkRangesPrefix            = []byte("R-bcr")
 	batchMetaPrefix                   = []byte("R-bm")
 	finalizedL2BlockNumberKey         = []byte("R-finalized")
+	batchBlobPrefix                   = []byte("R-blob")
+	rollupEventSyncCheckpointPrefix   = []byte("R-cp")
+	lastFinalizedBatchIndexKey        = []byte("R-LastFinalizedBatchIndex")
+	committedBatchMetaPrefix          = []byte("R-cbm") // committedBatchMetaPrefix + batch index (uint64 big endian) -> CommittedBatchMeta
+	committedL2BlockNumberKey         = []byte("R-committed")
+	batchEndBlockNumberPrefix         = []byte("R-beb") // batchEndBlockNumberPrefix + L2 block number (uint64 big endian) -> batch index
+	batchEndBlockNumberIndexedKey     = []byte("R-BatchEndBlockNumberIndexed")
+
+	// Scroll in-node proposer store
+	proposedChunkPrefix       = []byte("R-pc") // proposedChunkPrefix + chunk index (uint64 big endian) -> ProposedChunk
+	proposedBatchPrefix       = []byte("R-pb") // proposedBatchPrefix + batch index (uint64 big endian) -> ProposedBatch
+	lastProposedChunkIndexKey = []byte("R-LastProposedChunkIndex")
+	lastProposedBatchIndexKey = []byte("R-LastProposedBatchIndex")
+
+	// Scroll withdraw trie store
+	withdrawMessagePrefix      = []byte("W-m") // withdrawMessagePrefix + message index (uint64 big endian) -> WithdrawMessage
+	withdrawMessageIndexPrefix = []byte("W-i") // withdrawMessageIndexPrefix + message hash -> message index
+	withdrawTrieNodePrefix     = []byte("W-n") // withdrawTrieNodePrefix + height (uint8) + position (uint64 big endian) -> node hash
+	withdrawTrieProgressKey    = []byte("W-Progress")
 
 	// Row consumption
 	rowConsumptionPrefix = []byte("rc") // rowConsumptionPrefix + hash -> row consumption by block
@@ -275,6 +296,11 @@ func L1MessageKey(queueIndex uint64) []byte {
 	return append(l1MessagePrefix, encodeBigEndian(queueIndex)...)
 }
 
+// l1MessageSyncCheckpointKey = l1MessageSyncCheckpointPrefix + L1 block number (uint64 big endian)
+func l1MessageSyncCheckpointKey(l1BlockNumber uint64) []byte {
+	return append(l1MessageSyncCheckpointPrefix, encodeBigEndian(l1BlockNumber)...)
+}
+
 // FirstQueueIndexNotInL2BlockKey = firstQueueIndexNotInL2BlockPrefix + L2 block hash
 func FirstQueueIndexNotInL2BlockKey(l2BlockHash common.Hash) []byte {
 	return append(firstQueueIndexNotInL2BlockPrefix, l2BlockHash.Bytes()...)
@@ -308,3 +334,51 @@ func batchChunkRangesKey(batchIndex uint64) []byte {
 func batchMetaKey(batchIndex uint64) []byte {
 	return append(batchMetaPrefix, encodeBigEndian(batchIndex)...)
 }
+
+// batchBlobKey = batchBlobPrefix + batch index (uint64 big endian)
+func batchBlobKey(batchIndex uint64) []byte {
+	return append(batchBlobPrefix, encodeBigEndian(batchIndex)...)
+}
+
+// rollupEventSyncCheckpointKey = rollupEventSyncCheckpointPrefix + L1 block number (uint64 big endian)
+func rollupEventSyncCheckpointKey(l1BlockNumber uint64) []byte {
+	return append(rollupEventSyncCheckpointPrefix, encodeBigEndian(l1BlockNumber)...)
+}
+
+// committedBatchMetaKey = committedBatchMetaPrefix + batch index (uint64 big endian)
+func committedBatchMetaKey(batchIndex uint64) []byte {
+	return append(committedBatchMetaPrefix, encodeBigEndian(batchIndex)...)
+}
+
+// batchEndBlockNumberKey = batchEndBlockNumberPrefix + L2 block number (uint64 big endian)
+func batchEndBlockNumberKey(l2BlockNumber uint64) []byte {
+	return append(batchEndBlockNumberPrefix, encodeBigEndian(l2BlockNumber)...)
+}
+
+// proposedChunkKey = proposedChunkPrefix + chunk index (uint64 big endian)
+func proposedChunkKey(chunkIndex uint64) []byte {
+	return append(proposedChunkPrefix, encodeBigEndian(chunkIndex)...)
+}
+
+// proposedBatchKey = proposedBatchPrefix + batch index (uint64 big endian)
+func proposedBatchKey(batchIndex uint64) []byte {
+	return append(proposedBatchPrefix, encodeBigEndian(batchIndex)...)
+}
+
+// withdrawMessageKey = withdrawMessagePrefix + message index (uint64 big endian)
+func withdrawMessageKey(index uint64) []byte {
+	return append(withdrawMessagePrefix, encodeBigEndian(index)...)
+}
+
+// withdrawMessageIndexKey = withdrawMessageIndexPrefix + message hash
+func withdrawMessageIndexKey(messageHash common.Hash) []byte {
+	return append(withdrawMessageIndexPrefix, messageHash.Bytes()...)
+}
+
+// withdrawTrieNodeKey = withdrawTrieNodePrefix + height (uint8) + position (uint64 big endian)
+func withdrawTrieNodeKey(height uint8, position uint64) []byte {
+	key := make([]byte, 0, len(withdrawTrieNodePrefix)+1+8)
+	key = append(key, withdrawTrieNodePrefix...)
+	key = append(key, height)
+	return append(key, encodeBigEndian(position)...)
+}
@@ -35,6 +35,14 @@ func (t ZktrieProofTracer) Available() bool {
 	return t.ProofTracer != nil
 }
 
+// Copy returns a copy of the tracer, an unavailable tracer is returned as is
+func (t ZktrieProofTracer) Copy() ZktrieProofTracer {
+	if !t.Available() {
+		return t
+	}
+	return ZktrieProofTracer{t.ProofTracer.Copy()}
+}
+
 // NewProofTracer is not in Db interface and used explictily for reading proof in storage trie (not updated by the dirty value)
 func (s *StateDB) NewProofTracer(trieS Trie) ZktrieProofTracer {
 	if s.IsZktrie() {
@@ -20,6 +20,7 @@ import (
 	"github.com/scroll-tech/go-ethereum/core/state"
 	"github.com/scroll-tech/go-ethereum/core/types"
 	"github.com/scroll-tech/go-ethereum/core/vm"
+	"github.com/scroll-tech/go-ethereum/rollup/circuitcapacitychecker"
 )
 
 // Validator is an interface which defines the standard for block validation. It
@@ -34,8 +35,9 @@ type Validator interface {
 	ValidateState(block *types.Block, state *state.StateDB, receipts types.Receipts, usedGas uint64) error
 
 	// SetupTracerAndCircuitCapacityChecker sets up ScrollTracerWrapper and CircuitCapacityChecker for validator,
-	// to get scroll-related traces and to validate the circuit row consumption
-	SetupTracerAndCircuitCapacityChecker(tracer tracerWrapper)
+	// to get scroll-related traces and to validate the circuit row consumption. The checker runs out of process
+	// if a remote endpoint is configured.
+	SetupTracerAndCircuitCapacityChecker(tracer tracerWrapper, remote circuitcapacitychecker.RemoteConfig)
 }
 
 // Prefetcher is an interface for pre-caching transaction signatures and state.
@@ -0,0 +1,78 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package types
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/common/hexutil"
+	"github.com/scroll-tech/go-ethereum/crypto"
+	"github.com/scroll-tech/go-ethereum/rlp"
+)
+
+// preconfirmationDomain separates the signatures of preconfirmations from other messages signed by the sequencer.
+const preconfirmationDomain = "scroll-preconfirmation"
+
+// Preconfirmation is a soft promise of the sequencer that a transaction is included in the block it is
+// building, at the given index. The sequencer may still reorder the block before sealing it.
+type Preconfirmation struct {
+	ChainID     *hexutil.Big   `json:"chainId"`
+	TxHash      common.Hash    `json:"transactionHash"`
+	BlockNumber hexutil.Uint64 `json:"blockNumber"`
+	ParentHash  common.Hash    `json:"parentHash"`
+	Index       hexutil.Uint64 `json:"transactionIndex"`
+	Status      hexutil.Uint64 `json:"status"`
+	// PostStateHint commits to the parent block, the header fields of the block that transactions can read
+	// (number, timestamp, gas limit, coinbase, difficulty and base fee) and all transactions of the block up to
+	// and including this one, so equal hints imply equal post-states.
+	PostStateHint common.Hash   `json:"postStateHint"`
+	Signature     hexutil.Bytes `json:"signature"`
+}
+
+// SigningData returns the message the sequencer signs, the RLP encoding of the domain and the fields of the
+// preconfirmation without its signature.
+func (p *Preconfirmation) SigningData() []byte {
+	data, _ := rlp.EncodeToBytes([]interface{}{
+		preconfirmationDomain,
+		(*big.Int)(p.ChainID),
+		p.TxHash,
+		uint64(p.BlockNumber),
+		p.ParentHash,
+		uint64(p.Index),
+		uint64(p.Status),
+		p.PostStateHint,
+	})
+	return data
+}
+
+// SigningHash returns the hash the sequencer signs, the EIP-191 hash of the signing data as computed by
+// accounts.TextHash, so that keystore and external signers produce the same signature.
+func (p *Preconfirmation) SigningHash() common.Hash {
+	data := p.SigningData()
+	return crypto.Keccak256Hash([]byte(fmt.Sprintf("\x19Ethereum Signed Message:\n%d", len(data))), data)
+}
+
+// Signer recovers the address of the sequencer that signed the preconfirmation.
+func (p *Preconfirmation) Signer() (common.Address, error) {
+	pubkey, err := crypto.SigToPub(p.SigningHash().Bytes(), p.Signature)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return crypto.PubkeyToAddress(*pubkey), nil
+}
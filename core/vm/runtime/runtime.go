@@ -73,6 +73,7 @@ func setDefaults(cfg *Config) {
 			BernoulliBlock:      new(big.Int),
 			CurieBlock:          new(big.Int),
 			DescartesBlock:      new(big.Int),
+			DarwinBlock:         new(big.Int),
 		}
 	}
 
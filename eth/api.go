@@ -27,17 +27,27 @@ import (
 	"os"
 	"runtime"
 	"strings"
+	"sync"
 	"time"
 
+	"golang.org/x/time/rate"
+
 	"github.com/scroll-tech/go-ethereum/common"
 	"github.com/scroll-tech/go-ethereum/common/hexutil"
 	"github.com/scroll-tech/go-ethereum/core"
 	"github.com/scroll-tech/go-ethereum/core/rawdb"
 	"github.com/scroll-tech/go-ethereum/core/state"
 	"github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/eth/tracers"
 	"github.com/scroll-tech/go-ethereum/internal/ethapi"
 	"github.com/scroll-tech/go-ethereum/log"
+	"github.com/scroll-tech/go-ethereum/miner"
+	"github.com/scroll-tech/go-ethereum/params"
 	"github.com/scroll-tech/go-ethereum/rlp"
+	"github.com/scroll-tech/go-ethereum/rollup/ccc_verifier"
+	"github.com/scroll-tech/go-ethereum/rollup/circuitcapacitychecker"
+	"github.com/scroll-tech/go-ethereum/rollup/tracing"
+	"github.com/scroll-tech/go-ethereum/rollup/withdrawtrie"
 	"github.com/scroll-tech/go-ethereum/rpc"
 	"github.com/scroll-tech/go-ethereum/trie"
 )
@@ -63,6 +73,42 @@ func (api *PublicEthereumAPI) Coinbase() (common.Address, error) {
 	return api.Etherbase()
 }
 
+// SkippedTransactionsFilter selects the skipped transactions sent to a subscriber.
+type SkippedTransactionsFilter struct {
+	From *common.Address `json:"from"` // sender of the transactions, nil for all
+}
+
+// SkippedTransactions sends a notification each time a transaction is skipped, with the reason it was skipped.
+// The sequencer notifies all skipped transactions, follower nodes only notify the skipped L1 messages.
+func (api *PublicEthereumAPI) SkippedTransactions(ctx context.Context, filter *SkippedTransactionsFilter) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+
+	rpcSub := notifier.CreateSubscription()
+	go func() {
+		skipped := make(chan core.SkippedTxEvent, 128)
+		sub := api.e.BlockChain().SubscribeSkippedTxEvent(skipped)
+		defer sub.Unsubscribe()
+
+		for {
+			select {
+			case ev := <-skipped:
+				if filter != nil && filter.From != nil && *filter.From != ev.Sender {
+					continue
+				}
+				notifier.Notify(rpcSub.ID, newRPCSkippedTransaction(ev.Tx, ev.Reason, ev.BlockNumber, ev.BlockHash, api.e.BlockChain().Config()))
+			case <-rpcSub.Err():
+				return
+			case <-notifier.Closed():
+				return
+			}
+		}
+	}()
+	return rpcSub, nil
+}
+
 // Hashrate returns the POW hashrate
 func (api *PublicEthereumAPI) Hashrate() hexutil.Uint64 {
 	return hexutil.Uint64(api.e.Miner().Hashrate())
@@ -84,6 +130,34 @@ func (api *PublicMinerAPI) Mining() bool {
 	return api.e.IsMining()
 }
 
+// Preconfirmations creates a subscription that is triggered each time the sequencer preconfirms
+// a transaction committed into the block it is building.
+func (api *PublicMinerAPI) Preconfirmations(ctx context.Context) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+
+	rpcSub := notifier.CreateSubscription()
+	go func() {
+		preconfs := make(chan *types.Preconfirmation, 128)
+		sub := api.e.Miner().SubscribePreconfirmations(preconfs)
+		defer sub.Unsubscribe()
+
+		for {
+			select {
+			case preconf := <-preconfs:
+				notifier.Notify(rpcSub.ID, preconf)
+			case <-rpcSub.Err():
+				return
+			case <-notifier.Closed():
+				return
+			}
+		}
+	}()
+	return rpcSub, nil
+}
+
 // PrivateMinerAPI provides private RPC methods to control the miner.
 // These methods can be abused by external users and must be considered insecure for use by untrusted users.
 type PrivateMinerAPI struct {
@@ -148,6 +222,49 @@ func (api *PrivateMinerAPI) SetRecommitInterval(interval int) {
 	api.e.Miner().SetRecommitInterval(time.Duration(interval) * time.Millisecond)
 }
 
+// PublicBundleAPI provides an API to submit bundles of transactions to the miner. It is only registered
+// if the miner accepts bundles, as they are executed ahead of the tx pool.
+type PublicBundleAPI struct {
+	e *Ethereum
+}
+
+// NewPublicBundleAPI creates a new PublicBundleAPI instance.
+func NewPublicBundleAPI(e *Ethereum) *PublicBundleAPI {
+	return &PublicBundleAPI{e}
+}
+
+// SendBundleArgs represents the arguments of eth_sendBundle.
+type SendBundleArgs struct {
+	Txs               []hexutil.Bytes `json:"txs"`                         // signed transactions, in inclusion order
+	MinBlockNumber    hexutil.Uint64  `json:"minBlockNumber,omitempty"`    // first block the bundle can be included in
+	MaxBlockNumber    hexutil.Uint64  `json:"maxBlockNumber,omitempty"`    // last block the bundle can be included in, at most 100 blocks ahead
+	RevertingTxHashes []common.Hash   `json:"revertingTxHashes,omitempty"` // transactions allowed to revert
+}
+
+// SendBundle submits a bundle of transactions that are included atomically and in order,
+// and returns the bundle hash.
+func (api *PublicBundleAPI) SendBundle(ctx context.Context, args SendBundleArgs) (common.Hash, error) {
+	txs := make(types.Transactions, len(args.Txs))
+	for i, encoded := range args.Txs {
+		tx := new(types.Transaction)
+		if err := tx.UnmarshalBinary(encoded); err != nil {
+			return common.Hash{}, fmt.Errorf("failed to decode bundle tx %d, err: %w", i, err)
+		}
+		txs[i] = tx
+	}
+	return api.e.Miner().SendBundle(&miner.Bundle{
+		Txs:               txs,
+		MinBlock:          uint64(args.MinBlockNumber),
+		MaxBlock:          uint64(args.MaxBlockNumber),
+		RevertingTxHashes: args.RevertingTxHashes,
+	})
+}
+
+// GetBundleStatus returns the inclusion status of a bundle, nil if it is unknown.
+func (api *PublicBundleAPI) GetBundleStatus(ctx context.Context, hash common.Hash) (*miner.BundleStatus, error) {
+	return api.e.Miner().BundleStatus(hash), nil
+}
+
 // PrivateAdminAPI is the collection of Ethereum full node-related APIs
 // exposed over the private admin endpoint.
 type PrivateAdminAPI struct {
@@ -611,7 +728,8 @@ func (api *PrivateDebugAPI) GetAccessibleState(from, to rpc.BlockNumber) (uint64
 
 // ScrollAPI provides private RPC methods to query the L1 message database.
 type ScrollAPI struct {
-	eth *Ethereum
+	eth          *Ethereum
+	rowEstimator *rowEstimator
 }
 
 // l1MessageTxRPC is the RPC-layer representation of an L1 message.
@@ -627,7 +745,7 @@ type l1MessageTxRPC struct {
 
 // NewScrollAPI creates a new RPC service to query the L1 message database.
 func NewScrollAPI(eth *Ethereum) *ScrollAPI {
-	return &ScrollAPI{eth: eth}
+	return &ScrollAPI{eth: eth, rowEstimator: newRowEstimator()}
 }
 
 // GetL1SyncHeight returns the latest synced L1 block height from the local database.
@@ -716,16 +834,17 @@ func (api *ScrollAPI) GetNumSkippedTransactions(ctx context.Context) (uint64, er
 }
 
 // SyncStatus includes L2 block sync height, L1 rollup sync height,
-// L1 message sync height, and L2 finalized block height.
+// L1 message sync height, L2 committed block height and L2 finalized block height.
 type SyncStatus struct {
 	L2BlockSyncHeight      uint64 `json:"l2BlockSyncHeight,omitempty"`
 	L1RollupSyncHeight     uint64 `json:"l1RollupSyncHeight,omitempty"`
 	L1MessageSyncHeight    uint64 `json:"l1MessageSyncHeight,omitempty"`
+	L2CommittedBlockHeight uint64 `json:"l2CommittedBlockHeight,omitempty"`
 	L2FinalizedBlockHeight uint64 `json:"l2FinalizedBlockHeight,omitempty"`
 }
 
 // SyncStatus returns the overall rollup status including L2 block sync height, L1 rollup sync height,
-// L1 message sync height, and L2 finalized block height.
+// L1 message sync height, L2 committed block height and L2 finalized block height.
 func (api *ScrollAPI) SyncStatus(_ context.Context) *SyncStatus {
 	status := &SyncStatus{}
 
@@ -744,6 +863,11 @@ func (api *ScrollAPI) SyncStatus(_ context.Context) *SyncStatus {
 		status.L1MessageSyncHeight = *l1MessageSyncHeightPtr
 	}
 
+	l2CommittedBlockHeightPtr := rawdb.ReadCommittedL2BlockNumber(api.eth.ChainDb())
+	if l2CommittedBlockHeightPtr != nil {
+		status.L2CommittedBlockHeight = *l2CommittedBlockHeightPtr
+	}
+
 	l2FinalizedBlockHeightPtr := rawdb.ReadFinalizedL2BlockNumber(api.eth.ChainDb())
 	if l2FinalizedBlockHeightPtr != nil {
 		status.L2FinalizedBlockHeight = *l2FinalizedBlockHeightPtr
@@ -752,6 +876,259 @@ func (api *ScrollAPI) SyncStatus(_ context.Context) *SyncStatus {
 	return status
 }
 
+// RPCBatch is the RPC-layer representation of a batch committed to L1. The state root,
+// withdraw root and L1 message counts are only available once the batch is finalized.
+type RPCBatch struct {
+	Index                uint64       `json:"index"`
+	StartBlockNumber     uint64       `json:"startBlockNumber"`
+	EndBlockNumber       uint64       `json:"endBlockNumber"`
+	Finalized            bool         `json:"finalized"`
+	Hash                 *common.Hash `json:"hash,omitempty"`
+	CommitTxHash         *common.Hash `json:"commitTxHash,omitempty"`
+	CommitL1BlockNumber  *uint64      `json:"commitL1BlockNumber,omitempty"`
+	StateRoot            *common.Hash `json:"stateRoot,omitempty"`
+	WithdrawRoot         *common.Hash `json:"withdrawRoot,omitempty"`
+	L1MessagePopped      *uint64      `json:"l1MessagePopped,omitempty"`      // number of L1 messages popped in this batch
+	TotalL1MessagePopped *uint64      `json:"totalL1MessagePopped,omitempty"` // number of L1 messages popped before and in this batch
+}
+
+// RPCChunkRange is the RPC-layer representation of the block range of a chunk.
+type RPCChunkRange struct {
+	StartBlockNumber uint64 `json:"startBlockNumber"`
+	EndBlockNumber   uint64 `json:"endBlockNumber"`
+}
+
+// GetBatchByIndex returns a batch committed to L1 by its index.
+func (api *ScrollAPI) GetBatchByIndex(ctx context.Context, batchIndex uint64) (*RPCBatch, error) {
+	return api.batchByIndex(batchIndex), nil
+}
+
+// GetBatchForBlock returns the committed batch that contains the given L2 block. Only batches
+// whose commit events this node has synced are known, it returns nil for blocks of older batches.
+func (api *ScrollAPI) GetBatchForBlock(ctx context.Context, number uint64) (*RPCBatch, error) {
+	batchIndex := rawdb.ReadBatchIndexByBlockNumber(api.eth.ChainDb(), number)
+	if batchIndex == nil {
+		return nil, nil
+	}
+	batch := api.batchByIndex(*batchIndex)
+	if batch == nil || batch.StartBlockNumber > number {
+		return nil, nil
+	}
+	return batch, nil
+}
+
+// GetChunkRanges returns the block ranges of the chunks of a batch committed to L1.
+func (api *ScrollAPI) GetChunkRanges(ctx context.Context, batchIndex uint64) ([]*RPCChunkRange, error) {
+	chunkBlockRanges := rawdb.ReadBatchChunkRanges(api.eth.ChainDb(), batchIndex)
+	if len(chunkBlockRanges) == 0 {
+		return nil, nil
+	}
+	ranges := make([]*RPCChunkRange, len(chunkBlockRanges))
+	for i, cr := range chunkBlockRanges {
+		ranges[i] = &RPCChunkRange{StartBlockNumber: cr.StartBlockNumber, EndBlockNumber: cr.EndBlockNumber}
+	}
+	return ranges, nil
+}
+
+// GetLatestFinalizedBatch returns the latest batch finalized on L1.
+func (api *ScrollAPI) GetLatestFinalizedBatch(ctx context.Context) (*RPCBatch, error) {
+	batchIndex := rawdb.ReadLastFinalizedBatchIndex(api.eth.ChainDb())
+	if batchIndex == nil {
+		return nil, nil
+	}
+	return api.batchByIndex(*batchIndex), nil
+}
+
+// batchByIndex assembles the RPC representation of a committed batch from the local database.
+func (api *ScrollAPI) batchByIndex(batchIndex uint64) *RPCBatch {
+	db := api.eth.ChainDb()
+	chunkBlockRanges := rawdb.ReadBatchChunkRanges(db, batchIndex)
+	if len(chunkBlockRanges) == 0 {
+		return nil
+	}
+	batch := &RPCBatch{
+		Index:            batchIndex,
+		StartBlockNumber: chunkBlockRanges[0].StartBlockNumber,
+		EndBlockNumber:   chunkBlockRanges[len(chunkBlockRanges)-1].EndBlockNumber,
+	}
+
+	if committedMeta := rawdb.ReadCommittedBatchMeta(db, batchIndex); committedMeta != nil {
+		batch.Hash = &committedMeta.BatchHash
+		batch.CommitTxHash = &committedMeta.L1TxHash
+		batch.CommitL1BlockNumber = &committedMeta.L1BlockNumber
+	}
+
+	meta := rawdb.ReadFinalizedBatchMeta(db, batchIndex)
+	if meta == nil {
+		return batch
+	}
+	batch.Finalized = true
+	batch.Hash = &meta.BatchHash
+	batch.StateRoot = &meta.StateRoot
+	batch.WithdrawRoot = &meta.WithdrawRoot
+	batch.TotalL1MessagePopped = &meta.TotalL1MessagePopped
+
+	// the number of L1 messages in this batch is derived from the parent batch
+	var totalL1MessagePoppedBefore uint64
+	if batchIndex > 0 {
+		parentMeta := rawdb.ReadFinalizedBatchMeta(db, batchIndex-1)
+		if parentMeta == nil {
+			return batch
+		}
+		totalL1MessagePoppedBefore = parentMeta.TotalL1MessagePopped
+	}
+	l1MessagePopped := meta.TotalL1MessagePopped - totalL1MessagePoppedBefore
+	batch.L1MessagePopped = &l1MessagePopped
+	return batch
+}
+
+// RPCWithdrawalProof is the RPC-layer representation of the Merkle proof of a withdrawal message
+// against the withdraw root of a batch finalized on L1.
+type RPCWithdrawalProof struct {
+	MessageHash  common.Hash   `json:"messageHash"`
+	Index        uint64        `json:"index"`
+	BlockNumber  uint64        `json:"blockNumber"`
+	BatchIndex   uint64        `json:"batchIndex"`
+	WithdrawRoot common.Hash   `json:"withdrawRoot"`
+	Proof        hexutil.Bytes `json:"proof"` // the concatenated sibling hashes, as expected by `relayMessageWithProof`
+}
+
+// GetWithdrawalProof returns the Merkle proof of a withdrawal message against the withdraw root of
+// the latest finalized batch. It requires the withdraw trie indexer.
+func (api *ScrollAPI) GetWithdrawalProof(ctx context.Context, messageHash common.Hash) (*RPCWithdrawalProof, error) {
+	db := api.eth.ChainDb()
+	progress := rawdb.ReadWithdrawTrieProgress(db)
+	if progress == nil {
+		return nil, errors.New("withdraw trie is not indexed")
+	}
+	index := rawdb.ReadWithdrawMessageIndex(db, messageHash)
+	if index == nil || *index >= progress.NumMessages {
+		return nil, nil
+	}
+	msg := rawdb.ReadWithdrawMessage(db, *index)
+	if msg == nil || msg.MessageHash != messageHash {
+		return nil, nil
+	}
+
+	batchIndex := rawdb.ReadLastFinalizedBatchIndex(db)
+	finalizedBlockNumber := rawdb.ReadFinalizedL2BlockNumber(db)
+	if batchIndex == nil || finalizedBlockNumber == nil {
+		return nil, errors.New("no finalized batch")
+	}
+	meta := rawdb.ReadFinalizedBatchMeta(db, *batchIndex)
+	if meta == nil {
+		return nil, fmt.Errorf("missing finalized batch meta, batch index: %d", *batchIndex)
+	}
+	if msg.BlockNumber > *finalizedBlockNumber {
+		return nil, fmt.Errorf("withdrawal message is not finalized yet, message block: %d, finalized block: %d", msg.BlockNumber, *finalizedBlockNumber)
+	}
+	if progress.BlockNumber < *finalizedBlockNumber {
+		return nil, fmt.Errorf("withdraw trie is not indexed up to the finalized block, indexed block: %d, finalized block: %d", progress.BlockNumber, *finalizedBlockNumber)
+	}
+
+	numMessages := withdrawtrie.NumMessagesAt(db, *finalizedBlockNumber, progress.NumMessages)
+	root, err := withdrawtrie.Root(db, numMessages)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute withdraw trie root, err: %w", err)
+	}
+	if root != meta.WithdrawRoot {
+		return nil, fmt.Errorf("withdraw trie root mismatch, batch index: %d, local: %s, finalized: %s", *batchIndex, root.Hex(), meta.WithdrawRoot.Hex())
+	}
+	proof, err := withdrawtrie.Proof(db, *index, numMessages)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute withdrawal proof, err: %w", err)
+	}
+	if !withdrawtrie.VerifyProof(root, messageHash, *index, proof) {
+		return nil, fmt.Errorf("invalid withdrawal proof, message hash: %s", messageHash.Hex())
+	}
+
+	result := &RPCWithdrawalProof{
+		MessageHash:  messageHash,
+		Index:        *index,
+		BlockNumber:  msg.BlockNumber,
+		BatchIndex:   *batchIndex,
+		WithdrawRoot: root,
+	}
+	for _, item := range proof {
+		result.Proof = append(result.Proof, item.Bytes()...)
+	}
+	return result, nil
+}
+
+// proposedChunkRPC is the RPC-layer representation of a chunk proposed by the in-node proposer.
+type proposedChunkRPC struct {
+	Index                      uint64      `json:"index"`
+	StartBlockNumber           uint64      `json:"startBlockNumber"`
+	EndBlockNumber             uint64      `json:"endBlockNumber"`
+	TotalL1MessagePoppedBefore uint64      `json:"totalL1MessagePoppedBefore"`
+	TotalL1MessagePopped       uint64      `json:"totalL1MessagePopped"`
+	Hash                       common.Hash `json:"hash"`
+	CodecVersion               uint64      `json:"codecVersion"`
+}
+
+// proposedBatchRPC is the RPC-layer representation of a batch proposed by the in-node proposer.
+type proposedBatchRPC struct {
+	Index                      uint64        `json:"index"`
+	StartChunkIndex            uint64        `json:"startChunkIndex"`
+	EndChunkIndex              uint64        `json:"endChunkIndex"`
+	StartBlockNumber           uint64        `json:"startBlockNumber"`
+	EndBlockNumber             uint64        `json:"endBlockNumber"`
+	TotalL1MessagePoppedBefore uint64        `json:"totalL1MessagePoppedBefore"`
+	TotalL1MessagePopped       uint64        `json:"totalL1MessagePopped"`
+	ParentBatchHash            common.Hash   `json:"parentBatchHash"`
+	Hash                       common.Hash   `json:"hash"`
+	Header                     hexutil.Bytes `json:"header"`
+	CodecVersion               uint64        `json:"codecVersion"`
+}
+
+// GetLatestProposedChunkIndex returns the index of the latest chunk proposed by the in-node proposer.
+func (api *ScrollAPI) GetLatestProposedChunkIndex(ctx context.Context) (*uint64, error) {
+	return rawdb.ReadLastProposedChunkIndex(api.eth.ChainDb()), nil
+}
+
+// GetLatestProposedBatchIndex returns the index of the latest batch proposed by the in-node proposer.
+func (api *ScrollAPI) GetLatestProposedBatchIndex(ctx context.Context) (*uint64, error) {
+	return rawdb.ReadLastProposedBatchIndex(api.eth.ChainDb()), nil
+}
+
+// GetProposedChunkByIndex queries a chunk proposed by the in-node proposer by its index.
+func (api *ScrollAPI) GetProposedChunkByIndex(ctx context.Context, index uint64) (*proposedChunkRPC, error) {
+	chunk := rawdb.ReadProposedChunk(api.eth.ChainDb(), index)
+	if chunk == nil {
+		return nil, nil
+	}
+	return &proposedChunkRPC{
+		Index:                      chunk.Index,
+		StartBlockNumber:           chunk.StartBlockNumber,
+		EndBlockNumber:             chunk.EndBlockNumber,
+		TotalL1MessagePoppedBefore: chunk.TotalL1MessagePoppedBefore,
+		TotalL1MessagePopped:       chunk.TotalL1MessagePopped,
+		Hash:                       chunk.Hash,
+		CodecVersion:               chunk.CodecVersion,
+	}, nil
+}
+
+// GetProposedBatchByIndex queries a batch proposed by the in-node proposer by its index.
+func (api *ScrollAPI) GetProposedBatchByIndex(ctx context.Context, index uint64) (*proposedBatchRPC, error) {
+	batch := rawdb.ReadProposedBatch(api.eth.ChainDb(), index)
+	if batch == nil {
+		return nil, nil
+	}
+	return &proposedBatchRPC{
+		Index:                      batch.Index,
+		StartChunkIndex:            batch.StartChunkIndex,
+		EndChunkIndex:              batch.EndChunkIndex,
+		StartBlockNumber:           batch.StartBlockNumber,
+		EndBlockNumber:             batch.EndBlockNumber,
+		TotalL1MessagePoppedBefore: batch.TotalL1MessagePoppedBefore,
+		TotalL1MessagePopped:       batch.TotalL1MessagePopped,
+		ParentBatchHash:            batch.ParentBatchHash,
+		Hash:                       batch.Hash,
+		Header:                     batch.Header,
+		CodecVersion:               batch.CodecVersion,
+	}, nil
+}
+
 // EstimateL1DataFee returns an estimate of the L1 data fee required to
 // process the given transaction against the current pending block.
 func (api *ScrollAPI) EstimateL1DataFee(ctx context.Context, args ethapi.TransactionArgs, blockNrOrHash *rpc.BlockNumberOrHash) (*hexutil.Uint64, error) {
@@ -769,12 +1146,179 @@ func (api *ScrollAPI) EstimateL1DataFee(ctx context.Context, args ethapi.Transac
 	return &result, nil
 }
 
+// RPCSubCircuitRowUsage is the row usage of a sub-circuit and the rows left under the block limit.
+type RPCSubCircuitRowUsage struct {
+	Name      string `json:"name"`
+	RowNumber uint64 `json:"rowNumber"`
+	Headroom  uint64 `json:"headroom"`
+}
+
+// RPCRowConsumptionEstimate is the result of scroll_estimateRowConsumption.
+type RPCRowConsumptionEstimate struct {
+	// Overflow is set if the transaction alone exceeds the row limit of a sub-circuit,
+	// in which case the sequencer skips it and the sub-circuits are not known.
+	Overflow             bool                    `json:"overflow"`
+	MaxRowsPerSubCircuit uint64                  `json:"maxRowsPerSubCircuit"`
+	SubCircuits          []RPCSubCircuitRowUsage `json:"subCircuits"`
+}
+
+// newRPCRowConsumptionEstimate computes the headroom of each sub-circuit in rc under maxRows.
+func newRPCRowConsumptionEstimate(rc types.RowConsumption, maxRows uint64) *RPCRowConsumptionEstimate {
+	result := &RPCRowConsumptionEstimate{
+		MaxRowsPerSubCircuit: maxRows,
+		SubCircuits:          make([]RPCSubCircuitRowUsage, 0, len(rc)),
+	}
+	for _, usage := range rc {
+		var headroom uint64
+		if usage.RowNumber < maxRows {
+			headroom = maxRows - usage.RowNumber
+		} else {
+			result.Overflow = true
+		}
+		result.SubCircuits = append(result.SubCircuits, RPCSubCircuitRowUsage{Name: usage.Name, RowNumber: usage.RowNumber, Headroom: headroom})
+	}
+	return result
+}
+
+// EstimateRowConsumption returns the circuit row consumption of the given signed transaction, as if it were
+// the only transaction in the given block (latest by default). The transaction is traced and run through the
+// circuit capacity checker, so that users can tell in advance whether it would be skipped for exceeding the
+// circuit capacity.
+func (api *ScrollAPI) EstimateRowConsumption(ctx context.Context, input hexutil.Bytes, blockNrOrHash *rpc.BlockNumberOrHash) (*RPCRowConsumptionEstimate, error) {
+	if !api.rowEstimator.limiter.Allow() {
+		return nil, errRowEstimationRateLimited
+	}
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(input); err != nil {
+		return nil, err
+	}
+	if tx.IsL1MessageTx() {
+		return nil, errors.New("l1 messages are not supported")
+	}
+	bNrOrHash := rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
+	if blockNrOrHash != nil {
+		bNrOrHash = *blockNrOrHash
+	}
+	header, err := api.eth.APIBackend.HeaderByNumberOrHash(ctx, bNrOrHash)
+	if err != nil {
+		return nil, err
+	}
+	if header == nil {
+		return nil, errors.New("header not found")
+	}
+	if _, err := types.Sender(types.MakeSigner(api.eth.blockchain.Config(), header.Number), tx); err != nil {
+		return nil, fmt.Errorf("invalid sender, err: %w", err)
+	}
+
+	tracer := tracers.NewAPI(api.eth.APIBackend, tracing.NewTracerWrapper())
+	traces, err := tracer.GetTxBlockTraceOnTopOfBlock(ctx, tx, rpc.BlockNumberOrHashWithHash(header.Hash(), false), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to trace transaction, err: %w", err)
+	}
+
+	maxRows := api.eth.config.Miner.CircuitCapacityChecker.MaxRows
+	if maxRows == 0 {
+		maxRows = circuitcapacitychecker.DefaultMaxRows
+	}
+	rc, err := api.rowEstimator.apply(api.eth.config.Miner.CircuitCapacityChecker, traces)
+	if errors.Is(err, circuitcapacitychecker.ErrBlockRowConsumptionOverflow) {
+		return &RPCRowConsumptionEstimate{Overflow: true, MaxRowsPerSubCircuit: maxRows}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply transaction to circuit capacity checker, err: %w", err)
+	}
+	return newRPCRowConsumptionEstimate(*rc, maxRows), nil
+}
+
+const (
+	rowEstimationRate  = 10 // Row consumption estimations allowed per second
+	rowEstimationBurst = 10 // Row consumption estimations allowed at once
+)
+
+var errRowEstimationRateLimited = errors.New("too many row consumption estimations, try again later")
+
+// rowEstimator runs the transactions of scroll_estimateRowConsumption through a single circuit capacity
+// checker, created on first use, and limits how often it is called.
+type rowEstimator struct {
+	mu      sync.Mutex
+	checker circuitcapacitychecker.Checker
+	limiter *rate.Limiter
+}
+
+func newRowEstimator() *rowEstimator {
+	return &rowEstimator{limiter: rate.NewLimiter(rowEstimationRate, rowEstimationBurst)}
+}
+
+// apply resets the checker and applies the traces of a single transaction.
+func (e *rowEstimator) apply(config circuitcapacitychecker.RemoteConfig, traces *types.BlockTrace) (*types.RowConsumption, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.checker == nil {
+		e.checker = circuitcapacitychecker.New(config, true)
+	}
+	e.checker.Reset()
+	return e.checker.ApplyTransaction(traces)
+}
+
+// maxRowConsumptionStatsRange is the maximum number of blocks aggregated by scroll_getRowConsumptionStats.
+const maxRowConsumptionStatsRange = 100_000
+
+// GetPreconfirmation returns the latest preconfirmation of a transaction, nil if the sequencer has not
+// preconfirmed it recently.
+func (api *ScrollAPI) GetPreconfirmation(ctx context.Context, txHash common.Hash) (*types.Preconfirmation, error) {
+	return api.eth.Miner().Preconfirmation(txHash), nil
+}
+
+// GetRowConsumptionStats aggregates the row consumption of each sub-circuit over the blocks from fromBlock to
+// toBlock, including which sub-circuit has the most rows in how many blocks.
+func (api *ScrollAPI) GetRowConsumptionStats(ctx context.Context, fromBlock rpc.BlockNumber, toBlock rpc.BlockNumber) (*circuitcapacitychecker.RowConsumptionStats, error) {
+	from, err := api.eth.APIBackend.HeaderByNumber(ctx, fromBlock)
+	if err != nil {
+		return nil, err
+	}
+	to, err := api.eth.APIBackend.HeaderByNumber(ctx, toBlock)
+	if err != nil {
+		return nil, err
+	}
+	if from == nil || to == nil {
+		return nil, errors.New("block not found")
+	}
+	if from.Number.Uint64() > to.Number.Uint64() {
+		return nil, fmt.Errorf("invalid block range: %d > %d", from.Number.Uint64(), to.Number.Uint64())
+	}
+	if to.Number.Uint64()-from.Number.Uint64() >= maxRowConsumptionStatsRange {
+		return nil, fmt.Errorf("block range too large, max: %d", maxRowConsumptionStatsRange)
+	}
+
+	aggregator := circuitcapacitychecker.NewRowConsumptionAggregator(from.Number.Uint64(), to.Number.Uint64(), api.eth.config.Miner.CircuitCapacityChecker.MaxRows)
+	for number := from.Number.Uint64(); number <= to.Number.Uint64(); number++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		hash := rawdb.ReadCanonicalHash(api.eth.ChainDb(), number)
+		aggregator.Add(number, rawdb.ReadBlockRowConsumption(api.eth.ChainDb(), hash))
+	}
+	return aggregator.Stats(), nil
+}
+
+// GetRowConsumptionVerificationStatus returns the progress of the background row consumption verifier,
+// including the most recent blocks whose recomputed row consumption did not match the sequencer's.
+func (api *ScrollAPI) GetRowConsumptionVerificationStatus(ctx context.Context) (*ccc_verifier.Status, error) {
+	if api.eth.cccVerifier == nil {
+		return nil, errors.New("row consumption verifier is not enabled")
+	}
+	return api.eth.cccVerifier.Status(), nil
+}
+
 // RPCTransaction is the standard RPC transaction return type with some additional skip-related fields.
 type RPCTransaction struct {
 	ethapi.RPCTransaction
-	SkipReason      string       `json:"skipReason"`
-	SkipBlockNumber *hexutil.Big `json:"skipBlockNumber"`
-	SkipBlockHash   *common.Hash `json:"skipBlockHash,omitempty"`
+	SkipReason      string               `json:"skipReason"`
+	SkipReasonCode  rawdb.SkipReasonCode `json:"skipReasonCode"`
+	SkipSubCircuit  string               `json:"skipSubCircuit,omitempty"`
+	SkipBlockNumber *hexutil.Big         `json:"skipBlockNumber"`
+	SkipBlockHash   *common.Hash         `json:"skipBlockHash,omitempty"`
 
 	// wrapped traces, currently only available for `scroll_getSkippedTransaction` API, when `MinerStoreSkippedTxTracesFlag` is set
 	Traces *types.BlockTrace `json:"traces,omitempty"`
@@ -786,11 +1330,8 @@ func (api *ScrollAPI) GetSkippedTransaction(ctx context.Context, hash common.Has
 	if stx == nil {
 		return nil, nil
 	}
-	var rpcTx RPCTransaction
-	rpcTx.RPCTransaction = *ethapi.NewRPCTransaction(stx.Tx, common.Hash{}, 0, 0, nil, api.eth.blockchain.Config())
-	rpcTx.SkipReason = stx.Reason
-	rpcTx.SkipBlockNumber = (*hexutil.Big)(new(big.Int).SetUint64(stx.BlockNumber))
-	rpcTx.SkipBlockHash = stx.BlockHash
+	reason := rawdb.SkipReason{Code: stx.ReasonCode, SubCircuit: stx.SubCircuit, Message: stx.Reason}
+	rpcTx := newRPCSkippedTransaction(stx.Tx, reason, stx.BlockNumber, stx.BlockHash, api.eth.blockchain.Config())
 	if len(stx.TracesBytes) != 0 {
 		traces := &types.BlockTrace{}
 		if err := json.Unmarshal(stx.TracesBytes, traces); err != nil {
@@ -798,7 +1339,68 @@ func (api *ScrollAPI) GetSkippedTransaction(ctx context.Context, hash common.Has
 		}
 		rpcTx.Traces = traces
 	}
-	return &rpcTx, nil
+	return rpcTx, nil
+}
+
+// newRPCSkippedTransaction returns a skipped transaction that will serialize to the RPC representation.
+func newRPCSkippedTransaction(tx *types.Transaction, reason rawdb.SkipReason, blockNumber uint64, blockHash *common.Hash, config *params.ChainConfig) *RPCTransaction {
+	return &RPCTransaction{
+		RPCTransaction:  *ethapi.NewRPCTransaction(tx, common.Hash{}, 0, 0, nil, config),
+		SkipReason:      reason.Message,
+		SkipReasonCode:  reason.Code,
+		SkipSubCircuit:  reason.SubCircuit,
+		SkipBlockNumber: (*hexutil.Big)(new(big.Int).SetUint64(blockNumber)),
+		SkipBlockHash:   blockHash,
+	}
+}
+
+// Statuses of a transaction returned by scroll_getTransactionStatus.
+const (
+	TxStatusIncluded = "included" // included in a canonical block
+	TxStatusSkipped  = "skipped"  // skipped by the sequencer
+	TxStatusPending  = "pending"  // executable in the tx pool
+	TxStatusQueued   = "queued"   // waiting for a nonce gap in the tx pool
+	TxStatusUnknown  = "unknown"
+)
+
+// TransactionStatus is the status of a transaction, it also covers the transactions that have no receipt.
+type TransactionStatus struct {
+	Status         string                `json:"status"`
+	BlockNumber    *hexutil.Big          `json:"blockNumber,omitempty"` // block the transaction is included or skipped in
+	BlockHash      *common.Hash          `json:"blockHash,omitempty"`
+	SkipReason     string                `json:"skipReason,omitempty"`
+	SkipReasonCode *rawdb.SkipReasonCode `json:"skipReasonCode,omitempty"`
+	SkipSubCircuit string                `json:"skipSubCircuit,omitempty"`
+}
+
+// GetTransactionStatus returns whether a transaction is included, skipped or waiting in the tx pool. Unlike
+// eth_getTransactionReceipt, it tells apart the transactions skipped by the sequencer and why they were skipped.
+func (api *ScrollAPI) GetTransactionStatus(ctx context.Context, hash common.Hash) (*TransactionStatus, error) {
+	if tx, blockHash, blockNumber, _ := rawdb.ReadTransaction(api.eth.ChainDb(), hash); tx != nil {
+		return &TransactionStatus{
+			Status:      TxStatusIncluded,
+			BlockNumber: (*hexutil.Big)(new(big.Int).SetUint64(blockNumber)),
+			BlockHash:   &blockHash,
+		}, nil
+	}
+	// a skipped transaction can be resubmitted, so the tx pool takes precedence
+	switch api.eth.TxPool().Status([]common.Hash{hash})[0] {
+	case core.TxStatusPending:
+		return &TransactionStatus{Status: TxStatusPending}, nil
+	case core.TxStatusQueued:
+		return &TransactionStatus{Status: TxStatusQueued}, nil
+	}
+	if stx := rawdb.ReadSkippedTransaction(api.eth.ChainDb(), hash); stx != nil {
+		return &TransactionStatus{
+			Status:         TxStatusSkipped,
+			BlockNumber:    (*hexutil.Big)(new(big.Int).SetUint64(stx.BlockNumber)),
+			BlockHash:      stx.BlockHash,
+			SkipReason:     stx.Reason,
+			SkipReasonCode: &stx.ReasonCode,
+			SkipSubCircuit: stx.SubCircuit,
+		}, nil
+	}
+	return &TransactionStatus{Status: TxStatusUnknown}, nil
 }
 
 // GetSkippedTransactionHashes returns a list of skipped transaction hashes between the two indices provided (inclusive).
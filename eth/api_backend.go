@@ -87,6 +87,17 @@ func (b *EthAPIBackend) HeaderByNumber(ctx context.Context, number rpc.BlockNumb
 		number = rpc.BlockNumber(*finalizedBlockHeightPtr)
 		return b.eth.blockchain.GetHeaderByNumber(uint64(number)), nil
 	}
+	if number == rpc.SafeBlockNumber {
+		if !b.eth.config.EnableRollupVerify {
+			return nil, errors.New("sync L1 committed batch feature not enabled, cannot query L2 committed block height")
+		}
+		committedBlockHeightPtr := rawdb.ReadCommittedL2BlockNumber(b.eth.ChainDb())
+		if committedBlockHeightPtr == nil {
+			return nil, errors.New("L2 committed block height not found in database")
+		}
+		number = rpc.BlockNumber(*committedBlockHeightPtr)
+		return b.eth.blockchain.GetHeaderByNumber(uint64(number)), nil
+	}
 	return b.eth.blockchain.GetHeaderByNumber(uint64(number)), nil
 }
 
@@ -132,6 +143,17 @@ func (b *EthAPIBackend) BlockByNumber(ctx context.Context, number rpc.BlockNumbe
 		number = rpc.BlockNumber(*finalizedBlockHeightPtr)
 		return b.eth.blockchain.GetBlockByNumber(uint64(number)), nil
 	}
+	if number == rpc.SafeBlockNumber {
+		if !b.eth.config.EnableRollupVerify {
+			return nil, errors.New("sync L1 committed batch feature not enabled, cannot query L2 committed block height")
+		}
+		committedBlockHeightPtr := rawdb.ReadCommittedL2BlockNumber(b.eth.ChainDb())
+		if committedBlockHeightPtr == nil {
+			return nil, errors.New("L2 committed block height not found in database")
+		}
+		number = rpc.BlockNumber(*committedBlockHeightPtr)
+		return b.eth.blockchain.GetBlockByNumber(uint64(number)), nil
+	}
 	return b.eth.blockchain.GetBlockByNumber(uint64(number)), nil
 }
 
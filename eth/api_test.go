@@ -18,6 +18,7 @@ package eth
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"math/big"
 	"reflect"
@@ -29,6 +30,7 @@ import (
 	"github.com/scroll-tech/go-ethereum/common"
 	"github.com/scroll-tech/go-ethereum/core/rawdb"
 	"github.com/scroll-tech/go-ethereum/core/state"
+	"github.com/scroll-tech/go-ethereum/core/types"
 	"github.com/scroll-tech/go-ethereum/crypto"
 	"github.com/scroll-tech/go-ethereum/trie"
 )
@@ -220,3 +222,31 @@ func TestStorageRangeAt(t *testing.T) {
 		}
 	}
 }
+
+func TestRowConsumptionEstimate(t *testing.T) {
+	rc := types.RowConsumption{{Name: "evm", RowNumber: 300}, {Name: "keccak", RowNumber: 1000}}
+	result := newRPCRowConsumptionEstimate(rc, 1000)
+	want := &RPCRowConsumptionEstimate{
+		Overflow:             true,
+		MaxRowsPerSubCircuit: 1000,
+		SubCircuits:          []RPCSubCircuitRowUsage{{Name: "evm", RowNumber: 300, Headroom: 700}, {Name: "keccak", RowNumber: 1000, Headroom: 0}},
+	}
+	if !reflect.DeepEqual(result, want) {
+		t.Fatalf("row consumption estimate mismatch: have %+v, want %+v", result, want)
+	}
+
+	result = newRPCRowConsumptionEstimate(rc[:1], 1000)
+	if result.Overflow {
+		t.Fatalf("unexpected overflow: %+v", result)
+	}
+}
+
+func TestEstimateRowConsumptionRateLimit(t *testing.T) {
+	api := &ScrollAPI{rowEstimator: newRowEstimator()}
+	for i := 0; i < rowEstimationBurst; i++ {
+		api.rowEstimator.limiter.Allow()
+	}
+	if _, err := api.EstimateRowConsumption(context.Background(), nil, nil); err != errRowEstimationRateLimited {
+		t.Fatalf("error mismatch: have %v, want %v", err, errRowEstimationRateLimited)
+	}
+}
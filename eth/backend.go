@@ -55,9 +55,13 @@ import (
 	"github.com/scroll-tech/go-ethereum/p2p/enode"
 	"github.com/scroll-tech/go-ethereum/params"
 	"github.com/scroll-tech/go-ethereum/rlp"
+	"github.com/scroll-tech/go-ethereum/rollup/ccc_verifier"
+	"github.com/scroll-tech/go-ethereum/rollup/circuitcapacitychecker"
+	"github.com/scroll-tech/go-ethereum/rollup/proposer"
 	"github.com/scroll-tech/go-ethereum/rollup/rollup_sync_service"
 	"github.com/scroll-tech/go-ethereum/rollup/sync_service"
 	"github.com/scroll-tech/go-ethereum/rollup/tracing"
+	"github.com/scroll-tech/go-ethereum/rollup/withdrawtrie"
 	"github.com/scroll-tech/go-ethereum/rpc"
 )
 
@@ -73,6 +77,10 @@ type Ethereum struct {
 	txPool             *core.TxPool
 	syncService        *sync_service.SyncService
 	rollupSyncService  *rollup_sync_service.RollupSyncService
+	proposer           *proposer.Proposer
+	withdrawTrie       *withdrawtrie.Indexer
+	cccSupervisor      *circuitcapacitychecker.Supervisor
+	cccVerifier        *ccc_verifier.Verifier
 	blockchain         *core.BlockChain
 	handler            *handler
 	ethDialCandidates  enode.Iterator
@@ -199,9 +207,17 @@ func New(stack *node.Node, config *ethconfig.Config, l1Client sync_service.EthCl
 	if err != nil {
 		return nil, err
 	}
+	if command := config.Miner.CircuitCapacityChecker.Command; len(command) > 0 {
+		// run the out-of-process circuit capacity checker and restart it if it crashes
+		eth.cccSupervisor, err = circuitcapacitychecker.NewSupervisor(context.Background(), command)
+		if err != nil {
+			return nil, fmt.Errorf("cannot initialize circuit capacity checker supervisor: %w", err)
+		}
+		eth.cccSupervisor.Start()
+	}
 	if config.CheckCircuitCapacity {
 		tracer := tracing.NewTracerWrapper()
-		eth.blockchain.Validator().SetupTracerAndCircuitCapacityChecker(tracer)
+		eth.blockchain.Validator().SetupTracerAndCircuitCapacityChecker(tracer, config.Miner.CircuitCapacityChecker)
 	}
 
 	// Rewind the chain in case of an incompatible config upgrade.
@@ -233,6 +249,37 @@ func New(stack *node.Node, config *ethconfig.Config, l1Client sync_service.EthCl
 		eth.rollupSyncService.Start()
 	}
 
+	if config.EnableProposer {
+		// initialize and start the chunk and batch proposer
+		eth.proposer, err = proposer.NewProposer(context.Background(), config.Proposer, eth.chainDb, eth.blockchain)
+		if err != nil {
+			return nil, fmt.Errorf("cannot initialize chunk and batch proposer: %w", err)
+		}
+		eth.proposer.Start()
+	}
+
+	if config.EnableWithdrawTrieIndexer {
+		// initialize and start the withdraw trie indexer
+		eth.withdrawTrie = withdrawtrie.NewIndexer(context.Background(), eth.chainDb, eth.blockchain)
+		eth.withdrawTrie.Start()
+	}
+
+	if config.VerifyRowConsumption {
+		// initialize and start the background row consumption verifier
+		var sequencer ccc_verifier.Sequencer
+		if endpoint := config.RowConsumptionVerifier.Sequencer; endpoint != "" {
+			sequencer, err = ccc_verifier.DialSequencer(endpoint)
+			if err != nil {
+				return nil, fmt.Errorf("cannot connect to sequencer for row consumption verification: %w", err)
+			}
+		}
+		eth.cccVerifier, err = ccc_verifier.NewVerifier(context.Background(), config.RowConsumptionVerifier, config.Miner.CircuitCapacityChecker, eth.chainDb, eth.blockchain, tracing.NewTracerWrapper(), sequencer)
+		if err != nil {
+			return nil, fmt.Errorf("cannot initialize row consumption verifier: %w", err)
+		}
+		eth.cccVerifier.Start()
+	}
+
 	// Permit the downloader to use the trie cache allowance during fast sync
 	cacheLimit := cacheConfig.TrieCleanLimit + cacheConfig.TrieDirtyLimit + cacheConfig.SnapshotLimit
 	checkpoint := config.Checkpoint
@@ -325,6 +372,16 @@ func (s *Ethereum) APIs() []rpc.API {
 	// Append any APIs exposed explicitly by the consensus engine
 	apis = append(apis, s.engine.APIs(s.BlockChain())...)
 
+	// Append the bundle API if the miner accepts bundles
+	if s.config.Miner.Bundles {
+		apis = append(apis, rpc.API{
+			Namespace: "eth",
+			Version:   "1.0",
+			Service:   NewPublicBundleAPI(s),
+			Public:    true,
+		})
+	}
+
 	// Append all the local APIs and return
 	return append(apis, []rpc.API{
 		{
@@ -505,6 +562,9 @@ func (s *Ethereum) StartMining(threads int) error {
 				return fmt.Errorf("signer missing: %v", err)
 			}
 			clique.Authorize(eb, wallet.SignData)
+			if s.config.Miner.Preconfirmations {
+				s.miner.AuthorizePreconfirmations(eb, wallet.SignText)
+			}
 		}
 		// If mining is started, we can disable the transaction rejection mechanism
 		// introduced to speed sync times.
@@ -592,8 +652,20 @@ func (s *Ethereum) Stop() error {
 	if s.config.EnableRollupVerify {
 		s.rollupSyncService.Stop()
 	}
+	if s.config.EnableProposer {
+		s.proposer.Stop()
+	}
+	if s.config.EnableWithdrawTrieIndexer {
+		s.withdrawTrie.Stop()
+	}
+	if s.config.VerifyRowConsumption {
+		s.cccVerifier.Stop()
+	}
 	s.miner.Close()
 	s.blockchain.Stop()
+	if s.cccSupervisor != nil {
+		s.cccSupervisor.Stop()
+	}
 	s.engine.Close()
 	rawdb.PopUncleanShutdownMarker(s.chainDb)
 	s.chainDb.Close()
@@ -37,6 +37,9 @@ import (
 	"github.com/scroll-tech/go-ethereum/miner"
 	"github.com/scroll-tech/go-ethereum/node"
 	"github.com/scroll-tech/go-ethereum/params"
+	"github.com/scroll-tech/go-ethereum/rollup/ccc_verifier"
+	"github.com/scroll-tech/go-ethereum/rollup/circuitcapacitychecker"
+	"github.com/scroll-tech/go-ethereum/rollup/proposer"
 )
 
 // FullNodeGPO contains default gasprice oracle settings for full node.
@@ -86,6 +89,8 @@ var Defaults = Config{
 		GasCeil:  8000000,
 		GasPrice: big.NewInt(params.GWei),
 		Recommit: 3 * time.Second,
+
+		CircuitCapacityChecker: circuitcapacitychecker.RemoteConfig{MaxRows: circuitcapacitychecker.DefaultMaxRows},
 	},
 	TxPool:        core.DefaultTxPoolConfig,
 	RPCGasCap:     50000000,
@@ -93,6 +98,9 @@ var Defaults = Config{
 	GPO:           FullNodeGPO,
 	RPCTxFeeCap:   1,  // 1 ether
 	MaxBlockRange: -1, // Default unconfigured value: no block range limit for backward compatibility
+	Proposer:      proposer.DefaultConfig,
+
+	RowConsumptionVerifier: ccc_verifier.DefaultConfig,
 }
 
 func init() {
@@ -212,11 +220,26 @@ type Config struct {
 	// Check circuit capacity in block validator
 	CheckCircuitCapacity bool
 
+	// Recompute the row consumption of imported blocks in the background and compare it with the sequencer's
+	VerifyRowConsumption bool
+
+	// Row consumption verifier options
+	RowConsumptionVerifier ccc_verifier.Config
+
 	// Enable verification of batch consistency between L1 and L2 in rollup
 	EnableRollupVerify bool
 
 	// Max block range for eth_getLogs api method
 	MaxBlockRange int64
+
+	// Enable the in-node chunk and batch proposer
+	EnableProposer bool
+
+	// Chunk and batch proposer options
+	Proposer proposer.Config
+
+	// Enable the withdraw trie indexer that serves withdrawal proofs
+	EnableWithdrawTrieIndexer bool
 }
 
 // CreateConsensusEngine creates a consensus engine for the given chain configuration.
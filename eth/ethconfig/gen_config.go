@@ -13,57 +13,64 @@ import (
 	"github.com/scroll-tech/go-ethereum/eth/gasprice"
 	"github.com/scroll-tech/go-ethereum/miner"
 	"github.com/scroll-tech/go-ethereum/params"
+	"github.com/scroll-tech/go-ethereum/rollup/ccc_verifier"
+	"github.com/scroll-tech/go-ethereum/rollup/proposer"
 )
 
 // MarshalTOML marshals as TOML.
 func (c Config) MarshalTOML() (interface{}, error) {
 	type Config struct {
-		Genesis                 *core.Genesis `toml:",omitempty"`
-		NetworkId               uint64
-		SyncMode                downloader.SyncMode
-		EthDiscoveryURLs        []string
-		SnapDiscoveryURLs       []string
-		NoPruning               bool
-		NoPrefetch              bool
-		TxLookupLimit           uint64                 `toml:",omitempty"`
-		Whitelist               map[uint64]common.Hash `toml:"-"`
-		LightServ               int                    `toml:",omitempty"`
-		LightIngress            int                    `toml:",omitempty"`
-		LightEgress             int                    `toml:",omitempty"`
-		LightPeers              int                    `toml:",omitempty"`
-		LightNoPrune            bool                   `toml:",omitempty"`
-		LightNoSyncServe        bool                   `toml:",omitempty"`
-		SyncFromCheckpoint      bool                   `toml:",omitempty"`
-		UltraLightServers       []string               `toml:",omitempty"`
-		UltraLightFraction      int                    `toml:",omitempty"`
-		UltraLightOnlyAnnounce  bool                   `toml:",omitempty"`
-		SkipBcVersionCheck      bool                   `toml:"-"`
-		DatabaseHandles         int                    `toml:"-"`
-		DatabaseCache           int
-		DatabaseFreezer         string
-		TrieCleanCache          int
-		TrieCleanCacheJournal   string        `toml:",omitempty"`
-		TrieCleanCacheRejournal time.Duration `toml:",omitempty"`
-		TrieDirtyCache          int
-		TrieTimeout             time.Duration
-		SnapshotCache           int
-		Preimages               bool
-		Miner                   miner.Config
-		Ethash                  ethash.Config
-		TxPool                  core.TxPoolConfig
-		GPO                     gasprice.Config
-		EnablePreimageRecording bool
-		DocRoot                 string `toml:"-"`
-		RPCGasCap               uint64
-		RPCEVMTimeout           time.Duration
-		RPCTxFeeCap             float64
-		Checkpoint              *params.TrustedCheckpoint      `toml:",omitempty"`
-		CheckpointOracle        *params.CheckpointOracleConfig `toml:",omitempty"`
-		OverrideArrowGlacier    *big.Int                       `toml:",omitempty"`
-		MPTWitness              int
-		CheckCircuitCapacity    bool
-		EnableRollupVerify      bool
-		MaxBlockRange           int64
+		Genesis                   *core.Genesis `toml:",omitempty"`
+		NetworkId                 uint64
+		SyncMode                  downloader.SyncMode
+		EthDiscoveryURLs          []string
+		SnapDiscoveryURLs         []string
+		NoPruning                 bool
+		NoPrefetch                bool
+		TxLookupLimit             uint64                 `toml:",omitempty"`
+		Whitelist                 map[uint64]common.Hash `toml:"-"`
+		LightServ                 int                    `toml:",omitempty"`
+		LightIngress              int                    `toml:",omitempty"`
+		LightEgress               int                    `toml:",omitempty"`
+		LightPeers                int                    `toml:",omitempty"`
+		LightNoPrune              bool                   `toml:",omitempty"`
+		LightNoSyncServe          bool                   `toml:",omitempty"`
+		SyncFromCheckpoint        bool                   `toml:",omitempty"`
+		UltraLightServers         []string               `toml:",omitempty"`
+		UltraLightFraction        int                    `toml:",omitempty"`
+		UltraLightOnlyAnnounce    bool                   `toml:",omitempty"`
+		SkipBcVersionCheck        bool                   `toml:"-"`
+		DatabaseHandles           int                    `toml:"-"`
+		DatabaseCache             int
+		DatabaseFreezer           string
+		TrieCleanCache            int
+		TrieCleanCacheJournal     string        `toml:",omitempty"`
+		TrieCleanCacheRejournal   time.Duration `toml:",omitempty"`
+		TrieDirtyCache            int
+		TrieTimeout               time.Duration
+		SnapshotCache             int
+		Preimages                 bool
+		Miner                     miner.Config
+		Ethash                    ethash.Config
+		TxPool                    core.TxPoolConfig
+		GPO                       gasprice.Config
+		EnablePreimageRecording   bool
+		DocRoot                   string `toml:"-"`
+		RPCGasCap                 uint64
+		RPCEVMTimeout             time.Duration
+		RPCTxFeeCap               float64
+		Checkpoint                *params.TrustedCheckpoint      `toml:",omitempty"`
+		CheckpointOracle          *params.CheckpointOracleConfig `toml:",omitempty"`
+		OverrideArrowGlacier      *big.Int                       `toml:",omitempty"`
+		MPTWitness                int
+		CheckCircuitCapacity      bool
+		VerifyRowConsumption      bool
+		RowConsumptionVerifier    ccc_verifier.Config
+		EnableRollupVerify        bool
+		MaxBlockRange             int64
+		EnableProposer            bool
+		Proposer                  proposer.Config
+		EnableWithdrawTrieIndexer bool
 	}
 	var enc Config
 	enc.Genesis = c.Genesis
@@ -110,60 +117,70 @@ func (c Config) MarshalTOML() (interface{}, error) {
 	enc.OverrideArrowGlacier = c.OverrideArrowGlacier
 	enc.MPTWitness = c.MPTWitness
 	enc.CheckCircuitCapacity = c.CheckCircuitCapacity
+	enc.VerifyRowConsumption = c.VerifyRowConsumption
+	enc.RowConsumptionVerifier = c.RowConsumptionVerifier
 	enc.EnableRollupVerify = c.EnableRollupVerify
 	enc.MaxBlockRange = c.MaxBlockRange
+	enc.EnableProposer = c.EnableProposer
+	enc.Proposer = c.Proposer
+	enc.EnableWithdrawTrieIndexer = c.EnableWithdrawTrieIndexer
 	return &enc, nil
 }
 
 // UnmarshalTOML unmarshals from TOML.
 func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	type Config struct {
-		Genesis                 *core.Genesis `toml:",omitempty"`
-		NetworkId               *uint64
-		SyncMode                *downloader.SyncMode
-		EthDiscoveryURLs        []string
-		SnapDiscoveryURLs       []string
-		NoPruning               *bool
-		NoPrefetch              *bool
-		TxLookupLimit           *uint64                `toml:",omitempty"`
-		Whitelist               map[uint64]common.Hash `toml:"-"`
-		LightServ               *int                   `toml:",omitempty"`
-		LightIngress            *int                   `toml:",omitempty"`
-		LightEgress             *int                   `toml:",omitempty"`
-		LightPeers              *int                   `toml:",omitempty"`
-		LightNoPrune            *bool                  `toml:",omitempty"`
-		LightNoSyncServe        *bool                  `toml:",omitempty"`
-		SyncFromCheckpoint      *bool                  `toml:",omitempty"`
-		UltraLightServers       []string               `toml:",omitempty"`
-		UltraLightFraction      *int                   `toml:",omitempty"`
-		UltraLightOnlyAnnounce  *bool                  `toml:",omitempty"`
-		SkipBcVersionCheck      *bool                  `toml:"-"`
-		DatabaseHandles         *int                   `toml:"-"`
-		DatabaseCache           *int
-		DatabaseFreezer         *string
-		TrieCleanCache          *int
-		TrieCleanCacheJournal   *string        `toml:",omitempty"`
-		TrieCleanCacheRejournal *time.Duration `toml:",omitempty"`
-		TrieDirtyCache          *int
-		TrieTimeout             *time.Duration
-		SnapshotCache           *int
-		Preimages               *bool
-		Miner                   *miner.Config
-		Ethash                  *ethash.Config
-		TxPool                  *core.TxPoolConfig
-		GPO                     *gasprice.Config
-		EnablePreimageRecording *bool
-		DocRoot                 *string `toml:"-"`
-		RPCGasCap               *uint64
-		RPCEVMTimeout           *time.Duration
-		RPCTxFeeCap             *float64
-		Checkpoint              *params.TrustedCheckpoint      `toml:",omitempty"`
-		CheckpointOracle        *params.CheckpointOracleConfig `toml:",omitempty"`
-		OverrideArrowGlacier    *big.Int                       `toml:",omitempty"`
-		MPTWitness              *int
-		CheckCircuitCapacity    *bool
-		EnableRollupVerify      *bool
-		MaxBlockRange           *int64
+		Genesis                   *core.Genesis `toml:",omitempty"`
+		NetworkId                 *uint64
+		SyncMode                  *downloader.SyncMode
+		EthDiscoveryURLs          []string
+		SnapDiscoveryURLs         []string
+		NoPruning                 *bool
+		NoPrefetch                *bool
+		TxLookupLimit             *uint64                `toml:",omitempty"`
+		Whitelist                 map[uint64]common.Hash `toml:"-"`
+		LightServ                 *int                   `toml:",omitempty"`
+		LightIngress              *int                   `toml:",omitempty"`
+		LightEgress               *int                   `toml:",omitempty"`
+		LightPeers                *int                   `toml:",omitempty"`
+		LightNoPrune              *bool                  `toml:",omitempty"`
+		LightNoSyncServe          *bool                  `toml:",omitempty"`
+		SyncFromCheckpoint        *bool                  `toml:",omitempty"`
+		UltraLightServers         []string               `toml:",omitempty"`
+		UltraLightFraction        *int                   `toml:",omitempty"`
+		UltraLightOnlyAnnounce    *bool                  `toml:",omitempty"`
+		SkipBcVersionCheck        *bool                  `toml:"-"`
+		DatabaseHandles           *int                   `toml:"-"`
+		DatabaseCache             *int
+		DatabaseFreezer           *string
+		TrieCleanCache            *int
+		TrieCleanCacheJournal     *string        `toml:",omitempty"`
+		TrieCleanCacheRejournal   *time.Duration `toml:",omitempty"`
+		TrieDirtyCache            *int
+		TrieTimeout               *time.Duration
+		SnapshotCache             *int
+		Preimages                 *bool
+		Miner                     *miner.Config
+		Ethash                    *ethash.Config
+		TxPool                    *core.TxPoolConfig
+		GPO                       *gasprice.Config
+		EnablePreimageRecording   *bool
+		DocRoot                   *string `toml:"-"`
+		RPCGasCap                 *uint64
+		RPCEVMTimeout             *time.Duration
+		RPCTxFeeCap               *float64
+		Checkpoint                *params.TrustedCheckpoint      `toml:",omitempty"`
+		CheckpointOracle          *params.CheckpointOracleConfig `toml:",omitempty"`
+		OverrideArrowGlacier      *big.Int                       `toml:",omitempty"`
+		MPTWitness                *int
+		CheckCircuitCapacity      *bool
+		VerifyRowConsumption      *bool
+		RowConsumptionVerifier    *ccc_verifier.Config
+		EnableRollupVerify        *bool
+		MaxBlockRange             *int64
+		EnableProposer            *bool
+		Proposer                  *proposer.Config
+		EnableWithdrawTrieIndexer *bool
 	}
 	var dec Config
 	if err := unmarshal(&dec); err != nil {
@@ -301,11 +318,26 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.CheckCircuitCapacity != nil {
 		c.CheckCircuitCapacity = *dec.CheckCircuitCapacity
 	}
+	if dec.VerifyRowConsumption != nil {
+		c.VerifyRowConsumption = *dec.VerifyRowConsumption
+	}
+	if dec.RowConsumptionVerifier != nil {
+		c.RowConsumptionVerifier = *dec.RowConsumptionVerifier
+	}
 	if dec.EnableRollupVerify != nil {
 		c.EnableRollupVerify = *dec.EnableRollupVerify
 	}
 	if dec.MaxBlockRange != nil {
 		c.MaxBlockRange = *dec.MaxBlockRange
 	}
+	if dec.EnableProposer != nil {
+		c.EnableProposer = *dec.EnableProposer
+	}
+	if dec.Proposer != nil {
+		c.Proposer = *dec.Proposer
+	}
+	if dec.EnableWithdrawTrieIndexer != nil {
+		c.EnableWithdrawTrieIndexer = *dec.EnableWithdrawTrieIndexer
+	}
 	return nil
 }
@@ -293,6 +293,32 @@ func (ec *Client) TransactionReceipt(ctx context.Context, txHash common.Hash) (*
 	return r, err
 }
 
+// BlockHashByNumber returns the hash of the block with the given number as reported by the node. Unlike the
+// hash of the header returned by HeaderByNumber, it does not depend on which header fields this client knows.
+func (ec *Client) BlockHashByNumber(ctx context.Context, number *big.Int) (common.Hash, error) {
+	var head *struct {
+		Hash common.Hash `json:"hash"`
+	}
+	err := ec.c.CallContext(ctx, &head, "eth_getBlockByNumber", toBlockNumArg(number), false)
+	if err == nil && head == nil {
+		err = ethereum.NotFound
+	}
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return head.Hash, nil
+}
+
+// BlockReceiptsByHash returns the receipts of all transactions in the block with the given hash.
+func (ec *Client) BlockReceiptsByHash(ctx context.Context, blockHash common.Hash) ([]*types.Receipt, error) {
+	var r []*types.Receipt
+	err := ec.c.CallContext(ctx, &r, "eth_getBlockReceipts", blockHash)
+	if err == nil && r == nil {
+		return nil, ethereum.NotFound
+	}
+	return r, err
+}
+
 type rpcProgress struct {
 	StartingBlock hexutil.Uint64
 	CurrentBlock  hexutil.Uint64
@@ -368,6 +394,37 @@ func (ec *Client) GetSkippedTransaction(ctx context.Context, txHash common.Hash)
 	return tx, ec.c.CallContext(ctx, &tx, "scroll_getSkippedTransaction", txHash)
 }
 
+// GetBatchByIndex returns the batch committed to L1 with the given index, or nil if the node does not know it.
+func (ec *Client) GetBatchByIndex(ctx context.Context, batchIndex uint64) (*eth.RPCBatch, error) {
+	var batch *eth.RPCBatch
+	return batch, ec.c.CallContext(ctx, &batch, "scroll_getBatchByIndex", batchIndex)
+}
+
+// GetBatchForBlock returns the committed batch that contains the given L2 block, or nil if the node does not know it.
+func (ec *Client) GetBatchForBlock(ctx context.Context, number uint64) (*eth.RPCBatch, error) {
+	var batch *eth.RPCBatch
+	return batch, ec.c.CallContext(ctx, &batch, "scroll_getBatchForBlock", number)
+}
+
+// GetChunkRanges returns the block ranges of the chunks of a committed batch.
+func (ec *Client) GetChunkRanges(ctx context.Context, batchIndex uint64) ([]*eth.RPCChunkRange, error) {
+	var ranges []*eth.RPCChunkRange
+	return ranges, ec.c.CallContext(ctx, &ranges, "scroll_getChunkRanges", batchIndex)
+}
+
+// GetLatestFinalizedBatch returns the latest batch finalized on L1, or nil if the node has not synced any finalized batch.
+func (ec *Client) GetLatestFinalizedBatch(ctx context.Context) (*eth.RPCBatch, error) {
+	var batch *eth.RPCBatch
+	return batch, ec.c.CallContext(ctx, &batch, "scroll_getLatestFinalizedBatch")
+}
+
+// GetWithdrawalProof returns the Merkle proof of a withdrawal message against the withdraw root of the latest
+// finalized batch, or nil if the message is not indexed.
+func (ec *Client) GetWithdrawalProof(ctx context.Context, messageHash common.Hash) (*eth.RPCWithdrawalProof, error) {
+	var proof *eth.RPCWithdrawalProof
+	return proof, ec.c.CallContext(ctx, &proof, "scroll_getWithdrawalProof", messageHash)
+}
+
 type rpcRowConsumption struct {
 	RowConsumption types.RowConsumption `json:"rowConsumption"`
 }
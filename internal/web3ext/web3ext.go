@@ -517,6 +517,16 @@ web3._extend({
 			params: 1,
 			inputFormatter: [web3._extend.formatters.inputTransactionFormatter]
 		}),
+		new web3._extend.Method({
+			name: 'sendBundle',
+			call: 'eth_sendBundle',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'getBundleStatus',
+			call: 'eth_getBundleStatus',
+			params: 1
+		}),
 		new web3._extend.Method({
 			name: 'fillTransaction',
 			call: 'eth_fillTransaction',
@@ -903,6 +913,11 @@ web3._extend({
 			call: 'scroll_getSkippedTransactionHashes',
 			params: 2
 		}),
+		new web3._extend.Method({
+			name: 'getTransactionStatus',
+			call: 'scroll_getTransactionStatus',
+			params: 1
+		}),
 		new web3._extend.Method({
 			name: 'estimateL1DataFee',
 			call: 'scroll_estimateL1DataFee',
@@ -910,6 +925,57 @@ web3._extend({
 			inputFormatter: [web3._extend.formatters.inputCallFormatter, web3._extend.formatters.inputBlockNumberFormatter],
 			outputFormatter: web3._extend.utils.toDecimal
 		}),
+		new web3._extend.Method({
+			name: 'getPreconfirmation',
+			call: 'scroll_getPreconfirmation',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'getRowConsumptionStats',
+			call: 'scroll_getRowConsumptionStats',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter, web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getRowConsumptionVerificationStatus',
+			call: 'scroll_getRowConsumptionVerificationStatus'
+		}),
+		new web3._extend.Method({
+			name: 'estimateRowConsumption',
+			call: 'scroll_estimateRowConsumption',
+			params: 2,
+			inputFormatter: [null, web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getBatchByIndex',
+			call: 'scroll_getBatchByIndex',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'getBatchForBlock',
+			call: 'scroll_getBatchForBlock',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'getChunkRanges',
+			call: 'scroll_getChunkRanges',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'getProposedChunkByIndex',
+			call: 'scroll_getProposedChunkByIndex',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'getProposedBatchByIndex',
+			call: 'scroll_getProposedBatchByIndex',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'getWithdrawalProof',
+			call: 'scroll_getWithdrawalProof',
+			params: 1
+		}),
 	],
 	properties:
 	[
@@ -929,6 +995,18 @@ web3._extend({
 			name: 'syncStatus',
 			getter: 'scroll_syncStatus',
 		}),
+		new web3._extend.Property({
+			name: 'latestFinalizedBatch',
+			getter: 'scroll_getLatestFinalizedBatch'
+		}),
+		new web3._extend.Property({
+			name: 'latestProposedChunkIndex',
+			getter: 'scroll_getLatestProposedChunkIndex'
+		}),
+		new web3._extend.Property({
+			name: 'latestProposedBatchIndex',
+			getter: 'scroll_getLatestProposedBatchIndex'
+		}),
 	]
 });
 `
@@ -0,0 +1,319 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package miner
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	lru "github.com/hashicorp/golang-lru"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/crypto"
+	"github.com/scroll-tech/go-ethereum/metrics"
+)
+
+const (
+	maxBundleTxs        = 64   // Maximum number of transactions in a bundle
+	maxBundleBlocks     = 100  // Maximum number of blocks a bundle waits for inclusion, and its default range
+	maxPendingBundles   = 1024 // Maximum number of bundles waiting for inclusion
+	maxSenderBundles    = 4    // Maximum number of bundles with transactions of the same sender waiting for inclusion
+	maxBundlesPerBlock  = 16   // Maximum number of bundles tried in a block
+	finishedBundlesSize = 4096 // Number of included, failed or expired bundles whose status is kept
+)
+
+// States of a bundle.
+const (
+	BundlePending  = "pending"  // waiting for inclusion
+	BundleIncluded = "included" // included in a sealed block
+	BundleFailed   = "failed"   // cannot be included anymore
+	BundleExpired  = "expired"  // not included until its max block
+)
+
+var (
+	errEmptyBundle        = errors.New("empty bundle")
+	errBundleTooLarge     = fmt.Errorf("bundle has more than %d transactions", maxBundleTxs)
+	errBundlePoolFull     = fmt.Errorf("more than %d pending bundles", maxPendingBundles)
+	errBundleKnown        = errors.New("bundle already known")
+	errInvalidBundleRange = errors.New("invalid bundle block range")
+	errBundleExpired      = errors.New("bundle max block already passed")
+	errBundleTooFar       = fmt.Errorf("bundle max block is more than %d blocks ahead", maxBundleBlocks)
+	errSenderBundlesFull  = fmt.Errorf("sender has more than %d pending bundles", maxSenderBundles)
+	errBundleTxReverted   = errors.New("transaction reverted")
+
+	bundleIncludedCounter = metrics.NewRegisteredCounter("miner/bundles/included", nil)
+	bundleFailedCounter   = metrics.NewRegisteredCounter("miner/bundles/failed", nil)
+	bundleExpiredCounter  = metrics.NewRegisteredCounter("miner/bundles/expired", nil)
+	bundleRevertedCounter = metrics.NewRegisteredCounter("miner/bundles/reverted", nil)
+)
+
+// Bundle is a list of transactions that are included in a block atomically and in order.
+type Bundle struct {
+	Txs               types.Transactions
+	MinBlock          uint64        // First block the bundle can be included in, 0 for any
+	MaxBlock          uint64        // Last block the bundle can be included in, at most maxBundleBlocks ahead
+	RevertingTxHashes []common.Hash // Transactions that are allowed to revert without failing the bundle
+}
+
+// Hash returns the hash of the transaction hashes of the bundle.
+func (b *Bundle) Hash() common.Hash {
+	hashes := make([]byte, 0, len(b.Txs)*common.HashLength)
+	for _, tx := range b.Txs {
+		hashes = append(hashes, tx.Hash().Bytes()...)
+	}
+	return crypto.Keccak256Hash(hashes)
+}
+
+// canRevert returns whether the transaction with the given hash is allowed to revert.
+func (b *Bundle) canRevert(hash common.Hash) bool {
+	for _, h := range b.RevertingTxHashes {
+		if h == hash {
+			return true
+		}
+	}
+	return false
+}
+
+// validate checks the bundle before it is accepted, head is the number of the current block. It returns the
+// distinct senders of the bundle.
+func (b *Bundle) validate(signer types.Signer, head uint64) ([]common.Address, error) {
+	if len(b.Txs) == 0 {
+		return nil, errEmptyBundle
+	}
+	if len(b.Txs) > maxBundleTxs {
+		return nil, errBundleTooLarge
+	}
+	if b.MaxBlock < b.MinBlock {
+		return nil, errInvalidBundleRange
+	}
+	if b.MaxBlock <= head {
+		return nil, errBundleExpired
+	}
+	if b.MaxBlock > head+maxBundleBlocks {
+		return nil, errBundleTooFar
+	}
+	var senders []common.Address
+	hashes := make(map[common.Hash]struct{}, len(b.Txs))
+	for i, tx := range b.Txs {
+		if tx.IsL1MessageTx() {
+			return nil, fmt.Errorf("bundle tx %d is an L1 message", i)
+		}
+		from, err := types.Sender(signer, tx)
+		if err != nil {
+			return nil, fmt.Errorf("invalid sender of bundle tx %d, err: %w", i, err)
+		}
+		if !containsAddress(senders, from) {
+			senders = append(senders, from)
+		}
+		hashes[tx.Hash()] = struct{}{}
+	}
+	for _, hash := range b.RevertingTxHashes {
+		if _, ok := hashes[hash]; !ok {
+			return nil, fmt.Errorf("reverting tx %s is not in the bundle", hash.Hex())
+		}
+	}
+	return senders, nil
+}
+
+func containsAddress(addresses []common.Address, address common.Address) bool {
+	for _, a := range addresses {
+		if a == address {
+			return true
+		}
+	}
+	return false
+}
+
+// BundleStatus is the inclusion status of a bundle.
+type BundleStatus struct {
+	State       string       `json:"state"`
+	BlockNumber *uint64      `json:"blockNumber,omitempty"` // block the bundle is included in
+	BlockHash   *common.Hash `json:"blockHash,omitempty"`
+	Error       string       `json:"error,omitempty"` // why the bundle failed, or was not included in the last attempt
+}
+
+type bundleEntry struct {
+	bundle  *Bundle
+	senders []common.Address
+	status  BundleStatus
+}
+
+// bundlePool holds the bundles waiting for inclusion, and the status of recently finished ones.
+type bundlePool struct {
+	mu       sync.Mutex
+	pending  map[common.Hash]*bundleEntry
+	order    []common.Hash          // pending bundles in the order they are tried
+	senders  map[common.Address]int // number of pending bundles of each sender
+	finished *lru.Cache             // bundle hash -> BundleStatus
+}
+
+func newBundlePool() *bundlePool {
+	finished, _ := lru.New(finishedBundlesSize)
+	return &bundlePool{
+		pending:  make(map[common.Hash]*bundleEntry),
+		senders:  make(map[common.Address]int),
+		finished: finished,
+	}
+}
+
+// add validates and adds a bundle, head is the number of the current block. A bundle without a max block can be
+// included in the next maxBundleBlocks blocks.
+func (p *bundlePool) add(bundle *Bundle, signer types.Signer, head uint64) (common.Hash, error) {
+	if bundle.MaxBlock == 0 {
+		bundle.MaxBlock = head + maxBundleBlocks
+	}
+	senders, err := bundle.validate(signer, head)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	hash := bundle.Hash()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if _, ok := p.pending[hash]; ok || p.finished.Contains(hash) {
+		return common.Hash{}, errBundleKnown
+	}
+	if len(p.pending) >= maxPendingBundles {
+		return common.Hash{}, errBundlePoolFull
+	}
+	for _, from := range senders {
+		if p.senders[from] >= maxSenderBundles {
+			return common.Hash{}, errSenderBundlesFull
+		}
+	}
+	for _, from := range senders {
+		p.senders[from]++
+	}
+	p.pending[hash] = &bundleEntry{bundle: bundle, senders: senders, status: BundleStatus{State: BundlePending}}
+	p.order = append(p.order, hash)
+	return hash, nil
+}
+
+// includable returns up to maxBundlesPerBlock pending bundles that can be included in the block with the given
+// number, after expiring those whose max block has passed. Bundles are tried in submission order, except that a
+// bundle which was not included goes to the back, see setError.
+func (p *bundlePool) includable(number uint64) []*Bundle {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var bundles []*Bundle
+	for _, hash := range p.order {
+		entry, ok := p.pending[hash]
+		if !ok {
+			continue
+		}
+		if entry.bundle.MaxBlock < number {
+			entry.status.State = BundleExpired
+			p.finish(hash, entry.status)
+			bundleExpiredCounter.Inc(1)
+			continue
+		}
+		if entry.bundle.MinBlock <= number && len(bundles) < maxBundlesPerBlock {
+			bundles = append(bundles, entry.bundle)
+		}
+	}
+	p.compact()
+	return bundles
+}
+
+// setError records why a pending bundle could not be included in the last attempt, and moves it behind the
+// other pending bundles so that it does not hold them back.
+func (p *bundlePool) setError(hash common.Hash, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if entry, ok := p.pending[hash]; ok {
+		entry.status.Error = err.Error()
+		for i, h := range p.order {
+			if h == hash {
+				p.order = append(append(p.order[:i:i], p.order[i+1:]...), hash)
+				break
+			}
+		}
+	}
+}
+
+// fail removes a pending bundle that cannot be included anymore.
+func (p *bundlePool) fail(hash common.Hash, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if _, ok := p.pending[hash]; ok {
+		p.finish(hash, BundleStatus{State: BundleFailed, Error: err.Error()})
+		p.compact()
+		bundleFailedCounter.Inc(1)
+	}
+}
+
+// markIncluded removes the pending bundles that were included in a sealed block.
+func (p *bundlePool) markIncluded(hashes []common.Hash, block *types.Block) {
+	if len(hashes) == 0 {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	number, blockHash := block.NumberU64(), block.Hash()
+	for _, hash := range hashes {
+		if _, ok := p.pending[hash]; ok {
+			p.finish(hash, BundleStatus{State: BundleIncluded, BlockNumber: &number, BlockHash: &blockHash})
+			bundleIncludedCounter.Inc(1)
+		}
+	}
+	p.compact()
+}
+
+// status returns the status of a bundle, nil if it is unknown.
+func (p *bundlePool) status(hash common.Hash) *BundleStatus {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if entry, ok := p.pending[hash]; ok {
+		status := entry.status
+		return &status
+	}
+	if status, ok := p.finished.Get(hash); ok {
+		status := status.(BundleStatus)
+		return &status
+	}
+	return nil
+}
+
+// finish moves a pending bundle to the finished ones, the caller must hold the lock and compact the order.
+func (p *bundlePool) finish(hash common.Hash, status BundleStatus) {
+	for _, from := range p.pending[hash].senders {
+		if p.senders[from]--; p.senders[from] == 0 {
+			delete(p.senders, from)
+		}
+	}
+	delete(p.pending, hash)
+	p.finished.Add(hash, status)
+}
+
+// compact removes the finished bundles from the submission order.
+func (p *bundlePool) compact() {
+	order := p.order[:0]
+	for _, hash := range p.order {
+		if _, ok := p.pending[hash]; ok {
+			order = append(order, hash)
+		}
+	}
+	p.order = order
+}
@@ -0,0 +1,161 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package miner
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/crypto"
+	"github.com/scroll-tech/go-ethereum/params"
+)
+
+func TestBundleValidation(t *testing.T) {
+	signer := types.LatestSigner(params.TestChainConfig)
+	tx := newBundleTestTx(0, 1000, nil)
+	unsigned := types.NewTransaction(1, testUserAddress, big.NewInt(0), params.TxGas, big.NewInt(1), nil)
+	l1Msg := types.NewTx(&types.L1MessageTx{QueueIndex: 0, Gas: params.TxGas, To: &testUserAddress, Value: big.NewInt(0)})
+
+	tests := []struct {
+		name   string
+		bundle *Bundle
+		err    bool
+	}{
+		{"valid", &Bundle{Txs: types.Transactions{tx}, MinBlock: 11, MaxBlock: 12, RevertingTxHashes: []common.Hash{tx.Hash()}}, false},
+		{"empty", &Bundle{MaxBlock: 11}, true},
+		{"too large", &Bundle{Txs: make(types.Transactions, maxBundleTxs+1), MaxBlock: 11}, true},
+		{"invalid range", &Bundle{Txs: types.Transactions{tx}, MinBlock: 12, MaxBlock: 11}, true},
+		{"expired", &Bundle{Txs: types.Transactions{tx}, MaxBlock: 10}, true},
+		{"unbounded", &Bundle{Txs: types.Transactions{tx}}, true},
+		{"too far", &Bundle{Txs: types.Transactions{tx}, MaxBlock: 11 + maxBundleBlocks}, true},
+		{"unsigned", &Bundle{Txs: types.Transactions{tx, unsigned}, MaxBlock: 11}, true},
+		{"l1 message", &Bundle{Txs: types.Transactions{l1Msg}, MaxBlock: 11}, true},
+		{"unknown reverting tx", &Bundle{Txs: types.Transactions{tx}, MaxBlock: 11, RevertingTxHashes: []common.Hash{unsigned.Hash()}}, true},
+	}
+	for _, test := range tests {
+		senders, err := test.bundle.validate(signer, 10)
+		assert.Equal(t, test.err, err != nil, "%s: %v", test.name, err)
+		if err == nil {
+			assert.Equal(t, []common.Address{testBankAddress}, senders, test.name)
+		}
+	}
+}
+
+func TestBundlePool(t *testing.T) {
+	signer := types.LatestSigner(params.TestChainConfig)
+	p := newBundlePool()
+
+	a := &Bundle{Txs: types.Transactions{newBundleTestTx(0, 1, nil)}, MaxBlock: 2}
+	b := &Bundle{Txs: types.Transactions{newBundleTestTx(0, 2, nil)}, MinBlock: 2}
+	c := &Bundle{Txs: types.Transactions{newBundleTestTx(0, 3, nil)}}
+	for _, bundle := range []*Bundle{a, b, c} {
+		hash, err := p.add(bundle, signer, 0)
+		require.NoError(t, err)
+		assert.Equal(t, bundle.Hash(), hash)
+	}
+	_, err := p.add(a, signer, 0)
+	assert.ErrorIs(t, err, errBundleKnown)
+	assert.Equal(t, uint64(maxBundleBlocks), c.MaxBlock)
+	assert.Nil(t, p.status(common.Hash{}))
+
+	// bundles are returned in submission order once their range starts
+	assert.Equal(t, []*Bundle{a, c}, p.includable(1))
+	p.setError(c.Hash(), errors.New("gas limit reached"))
+	assert.Equal(t, &BundleStatus{State: BundlePending, Error: "gas limit reached"}, p.status(c.Hash()))
+
+	// and expire once it ends
+	assert.Equal(t, []*Bundle{b, c}, p.includable(3))
+	assert.Equal(t, BundleExpired, p.status(a.Hash()).State)
+
+	block := types.NewBlockWithHeader(&types.Header{Number: big.NewInt(3)})
+	p.markIncluded([]common.Hash{b.Hash()}, block)
+	p.fail(c.Hash(), errors.New("nonce too low"))
+	assert.Empty(t, p.includable(3))
+
+	number, hash := block.NumberU64(), block.Hash()
+	assert.Equal(t, &BundleStatus{State: BundleIncluded, BlockNumber: &number, BlockHash: &hash}, p.status(b.Hash()))
+	assert.Equal(t, &BundleStatus{State: BundleFailed, Error: "nonce too low"}, p.status(c.Hash()))
+
+	// finished bundles are not accepted again
+	_, err = p.add(b, signer, 3)
+	assert.ErrorIs(t, err, errBundleKnown)
+}
+
+// newBundleTestSenders returns n bundles of a single transaction of each of the given number of new senders.
+func newBundleTestSenders(t *testing.T, senders, n int) []*Bundle {
+	signer := types.LatestSigner(params.TestChainConfig)
+	var bundles []*Bundle
+	for i := 0; i < senders; i++ {
+		key, err := crypto.GenerateKey()
+		require.NoError(t, err)
+		for nonce := 0; nonce < n; nonce++ {
+			tx := types.MustSignNewTx(key, signer, &types.LegacyTx{Nonce: uint64(nonce), To: &testUserAddress, Gas: params.TxGas, GasPrice: big.NewInt(1)})
+			bundles = append(bundles, &Bundle{Txs: types.Transactions{tx}})
+		}
+	}
+	return bundles
+}
+
+func TestBundlePoolFull(t *testing.T) {
+	signer := types.LatestSigner(params.TestChainConfig)
+	p := newBundlePool()
+	bundles := newBundleTestSenders(t, maxPendingBundles/maxSenderBundles+1, maxSenderBundles)
+	for _, bundle := range bundles[:maxPendingBundles] {
+		_, err := p.add(bundle, signer, 0)
+		require.NoError(t, err)
+	}
+	_, err := p.add(bundles[maxPendingBundles], signer, 0)
+	assert.ErrorIs(t, err, errBundlePoolFull)
+}
+
+func TestBundlePoolSenderLimit(t *testing.T) {
+	signer := types.LatestSigner(params.TestChainConfig)
+	p := newBundlePool()
+	bundles := newBundleTestSenders(t, 1, maxSenderBundles+1)
+	for _, bundle := range bundles[:maxSenderBundles] {
+		_, err := p.add(bundle, signer, 0)
+		require.NoError(t, err)
+	}
+	_, err := p.add(bundles[maxSenderBundles], signer, 0)
+	assert.ErrorIs(t, err, errSenderBundlesFull)
+
+	// finished bundles do not count towards the limit
+	p.fail(bundles[0].Hash(), errors.New("nonce too high"))
+	_, err = p.add(bundles[maxSenderBundles], signer, 0)
+	assert.NoError(t, err)
+}
+
+func TestBundlePoolPerBlockLimit(t *testing.T) {
+	signer := types.LatestSigner(params.TestChainConfig)
+	p := newBundlePool()
+	bundles := newBundleTestSenders(t, maxBundlesPerBlock+1, 1)
+	for _, bundle := range bundles {
+		_, err := p.add(bundle, signer, 0)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, bundles[:maxBundlesPerBlock], p.includable(1))
+
+	// a bundle that was not included is tried after the others
+	p.setError(bundles[0].Hash(), errors.New("gas limit reached"))
+	assert.Equal(t, bundles[1:], p.includable(1))
+}
@@ -34,6 +34,7 @@ import (
 	"github.com/scroll-tech/go-ethereum/event"
 	"github.com/scroll-tech/go-ethereum/log"
 	"github.com/scroll-tech/go-ethereum/params"
+	"github.com/scroll-tech/go-ethereum/rollup/circuitcapacitychecker"
 	"github.com/scroll-tech/go-ethereum/rollup/sync_service"
 )
 
@@ -59,6 +60,15 @@ type Config struct {
 
 	StoreSkippedTxTraces bool // Whether store the wrapped traces when storing a skipped tx
 	MaxAccountsNum       int  // Maximum number of accounts that miner will fetch the pending transactions of when building a new block
+
+	CircuitCapacityChecker circuitcapacitychecker.RemoteConfig // Out-of-process circuit capacity checker, used instead of the in-process one if its endpoint is set
+
+	TxOrdering        string           // Name of the built-in ordering policy of pending L2 transactions (default = price)
+	PriorityAddresses []common.Address `toml:",omitempty"` // Senders whose transactions are included first by the priority ordering policy
+	TxOrderingPolicy  OrderingPolicy   `toml:"-"`          // Custom ordering policy of pending L2 transactions, used instead of TxOrdering if set
+
+	Preconfirmations bool // Whether to sign and stream preconfirmations of the transactions committed into the pending block
+	Bundles          bool // Whether to accept transaction bundles over eth_sendBundle
 }
 
 // Miner creates blocks and searches for proof-of-work values.
@@ -245,3 +255,32 @@ func (miner *Miner) DisablePreseal() {
 func (miner *Miner) SubscribePendingLogs(ch chan<- []*types.Log) event.Subscription {
 	return miner.worker.pendingLogsFeed.Subscribe(ch)
 }
+
+// SendBundle adds a bundle of transactions that the miner tries to include atomically and in order,
+// and returns its hash.
+func (miner *Miner) SendBundle(bundle *Bundle) (common.Hash, error) {
+	signer := types.LatestSigner(miner.worker.chainConfig)
+	return miner.worker.bundles.add(bundle, signer, miner.eth.BlockChain().CurrentBlock().NumberU64())
+}
+
+// BundleStatus returns the inclusion status of a bundle, nil if it is unknown.
+func (miner *Miner) BundleStatus(hash common.Hash) *BundleStatus {
+	return miner.worker.bundles.status(hash)
+}
+
+// AuthorizePreconfirmations sets the sequencer account that signs the preconfirmations of the transactions
+// committed into the pending block. Transactions are not preconfirmed until it is set.
+func (miner *Miner) AuthorizePreconfirmations(signer common.Address, signFn PreconfirmationSignerFn) {
+	miner.worker.preconfirmer.authorize(signer, signFn)
+}
+
+// SubscribePreconfirmations starts delivering the preconfirmations of the transactions committed into the
+// pending block to the given channel.
+func (miner *Miner) SubscribePreconfirmations(ch chan<- *types.Preconfirmation) event.Subscription {
+	return miner.worker.preconfirmer.feed.Subscribe(ch)
+}
+
+// Preconfirmation returns the latest preconfirmation of a transaction, nil if there is none.
+func (miner *Miner) Preconfirmation(txHash common.Hash) *types.Preconfirmation {
+	return miner.worker.preconfirmer.get(txHash)
+}
@@ -0,0 +1,289 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package miner
+
+import (
+	"bytes"
+	"container/heap"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/core/types"
+)
+
+// Names of the built-in transaction ordering policies.
+const (
+	OrderingPrice      = "price"      // highest miner fee first, the default
+	OrderingFIFO       = "fifo"       // earliest arrival first
+	OrderingRoundRobin = "roundrobin" // one transaction per sender in turn
+	OrderingPriority   = "priority"   // senders in the priority lane first, each lane by miner fee
+)
+
+// OrderingPolicy orders the pending L2 transactions a new block is filled with.
+type OrderingPolicy interface {
+	// NewTransactionSet creates the ordered set of the given nonce-sorted transactions of each sender. Except
+	// for the price ordering, the set holds both the local and the remote pending transactions.
+	// Transactions of the same sender must be returned in nonce order. The input map is reowned by the set.
+	NewTransactionSet(signer types.Signer, txs map[common.Address]types.Transactions, baseFee *big.Int) types.OrderedTransactionSet
+}
+
+// NewOrderingPolicy returns the built-in ordering policy with the given name. The priority addresses are only
+// used by the priority policy.
+func NewOrderingPolicy(name string, priorityAddresses []common.Address) (OrderingPolicy, error) {
+	switch name {
+	case "", OrderingPrice:
+		return PriceAndNonceOrdering{}, nil
+	case OrderingFIFO:
+		return FIFOOrdering{}, nil
+	case OrderingRoundRobin:
+		return RoundRobinOrdering{}, nil
+	case OrderingPriority:
+		if len(priorityAddresses) == 0 {
+			return nil, fmt.Errorf("%s ordering requires priority addresses", OrderingPriority)
+		}
+		return NewPriorityOrdering(priorityAddresses, PriceAndNonceOrdering{}), nil
+	default:
+		return nil, fmt.Errorf("unknown transaction ordering policy: %s", name)
+	}
+}
+
+// splitLocals moves the transactions of the local accounts out of pending, to be committed ahead of the
+// remote ones. Only the price ordering does so, the other policies order local and remote transactions as one
+// set, so that arrival times and priority lanes hold across both.
+func splitLocals(policy OrderingPolicy, pending map[common.Address]types.Transactions, locals []common.Address) (map[common.Address]types.Transactions, map[common.Address]types.Transactions) {
+	localTxs := make(map[common.Address]types.Transactions)
+	if _, ok := policy.(PriceAndNonceOrdering); !ok {
+		return localTxs, pending
+	}
+	for _, account := range locals {
+		if txs := pending[account]; len(txs) > 0 {
+			delete(pending, account)
+			localTxs[account] = txs
+		}
+	}
+	return localTxs, pending
+}
+
+// PriceAndNonceOrdering orders transactions by miner fee, breaking ties by arrival time.
+type PriceAndNonceOrdering struct{}
+
+func (PriceAndNonceOrdering) NewTransactionSet(signer types.Signer, txs map[common.Address]types.Transactions, baseFee *big.Int) types.OrderedTransactionSet {
+	return types.NewTransactionsByPriceAndNonce(signer, txs, baseFee)
+}
+
+// FIFOOrdering orders transactions by the time they were first seen, breaking ties by sender.
+type FIFOOrdering struct{}
+
+func (FIFOOrdering) NewTransactionSet(signer types.Signer, txs map[common.Address]types.Transactions, baseFee *big.Int) types.OrderedTransactionSet {
+	heads := make(txHeadsByArrival, 0, len(txs))
+	for _, from := range senders(signer, txs, baseFee) {
+		heads = append(heads, txHead{from: from, tx: txs[from][0]})
+		txs[from] = txs[from][1:]
+	}
+	heap.Init(&heads)
+	return &transactionsByArrival{txs: txs, heads: heads, baseFee: baseFee}
+}
+
+// RoundRobinOrdering takes one transaction from each sender in turn, starting with the sender whose first
+// transaction arrived earliest.
+type RoundRobinOrdering struct{}
+
+func (RoundRobinOrdering) NewTransactionSet(signer types.Signer, txs map[common.Address]types.Transactions, baseFee *big.Int) types.OrderedTransactionSet {
+	queue := senders(signer, txs, baseFee)
+	sort.SliceStable(queue, func(i, j int) bool {
+		return txs[queue[i]][0].Time().Before(txs[queue[j]][0].Time())
+	})
+	return &transactionsByRoundRobin{txs: txs, queue: queue, baseFee: baseFee}
+}
+
+// PriorityOrdering returns the transactions of the senders in the priority lane before all others. Both lanes
+// are ordered by the inner policy.
+type PriorityOrdering struct {
+	priority map[common.Address]struct{}
+	inner    OrderingPolicy
+}
+
+// NewPriorityOrdering creates a priority ordering of the given addresses on top of the inner policy.
+func NewPriorityOrdering(addresses []common.Address, inner OrderingPolicy) *PriorityOrdering {
+	priority := make(map[common.Address]struct{}, len(addresses))
+	for _, addr := range addresses {
+		priority[addr] = struct{}{}
+	}
+	return &PriorityOrdering{priority: priority, inner: inner}
+}
+
+func (p *PriorityOrdering) NewTransactionSet(signer types.Signer, txs map[common.Address]types.Transactions, baseFee *big.Int) types.OrderedTransactionSet {
+	prioritized := make(map[common.Address]types.Transactions)
+	for from, accTxs := range txs {
+		if _, ok := p.priority[from]; ok {
+			prioritized[from] = accTxs
+			delete(txs, from)
+		}
+	}
+	return &transactionLanes{lanes: []types.OrderedTransactionSet{
+		p.inner.NewTransactionSet(signer, prioritized, baseFee),
+		p.inner.NewTransactionSet(signer, txs, baseFee),
+	}}
+}
+
+// includable returns whether the fee cap of tx covers the base fee.
+func includable(tx *types.Transaction, baseFee *big.Int) bool {
+	return baseFee == nil || tx.GasFeeCapIntCmp(baseFee) >= 0
+}
+
+// senders returns the sorted senders of txs, after removing the senders whose first transaction has a
+// different sender or cannot pay the base fee.
+func senders(signer types.Signer, txs map[common.Address]types.Transactions, baseFee *big.Int) []common.Address {
+	accounts := make([]common.Address, 0, len(txs))
+	for from, accTxs := range txs {
+		if len(accTxs) == 0 {
+			delete(txs, from)
+			continue
+		}
+		acc, _ := types.Sender(signer, accTxs[0])
+		if acc != from || !includable(accTxs[0], baseFee) {
+			delete(txs, from)
+			continue
+		}
+		accounts = append(accounts, from)
+	}
+	sort.Slice(accounts, func(i, j int) bool { return bytes.Compare(accounts[i][:], accounts[j][:]) < 0 })
+	return accounts
+}
+
+// txHead is the next transaction of a sender.
+type txHead struct {
+	from common.Address
+	tx   *types.Transaction
+}
+
+// txHeadsByArrival implements heap.Interface, ordering the heads by arrival time and sender.
+type txHeadsByArrival []txHead
+
+func (s txHeadsByArrival) Len() int { return len(s) }
+func (s txHeadsByArrival) Less(i, j int) bool {
+	if ti, tj := s[i].tx.Time(), s[j].tx.Time(); !ti.Equal(tj) {
+		return ti.Before(tj)
+	}
+	return bytes.Compare(s[i].from[:], s[j].from[:]) < 0
+}
+func (s txHeadsByArrival) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+
+func (s *txHeadsByArrival) Push(x interface{}) {
+	*s = append(*s, x.(txHead))
+}
+
+func (s *txHeadsByArrival) Pop() interface{} {
+	old := *s
+	n := len(old)
+	x := old[n-1]
+	*s = old[0 : n-1]
+	return x
+}
+
+// transactionsByArrival is the transaction set of FIFOOrdering.
+type transactionsByArrival struct {
+	txs     map[common.Address]types.Transactions // Per account nonce-sorted list of the remaining transactions
+	heads   txHeadsByArrival                      // Next transaction for each unique account (arrival heap)
+	baseFee *big.Int
+}
+
+func (t *transactionsByArrival) Peek() *types.Transaction {
+	if len(t.heads) == 0 {
+		return nil
+	}
+	return t.heads[0].tx
+}
+
+func (t *transactionsByArrival) Shift() {
+	from := t.heads[0].from
+	if txs := t.txs[from]; len(txs) > 0 && includable(txs[0], t.baseFee) {
+		t.heads[0].tx, t.txs[from] = txs[0], txs[1:]
+		heap.Fix(&t.heads, 0)
+		return
+	}
+	heap.Pop(&t.heads)
+}
+
+func (t *transactionsByArrival) Pop() {
+	heap.Pop(&t.heads)
+}
+
+// transactionsByRoundRobin is the transaction set of RoundRobinOrdering.
+type transactionsByRoundRobin struct {
+	txs     map[common.Address]types.Transactions // Per account nonce-sorted list of the remaining transactions, head first
+	queue   []common.Address                      // Accounts in turn order
+	baseFee *big.Int
+}
+
+func (t *transactionsByRoundRobin) Peek() *types.Transaction {
+	if len(t.queue) == 0 {
+		return nil
+	}
+	return t.txs[t.queue[0]][0]
+}
+
+// Shift moves the current account to the end of the queue, unless it has no more transactions.
+func (t *transactionsByRoundRobin) Shift() {
+	from := t.queue[0]
+	t.queue = t.queue[1:]
+	if txs := t.txs[from][1:]; len(txs) > 0 && includable(txs[0], t.baseFee) {
+		t.txs[from] = txs
+		t.queue = append(t.queue, from)
+	}
+}
+
+func (t *transactionsByRoundRobin) Pop() {
+	t.queue = t.queue[1:]
+}
+
+// transactionLanes returns the transactions of each lane before those of the next one.
+type transactionLanes struct {
+	lanes []types.OrderedTransactionSet
+}
+
+// current returns the first lane that has transactions left, nil if there is none.
+func (t *transactionLanes) current() types.OrderedTransactionSet {
+	for len(t.lanes) > 0 {
+		if t.lanes[0].Peek() != nil {
+			return t.lanes[0]
+		}
+		t.lanes = t.lanes[1:]
+	}
+	return nil
+}
+
+func (t *transactionLanes) Peek() *types.Transaction {
+	if lane := t.current(); lane != nil {
+		return lane.Peek()
+	}
+	return nil
+}
+
+func (t *transactionLanes) Shift() {
+	if lane := t.current(); lane != nil {
+		lane.Shift()
+	}
+}
+
+func (t *transactionLanes) Pop() {
+	if lane := t.current(); lane != nil {
+		lane.Pop()
+	}
+}
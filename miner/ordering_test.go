@@ -0,0 +1,205 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package miner
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/crypto"
+)
+
+// orderingTestTx describes a transaction of an ordering test, in arrival order.
+type orderingTestTx struct {
+	sender   int
+	gasPrice int64
+}
+
+// orderingTest holds signed transactions that arrived in the described order.
+type orderingTest struct {
+	signer types.Signer
+	keys   []*ecdsa.PrivateKey
+	addrs  []common.Address
+	txs    []*types.Transaction
+	names  map[common.Hash]string // sender index and nonce of each transaction, e.g. "1/0"
+}
+
+func newOrderingTest(t *testing.T, senders int, txs []orderingTestTx) *orderingTest {
+	ot := &orderingTest{signer: types.HomesteadSigner{}, names: make(map[common.Hash]string)}
+	for i := 0; i < senders; i++ {
+		key, err := crypto.GenerateKey()
+		require.NoError(t, err)
+		ot.keys = append(ot.keys, key)
+		ot.addrs = append(ot.addrs, crypto.PubkeyToAddress(key.PublicKey))
+	}
+	nonces := make([]uint64, senders)
+	var last time.Time
+	for _, desc := range txs {
+		// make sure the arrival times are distinct even with a coarse clock
+		for !time.Now().After(last) {
+			time.Sleep(time.Microsecond)
+		}
+		nonce := nonces[desc.sender]
+		nonces[desc.sender]++
+		tx, err := types.SignTx(types.NewTransaction(nonce, common.Address{}, big.NewInt(0), 21000, big.NewInt(desc.gasPrice), nil), ot.signer, ot.keys[desc.sender])
+		require.NoError(t, err)
+		last = tx.Time()
+		ot.txs = append(ot.txs, tx)
+		ot.names[tx.Hash()] = string(rune('0'+desc.sender)) + "/" + string(rune('0'+nonce))
+	}
+	return ot
+}
+
+// pending returns the nonce-sorted transactions of each sender, as the tx pool does.
+func (ot *orderingTest) pending() map[common.Address]types.Transactions {
+	pending := make(map[common.Address]types.Transactions)
+	for _, tx := range ot.txs {
+		from, _ := types.Sender(ot.signer, tx)
+		pending[from] = append(pending[from], tx)
+	}
+	return pending
+}
+
+// order returns the names of the transactions of the policy's set, popping the sender of each transaction
+// in pop instead of shifting.
+func (ot *orderingTest) order(policy OrderingPolicy, baseFee *big.Int, pop ...string) []string {
+	set := policy.NewTransactionSet(ot.signer, ot.pending(), baseFee)
+	var order []string
+	for tx := set.Peek(); tx != nil; tx = set.Peek() {
+		name := ot.names[tx.Hash()]
+		order = append(order, name)
+		popped := false
+		for _, p := range pop {
+			popped = popped || p == name
+		}
+		if popped {
+			set.Pop()
+		} else {
+			set.Shift()
+		}
+	}
+	return order
+}
+
+var orderingTestTxs = []orderingTestTx{
+	{sender: 0, gasPrice: 1},
+	{sender: 1, gasPrice: 3},
+	{sender: 0, gasPrice: 5},
+	{sender: 0, gasPrice: 1},
+	{sender: 2, gasPrice: 2},
+	{sender: 1, gasPrice: 1},
+}
+
+func TestFIFOOrdering(t *testing.T) {
+	ot := newOrderingTest(t, 3, orderingTestTxs)
+	assert.Equal(t, []string{"0/0", "1/0", "0/1", "0/2", "2/0", "1/1"}, ot.order(FIFOOrdering{}, nil))
+	// popping a transaction drops the following ones of its sender
+	assert.Equal(t, []string{"0/0", "1/0", "0/1", "2/0"}, ot.order(FIFOOrdering{}, nil, "0/1", "1/0"))
+}
+
+func TestRoundRobinOrdering(t *testing.T) {
+	ot := newOrderingTest(t, 3, orderingTestTxs)
+	assert.Equal(t, []string{"0/0", "1/0", "2/0", "0/1", "1/1", "0/2"}, ot.order(RoundRobinOrdering{}, nil))
+	assert.Equal(t, []string{"0/0", "1/0", "2/0", "0/1"}, ot.order(RoundRobinOrdering{}, nil, "0/1", "1/0"))
+}
+
+func TestPriorityOrdering(t *testing.T) {
+	ot := newOrderingTest(t, 3, orderingTestTxs)
+	policy, err := NewOrderingPolicy(OrderingPriority, []common.Address{ot.addrs[2], ot.addrs[0]})
+	require.NoError(t, err)
+	// the priority lane is ordered by price too, so the underpriced nonce 0 of sender 0 goes after sender 2
+	assert.Equal(t, []string{"2/0", "0/0", "0/1", "0/2", "1/0", "1/1"}, ot.order(policy, nil))
+	assert.Equal(t, []string{"2/0", "0/0", "1/0", "1/1"}, ot.order(policy, nil, "0/0"))
+
+	// the lanes can use any policy
+	fifo := NewPriorityOrdering([]common.Address{ot.addrs[1]}, FIFOOrdering{})
+	assert.Equal(t, []string{"1/0", "1/1", "0/0", "0/1", "0/2", "2/0"}, ot.order(fifo, nil))
+}
+
+func TestOrderingBaseFee(t *testing.T) {
+	ot := newOrderingTest(t, 3, orderingTestTxs)
+	// transactions that cannot pay the base fee are dropped along with the following ones of their sender
+	baseFee := big.NewInt(2)
+	assert.Equal(t, []string{"1/0", "2/0"}, ot.order(FIFOOrdering{}, baseFee))
+	assert.Equal(t, []string{"1/0", "2/0"}, ot.order(RoundRobinOrdering{}, baseFee))
+	assert.Equal(t, []string{"1/0", "2/0"}, ot.order(PriceAndNonceOrdering{}, baseFee))
+}
+
+func TestOrderingDeterminism(t *testing.T) {
+	txs := make([]orderingTestTx, 0, 64)
+	for i := 0; i < cap(txs); i++ {
+		txs = append(txs, orderingTestTx{sender: i % 8, gasPrice: int64(i%3 + 1)})
+	}
+	ot := newOrderingTest(t, 8, txs)
+	priority, err := NewOrderingPolicy(OrderingPriority, []common.Address{ot.addrs[3], ot.addrs[5]})
+	require.NoError(t, err)
+
+	for name, policy := range map[string]OrderingPolicy{
+		OrderingPrice:      PriceAndNonceOrdering{},
+		OrderingFIFO:       FIFOOrdering{},
+		OrderingRoundRobin: RoundRobinOrdering{},
+		OrderingPriority:   priority,
+	} {
+		// the pending map is iterated in a random order each time
+		expected := ot.order(policy, nil, "2/4")
+		assert.Len(t, expected, len(txs)-3, name)
+		for i := 0; i < 20; i++ {
+			assert.Equal(t, expected, ot.order(policy, nil, "2/4"), name)
+		}
+	}
+}
+
+func TestNewOrderingPolicy(t *testing.T) {
+	for name, expected := range map[string]OrderingPolicy{
+		"":                 PriceAndNonceOrdering{},
+		OrderingPrice:      PriceAndNonceOrdering{},
+		OrderingFIFO:       FIFOOrdering{},
+		OrderingRoundRobin: RoundRobinOrdering{},
+	} {
+		policy, err := NewOrderingPolicy(name, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, policy)
+	}
+
+	_, err := NewOrderingPolicy(OrderingPriority, nil)
+	assert.Error(t, err)
+	_, err = NewOrderingPolicy("lifo", nil)
+	assert.Error(t, err)
+}
+
+func TestSplitLocals(t *testing.T) {
+	ot := newOrderingTest(t, 3, orderingTestTxs)
+	local := ot.addrs[0]
+
+	// the price ordering commits local transactions first
+	localTxs, remoteTxs := splitLocals(PriceAndNonceOrdering{}, ot.pending(), []common.Address{local})
+	assert.Len(t, localTxs, 1)
+	assert.Contains(t, localTxs, local)
+	assert.Len(t, remoteTxs, 2)
+
+	// other policies order all transactions together
+	localTxs, remoteTxs = splitLocals(FIFOOrdering{}, ot.pending(), []common.Address{local})
+	assert.Empty(t, localTxs)
+	assert.Len(t, remoteTxs, 3)
+}
@@ -0,0 +1,156 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package miner
+
+import (
+	"math/big"
+	"sync"
+
+	lru "github.com/hashicorp/golang-lru"
+
+	"github.com/scroll-tech/go-ethereum/accounts"
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/common/hexutil"
+	"github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/crypto"
+	"github.com/scroll-tech/go-ethereum/event"
+	"github.com/scroll-tech/go-ethereum/log"
+	"github.com/scroll-tech/go-ethereum/metrics"
+)
+
+const (
+	preconfirmationsCacheSize = 16384 // Number of transactions whose latest preconfirmation is kept for queries
+	preconfirmationQueueSize  = 1024  // Number of preconfirmations waiting to be sent to the subscribers
+)
+
+var (
+	preconfirmationCounter           = metrics.NewRegisteredCounter("miner/preconfirmations", nil)
+	preconfirmationSignFailedCounter = metrics.NewRegisteredCounter("miner/preconfirmations/sign_failed", nil)
+	preconfirmationDroppedCounter    = metrics.NewRegisteredCounter("miner/preconfirmations/dropped", nil)
+)
+
+// PreconfirmationSignerFn signs the EIP-191 hash of a message, like accounts.Wallet.SignText.
+type PreconfirmationSignerFn func(signer accounts.Account, text []byte) ([]byte, error)
+
+// preconfirmer signs and streams the preconfirmations of the transactions committed into the pending block.
+type preconfirmer struct {
+	mu      sync.Mutex
+	chainID *big.Int
+	signer  common.Address
+	signFn  PreconfirmationSignerFn     // nil if preconfirmations are disabled
+	number  uint64                      // number of the block of the emitted preconfirmations
+	emitted map[common.Hash]common.Hash // tx hash -> signing hash of its latest preconfirmation in the block
+	recent  *lru.Cache                  // tx hash -> *types.Preconfirmation
+	feed    event.Feed
+	queue   chan *types.Preconfirmation // preconfirmations waiting to be sent to the feed
+	exitCh  chan struct{}
+}
+
+func newPreconfirmer(chainID *big.Int) *preconfirmer {
+	recent, _ := lru.New(preconfirmationsCacheSize)
+	p := &preconfirmer{
+		chainID: chainID,
+		emitted: make(map[common.Hash]common.Hash),
+		recent:  recent,
+		queue:   make(chan *types.Preconfirmation, preconfirmationQueueSize),
+		exitCh:  make(chan struct{}),
+	}
+	go p.loop()
+	return p
+}
+
+// loop sends the queued preconfirmations to the subscribers, so that slow subscribers do not block the worker.
+func (p *preconfirmer) loop() {
+	for {
+		select {
+		case preconf := <-p.queue:
+			p.feed.Send(preconf)
+		case <-p.exitCh:
+			return
+		}
+	}
+}
+
+// close stops sending preconfirmations to the subscribers.
+func (p *preconfirmer) close() {
+	close(p.exitCh)
+}
+
+// authorize sets the sequencer account that signs the preconfirmations.
+func (p *preconfirmer) authorize(signer common.Address, signFn PreconfirmationSignerFn) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.signer = signer
+	p.signFn = signFn
+}
+
+// preconfirm signs and emits the preconfirmation of a transaction committed into the block of header at index.
+// fingerprint is the fingerprint of the transactions of the block up to and including the transaction.
+// A transaction committed again at the same position in a recommit of the block is not preconfirmed again.
+func (p *preconfirmer) preconfirm(header *types.Header, tx *types.Transaction, index int, receipt *types.Receipt, fingerprint common.Hash) {
+	p.mu.Lock()
+	if p.signFn == nil {
+		p.mu.Unlock()
+		return
+	}
+	preconf := &types.Preconfirmation{
+		ChainID:       (*hexutil.Big)(p.chainID),
+		TxHash:        tx.Hash(),
+		BlockNumber:   hexutil.Uint64(header.Number.Uint64()),
+		ParentHash:    header.ParentHash,
+		Index:         hexutil.Uint64(index),
+		Status:        hexutil.Uint64(receipt.Status),
+		PostStateHint: crypto.Keccak256Hash(header.ParentHash.Bytes(), fingerprint.Bytes()),
+	}
+	if p.number != header.Number.Uint64() {
+		p.number = header.Number.Uint64()
+		p.emitted = make(map[common.Hash]common.Hash)
+	}
+	hash := preconf.SigningHash()
+	if p.emitted[preconf.TxHash] == hash {
+		p.mu.Unlock()
+		return
+	}
+	sig, err := p.signFn(accounts.Account{Address: p.signer}, preconf.SigningData())
+	if err != nil {
+		p.mu.Unlock()
+		log.Warn("Failed to sign preconfirmation", "tx", preconf.TxHash.String(), "err", err)
+		preconfirmationSignFailedCounter.Inc(1)
+		return
+	}
+	preconf.Signature = sig
+	p.emitted[preconf.TxHash] = hash
+	p.recent.Add(preconf.TxHash, preconf)
+	p.mu.Unlock()
+
+	preconfirmationCounter.Inc(1)
+	select {
+	case p.queue <- preconf:
+	default:
+		log.Warn("Dropping preconfirmation", "tx", preconf.TxHash.String())
+		preconfirmationDroppedCounter.Inc(1)
+	}
+}
+
+// get returns the latest preconfirmation of a transaction, nil if there is none.
+func (p *preconfirmer) get(txHash common.Hash) *types.Preconfirmation {
+	if preconf, ok := p.recent.Get(txHash); ok {
+		return preconf.(*types.Preconfirmation)
+	}
+	return nil
+}
@@ -0,0 +1,165 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package miner
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scroll-tech/go-ethereum/accounts"
+	"github.com/scroll-tech/go-ethereum/accounts/keystore"
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/common/hexutil"
+	"github.com/scroll-tech/go-ethereum/consensus/ethash"
+	"github.com/scroll-tech/go-ethereum/core/rawdb"
+	"github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/crypto"
+)
+
+// testPreconfirmationSigner signs like the keystore wallet with the test bank key.
+func testPreconfirmationSigner(signer accounts.Account, text []byte) ([]byte, error) {
+	return crypto.Sign(accounts.TextHash(text), testBankKey)
+}
+
+// newTestKeystoreWallet returns an unlocked keystore wallet of the test bank key.
+func newTestKeystoreWallet(t *testing.T) accounts.Wallet {
+	ks := keystore.NewKeyStore(t.TempDir(), keystore.LightScryptN, keystore.LightScryptP)
+	account, err := ks.ImportECDSA(testBankKey, "")
+	require.NoError(t, err)
+	require.NoError(t, ks.Unlock(account, ""))
+	return ks.Wallets()[0]
+}
+
+func waitPreconfirmation(t *testing.T, preconfs <-chan *types.Preconfirmation) *types.Preconfirmation {
+	select {
+	case preconf := <-preconfs:
+		return preconf
+	case <-time.After(3 * time.Second):
+		t.Fatal("timeout")
+		return nil
+	}
+}
+
+func TestPreconfirmer(t *testing.T) {
+	p := newPreconfirmer(big.NewInt(1))
+	defer p.close()
+	preconfs := make(chan *types.Preconfirmation, 10)
+	sub := p.feed.Subscribe(preconfs)
+	defer sub.Unsubscribe()
+
+	header := &types.Header{Number: big.NewInt(1), ParentHash: common.HexToHash("0x01")}
+	tx := newBundleTestTx(0, 1000, nil)
+	other := newBundleTestTx(1, 1000, nil)
+	receipt := &types.Receipt{Status: types.ReceiptStatusSuccessful}
+
+	// nothing is preconfirmed before the signer is set
+	p.preconfirm(header, tx, 0, receipt, common.HexToHash("0x11"))
+	assert.Nil(t, p.get(tx.Hash()))
+
+	p.authorize(testBankAddress, newTestKeystoreWallet(t).SignText)
+	p.preconfirm(header, tx, 0, receipt, common.HexToHash("0x11"))
+	preconf := waitPreconfirmation(t, preconfs)
+	assert.Equal(t, tx.Hash(), preconf.TxHash)
+	assert.Equal(t, big.NewInt(1), preconf.ChainID.ToInt())
+	assert.Equal(t, uint64(1), uint64(preconf.BlockNumber))
+	assert.Equal(t, uint64(0), uint64(preconf.Index))
+	assert.Equal(t, types.ReceiptStatusSuccessful, uint64(preconf.Status))
+	signer, err := preconf.Signer()
+	require.NoError(t, err)
+	assert.Equal(t, testBankAddress, signer)
+	assert.Equal(t, preconf, p.get(tx.Hash()))
+
+	// a recommit at the same position is not preconfirmed again, the next preconfirmation is the other tx
+	p.preconfirm(header, tx, 0, receipt, common.HexToHash("0x11"))
+	p.preconfirm(header, other, 1, receipt, common.HexToHash("0x12"))
+	assert.Equal(t, other.Hash(), waitPreconfirmation(t, preconfs).TxHash)
+
+	// but a new position is, as is the same position in the next block
+	p.preconfirm(header, tx, 2, receipt, common.HexToHash("0x13"))
+	assert.Equal(t, uint64(2), uint64(waitPreconfirmation(t, preconfs).Index))
+	p.preconfirm(&types.Header{Number: big.NewInt(2)}, tx, 2, receipt, common.HexToHash("0x13"))
+	assert.Equal(t, uint64(2), uint64(waitPreconfirmation(t, preconfs).BlockNumber))
+	assert.Equal(t, uint64(2), uint64(p.get(tx.Hash()).BlockNumber))
+
+	// a modified preconfirmation is not signed by the sequencer, nor is one of another chain
+	forged := *preconf
+	forged.Index = 5
+	signer, err = forged.Signer()
+	require.NoError(t, err)
+	assert.NotEqual(t, testBankAddress, signer)
+
+	forged = *preconf
+	forged.ChainID = (*hexutil.Big)(big.NewInt(2))
+	signer, err = forged.Signer()
+	require.NoError(t, err)
+	assert.NotEqual(t, testBankAddress, signer)
+}
+
+func TestPreconfirmerSlowSubscriber(t *testing.T) {
+	p := newPreconfirmer(big.NewInt(1))
+	defer p.close()
+	preconfs := make(chan *types.Preconfirmation)
+	sub := p.feed.Subscribe(preconfs)
+	defer sub.Unsubscribe()
+	p.authorize(testBankAddress, testPreconfirmationSigner)
+
+	// preconfirmations are dropped instead of blocking the worker on a subscriber that does not read them
+	header := &types.Header{Number: big.NewInt(1)}
+	receipt := &types.Receipt{Status: types.ReceiptStatusSuccessful}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < preconfirmationQueueSize+2; i++ {
+			p.preconfirm(header, newBundleTestTx(uint64(i), 1000, nil), i, receipt, common.BigToHash(big.NewInt(int64(i))))
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("preconfirm blocked on a slow subscriber")
+	}
+	assert.Equal(t, uint64(0), uint64(waitPreconfirmation(t, preconfs).Index))
+}
+
+func TestWorkerPreconfirmations(t *testing.T) {
+	engine := ethash.NewFaker()
+	defer engine.Close()
+
+	w, _ := newTestWorker(t, ethashChainConfig, engine, rawdb.NewMemoryDatabase(), 0)
+	defer w.close()
+	w.skipSealHook = func(task *task) bool { return true }
+
+	preconfs := make(chan *types.Preconfirmation, 10)
+	sub := w.preconfirmer.feed.Subscribe(preconfs)
+	defer sub.Unsubscribe()
+	w.preconfirmer.authorize(testBankAddress, testPreconfirmationSigner)
+	w.start()
+
+	select {
+	case preconf := <-preconfs:
+		assert.Equal(t, pendingTxs[0].Hash(), preconf.TxHash)
+		assert.Equal(t, uint64(1), uint64(preconf.BlockNumber))
+		assert.Equal(t, w.chain.Genesis().Hash(), preconf.ParentHash)
+		assert.Equal(t, uint64(0), uint64(preconf.Index))
+	case <-time.After(3 * time.Second):
+		t.Fatal("timeout")
+	}
+}
@@ -0,0 +1,119 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package miner
+
+import (
+	"encoding/binary"
+
+	lru "github.com/hashicorp/golang-lru"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/crypto"
+	"github.com/scroll-tech/go-ethereum/metrics"
+	"github.com/scroll-tech/go-ethereum/rollup/tracing"
+)
+
+// rowConsumptionCacheSize is the maximum number of transactions whose traces and row consumption are cached.
+const rowConsumptionCacheSize = 4096
+
+var (
+	rowConsumptionCacheHitCounter  = metrics.NewRegisteredCounter("miner/ccc_cache/hit", nil)
+	rowConsumptionCacheMissCounter = metrics.NewRegisteredCounter("miner/ccc_cache/miss", nil)
+)
+
+// rowConsumptionCacheEntry is the result of tracing a transaction and applying it to the circuit capacity checker.
+type rowConsumptionCacheEntry struct {
+	traces   *types.BlockTrace
+	accRows  *types.RowConsumption  // accumulated row consumption of the block up to and including the transaction
+	envState *tracing.TraceEnvState // trace env state after tracing the transaction
+}
+
+// rowConsumptionCache caches the traces and the accumulated row consumption of the transactions packed
+// into a block, so that a recommit on the same parent does not trace and check them again.
+//
+// A transaction's trace and row consumption only depend on the parent state, the transaction and the
+// transactions before it in the block, which is what the cache is keyed by. Cache entries are only
+// valid for one parent block, so the cache is cleared when the worker builds on a new head.
+type rowConsumptionCache struct {
+	parent  common.Hash
+	entries *lru.Cache
+}
+
+func newRowConsumptionCache() *rowConsumptionCache {
+	entries, _ := lru.New(rowConsumptionCacheSize)
+	return &rowConsumptionCache{entries: entries}
+}
+
+// setParent clears the cache if the parent block changed.
+func (c *rowConsumptionCache) setParent(parent common.Hash) {
+	if c.parent != parent {
+		c.entries.Purge()
+		c.parent = parent
+	}
+}
+
+func (c *rowConsumptionCache) get(key common.Hash) *rowConsumptionCacheEntry {
+	if entry, ok := c.entries.Get(key); ok {
+		rowConsumptionCacheHitCounter.Inc(1)
+		return entry.(*rowConsumptionCacheEntry)
+	}
+	rowConsumptionCacheMissCounter.Inc(1)
+	return nil
+}
+
+// add caches the result of a transaction. The traces are copied, since the trace env reuses
+// their slices and storage trace for the next transaction.
+func (c *rowConsumptionCache) add(key common.Hash, traces *types.BlockTrace, accRows *types.RowConsumption, envState *tracing.TraceEnvState) {
+	cpy := *traces
+	cpy.StorageTrace = envState.StorageTrace()
+	cpy.ExecutionResults = append([]*types.ExecutionResult(nil), traces.ExecutionResults...)
+	cpy.TxStorageTraces = make([]*types.StorageTrace, len(traces.TxStorageTraces))
+	for i, st := range traces.TxStorageTraces {
+		if st != nil {
+			stCpy := *st
+			cpy.TxStorageTraces[i] = &stCpy
+		}
+	}
+	c.entries.Add(key, &rowConsumptionCacheEntry{traces: &cpy, accRows: accRows, envState: envState})
+}
+
+// rowConsumptionCacheKey returns the cache key of a transaction. The prefix is the fingerprint of the
+// transactions before it in the block, see nextTxFingerprint.
+func rowConsumptionCacheKey(parentRoot common.Hash, prefix common.Hash, txHash common.Hash) common.Hash {
+	return crypto.Keccak256Hash(parentRoot.Bytes(), prefix.Bytes(), txHash.Bytes())
+}
+
+// headerFingerprint returns the fingerprint of a block before its first transaction. It covers the header
+// fields that transactions can observe, so that a transaction's cached results are only reused in the
+// same block context.
+func headerFingerprint(header *types.Header) common.Hash {
+	var buf [24]byte
+	binary.BigEndian.PutUint64(buf[0:8], header.Number.Uint64())
+	binary.BigEndian.PutUint64(buf[8:16], header.Time)
+	binary.BigEndian.PutUint64(buf[16:24], header.GasLimit)
+	var baseFee []byte
+	if header.BaseFee != nil {
+		baseFee = header.BaseFee.Bytes()
+	}
+	return crypto.Keccak256Hash(buf[:], header.Coinbase.Bytes(), header.Difficulty.Bytes(), baseFee)
+}
+
+// nextTxFingerprint extends the fingerprint of the transactions in a block with a transaction.
+func nextTxFingerprint(prefix common.Hash, txHash common.Hash) common.Hash {
+	return crypto.Keccak256Hash(prefix.Bytes(), txHash.Bytes())
+}
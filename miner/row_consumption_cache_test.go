@@ -0,0 +1,96 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package miner
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/rollup/tracing"
+)
+
+func TestRowConsumptionCacheKey(t *testing.T) {
+	root := common.HexToHash("0x01")
+	tx1, tx2 := common.HexToHash("0x11"), common.HexToHash("0x12")
+
+	// the key depends on the order of the txs before the tx
+	seed := common.HexToHash("0xff")
+	key12 := rowConsumptionCacheKey(root, nextTxFingerprint(nextTxFingerprint(seed, tx1), tx2), common.HexToHash("0x13"))
+	key21 := rowConsumptionCacheKey(root, nextTxFingerprint(nextTxFingerprint(seed, tx2), tx1), common.HexToHash("0x13"))
+	assert.NotEqual(t, key12, key21)
+	assert.Equal(t, key12, rowConsumptionCacheKey(root, nextTxFingerprint(nextTxFingerprint(seed, tx1), tx2), common.HexToHash("0x13")))
+
+	// and on the parent root
+	assert.NotEqual(t, rowConsumptionCacheKey(root, seed, tx1), rowConsumptionCacheKey(common.HexToHash("0x02"), seed, tx1))
+}
+
+func TestRowConsumptionCache(t *testing.T) {
+	c := newRowConsumptionCache()
+	c.setParent(common.HexToHash("0x01"))
+
+	key := common.HexToHash("0x11")
+	assert.Nil(t, c.get(key))
+
+	traces := &types.BlockTrace{
+		ExecutionResults: []*types.ExecutionResult{{Gas: 21000}},
+		TxStorageTraces:  []*types.StorageTrace{{RootBefore: common.HexToHash("0x21")}},
+	}
+	accRows := &types.RowConsumption{{Name: "evm", RowNumber: 1}}
+	c.add(key, traces, accRows, &tracing.TraceEnvState{})
+
+	// the cached traces do not change when the trace env reuses the traces
+	traces.ExecutionResults[0] = &types.ExecutionResult{Gas: 42000}
+	traces.TxStorageTraces[0].RootBefore = common.HexToHash("0x22")
+	entry := c.get(key)
+	if assert.NotNil(t, entry) {
+		assert.Equal(t, uint64(21000), entry.traces.ExecutionResults[0].Gas)
+		assert.Equal(t, common.HexToHash("0x21"), entry.traces.TxStorageTraces[0].RootBefore)
+		assert.Equal(t, accRows, entry.accRows)
+	}
+
+	// the same parent keeps the entries, a new parent clears them
+	c.setParent(common.HexToHash("0x01"))
+	assert.NotNil(t, c.get(key))
+	c.setParent(common.HexToHash("0x02"))
+	assert.Nil(t, c.get(key))
+}
+
+func TestHeaderFingerprint(t *testing.T) {
+	newHeader := func() *types.Header {
+		return &types.Header{Number: big.NewInt(1), Time: 100, GasLimit: 1000, Coinbase: common.HexToAddress("0x01"), Difficulty: big.NewInt(1), BaseFee: big.NewInt(1)}
+	}
+	seed := headerFingerprint(newHeader())
+	assert.Equal(t, seed, headerFingerprint(newHeader()))
+
+	// each field that transactions can observe changes the fingerprint
+	for name, modify := range map[string]func(*types.Header){
+		"number":     func(h *types.Header) { h.Number = big.NewInt(2) },
+		"time":       func(h *types.Header) { h.Time = 101 },
+		"gas limit":  func(h *types.Header) { h.GasLimit = 1001 },
+		"coinbase":   func(h *types.Header) { h.Coinbase = common.HexToAddress("0x02") },
+		"difficulty": func(h *types.Header) { h.Difficulty = big.NewInt(2) },
+		"base fee":   func(h *types.Header) { h.BaseFee = nil },
+	} {
+		header := newHeader()
+		modify(header)
+		assert.NotEqual(t, seed, headerFingerprint(header), name)
+	}
+}
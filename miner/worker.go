@@ -94,6 +94,9 @@ var (
 	l2TxCccUnknownErrCounter          = metrics.NewRegisteredCounter("miner/skipped_txs/l2/ccc_unknown_err", nil)
 	l1TxStrangeErrCounter             = metrics.NewRegisteredCounter("miner/skipped_txs/l1/strange_err", nil)
 
+	// Metrics for blocks sealed early because the circuit capacity checker is unavailable
+	cccUnavailableCounter = metrics.NewRegisteredCounter("miner/ccc_unavailable", nil)
+
 	l2CommitTxsTimer                = metrics.NewRegisteredTimer("miner/commit/txs_all", nil)
 	l2CommitTxTimer                 = metrics.NewRegisteredTimer("miner/commit/tx_all", nil)
 	l2CommitTxFailedTimer           = metrics.NewRegisteredTimer("miner/commit/tx_all_failed", nil)
@@ -109,6 +112,7 @@ var (
 	l2CommitNewWorkTidyPendingTxTimer       = metrics.NewRegisteredTimer("miner/commit/new_work_tidy_pending", nil)
 	l2CommitNewWorkCommitL1MsgTimer         = metrics.NewRegisteredTimer("miner/commit/new_work_commit_l1_msg", nil)
 	l2CommitNewWorkPrioritizedTxCommitTimer = metrics.NewRegisteredTimer("miner/commit/new_work_prioritized", nil)
+	l2CommitNewWorkBundleCommitTimer        = metrics.NewRegisteredTimer("miner/commit/new_work_bundle", nil)
 	l2CommitNewWorkRemoteLocalCommitTimer   = metrics.NewRegisteredTimer("miner/commit/new_work_remote_local", nil)
 	l2CommitNewWorkLocalPriceAndNonceTimer  = metrics.NewRegisteredTimer("miner/commit/new_work_local_price_and_nonce", nil)
 	l2CommitNewWorkRemotePriceAndNonceTimer = metrics.NewRegisteredTimer("miner/commit/new_work_remote_price_and_nonce", nil)
@@ -140,6 +144,14 @@ type environment struct {
 	traceEnv       *tracing.TraceEnv     // env for tracing
 	accRows        *types.RowConsumption // accumulated row consumption for a block
 	nextL1MsgIndex uint64                // next L1 queue index to be processed
+
+	// row consumption cache related fields
+	txFingerprint common.Hash         // fingerprint of the header and the transactions packed so far
+	cccBacklog    []*types.BlockTrace // cached traces of packed transactions not applied to the ccc yet
+
+	// bundle related fields
+	cccTraces []*types.BlockTrace // traces of all packed transactions, to rebuild the ccc after reverting a bundle
+	bundles   []common.Hash       // bundles packed in the block
 }
 
 // task contains all information for consensus engine sealing and result submitting.
@@ -150,6 +162,7 @@ type task struct {
 	createdAt      time.Time
 	accRows        *types.RowConsumption // accumulated row consumption in the circuit side
 	nextL1MsgIndex uint64                // next L1 queue index to be processed
+	bundles        []common.Hash         // bundles included in the block
 }
 
 const (
@@ -244,7 +257,11 @@ type worker struct {
 	// External functions
 	isLocalBlock func(block *types.Block) bool // Function used to determine whether the specified block is mined by local miner.
 
-	circuitCapacityChecker *circuitcapacitychecker.CircuitCapacityChecker
+	circuitCapacityChecker circuitcapacitychecker.Checker
+	rowConsumptionCache    *rowConsumptionCache
+	ordering               OrderingPolicy // Ordering policy of pending L2 transactions
+	bundles                *bundlePool    // Bundles waiting for inclusion
+	preconfirmer           *preconfirmer  // Signs and streams preconfirmations of committed transactions
 	prioritizedTx          *prioritizedTransaction
 
 	// Test hooks
@@ -279,9 +296,12 @@ func newWorker(config *Config, chainConfig *params.ChainConfig, engine consensus
 		startCh:                make(chan struct{}, 1),
 		resubmitIntervalCh:     make(chan time.Duration),
 		resubmitAdjustCh:       make(chan *intervalAdjust, resubmitAdjustChanSize),
-		circuitCapacityChecker: circuitcapacitychecker.NewCircuitCapacityChecker(true),
+		circuitCapacityChecker: circuitcapacitychecker.New(config.CircuitCapacityChecker, true),
+		rowConsumptionCache:    newRowConsumptionCache(),
+		bundles:                newBundlePool(),
+		preconfirmer:           newPreconfirmer(chainConfig.ChainID),
 	}
-	log.Info("created new worker", "CircuitCapacityChecker ID", worker.circuitCapacityChecker.ID)
+	log.Info("created new worker", "CircuitCapacityChecker ID", worker.circuitCapacityChecker.ID())
 
 	// Subscribe NewTxsEvent for tx pool
 	worker.txsSub = eth.TxPool().SubscribeNewTxsEvent(worker.txsCh)
@@ -308,6 +328,17 @@ func newWorker(config *Config, chainConfig *params.ChainConfig, engine consensus
 		recommit = minRecommitInterval
 	}
 
+	// Sanitize transaction ordering policy.
+	worker.ordering = worker.config.TxOrderingPolicy
+	if worker.ordering == nil {
+		ordering, err := NewOrderingPolicy(worker.config.TxOrdering, worker.config.PriorityAddresses)
+		if err != nil {
+			log.Warn("Sanitizing miner transaction ordering", "provided", worker.config.TxOrdering, "updated", OrderingPrice, "err", err)
+			ordering = PriceAndNonceOrdering{}
+		}
+		worker.ordering = ordering
+	}
+
 	// Sanitize account fetch limit.
 	if worker.config.MaxAccountsNum == 0 {
 		log.Warn("Sanitizing miner account fetch limit", "provided", worker.config.MaxAccountsNum, "updated", math.MaxInt)
@@ -327,10 +358,14 @@ func newWorker(config *Config, chainConfig *params.ChainConfig, engine consensus
 	return worker
 }
 
-// getCCC returns a pointer to this worker's CCC instance.
+// getCCC returns a pointer to this worker's CCC instance, or nil if the worker uses a remote CCC.
 // Only used in tests.
 func (w *worker) getCCC() *circuitcapacitychecker.CircuitCapacityChecker {
-	return w.circuitCapacityChecker
+	ccc, ok := w.circuitCapacityChecker.(*circuitcapacitychecker.CircuitCapacityChecker)
+	if !ok {
+		return nil
+	}
+	return ccc
 }
 
 // setEtherbase sets the etherbase used to initialize the block coinbase field.
@@ -417,6 +452,10 @@ func (w *worker) close() {
 	atomic.StoreInt32(&w.running, 0)
 	close(w.exitCh)
 	w.wg.Wait()
+	w.preconfirmer.close()
+	if ccc, ok := w.circuitCapacityChecker.(*circuitcapacitychecker.RemoteCircuitCapacityChecker); ok {
+		ccc.Close()
+	}
 }
 
 // recalcRecommit recalculates the resubmitting interval upon feedback.
@@ -618,7 +657,7 @@ func (w *worker) mainLoop() {
 					acc, _ := types.Sender(w.current.signer, tx)
 					txs[acc] = append(txs[acc], tx)
 				}
-				txset := types.NewTransactionsByPriceAndNonce(w.current.signer, txs, w.current.header.BaseFee)
+				txset := w.ordering.NewTransactionSet(w.current.signer, txs, w.current.header.BaseFee)
 				tcount := w.current.tcount
 				w.commitTransactions(txset, coinbase, nil)
 				// Only update the snapshot if any new transactons were added
@@ -789,12 +828,13 @@ func (w *worker) resultLoop() {
 			// Store circuit row consumption.
 			log.Trace(
 				"Worker write block row consumption",
-				"id", w.circuitCapacityChecker.ID,
+				"id", w.circuitCapacityChecker.ID(),
 				"number", block.Number(),
 				"hash", hash.String(),
 				"accRows", task.accRows,
 			)
 			rawdb.WriteBlockRowConsumption(w.eth.ChainDb(), hash, task.accRows)
+			circuitcapacitychecker.UpdateRowConsumptionMetrics(task.accRows, w.config.CircuitCapacityChecker.MaxRows)
 			// Commit block and state to database.
 			_, err := w.chain.WriteBlockWithState(block, receipts, logs, task.state, true)
 			if err != nil {
@@ -804,6 +844,7 @@ func (w *worker) resultLoop() {
 			}
 			log.Info("Successfully sealed new block", "number", block.Number(), "sealhash", sealhash, "hash", hash,
 				"elapsed", common.PrettyDuration(time.Since(task.createdAt)))
+			w.bundles.markIncluded(task.bundles, block)
 
 			// Broadcast the block and announce chain insertion event
 			w.mux.Post(core.NewMinedBlockEvent{Block: block})
@@ -864,6 +905,7 @@ func (w *worker) makeCurrent(parent *types.Block, header *types.Header) error {
 	env.blockSize = 0
 	env.l1TxCount = 0
 	env.nextL1MsgIndex = traceEnv.StartL1QueueIndex
+	env.txFingerprint = headerFingerprint(header)
 
 	// Swap out the old work with the new one, terminating any leftover prefetcher
 	// processes in the mean time and starting a new one.
@@ -932,6 +974,12 @@ func (w *worker) commitTransaction(tx *types.Transaction, coinbase common.Addres
 	var traces *types.BlockTrace
 	var err error
 
+	var (
+		cacheKey  common.Hash
+		fromCache bool
+		envState  *tracing.TraceEnvState
+	)
+
 	// do not do CCC checks on follower nodes
 	if w.isRunning() {
 		defer func(t0 time.Time) {
@@ -946,46 +994,21 @@ func (w *worker) commitTransaction(tx *types.Transaction, coinbase common.Addres
 			return nil, nil, core.ErrGasLimitReached
 		}
 
-		snap := w.current.state.Snapshot()
-
-		log.Trace(
-			"Worker apply ccc for tx",
-			"id", w.circuitCapacityChecker.ID,
-			"txHash", tx.Hash().Hex(),
-		)
-
-		// 1. we have to check circuit capacity before `core.ApplyTransaction`,
-		// because if the tx can be successfully executed but circuit capacity overflows, it will be inconvenient to revert.
-		// 2. even if we don't commit to the state during the tracing (which means `clearJournalAndRefund` is not called during the tracing),
-		// the `refund` value will still be correct, because:
-		// 2.1 when starting handling the first tx, `state.refund` is 0 by default,
-		// 2.2 after tracing, the state is either committed in `core.ApplyTransaction`, or reverted, so the `state.refund` can be cleared,
-		// 2.3 when starting handling the following txs, `state.refund` comes as 0
-		common.WithTimer(l2CommitTxTraceTimer, func() {
-			traces, err = w.current.traceEnv.GetBlockTrace(
-				types.NewBlockWithHeader(w.current.header).WithBody([]*types.Transaction{tx}, nil),
-			)
-		})
-		common.WithTimer(l2CommitTxTraceStateRevertTimer, func() {
-			// `w.current.traceEnv.State` & `w.current.state` share a same pointer to the state, so only need to revert `w.current.state`
-			// revert to snapshot for calling `core.ApplyMessage` again, (both `traceEnv.GetBlockTrace` & `core.ApplyTransaction` will call `core.ApplyMessage`)
-			w.current.state.RevertToSnapshot(snap)
-		})
-		if err != nil {
-			return nil, nil, err
-		}
-		common.WithTimer(l2CommitTxCCCTimer, func() {
-			accRows, err = w.circuitCapacityChecker.ApplyTransaction(traces)
-		})
-		if err != nil {
-			return nil, traces, err
+		cacheKey = rowConsumptionCacheKey(w.current.traceEnv.StorageTrace.RootBefore, w.current.txFingerprint, tx.Hash())
+		if cached := w.rowConsumptionCache.get(cacheKey); cached != nil {
+			// the tx was traced and checked on the same parent state after the same txs before, so reuse the results
+			// instead of tracing and checking it again. the ccc applies the cached traces when it checks the next uncached tx.
+			traces, accRows, fromCache = cached.traces, cached.accRows, true
+			w.current.traceEnv.SetState(cached.envState)
+			log.Trace("Worker reuse cached ccc result for tx", "txHash", tx.Hash().Hex(), "accRows", accRows)
+		} else {
+			if err = w.applyCCCBacklog(); err != nil {
+				return nil, nil, err
+			}
+			if traces, accRows, envState, err = w.traceAndApplyCCC(tx); err != nil {
+				return nil, traces, err
+			}
 		}
-		log.Trace(
-			"Worker apply ccc for tx result",
-			"id", w.circuitCapacityChecker.ID,
-			"txHash", tx.Hash().Hex(),
-			"accRows", accRows,
-		)
 	}
 
 	// create new snapshot for `core.ApplyTransaction`
@@ -1017,10 +1040,96 @@ func (w *worker) commitTransaction(tx *types.Transaction, coinbase common.Addres
 	w.current.txs = append(w.current.txs, tx)
 	w.current.receipts = append(w.current.receipts, receipt)
 	w.current.accRows = accRows
+	if fromCache {
+		w.current.cccBacklog = append(w.current.cccBacklog, traces)
+	} else if envState != nil {
+		w.rowConsumptionCache.add(cacheKey, traces, accRows, envState)
+	}
+	if traces != nil {
+		w.current.cccTraces = append(w.current.cccTraces, traces)
+	}
+	w.current.txFingerprint = nextTxFingerprint(w.current.txFingerprint, tx.Hash())
 
 	return receipt.Logs, traces, nil
 }
 
+// errCCCBacklog is returned if the ccc rejects the cached traces of packed txs that it accepted before.
+// The next tx was not checked at all then.
+var errCCCBacklog = errors.New("failed to apply cached tx traces to ccc")
+
+// applyCCCBacklog applies the cached traces of the packed txs to the ccc, so that it accumulates all packed txs before checking the next one.
+func (w *worker) applyCCCBacklog() error {
+	for len(w.current.cccBacklog) > 0 {
+		traces := w.current.cccBacklog[0]
+		var err error
+		common.WithTimer(l2CommitTxCCCTimer, func() {
+			_, err = w.circuitCapacityChecker.ApplyTransaction(traces)
+		})
+		if errors.Is(err, circuitcapacitychecker.ErrCheckerUnavailable) {
+			return err
+		}
+		if err != nil {
+			// the tx passed the ccc after the same txs before, so this is unexpected. the cached results
+			// cannot be trusted anymore, and the ccc no longer matches the packed txs.
+			log.Error("Worker failed to apply cached tx traces to ccc", "id", w.circuitCapacityChecker.ID(), "txHash", traces.Transactions[0].TxHash, "err", err)
+			w.rowConsumptionCache.entries.Purge()
+			return fmt.Errorf("%w, txHash: %s, err: %v", errCCCBacklog, traces.Transactions[0].TxHash, err)
+		}
+		w.current.cccBacklog = w.current.cccBacklog[1:]
+	}
+	return nil
+}
+
+// traceAndApplyCCC traces a tx on the current state and applies it to the ccc. Besides the traces and the accumulated
+// row consumption, it returns the trace env state after tracing the tx, to cache the results.
+func (w *worker) traceAndApplyCCC(tx *types.Transaction) (*types.BlockTrace, *types.RowConsumption, *tracing.TraceEnvState, error) {
+	var accRows *types.RowConsumption
+	var traces *types.BlockTrace
+	var err error
+
+	snap := w.current.state.Snapshot()
+
+	log.Trace(
+		"Worker apply ccc for tx",
+		"id", w.circuitCapacityChecker.ID(),
+		"txHash", tx.Hash().Hex(),
+	)
+
+	// 1. we have to check circuit capacity before `core.ApplyTransaction`,
+	// because if the tx can be successfully executed but circuit capacity overflows, it will be inconvenient to revert.
+	// 2. even if we don't commit to the state during the tracing (which means `clearJournalAndRefund` is not called during the tracing),
+	// the `refund` value will still be correct, because:
+	// 2.1 when starting handling the first tx, `state.refund` is 0 by default,
+	// 2.2 after tracing, the state is either committed in `core.ApplyTransaction`, or reverted, so the `state.refund` can be cleared,
+	// 2.3 when starting handling the following txs, `state.refund` comes as 0
+	common.WithTimer(l2CommitTxTraceTimer, func() {
+		traces, err = w.current.traceEnv.GetBlockTrace(
+			types.NewBlockWithHeader(w.current.header).WithBody([]*types.Transaction{tx}, nil),
+		)
+	})
+	common.WithTimer(l2CommitTxTraceStateRevertTimer, func() {
+		// `w.current.traceEnv.State` & `w.current.state` share a same pointer to the state, so only need to revert `w.current.state`
+		// revert to snapshot for calling `core.ApplyMessage` again, (both `traceEnv.GetBlockTrace` & `core.ApplyTransaction` will call `core.ApplyMessage`)
+		w.current.state.RevertToSnapshot(snap)
+	})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	common.WithTimer(l2CommitTxCCCTimer, func() {
+		accRows, err = w.circuitCapacityChecker.ApplyTransaction(traces)
+	})
+	if err != nil {
+		return traces, nil, nil, err
+	}
+	log.Trace(
+		"Worker apply ccc for tx result",
+		"id", w.circuitCapacityChecker.ID(),
+		"txHash", tx.Hash().Hex(),
+		"accRows", accRows,
+	)
+	return traces, accRows, w.current.traceEnv.State(), nil
+}
+
 func (w *worker) commitTransactions(txs types.OrderedTransactionSet, coinbase common.Address, interrupt *int32) (bool, bool) {
 	defer func(t0 time.Time) {
 		l2CommitTxsTimer.Update(time.Since(t0))
@@ -1131,11 +1240,7 @@ loop:
 			log.Info("Skipping L1 message", "queueIndex", queueIndex, "tx", tx.Hash().String(), "block", w.current.header.Number, "reason", "gas limit exceeded")
 			w.current.nextL1MsgIndex = queueIndex + 1
 			txs.Shift()
-			if w.config.StoreSkippedTxTraces {
-				rawdb.WriteSkippedTransaction(w.eth.ChainDb(), tx, traces, "gas limit exceeded", w.current.header.Number.Uint64(), nil)
-			} else {
-				rawdb.WriteSkippedTransaction(w.eth.ChainDb(), tx, nil, "gas limit exceeded", w.current.header.Number.Uint64(), nil)
-			}
+			w.skipTransaction(tx, traces, rawdb.SkipReason{Code: rawdb.SkipReasonL1MessageSkipped, Message: "gas limit exceeded"})
 			l1TxGasLimitExceededCounter.Inc(1)
 
 		case errors.Is(err, core.ErrGasLimitReached):
@@ -1167,6 +1272,7 @@ loop:
 			} else {
 				// only consider block size limit for L2 transactions
 				w.current.blockSize += tx.Size()
+				w.preconfirm(len(w.current.txs)-1, w.current.txFingerprint)
 			}
 
 		case errors.Is(err, core.ErrTxTypeNotSupported):
@@ -1200,18 +1306,26 @@ loop:
 				// 2. Circuit capacity limit reached in a block, and it's the first tx: skip the tx
 				log.Trace("Circuit capacity limit reached for a single tx", "tx", tx.Hash().String())
 
+				// record which sub-circuit the tx overflows, if the ccc reports it
+				reason := rawdb.SkipReason{Code: rawdb.SkipReasonRowConsumptionOverflow, Message: "row consumption overflow"}
+				if subCircuit := circuitcapacitychecker.OverflowSubCircuit(err); subCircuit != "" {
+					reason.SubCircuit = subCircuit
+					reason.Message = fmt.Sprintf("row consumption overflow in %s", subCircuit)
+					metrics.GetOrRegisterCounter("miner/skipped_txs/row_consumption_overflow/"+subCircuit, nil).Inc(1)
+				}
+
 				if tx.IsL1MessageTx() {
 					// Skip L1 message transaction,
 					// shift to the next from the account because we shouldn't skip the entire txs from the same account
 					txs.Shift()
 
 					queueIndex := tx.AsL1MessageTx().QueueIndex
-					log.Info("Skipping L1 message", "queueIndex", queueIndex, "tx", tx.Hash().String(), "block", w.current.header.Number, "reason", "first tx row consumption overflow")
+					log.Info("Skipping L1 message", "queueIndex", queueIndex, "tx", tx.Hash().String(), "block", w.current.header.Number, "reason", "first tx row consumption overflow", "err", err)
 					w.current.nextL1MsgIndex = queueIndex + 1
 					l1TxRowConsumptionOverflowCounter.Inc(1)
 				} else {
 					// Skip L2 transaction and all other transactions from the same sender account
-					log.Info("Skipping L2 message", "tx", tx.Hash().String(), "block", w.current.header.Number, "reason", "first tx row consumption overflow")
+					log.Info("Skipping L2 message", "tx", tx.Hash().String(), "block", w.current.header.Number, "reason", "first tx row consumption overflow", "err", err)
 					txs.Pop()
 					w.eth.TxPool().RemoveTx(tx.Hash(), true)
 					l2TxRowConsumptionOverflowCounter.Inc(1)
@@ -1219,17 +1333,28 @@ loop:
 
 				// Reset ccc so that we can process other transactions for this block
 				w.circuitCapacityChecker.Reset()
-				log.Trace("Worker reset ccc", "id", w.circuitCapacityChecker.ID)
+				log.Trace("Worker reset ccc", "id", w.circuitCapacityChecker.ID())
 				circuitCapacityOrBlockTimeReached = false
 
 				// Store skipped transaction in local db
-				if w.config.StoreSkippedTxTraces {
-					rawdb.WriteSkippedTransaction(w.eth.ChainDb(), tx, traces, "row consumption overflow", w.current.header.Number.Uint64(), nil)
-				} else {
-					rawdb.WriteSkippedTransaction(w.eth.ChainDb(), tx, nil, "row consumption overflow", w.current.header.Number.Uint64(), nil)
-				}
+				w.skipTransaction(tx, traces, reason)
 			}
 
+		case errors.Is(err, circuitcapacitychecker.ErrCheckerUnavailable):
+			// The ccc could not check the tx at all, which says nothing about the tx: neither skip it nor
+			// advance the L1 message queue. Seal the txs packed so far, or abort the block if there are none,
+			// the ccc is reset for the next block.
+			log.Warn("Circuit capacity checker unavailable, sealing block early", "tx", tx.Hash().String(), "block", w.current.header.Number, "txs", w.current.tcount, "err", err)
+			cccUnavailableCounter.Inc(1)
+			circuitCapacityOrBlockTimeReached = true
+			break loop
+
+		case errors.Is(err, errCCCBacklog):
+			// Same as above, the ccc failed on the packed txs before it checked this tx
+			log.Error("Circuit capacity checker rejected packed txs, sealing block early", "tx", tx.Hash().String(), "block", w.current.header.Number, "txs", w.current.tcount, "err", err)
+			circuitCapacityOrBlockTimeReached = true
+			break loop
+
 		case (errors.Is(err, circuitcapacitychecker.ErrUnknown) && tx.IsL1MessageTx()):
 			// Circuit capacity check: unknown circuit capacity checker error for L1MessageTx,
 			// shift to the next from the account because we shouldn't skip the entire txs from the same account
@@ -1238,11 +1363,7 @@ loop:
 			log.Info("Skipping L1 message", "queueIndex", queueIndex, "tx", tx.Hash().String(), "block", w.current.header.Number, "reason", "unknown row consumption error")
 			w.current.nextL1MsgIndex = queueIndex + 1
 			// TODO: propagate more info about the error from CCC
-			if w.config.StoreSkippedTxTraces {
-				rawdb.WriteSkippedTransaction(w.eth.ChainDb(), tx, traces, "unknown circuit capacity checker error", w.current.header.Number.Uint64(), nil)
-			} else {
-				rawdb.WriteSkippedTransaction(w.eth.ChainDb(), tx, nil, "unknown circuit capacity checker error", w.current.header.Number.Uint64(), nil)
-			}
+			w.skipTransaction(tx, traces, rawdb.SkipReason{Code: rawdb.SkipReasonUnknownCCCError, Message: "unknown circuit capacity checker error"})
 			l1TxCccUnknownErrCounter.Inc(1)
 
 			// Normally we would do `txs.Shift()` here.
@@ -1257,11 +1378,7 @@ loop:
 			log.Trace("Unknown circuit capacity checker error for L2MessageTx", "tx", tx.Hash().String())
 			log.Info("Skipping L2 message", "tx", tx.Hash().String(), "block", w.current.header.Number, "reason", "unknown row consumption error")
 			// TODO: propagate more info about the error from CCC
-			if w.config.StoreSkippedTxTraces {
-				rawdb.WriteSkippedTransaction(w.eth.ChainDb(), tx, traces, "unknown circuit capacity checker error", w.current.header.Number.Uint64(), nil)
-			} else {
-				rawdb.WriteSkippedTransaction(w.eth.ChainDb(), tx, nil, "unknown circuit capacity checker error", w.current.header.Number.Uint64(), nil)
-			}
+			w.skipTransaction(tx, traces, rawdb.SkipReason{Code: rawdb.SkipReasonUnknownCCCError, Message: "unknown circuit capacity checker error"})
 			l2TxCccUnknownErrCounter.Inc(1)
 
 			// Normally we would do `txs.Pop()` here.
@@ -1285,11 +1402,7 @@ loop:
 				queueIndex := tx.AsL1MessageTx().QueueIndex
 				log.Info("Skipping L1 message", "queueIndex", queueIndex, "tx", tx.Hash().String(), "block", w.current.header.Number, "reason", "strange error", "err", err)
 				w.current.nextL1MsgIndex = queueIndex + 1
-				if w.config.StoreSkippedTxTraces {
-					rawdb.WriteSkippedTransaction(w.eth.ChainDb(), tx, traces, fmt.Sprintf("strange error: %v", err), w.current.header.Number.Uint64(), nil)
-				} else {
-					rawdb.WriteSkippedTransaction(w.eth.ChainDb(), tx, nil, fmt.Sprintf("strange error: %v", err), w.current.header.Number.Uint64(), nil)
-				}
+				w.skipTransaction(tx, traces, rawdb.SkipReason{Code: rawdb.SkipReasonTraceFailure, Message: fmt.Sprintf("strange error: %v", err)})
 				l1TxStrangeErrCounter.Inc(1)
 			}
 			txs.Shift()
@@ -1319,8 +1432,182 @@ loop:
 	return false, circuitCapacityOrBlockTimeReached
 }
 
+// environmentSnapshot is the part of the environment that committing a bundle changes.
+type environmentSnapshot struct {
+	state         *state.StateDB
+	traceEnvState *tracing.TraceEnvState
+	tcount        int
+	blockSize     common.StorageSize
+	gas           uint64
+	gasUsed       uint64
+	txs           int
+	accRows       *types.RowConsumption
+	txFingerprint common.Hash
+	cccTraces     int
+}
+
+// snapshot copies the parts of the environment that committing a bundle changes.
+func (env *environment) snapshot() *environmentSnapshot {
+	return &environmentSnapshot{
+		state:         env.state.Copy(),
+		traceEnvState: env.traceEnv.State(),
+		tcount:        env.tcount,
+		blockSize:     env.blockSize,
+		gas:           env.gasPool.Gas(),
+		gasUsed:       env.header.GasUsed,
+		txs:           len(env.txs),
+		accRows:       env.accRows,
+		txFingerprint: env.txFingerprint,
+		cccTraces:     len(env.cccTraces),
+	}
+}
+
+// revert restores a snapshot. The caller has to reset the ccc, which then applies the traces of all packed
+// transactions again before checking the next one.
+func (env *environment) revert(snap *environmentSnapshot) {
+	// the state journal is cleared after each transaction, so restore the copy instead of reverting the journal
+	env.state = snap.state
+	env.traceEnv.SetStateDB(snap.state)
+	env.traceEnv.SetState(snap.traceEnvState)
+	env.tcount = snap.tcount
+	env.blockSize = snap.blockSize
+	env.gasPool = new(core.GasPool).AddGas(snap.gas)
+	env.header.GasUsed = snap.gasUsed
+	env.txs = env.txs[:snap.txs]
+	env.receipts = env.receipts[:snap.txs]
+	env.accRows = snap.accRows
+	env.txFingerprint = snap.txFingerprint
+	env.cccTraces = env.cccTraces[:snap.cccTraces]
+	env.cccBacklog = append([]*types.BlockTrace(nil), env.cccTraces...)
+}
+
+// commitBundles tries to commit each bundle as a unit, in submission order.
+func (w *worker) commitBundles(bundles []*Bundle, coinbase common.Address, interrupt *int32) (bool, bool) {
+	// Short circuit if current is nil
+	if w.current == nil {
+		return true, false
+	}
+	if w.current.gasPool == nil {
+		w.current.gasPool = new(core.GasPool).AddGas(w.current.header.GasLimit)
+	}
+
+	var coalescedLogs []*types.Log
+	cccUnavailable := false
+loop:
+	for _, bundle := range bundles {
+		if interrupt != nil && atomic.LoadInt32(interrupt) != commitInterruptNone {
+			return atomic.LoadInt32(interrupt) == commitInterruptNewHead, false
+		}
+		hash := bundle.Hash()
+		logs, err := w.commitBundle(bundle, coinbase)
+		switch {
+		case err == nil:
+			log.Debug("Including bundle", "hash", hash.String(), "txs", len(bundle.Txs), "block", w.current.header.Number)
+			coalescedLogs = append(coalescedLogs, logs...)
+
+		case errors.Is(err, core.ErrNonceTooLow),
+			errors.Is(err, core.ErrInsufficientFunds), errors.Is(err, core.ErrInsufficientFundsForTransfer),
+			errors.Is(err, core.ErrIntrinsicGas), errors.Is(err, errBundleTxReverted),
+			errors.Is(err, circuitcapacitychecker.ErrBlockRowConsumptionOverflow) && w.current.tcount == 0:
+			// A bundle transaction is invalid or reverts on the current state, or the bundle does not fit in an
+			// empty block. A nonce gap is not final, as earlier transactions of the sender may still be mined
+			log.Info("Dropping bundle", "hash", hash.String(), "block", w.current.header.Number, "err", err)
+			w.bundles.fail(hash, err)
+
+		case errors.Is(err, circuitcapacitychecker.ErrCheckerUnavailable), errors.Is(err, errCCCBacklog):
+			// Try again in the next block, and seal the txs packed so far, see commitTransactions
+			log.Warn("Circuit capacity checker failed before checking bundle, sealing block early", "bundle", hash.String(), "block", w.current.header.Number, "txs", w.current.tcount, "err", err)
+			if errors.Is(err, circuitcapacitychecker.ErrCheckerUnavailable) {
+				cccUnavailableCounter.Inc(1)
+			}
+			w.bundles.setError(hash, err)
+			cccUnavailable = true
+			break loop
+
+		default:
+			// Try again in the next block
+			log.Debug("Bundle not included", "hash", hash.String(), "block", w.current.header.Number, "err", err)
+			w.bundles.setError(hash, err)
+		}
+	}
+
+	if !w.isRunning() && len(coalescedLogs) > 0 {
+		// make a copy, see commitTransactions
+		cpy := make([]*types.Log, len(coalescedLogs))
+		for i, l := range coalescedLogs {
+			cpy[i] = new(types.Log)
+			*cpy[i] = *l
+		}
+		w.pendingLogsFeed.Send(cpy)
+	}
+	return false, cccUnavailable
+}
+
+// commitBundle commits all transactions of a bundle in order. If any of them fails, reverts without being allowed
+// to or does not fit in the block, it reverts all changes of the bundle.
+func (w *worker) commitBundle(bundle *Bundle, coinbase common.Address) ([]*types.Log, error) {
+	if !w.chainConfig.Scroll.IsValidTxCount(w.current.tcount + len(bundle.Txs)) {
+		return nil, errors.New("bundle exceeds the transaction count limit")
+	}
+	var size common.StorageSize
+	for _, tx := range bundle.Txs {
+		size += tx.Size()
+	}
+	if !w.chainConfig.Scroll.IsValidBlockSize(w.current.blockSize + size) {
+		return nil, errors.New("bundle exceeds the block size limit")
+	}
+
+	snap := w.current.snapshot()
+	var logs []*types.Log
+	for i, tx := range bundle.Txs {
+		w.current.state.SetTxContext(tx.Hash(), w.current.tcount)
+		txLogs, _, err := w.commitTransaction(tx, coinbase)
+		if err == nil && w.current.receipts[len(w.current.receipts)-1].Status == types.ReceiptStatusFailed && !bundle.canRevert(tx.Hash()) {
+			err = errBundleTxReverted
+		}
+		if err != nil {
+			w.current.revert(snap)
+			w.circuitCapacityChecker.Reset()
+			log.Trace("Worker reset ccc after reverting bundle", "id", w.circuitCapacityChecker.ID(), "backlog", len(w.current.cccBacklog))
+			bundleRevertedCounter.Inc(1)
+			return nil, fmt.Errorf("failed to commit bundle tx %d (%s), err: %w", i, tx.Hash().Hex(), err)
+		}
+		w.current.tcount++
+		w.current.blockSize += tx.Size()
+		logs = append(logs, txLogs...)
+	}
+	w.current.bundles = append(w.current.bundles, bundle.Hash())
+
+	// preconfirm the bundle once all its transactions are committed
+	fingerprint := snap.txFingerprint
+	for i, tx := range bundle.Txs {
+		fingerprint = nextTxFingerprint(fingerprint, tx.Hash())
+		w.preconfirm(snap.txs+i, fingerprint)
+	}
+	return logs, nil
+}
+
+// skipTransaction stores a transaction skipped in the current block and notifies its subscribers. The traces are
+// only stored if StoreSkippedTxTraces is enabled.
+func (w *worker) skipTransaction(tx *types.Transaction, traces *types.BlockTrace, reason rawdb.SkipReason) {
+	if !w.config.StoreSkippedTxTraces {
+		traces = nil
+	}
+	w.chain.WriteSkippedTransaction(tx, traces, reason, w.current.header.Number.Uint64(), nil)
+}
+
+// preconfirm emits the preconfirmation of the transaction at index in the current block, whose transactions up to
+// and including it have the given fingerprint. Only the sequencer preconfirms transactions.
+func (w *worker) preconfirm(index int, fingerprint common.Hash) {
+	if !w.isRunning() {
+		return
+	}
+	w.preconfirmer.preconfirm(w.current.header, w.current.txs[index], index, w.current.receipts[index], fingerprint)
+}
+
 func (w *worker) checkCurrentTxNumWithCCC(expected int) {
-	match, got, err := w.circuitCapacityChecker.CheckTxNum(expected)
+	// cached transactions that are not applied to the ccc yet are not counted by it
+	match, got, err := w.circuitCapacityChecker.CheckTxNum(expected - len(w.current.cccBacklog))
 	if err != nil {
 		log.Error("failed to CheckTxNum in ccc", "err", err)
 		return
@@ -1347,7 +1634,8 @@ func (w *worker) commitNewWork(interrupt *int32, noempty bool, timestamp int64)
 	tstart := time.Now()
 	parent := w.chain.CurrentBlock()
 	w.circuitCapacityChecker.Reset()
-	log.Trace("Worker reset ccc", "id", w.circuitCapacityChecker.ID)
+	log.Trace("Worker reset ccc", "id", w.circuitCapacityChecker.ID())
+	w.rowConsumptionCache.setParent(parent.Hash())
 
 	if parent.Time() >= uint64(timestamp) {
 		timestamp = int64(parent.Time() + 1)
@@ -1454,22 +1742,17 @@ func (w *worker) commitNewWork(interrupt *int32, noempty bool, timestamp int64)
 	tidyPendingStart := time.Now()
 	// Fill the block with all available pending transactions.
 	pending := w.eth.TxPool().PendingWithMax(false, w.config.MaxAccountsNum)
+	bundles := w.bundles.includable(header.Number.Uint64())
 	// Short circuit if there is no available pending transactions.
 	// But if we disable empty precommit already, ignore it. Since
 	// empty block is necessary to keep the liveness of the network.
-	if len(pending) == 0 && len(l1Messages) == 0 && atomic.LoadUint32(&w.noempty) == 0 {
+	if len(pending) == 0 && len(l1Messages) == 0 && len(bundles) == 0 && atomic.LoadUint32(&w.noempty) == 0 {
 		w.updateSnapshot()
 		l2CommitNewWorkTidyPendingTxTimer.UpdateSince(tidyPendingStart)
 		return
 	}
 	// Split the pending transactions into locals and remotes
-	localTxs, remoteTxs := make(map[common.Address]types.Transactions), pending
-	for _, account := range w.eth.TxPool().Locals() {
-		if txs := remoteTxs[account]; len(txs) > 0 {
-			delete(remoteTxs, account)
-			localTxs[account] = txs
-		}
-	}
+	localTxs, remoteTxs := splitLocals(w.ordering, pending, w.eth.TxPool().Locals())
 	l2CommitNewWorkTidyPendingTxTimer.UpdateSince(tidyPendingStart)
 
 	var skipCommit, circuitCapacityOrBlockTimeReached bool
@@ -1506,10 +1789,20 @@ func (w *worker) commitNewWork(interrupt *int32, noempty bool, timestamp int64)
 	}
 	l2CommitNewWorkPrioritizedTxCommitTimer.UpdateSince(prioritizedTxStart)
 
+	bundleStart := time.Now()
+	if len(bundles) > 0 && !circuitCapacityOrBlockTimeReached {
+		skipCommit, circuitCapacityOrBlockTimeReached = w.commitBundles(bundles, w.coinbase, interrupt)
+		if skipCommit {
+			l2CommitNewWorkBundleCommitTimer.UpdateSince(bundleStart)
+			return
+		}
+	}
+	l2CommitNewWorkBundleCommitTimer.UpdateSince(bundleStart)
+
 	remoteLocalStart := time.Now()
 	if len(localTxs) > 0 && !circuitCapacityOrBlockTimeReached {
 		localTxPriceAndNonceStart := time.Now()
-		txs := types.NewTransactionsByPriceAndNonce(w.current.signer, localTxs, header.BaseFee)
+		txs := w.ordering.NewTransactionSet(w.current.signer, localTxs, header.BaseFee)
 		l2CommitNewWorkLocalPriceAndNonceTimer.UpdateSince(localTxPriceAndNonceStart)
 
 		skipCommit, circuitCapacityOrBlockTimeReached = w.commitTransactions(txs, w.coinbase, interrupt)
@@ -1521,7 +1814,7 @@ func (w *worker) commitNewWork(interrupt *int32, noempty bool, timestamp int64)
 
 	if len(remoteTxs) > 0 && !circuitCapacityOrBlockTimeReached {
 		remoteTxPriceAndNonceStart := time.Now()
-		txs := types.NewTransactionsByPriceAndNonce(w.current.signer, remoteTxs, header.BaseFee)
+		txs := w.ordering.NewTransactionSet(w.current.signer, remoteTxs, header.BaseFee)
 		l2CommitNewWorkRemotePriceAndNonceTimer.UpdateSince(remoteTxPriceAndNonceStart)
 
 		// don't need to get `circuitCapacityOrBlockTimeReached` here because we don't have further `commitTransactions`
@@ -1554,7 +1847,7 @@ func (w *worker) commit(uncles []*types.Header, interval func(), update bool, st
 		(w.current.accRows == nil || len(*w.current.accRows) == 0) && w.isRunning() {
 		log.Trace(
 			"Worker apply ccc for empty block",
-			"id", w.circuitCapacityChecker.ID,
+			"id", w.circuitCapacityChecker.ID(),
 			"number", w.current.header.Number,
 			"hash", w.current.header.Hash().String(),
 		)
@@ -1579,7 +1872,7 @@ func (w *worker) commit(uncles []*types.Header, interval func(), update bool, st
 		}
 		log.Trace(
 			"Worker apply ccc for empty block result",
-			"id", w.circuitCapacityChecker.ID,
+			"id", w.circuitCapacityChecker.ID(),
 			"number", w.current.header.Number,
 			"hash", w.current.header.Hash().String(),
 			"accRows", accRows,
@@ -1599,7 +1892,7 @@ func (w *worker) commit(uncles []*types.Header, interval func(), update bool, st
 			interval()
 		}
 		select {
-		case w.taskCh <- &task{receipts: receipts, state: s, block: block, createdAt: time.Now(), accRows: w.current.accRows, nextL1MsgIndex: w.current.nextL1MsgIndex}:
+		case w.taskCh <- &task{receipts: receipts, state: s, block: block, createdAt: time.Now(), accRows: w.current.accRows, nextL1MsgIndex: w.current.nextL1MsgIndex, bundles: w.current.bundles}:
 			w.unconfirmed.Shift(block.NumberU64() - 1)
 			log.Info("Commit new mining work", "number", block.Number(), "sealhash", w.engine.SealHash(block.Header()),
 				"uncles", len(uncles), "txs", w.current.tcount,
@@ -25,6 +25,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/scroll-tech/go-ethereum/accounts"
 	"github.com/scroll-tech/go-ethereum/common"
@@ -1320,3 +1321,251 @@ func TestSealBlockAfterCliquePeriod(t *testing.T) {
 		t.Fatalf("timeout")
 	}
 }
+
+// newBundleTestWorker creates a worker without pending transactions, and sends the tasks with transactions to
+// the returned channel instead of sealing them.
+func newBundleTestWorker(t *testing.T) (*worker, <-chan *task) {
+	engine := ethash.NewFaker()
+	t.Cleanup(func() { engine.Close() })
+	b := newTestWorkerBackend(t, ethashChainConfig, engine, rawdb.NewMemoryDatabase(), 0)
+	w := newWorker(testConfig, ethashChainConfig, engine, b, new(event.TypeMux), nil, false)
+	w.setEtherbase(testBankAddress)
+	t.Cleanup(w.close)
+
+	taskCh := make(chan *task, 1)
+	w.newTaskHook = func(task *task) {
+		if len(task.receipts) > 0 {
+			select {
+			case taskCh <- task:
+			default:
+			}
+		}
+	}
+	w.skipSealHook = func(task *task) bool { return true }
+	return w, taskCh
+}
+
+func newBundleTestTx(nonce uint64, value int64, data []byte) *types.Transaction {
+	var to *common.Address
+	if data == nil {
+		to = &testUserAddress
+	}
+	return types.MustSignNewTx(testBankKey, types.LatestSigner(ethashChainConfig), &types.LegacyTx{
+		Nonce:    nonce,
+		To:       to,
+		Value:    big.NewInt(value),
+		Gas:      100000,
+		GasPrice: big.NewInt(params.InitialBaseFee),
+		Data:     data,
+	})
+}
+
+func TestBundleRevertsAllChanges(t *testing.T) {
+	assert := assert.New(t)
+	w, taskCh := newBundleTestWorker(t)
+
+	// a contract creation whose init code reverts
+	revert := newBundleTestTx(1, 0, common.FromHex("0x60006000fd"))
+	failing := &Bundle{Txs: types.Transactions{newBundleTestTx(0, 2000, nil), revert}}
+	reverting := &Bundle{Txs: types.Transactions{newBundleTestTx(0, 1000, nil), revert}, RevertingTxHashes: []common.Hash{revert.Hash()}}
+
+	signer := types.LatestSigner(ethashChainConfig)
+	for _, bundle := range []*Bundle{failing, reverting} {
+		_, err := w.bundles.add(bundle, signer, 0)
+		require.NoError(t, err)
+	}
+	w.start()
+
+	select {
+	case task := <-taskCh:
+		// the transfer of the failing bundle is reverted, so the same nonce is used by the next bundle
+		if assert.Len(task.receipts, 2) {
+			assert.Equal(reverting.Txs[0].Hash(), task.block.Transactions()[0].Hash())
+			assert.Equal(types.ReceiptStatusFailed, task.receipts[1].Status)
+		}
+		assert.Equal(big.NewInt(1000), task.state.GetBalance(testUserAddress))
+		assert.Equal(uint64(2), task.state.GetNonce(testBankAddress))
+		assert.Equal([]common.Hash{reverting.Hash()}, task.bundles)
+	case <-time.After(3 * time.Second):
+		t.Fatal("timeout")
+	}
+
+	// the failing bundle is dropped
+	status := w.bundles.status(failing.Hash())
+	if assert.NotNil(status) {
+		assert.Equal(BundleFailed, status.State)
+		assert.Contains(status.Error, errBundleTxReverted.Error())
+	}
+}
+
+func TestBundleNonceGap(t *testing.T) {
+	assert := assert.New(t)
+	w, taskCh := newBundleTestWorker(t)
+
+	// the bundle depends on a transaction of the sender that is not mined yet
+	gapped := &Bundle{Txs: types.Transactions{newBundleTestTx(5, 2000, nil)}, MinBlock: 1}
+	fitting := &Bundle{Txs: types.Transactions{newBundleTestTx(0, 1000, nil)}}
+
+	signer := types.LatestSigner(ethashChainConfig)
+	for _, bundle := range []*Bundle{gapped, fitting} {
+		_, err := w.bundles.add(bundle, signer, 0)
+		require.NoError(t, err)
+	}
+	w.start()
+
+	select {
+	case task := <-taskCh:
+		assert.Equal([]common.Hash{fitting.Hash()}, task.bundles)
+	case <-time.After(3 * time.Second):
+		t.Fatal("timeout")
+	}
+
+	// so it is tried again in the next blocks
+	status := w.bundles.status(gapped.Hash())
+	if assert.NotNil(status) {
+		assert.Equal(BundlePending, status.State)
+		assert.Contains(status.Error, core.ErrNonceTooHigh.Error())
+	}
+}
+
+func TestBundleRowConsumptionOverflow(t *testing.T) {
+	assert := assert.New(t)
+	w, taskCh := newBundleTestWorker(t)
+
+	overflowing := &Bundle{Txs: types.Transactions{newBundleTestTx(0, 2000, nil), newBundleTestTx(1, 2000, nil)}}
+	fitting := &Bundle{Txs: types.Transactions{newBundleTestTx(0, 1000, nil)}}
+
+	signer := types.LatestSigner(ethashChainConfig)
+	for _, bundle := range []*Bundle{overflowing, fitting} {
+		_, err := w.bundles.add(bundle, signer, 0)
+		require.NoError(t, err)
+	}
+	w.getCCC().ScheduleError(2, circuitcapacitychecker.ErrBlockRowConsumptionOverflow)
+	w.start()
+
+	select {
+	case task := <-taskCh:
+		if assert.Len(task.receipts, 1) {
+			assert.Equal(fitting.Txs[0].Hash(), task.block.Transactions()[0].Hash())
+		}
+		assert.Equal(big.NewInt(1000), task.state.GetBalance(testUserAddress))
+	case <-time.After(3 * time.Second):
+		t.Fatal("timeout")
+	}
+
+	// the bundle does not fit in an empty block, so it is dropped
+	status := w.bundles.status(overflowing.Hash())
+	if assert.NotNil(status) {
+		assert.Equal(BundleFailed, status.State)
+	}
+}
+
+func TestCircuitCapacityCheckerUnavailable(t *testing.T) {
+	assert := assert.New(t)
+
+	chainConfig := new(params.ChainConfig)
+	*chainConfig = *ethashChainConfig
+	chainConfig.Scroll.L1Config = &params.L1Config{NumL1MessagesPerBlock: 10}
+	db := rawdb.NewMemoryDatabase()
+	msgs := []types.L1MessageTx{
+		{QueueIndex: 0, Gas: 21016, To: &common.Address{1}, Data: []byte{0x01}, Sender: common.Address{2}},
+		{QueueIndex: 1, Gas: 21016, To: &common.Address{1}, Data: []byte{0x01}, Sender: common.Address{2}},
+	}
+	rawdb.WriteL1Messages(db, msgs)
+
+	engine := ethash.NewFaker()
+	defer engine.Close()
+	w, _ := newTestWorker(t, chainConfig, engine, db, 0)
+	defer w.close()
+	taskCh := make(chan *task, 1)
+	w.newTaskHook = func(task *task) {
+		if len(task.block.Transactions()) > 0 {
+			select {
+			case taskCh <- task:
+			default:
+			}
+		}
+	}
+	w.skipSealHook = func(task *task) bool { return true }
+
+	w.getCCC().ScheduleError(2, circuitcapacitychecker.ErrCheckerUnavailable)
+	w.start()
+
+	// the block is sealed with the L1 message checked before the ccc became unavailable
+	select {
+	case task := <-taskCh:
+		if assert.Len(task.block.Transactions(), 1) {
+			assert.Equal(uint64(0), task.block.Transactions()[0].AsL1MessageTx().QueueIndex)
+		}
+		assert.Equal(uint64(1), task.nextL1MsgIndex)
+	case <-time.After(3 * time.Second):
+		t.Fatal("timeout")
+	}
+
+	// the L1 message the ccc could not check is not skipped
+	assert.Nil(rawdb.ReadSkippedTransaction(db, types.NewTx(&msgs[1]).Hash()))
+}
+
+func TestCCCBacklogFailure(t *testing.T) {
+	w, _ := newBundleTestWorker(t)
+
+	parent := w.chain.CurrentBlock()
+	header := &types.Header{
+		ParentHash: parent.Hash(),
+		Number:     big.NewInt(1),
+		GasLimit:   parent.GasLimit(),
+		Time:       parent.Time() + 1,
+		Difficulty: big.NewInt(1),
+		BaseFee:    big.NewInt(params.InitialBaseFee),
+	}
+	require.NoError(t, w.makeCurrent(parent, header))
+	backlog := &types.BlockTrace{Transactions: []*types.TransactionData{{TxHash: common.HexToHash("0x01").String()}}}
+	w.current.cccBacklog = []*types.BlockTrace{backlog}
+
+	// a ccc that rejects the cached traces it accepted before is not blamed on the next tx
+	w.getCCC().ScheduleError(1, circuitcapacitychecker.ErrUnknown)
+	err := w.applyCCCBacklog()
+	assert.ErrorIs(t, err, errCCCBacklog)
+	assert.NotErrorIs(t, err, circuitcapacitychecker.ErrUnknown)
+	assert.Len(t, w.current.cccBacklog, 1)
+
+	w.getCCC().ScheduleError(1, circuitcapacitychecker.ErrCheckerUnavailable)
+	assert.ErrorIs(t, w.applyCCCBacklog(), circuitcapacitychecker.ErrCheckerUnavailable)
+
+	require.NoError(t, w.applyCCCBacklog())
+	assert.Empty(t, w.current.cccBacklog)
+}
+
+func TestSkippedTransactionEvent(t *testing.T) {
+	assert := assert.New(t)
+	w, _ := newBundleTestWorker(t)
+
+	skipped := make(chan core.SkippedTxEvent, 1)
+	sub := w.chain.SubscribeSkippedTxEvent(skipped)
+	defer sub.Unsubscribe()
+
+	tx := newBundleTestTx(0, 1000, nil)
+	overflow := &circuitcapacitychecker.RowConsumptionOverflowError{RowConsumption: types.RowConsumption{{Name: "evm", RowNumber: 2_000_000}}}
+	w.getCCC().ScheduleError(1, overflow)
+	require.NoError(t, w.eth.TxPool().AddLocal(tx))
+	w.start()
+
+	select {
+	case ev := <-skipped:
+		assert.Equal(tx.Hash(), ev.Tx.Hash())
+		assert.Equal(testBankAddress, ev.Sender)
+		assert.Equal(rawdb.SkipReasonRowConsumptionOverflow, ev.Reason.Code)
+		assert.Equal("evm", ev.Reason.SubCircuit)
+		assert.Equal(uint64(1), ev.BlockNumber)
+		assert.Nil(ev.BlockHash)
+	case <-time.After(3 * time.Second):
+		t.Fatal("timeout")
+	}
+
+	stx := rawdb.ReadSkippedTransaction(w.eth.ChainDb(), tx.Hash())
+	if assert.NotNil(stx) {
+		assert.Equal(rawdb.SkipReasonRowConsumptionOverflow, stx.ReasonCode)
+		assert.Equal("evm", stx.SubCircuit)
+		assert.Equal("row consumption overflow in evm", stx.Reason)
+	}
+}
@@ -25,6 +25,7 @@ import (
 	"runtime"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/scroll-tech/go-ethereum/common"
 	"github.com/scroll-tech/go-ethereum/crypto"
@@ -191,12 +192,21 @@ type Config struct {
 	// AllowUnprotectedTxs allows non EIP-155 protected transactions to be send over RPC.
 	AllowUnprotectedTxs bool `toml:",omitempty"`
 
-	// Endpoint of L1 HTTP-RPC server
+	// Endpoint of L1 HTTP-RPC or WebSocket server, or a comma-separated list of endpoints in order of preference
 	L1Endpoint string `toml:",omitempty"`
 	// Number of confirmations on L1 needed for finalization
 	L1Confirmations rpc.BlockNumber `toml:",omitempty"`
 	// L1 bridge deployment block number
 	L1DeploymentBlock uint64 `toml:",omitempty"`
+	// Endpoint of L1 beacon node API, used to retrieve blob sidecars
+	L1BeaconEndpoint string `toml:",omitempty"`
+	// Delay after which slow L1 requests are also sent to the next L1 endpoint, 0 disables hedged requests
+	L1HedgeDelay time.Duration `toml:",omitempty"`
+	// Only accept L1 logs if two L1 endpoints return the same logs
+	L1CrossCheckLogs bool `toml:",omitempty"`
+	// Trusted L1 block that anchors the verification of L1 messages, verification is disabled if the hash is empty
+	L1CheckpointNumber uint64      `toml:",omitempty"`
+	L1CheckpointHash   common.Hash `toml:",omitempty"`
 }
 
 // IPCEndpoint resolves an IPC endpoint based on a configured value, taking into
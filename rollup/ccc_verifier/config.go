@@ -0,0 +1,14 @@
+package ccc_verifier
+
+// Config contains the options of the row consumption verifier.
+type Config struct {
+	Workers   int    // number of blocks verified concurrently
+	QueueSize int    // number of imported blocks waiting for verification, further blocks are dropped
+	Sequencer string // RPC endpoint of the sequencer to fetch the row consumption from, the local database if empty
+}
+
+// DefaultConfig contains the default row consumption verifier options.
+var DefaultConfig = Config{
+	Workers:   2,
+	QueueSize: 1024,
+}
@@ -0,0 +1,351 @@
+package ccc_verifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/scroll-tech/go-ethereum"
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/consensus"
+	"github.com/scroll-tech/go-ethereum/core"
+	"github.com/scroll-tech/go-ethereum/core/rawdb"
+	"github.com/scroll-tech/go-ethereum/core/state"
+	"github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/ethdb"
+	"github.com/scroll-tech/go-ethereum/event"
+	"github.com/scroll-tech/go-ethereum/log"
+	"github.com/scroll-tech/go-ethereum/metrics"
+	"github.com/scroll-tech/go-ethereum/params"
+	"github.com/scroll-tech/go-ethereum/rollup/circuitcapacitychecker"
+	"github.com/scroll-tech/go-ethereum/rpc"
+)
+
+const (
+	// maxRecentMismatches is the number of the most recent mismatches kept for the RPC API.
+	maxRecentMismatches = 64
+
+	// defaultReferenceTimeout is the timeout of fetching a block's row consumption from the sequencer.
+	defaultReferenceTimeout = 10 * time.Second
+)
+
+var (
+	verifiedCounter   = metrics.NewRegisteredCounter("rollup/ccc_verifier/verified", nil)
+	mismatchCounter   = metrics.NewRegisteredCounter("rollup/ccc_verifier/mismatch", nil)
+	unverifiedCounter = metrics.NewRegisteredCounter("rollup/ccc_verifier/unverified", nil)
+	failedCounter     = metrics.NewRegisteredCounter("rollup/ccc_verifier/failed", nil)
+	droppedCounter    = metrics.NewRegisteredCounter("rollup/ccc_verifier/dropped", nil)
+	queueGauge        = metrics.NewRegisteredGauge("rollup/ccc_verifier/queue", nil)
+	verifyTimer       = metrics.NewRegisteredTimer("rollup/ccc_verifier/verify", nil)
+)
+
+// BlockChain is the subset of core.BlockChain used by the verifier.
+type BlockChain interface {
+	core.ChainContext
+	Config() *params.ChainConfig
+	GetBlock(hash common.Hash, number uint64) *types.Block
+	StateAt(root common.Hash) (*state.StateDB, error)
+	SubscribeChainEvent(ch chan<- core.ChainEvent) event.Subscription
+}
+
+// Tracer creates the trace of a block, see tracing.TracerWrapper.
+type Tracer interface {
+	CreateTraceEnvAndGetBlockTrace(*params.ChainConfig, core.ChainContext, consensus.Engine, ethdb.Database, *state.StateDB, *types.Block, *types.Block, bool) (*types.BlockTrace, error)
+}
+
+// Sequencer returns the row consumption of blocks computed by the sequencer.
+type Sequencer interface {
+	BlockRowConsumption(ctx context.Context, hash common.Hash) (*types.RowConsumption, error)
+}
+
+// rpcSequencer fetches the row consumption of blocks from the scroll_getBlockByHash API of the sequencer.
+type rpcSequencer struct {
+	client *rpc.Client
+}
+
+// DialSequencer connects to the RPC endpoint of the sequencer.
+func DialSequencer(endpoint string) (Sequencer, error) {
+	client, err := rpc.Dial(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	return &rpcSequencer{client: client}, nil
+}
+
+func (s *rpcSequencer) BlockRowConsumption(ctx context.Context, hash common.Hash) (*types.RowConsumption, error) {
+	var result *struct {
+		RowConsumption *types.RowConsumption `json:"rowConsumption"`
+	}
+	if err := s.client.CallContext(ctx, &result, "scroll_getBlockByHash", hash, false); err != nil {
+		return nil, err
+	}
+	if result == nil {
+		return nil, ethereum.NotFound
+	}
+	return result.RowConsumption, nil
+}
+
+// Mismatch is a block whose recomputed row consumption differs from the sequencer's.
+type Mismatch struct {
+	BlockNumber uint64                `json:"blockNumber"`
+	BlockHash   common.Hash           `json:"blockHash"`
+	Expected    *types.RowConsumption `json:"expected"`
+	Actual      *types.RowConsumption `json:"actual"`
+	Error       string                `json:"error,omitempty"` // set if the circuit capacity checker rejected the block
+}
+
+// Status is the progress of the verifier.
+type Status struct {
+	Verified          uint64     `json:"verified"`   // number of blocks whose row consumption matched
+	Mismatched        uint64     `json:"mismatched"` // number of blocks whose row consumption did not match
+	Unverified        uint64     `json:"unverified"` // number of blocks without row consumption to compare with
+	Failed            uint64     `json:"failed"`     // number of blocks that could not be verified, e.g. because the state was pruned
+	Dropped           uint64     `json:"dropped"`    // number of blocks dropped because the queue was full
+	Pending           int        `json:"pending"`    // number of blocks waiting for verification
+	LastVerifiedBlock uint64     `json:"lastVerifiedBlock"`
+	Mismatches        []Mismatch `json:"mismatches"` // the most recent mismatches, latest first
+}
+
+// Verifier recomputes the row consumption of imported blocks in the background and compares it with
+// the row consumption computed by the sequencer, so that follower nodes, which do not run the circuit
+// capacity checker during import, detect blocks that the sequencer accounted for incorrectly.
+// Blocks are verified by a pool of workers and never block the import: if the workers fall behind,
+// further blocks are dropped.
+type Verifier struct {
+	ctx       context.Context
+	cancel    context.CancelFunc
+	wg        sync.WaitGroup
+	config    Config
+	db        ethdb.Database
+	bc        BlockChain
+	tracer    Tracer
+	sequencer Sequencer // nil if the row consumption is read from the local database
+
+	newChecker func() circuitcapacitychecker.Checker
+	queue      chan *types.Block
+
+	mu     sync.Mutex
+	status Status
+}
+
+// NewVerifier creates a new row consumption verifier. If sequencer is nil, the row consumption is read
+// from the local database.
+func NewVerifier(ctx context.Context, config Config, ccc circuitcapacitychecker.RemoteConfig, db ethdb.Database, bc BlockChain, tracer Tracer, sequencer Sequencer) (*Verifier, error) {
+	if config.Workers <= 0 {
+		return nil, fmt.Errorf("invalid number of row consumption verifier workers: %d", config.Workers)
+	}
+	if config.QueueSize <= 0 {
+		return nil, fmt.Errorf("invalid row consumption verifier queue size: %d", config.QueueSize)
+	}
+	ctx, cancel := context.WithCancel(ctx)
+
+	return &Verifier{
+		ctx:        ctx,
+		cancel:     cancel,
+		config:     config,
+		db:         db,
+		bc:         bc,
+		tracer:     tracer,
+		sequencer:  sequencer,
+		newChecker: func() circuitcapacitychecker.Checker { return circuitcapacitychecker.New(ccc, true) },
+		queue:      make(chan *types.Block, config.QueueSize),
+	}, nil
+}
+
+func (v *Verifier) Start() {
+	if v == nil {
+		return
+	}
+
+	log.Info("Starting row consumption verifier", "workers", v.config.Workers, "queue", v.config.QueueSize, "sequencer", v.config.Sequencer)
+
+	chainCh := make(chan core.ChainEvent, 16)
+	sub := v.bc.SubscribeChainEvent(chainCh)
+
+	v.wg.Add(1)
+	go func() {
+		defer v.wg.Done()
+		defer sub.Unsubscribe()
+
+		for {
+			select {
+			case <-v.ctx.Done():
+				return
+			case err := <-sub.Err():
+				if err != nil {
+					log.Error("Row consumption verifier chain event subscription failed", "err", err)
+				}
+				return
+			case ev := <-chainCh:
+				v.enqueue(ev.Block)
+			}
+		}
+	}()
+
+	for i := 0; i < v.config.Workers; i++ {
+		v.wg.Add(1)
+		go func() {
+			defer v.wg.Done()
+			v.work()
+		}()
+	}
+}
+
+func (v *Verifier) Stop() {
+	if v == nil {
+		return
+	}
+
+	log.Info("Stopping row consumption verifier")
+
+	if v.cancel != nil {
+		v.cancel()
+	}
+	v.wg.Wait()
+}
+
+// Status returns the progress of the verifier.
+func (v *Verifier) Status() *Status {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	status := v.status
+	status.Pending = len(v.queue)
+	status.Mismatches = append([]Mismatch(nil), v.status.Mismatches...)
+	return &status
+}
+
+// enqueue queues a block for verification, or drops it if the queue is full.
+func (v *Verifier) enqueue(block *types.Block) {
+	if block == nil || block.NumberU64() == 0 {
+		return
+	}
+	select {
+	case v.queue <- block:
+		queueGauge.Update(int64(len(v.queue)))
+	default:
+		log.Debug("Row consumption verifier queue full, dropping block", "number", block.NumberU64(), "hash", block.Hash().Hex())
+		droppedCounter.Inc(1)
+		v.mu.Lock()
+		v.status.Dropped++
+		v.mu.Unlock()
+	}
+}
+
+// work verifies the queued blocks with its own circuit capacity checker.
+func (v *Verifier) work() {
+	ccc := v.newChecker()
+	if remote, ok := ccc.(*circuitcapacitychecker.RemoteCircuitCapacityChecker); ok {
+		defer remote.Close()
+	}
+
+	for {
+		select {
+		case <-v.ctx.Done():
+			return
+		case block := <-v.queue:
+			queueGauge.Update(int64(len(v.queue)))
+			if err := v.verify(ccc, block); err != nil {
+				log.Warn("Failed to verify block row consumption", "number", block.NumberU64(), "hash", block.Hash().Hex(), "err", err)
+				failedCounter.Inc(1)
+				v.mu.Lock()
+				v.status.Failed++
+				v.mu.Unlock()
+			}
+		}
+	}
+}
+
+// verify recomputes the row consumption of a block and compares it with the sequencer's.
+func (v *Verifier) verify(ccc circuitcapacitychecker.Checker, block *types.Block) error {
+	defer func(t0 time.Time) {
+		verifyTimer.Update(time.Since(t0))
+	}(time.Now())
+
+	expected, err := v.expectedRowConsumption(block)
+	if err != nil {
+		return fmt.Errorf("failed to get row consumption, err: %w", err)
+	}
+	if expected == nil {
+		log.Trace("No row consumption to verify block against", "number", block.NumberU64(), "hash", block.Hash().Hex())
+		unverifiedCounter.Inc(1)
+		v.mu.Lock()
+		v.status.Unverified++
+		v.mu.Unlock()
+		return nil
+	}
+
+	parent := v.bc.GetBlock(block.ParentHash(), block.NumberU64()-1)
+	if parent == nil {
+		return errors.New("parent block not found")
+	}
+	statedb, err := v.bc.StateAt(parent.Root())
+	if err != nil {
+		return fmt.Errorf("failed to get parent state, err: %w", err)
+	}
+	traces, err := v.tracer.CreateTraceEnvAndGetBlockTrace(v.bc.Config(), v.bc, v.bc.Engine(), v.db, statedb, parent, block, true)
+	if err != nil {
+		return fmt.Errorf("failed to trace block, err: %w", err)
+	}
+
+	ccc.Reset()
+	actual, err := ccc.ApplyBlock(traces)
+	if err != nil && !errors.Is(err, circuitcapacitychecker.ErrBlockRowConsumptionOverflow) {
+		return fmt.Errorf("failed to apply block to circuit capacity checker, err: %w", err)
+	}
+	if err == nil && equalRowConsumption(*expected, *actual) {
+		verifiedCounter.Inc(1)
+		v.mu.Lock()
+		v.status.Verified++
+		v.status.LastVerifiedBlock = block.NumberU64()
+		v.mu.Unlock()
+		return nil
+	}
+
+	mismatch := Mismatch{BlockNumber: block.NumberU64(), BlockHash: block.Hash(), Expected: expected, Actual: actual}
+	if err != nil {
+		mismatch.Error = err.Error()
+	}
+	log.Error("Block row consumption mismatch", "number", block.NumberU64(), "hash", block.Hash().Hex(), "expected", expected, "actual", actual, "err", err)
+	mismatchCounter.Inc(1)
+	v.mu.Lock()
+	v.status.Mismatched++
+	v.status.LastVerifiedBlock = block.NumberU64()
+	v.status.Mismatches = append([]Mismatch{mismatch}, v.status.Mismatches...)
+	if len(v.status.Mismatches) > maxRecentMismatches {
+		v.status.Mismatches = v.status.Mismatches[:maxRecentMismatches]
+	}
+	v.mu.Unlock()
+	return nil
+}
+
+// expectedRowConsumption returns the row consumption of a block computed by the sequencer,
+// or nil if it is not known.
+func (v *Verifier) expectedRowConsumption(block *types.Block) (*types.RowConsumption, error) {
+	if v.sequencer == nil {
+		return rawdb.ReadBlockRowConsumption(v.db, block.Hash()), nil
+	}
+
+	ctx, cancel := context.WithTimeout(v.ctx, defaultReferenceTimeout)
+	defer cancel()
+	return v.sequencer.BlockRowConsumption(ctx, block.Hash())
+}
+
+// equalRowConsumption returns whether two row consumptions have the same rows for each sub-circuit.
+func equalRowConsumption(a, b types.RowConsumption) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	rows := make(map[string]uint64, len(a))
+	for _, usage := range a {
+		rows[usage.Name] = usage.RowNumber
+	}
+	for _, usage := range b {
+		if number, ok := rows[usage.Name]; !ok || number != usage.RowNumber {
+			return false
+		}
+	}
+	return true
+}
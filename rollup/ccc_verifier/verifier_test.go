@@ -0,0 +1,210 @@
+package ccc_verifier
+
+import (
+	"context"
+	"math/big"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scroll-tech/go-ethereum"
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/consensus"
+	"github.com/scroll-tech/go-ethereum/core"
+	"github.com/scroll-tech/go-ethereum/core/rawdb"
+	"github.com/scroll-tech/go-ethereum/core/state"
+	"github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/ethdb"
+	"github.com/scroll-tech/go-ethereum/event"
+	"github.com/scroll-tech/go-ethereum/params"
+	"github.com/scroll-tech/go-ethereum/rollup/circuitcapacitychecker"
+	"github.com/scroll-tech/go-ethereum/rpc"
+)
+
+// testChain is an in-memory chain that sends a chain event for each added block.
+type testChain struct {
+	mu      sync.Mutex
+	blocks  map[common.Hash]*types.Block
+	genesis *types.Block
+	db      ethdb.Database
+	feed    event.Feed
+}
+
+func newTestChain(db ethdb.Database) *testChain {
+	genesis := types.NewBlockWithHeader(&types.Header{Number: big.NewInt(0)})
+	return &testChain{blocks: map[common.Hash]*types.Block{genesis.Hash(): genesis}, genesis: genesis, db: db}
+}
+
+func (bc *testChain) Engine() consensus.Engine                                { return nil }
+func (bc *testChain) GetHeader(hash common.Hash, number uint64) *types.Header { return nil }
+func (bc *testChain) Config() *params.ChainConfig                             { return params.TestChainConfig }
+func (bc *testChain) StateAt(root common.Hash) (*state.StateDB, error)        { return nil, nil }
+func (bc *testChain) GetBlock(hash common.Hash, number uint64) *types.Block {
+	bc.mu.Lock()
+	defer bc.mu.Unlock()
+	return bc.blocks[hash]
+}
+
+func (bc *testChain) SubscribeChainEvent(ch chan<- core.ChainEvent) event.Subscription {
+	return bc.feed.Subscribe(ch)
+}
+
+// addBlock adds a block on top of parent, stores its row consumption if rc is not nil and sends its
+// chain event, as the sequencer does when it imports a block.
+func (bc *testChain) addBlock(parent *types.Block, rc *types.RowConsumption) *types.Block {
+	block := types.NewBlockWithHeader(&types.Header{ParentHash: parent.Hash(), Number: new(big.Int).Add(parent.Number(), common.Big1)})
+	bc.mu.Lock()
+	bc.blocks[block.Hash()] = block
+	bc.mu.Unlock()
+	if rc != nil {
+		rawdb.WriteBlockRowConsumption(bc.db, block.Hash(), rc)
+	}
+	bc.feed.Send(core.ChainEvent{Block: block, Hash: block.Hash()})
+	return block
+}
+
+// testTracer returns an empty trace once a value is sent to gate, if it is not nil.
+type testTracer struct {
+	gate chan struct{}
+}
+
+func (tr *testTracer) CreateTraceEnvAndGetBlockTrace(*params.ChainConfig, core.ChainContext, consensus.Engine, ethdb.Database, *state.StateDB, *types.Block, *types.Block, bool) (*types.BlockTrace, error) {
+	if tr.gate != nil {
+		<-tr.gate
+	}
+	return &types.BlockTrace{}, nil
+}
+
+// testSequencer returns the row consumption of the blocks it knows.
+type testSequencer map[common.Hash]*types.RowConsumption
+
+func (s testSequencer) BlockRowConsumption(ctx context.Context, hash common.Hash) (*types.RowConsumption, error) {
+	return s[hash], nil
+}
+
+// mockRows is the row consumption of a block computed by the mock circuit capacity checker.
+var mockRows = types.RowConsumption{{Name: "mock", RowNumber: 2}}
+
+func newTestVerifier(t *testing.T, config Config, db ethdb.Database, bc BlockChain, tracer Tracer, sequencer Sequencer) *Verifier {
+	v, err := NewVerifier(context.Background(), config, circuitcapacitychecker.RemoteConfig{}, db, bc, tracer, sequencer)
+	require.NoError(t, err)
+	v.newChecker = func() circuitcapacitychecker.Checker { return circuitcapacitychecker.NewCircuitCapacityChecker(true) }
+	v.Start()
+	t.Cleanup(v.Stop)
+	return v
+}
+
+func TestVerifier(t *testing.T) {
+	db := rawdb.NewMemoryDatabase()
+	bc := newTestChain(db)
+	v := newTestVerifier(t, Config{Workers: 2, QueueSize: 16}, db, bc, &testTracer{}, nil)
+
+	mismatching := &types.RowConsumption{{Name: "mock", RowNumber: 3}}
+	block1 := bc.addBlock(bc.genesis, &mockRows)
+	block2 := bc.addBlock(block1, mismatching)
+	// no row consumption to compare with
+	bc.addBlock(block2, nil)
+
+	require.Eventually(t, func() bool {
+		status := v.Status()
+		return status.Verified+status.Mismatched+status.Unverified == 3
+	}, 5*time.Second, 10*time.Millisecond)
+
+	status := v.Status()
+	assert.Equal(t, uint64(1), status.Verified)
+	assert.Equal(t, uint64(1), status.Mismatched)
+	assert.Equal(t, uint64(1), status.Unverified)
+	assert.Equal(t, uint64(0), status.Failed)
+	if assert.Len(t, status.Mismatches, 1) {
+		assert.Equal(t, block2.Hash(), status.Mismatches[0].BlockHash)
+		assert.Equal(t, mismatching, status.Mismatches[0].Expected)
+		assert.Equal(t, &mockRows, status.Mismatches[0].Actual)
+	}
+}
+
+func TestVerifierSequencer(t *testing.T) {
+	db := rawdb.NewMemoryDatabase()
+	bc := newTestChain(db)
+	sequencer := make(testSequencer)
+	v := newTestVerifier(t, Config{Workers: 1, QueueSize: 16}, db, bc, &testTracer{}, sequencer)
+
+	// the row consumption in the local database is ignored
+	block := types.NewBlockWithHeader(&types.Header{ParentHash: bc.genesis.Hash(), Number: big.NewInt(1)})
+	sequencer[block.Hash()] = &mockRows
+	bc.mu.Lock()
+	bc.blocks[block.Hash()] = block
+	bc.mu.Unlock()
+	rawdb.WriteBlockRowConsumption(db, block.Hash(), &types.RowConsumption{{Name: "mock", RowNumber: 3}})
+	bc.feed.Send(core.ChainEvent{Block: block, Hash: block.Hash()})
+
+	require.Eventually(t, func() bool { return v.Status().Verified == 1 }, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, uint64(0), v.Status().Mismatched)
+}
+
+func TestVerifierDropsBlocks(t *testing.T) {
+	db := rawdb.NewMemoryDatabase()
+	bc := newTestChain(db)
+	tracer := &testTracer{gate: make(chan struct{})}
+	v := newTestVerifier(t, Config{Workers: 1, QueueSize: 2}, db, bc, tracer, nil)
+
+	// the import does not wait for the worker stuck in tracing the first block
+	parent := bc.genesis
+	for i := 0; i < 10; i++ {
+		parent = bc.addBlock(parent, &mockRows)
+	}
+	require.Eventually(t, func() bool { return v.Status().Dropped > 0 }, 5*time.Second, 10*time.Millisecond)
+
+	close(tracer.gate)
+	require.Eventually(t, func() bool {
+		status := v.Status()
+		return status.Pending == 0 && status.Verified+status.Dropped == 10
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestEqualRowConsumption(t *testing.T) {
+	a := types.RowConsumption{{Name: "evm", RowNumber: 1}, {Name: "keccak", RowNumber: 2}}
+	assert.True(t, equalRowConsumption(a, types.RowConsumption{{Name: "keccak", RowNumber: 2}, {Name: "evm", RowNumber: 1}}))
+	assert.False(t, equalRowConsumption(a, types.RowConsumption{{Name: "evm", RowNumber: 1}, {Name: "keccak", RowNumber: 3}}))
+	assert.False(t, equalRowConsumption(a, types.RowConsumption{{Name: "evm", RowNumber: 1}}))
+	assert.False(t, equalRowConsumption(a, types.RowConsumption{{Name: "evm", RowNumber: 1}, {Name: "mpt", RowNumber: 2}}))
+}
+
+func TestNewVerifierInvalidConfig(t *testing.T) {
+	_, err := NewVerifier(context.Background(), Config{Workers: 0, QueueSize: 1}, circuitcapacitychecker.RemoteConfig{}, nil, nil, nil, nil)
+	assert.Error(t, err)
+	_, err = NewVerifier(context.Background(), Config{Workers: 1, QueueSize: 0}, circuitcapacitychecker.RemoteConfig{}, nil, nil, nil, nil)
+	assert.Error(t, err)
+}
+
+// testScrollAPI serves the row consumption of the blocks it knows in scroll_getBlockByHash.
+type testScrollAPI map[common.Hash]*types.RowConsumption
+
+func (api testScrollAPI) GetBlockByHash(hash common.Hash, fullTx bool) map[string]interface{} {
+	rc, ok := api[hash]
+	if !ok {
+		return nil
+	}
+	return map[string]interface{}{"hash": hash, "rowConsumption": rc}
+}
+
+func TestRPCSequencer(t *testing.T) {
+	known := common.HexToHash("0x01")
+	server := rpc.NewServer()
+	require.NoError(t, server.RegisterName("scroll", testScrollAPI{known: &mockRows}))
+	httpServer := httptest.NewServer(server)
+	defer httpServer.Close()
+
+	sequencer, err := DialSequencer(httpServer.URL)
+	require.NoError(t, err)
+
+	rc, err := sequencer.BlockRowConsumption(context.Background(), known)
+	require.NoError(t, err)
+	assert.Equal(t, &mockRows, rc)
+
+	_, err = sequencer.BlockRowConsumption(context.Background(), common.HexToHash("0x02"))
+	assert.ErrorIs(t, err, ethereum.NotFound)
+}
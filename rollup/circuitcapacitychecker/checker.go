@@ -0,0 +1,51 @@
+package circuitcapacitychecker
+
+import (
+	"time"
+
+	"github.com/scroll-tech/go-ethereum/core/types"
+)
+
+// DefaultRemoteTimeout is the default timeout of a call to a remote circuit capacity checker.
+const DefaultRemoteTimeout = 10 * time.Second
+
+// DefaultMaxRows is the row limit of each sub-circuit of the degree 20 circuits checked by libzkp,
+// 2^20 rows minus the rows reserved for blinding. It must follow the circuits of the linked libzkp.
+const DefaultMaxRows = 1<<20 - 256
+
+// Checker is the interface of the in-process CircuitCapacityChecker and of RemoteCircuitCapacityChecker.
+type Checker interface {
+	// ID returns the ID of the checker, used in logs.
+	ID() uint64
+
+	// Reset discards the transactions applied since the last reset.
+	Reset()
+
+	// ApplyTransaction appends a tx's wrapped BlockTrace and returns the accumulated RowConsumption.
+	ApplyTransaction(traces *types.BlockTrace) (*types.RowConsumption, error)
+
+	// ApplyBlock gets a block's RowConsumption.
+	ApplyBlock(traces *types.BlockTrace) (*types.RowConsumption, error)
+
+	// CheckTxNum compares whether the number of applied transactions matches the expected.
+	CheckTxNum(expected int) (bool, uint64, error)
+
+	// SetLightMode sets the light mode of the checker.
+	SetLightMode(lightMode bool) error
+}
+
+// RemoteConfig configures the circuit capacity checker, and an out-of-process checker if an endpoint is set.
+type RemoteConfig struct {
+	Endpoint string        `toml:",omitempty"` // RPC endpoint of the checker process, the in-process checker is used if empty
+	Timeout  time.Duration `toml:",omitempty"` // Timeout of a call to the checker process
+	Command  []string      `toml:",omitempty"` // Command that starts the checker process, which is restarted if it exits
+	MaxRows  uint64        `toml:",omitempty"` // Row limit of each sub-circuit of the checker, row shares are not reported if 0
+}
+
+// New creates the in-process CircuitCapacityChecker, or a RemoteCircuitCapacityChecker if a remote endpoint is configured.
+func New(config RemoteConfig, lightMode bool) Checker {
+	if config.Endpoint == "" {
+		return NewCircuitCapacityChecker(lightMode)
+	}
+	return NewRemoteCircuitCapacityChecker(config, lightMode)
+}
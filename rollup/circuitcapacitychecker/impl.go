@@ -30,7 +30,7 @@ func init() {
 type CircuitCapacityChecker struct {
 	// mutex for each CircuitCapacityChecker itself
 	sync.Mutex
-	ID         uint64
+	id         uint64
 	jsonBuffer bytes.Buffer
 }
 
@@ -40,17 +40,22 @@ func NewCircuitCapacityChecker(lightMode bool) *CircuitCapacityChecker {
 	defer creationMu.Unlock()
 
 	id := C.new_circuit_capacity_checker()
-	ccc := &CircuitCapacityChecker{ID: uint64(id)}
+	ccc := &CircuitCapacityChecker{id: uint64(id)}
 	ccc.SetLightMode(lightMode)
 	return ccc
 }
 
+// ID returns the ID of the CircuitCapacityChecker
+func (ccc *CircuitCapacityChecker) ID() uint64 {
+	return ccc.id
+}
+
 // Reset resets a CircuitCapacityChecker
 func (ccc *CircuitCapacityChecker) Reset() {
 	ccc.Lock()
 	defer ccc.Unlock()
 
-	C.reset_circuit_capacity_checker(C.uint64_t(ccc.ID))
+	C.reset_circuit_capacity_checker(C.uint64_t(ccc.id))
 }
 
 // ApplyTransaction appends a tx's wrapped BlockTrace into the ccc, and return the accumulated RowConsumption
@@ -59,7 +64,7 @@ func (ccc *CircuitCapacityChecker) ApplyTransaction(traces *types.BlockTrace) (*
 	defer ccc.Unlock()
 
 	if len(traces.Transactions) != 1 || len(traces.ExecutionResults) != 1 || len(traces.TxStorageTraces) != 1 {
-		log.Error("malformatted BlockTrace in ApplyTransaction", "id", ccc.ID,
+		log.Error("malformatted BlockTrace in ApplyTransaction", "id", ccc.id,
 			"len(traces.Transactions)", len(traces.Transactions),
 			"len(traces.ExecutionResults)", len(traces.ExecutionResults),
 			"len(traces.TxStorageTraces)", len(traces.TxStorageTraces),
@@ -70,7 +75,7 @@ func (ccc *CircuitCapacityChecker) ApplyTransaction(traces *types.BlockTrace) (*
 	ccc.jsonBuffer.Reset()
 	err := json.NewEncoder(&ccc.jsonBuffer).Encode(traces)
 	if err != nil {
-		log.Error("fail to json marshal traces in ApplyTransaction", "id", ccc.ID, "TxHash", traces.Transactions[0].TxHash, "err", err)
+		log.Error("fail to json marshal traces in ApplyTransaction", "id", ccc.id, "TxHash", traces.Transactions[0].TxHash, "err", err)
 		return nil, ErrUnknown
 	}
 
@@ -79,32 +84,32 @@ func (ccc *CircuitCapacityChecker) ApplyTransaction(traces *types.BlockTrace) (*
 		C.free(unsafe.Pointer(tracesStr))
 	}()
 
-	log.Debug("start to check circuit capacity for tx", "id", ccc.ID, "TxHash", traces.Transactions[0].TxHash)
-	rawResult := C.apply_tx(C.uint64_t(ccc.ID), tracesStr)
+	log.Debug("start to check circuit capacity for tx", "id", ccc.id, "TxHash", traces.Transactions[0].TxHash)
+	rawResult := C.apply_tx(C.uint64_t(ccc.id), tracesStr)
 	defer func() {
 		C.free_c_chars(rawResult)
 	}()
-	log.Debug("check circuit capacity for tx done", "id", ccc.ID, "TxHash", traces.Transactions[0].TxHash)
+	log.Debug("check circuit capacity for tx done", "id", ccc.id, "TxHash", traces.Transactions[0].TxHash)
 
 	result := &WrappedRowUsage{}
 	if err = json.Unmarshal([]byte(C.GoString(rawResult)), result); err != nil {
-		log.Error("fail to json unmarshal apply_tx result", "id", ccc.ID, "TxHash", traces.Transactions[0].TxHash, "err", err)
+		log.Error("fail to json unmarshal apply_tx result", "id", ccc.id, "TxHash", traces.Transactions[0].TxHash, "err", err)
 		return nil, ErrUnknown
 	}
 
 	if result.Error != "" {
-		log.Error("fail to apply_tx in CircuitCapacityChecker", "id", ccc.ID, "TxHash", traces.Transactions[0].TxHash, "err", result.Error)
+		log.Error("fail to apply_tx in CircuitCapacityChecker", "id", ccc.id, "TxHash", traces.Transactions[0].TxHash, "err", result.Error)
 		return nil, ErrUnknown
 	}
 	if result.AccRowUsage == nil {
 		log.Error("fail to apply_tx in CircuitCapacityChecker",
-			"id", ccc.ID, "TxHash", traces.Transactions[0].TxHash,
+			"id", ccc.id, "TxHash", traces.Transactions[0].TxHash,
 			"result.AccRowUsage == nil", result.AccRowUsage == nil,
 			"err", "AccRowUsage is empty unexpectedly")
 		return nil, ErrUnknown
 	}
 	if !result.AccRowUsage.IsOk {
-		return nil, ErrBlockRowConsumptionOverflow
+		return nil, &RowConsumptionOverflowError{RowConsumption: result.AccRowUsage.RowUsageDetails}
 	}
 	return (*types.RowConsumption)(&result.AccRowUsage.RowUsageDetails), nil
 }
@@ -117,7 +122,7 @@ func (ccc *CircuitCapacityChecker) ApplyBlock(traces *types.BlockTrace) (*types.
 	ccc.jsonBuffer.Reset()
 	err := json.NewEncoder(&ccc.jsonBuffer).Encode(traces)
 	if err != nil {
-		log.Error("fail to json marshal traces in ApplyBlock", "id", ccc.ID, "blockNumber", traces.Header.Number, "blockHash", traces.Header.Hash(), "err", err)
+		log.Error("fail to json marshal traces in ApplyBlock", "id", ccc.id, "blockNumber", traces.Header.Number, "blockHash", traces.Header.Hash(), "err", err)
 		return nil, ErrUnknown
 	}
 
@@ -126,29 +131,29 @@ func (ccc *CircuitCapacityChecker) ApplyBlock(traces *types.BlockTrace) (*types.
 		C.free(unsafe.Pointer(tracesStr))
 	}()
 
-	log.Debug("start to check circuit capacity for block", "id", ccc.ID, "blockNumber", traces.Header.Number, "blockHash", traces.Header.Hash())
-	rawResult := C.apply_block(C.uint64_t(ccc.ID), tracesStr)
+	log.Debug("start to check circuit capacity for block", "id", ccc.id, "blockNumber", traces.Header.Number, "blockHash", traces.Header.Hash())
+	rawResult := C.apply_block(C.uint64_t(ccc.id), tracesStr)
 	defer func() {
 		C.free_c_chars(rawResult)
 	}()
-	log.Debug("check circuit capacity for block done", "id", ccc.ID, "blockNumber", traces.Header.Number, "blockHash", traces.Header.Hash())
+	log.Debug("check circuit capacity for block done", "id", ccc.id, "blockNumber", traces.Header.Number, "blockHash", traces.Header.Hash())
 
 	result := &WrappedRowUsage{}
 	if err = json.Unmarshal([]byte(C.GoString(rawResult)), result); err != nil {
-		log.Error("fail to json unmarshal apply_block result", "id", ccc.ID, "blockNumber", traces.Header.Number, "blockHash", traces.Header.Hash(), "err", err)
+		log.Error("fail to json unmarshal apply_block result", "id", ccc.id, "blockNumber", traces.Header.Number, "blockHash", traces.Header.Hash(), "err", err)
 		return nil, ErrUnknown
 	}
 
 	if result.Error != "" {
-		log.Error("fail to apply_block in CircuitCapacityChecker", "id", ccc.ID, "blockNumber", traces.Header.Number, "blockHash", traces.Header.Hash(), "err", result.Error)
+		log.Error("fail to apply_block in CircuitCapacityChecker", "id", ccc.id, "blockNumber", traces.Header.Number, "blockHash", traces.Header.Hash(), "err", result.Error)
 		return nil, ErrUnknown
 	}
 	if result.AccRowUsage == nil {
-		log.Error("fail to apply_block in CircuitCapacityChecker", "id", ccc.ID, "blockNumber", traces.Header.Number, "blockHash", traces.Header.Hash(), "err", "AccRowUsage is empty unexpectedly")
+		log.Error("fail to apply_block in CircuitCapacityChecker", "id", ccc.id, "blockNumber", traces.Header.Number, "blockHash", traces.Header.Hash(), "err", "AccRowUsage is empty unexpectedly")
 		return nil, ErrUnknown
 	}
 	if !result.AccRowUsage.IsOk {
-		return nil, ErrBlockRowConsumptionOverflow
+		return nil, &RowConsumptionOverflowError{RowConsumption: result.AccRowUsage.RowUsageDetails}
 	}
 	return (*types.RowConsumption)(&result.AccRowUsage.RowUsageDetails), nil
 }
@@ -158,19 +163,19 @@ func (ccc *CircuitCapacityChecker) CheckTxNum(expected int) (bool, uint64, error
 	ccc.Lock()
 	defer ccc.Unlock()
 
-	log.Debug("ccc get_tx_num start", "id", ccc.ID)
-	rawResult := C.get_tx_num(C.uint64_t(ccc.ID))
+	log.Debug("ccc get_tx_num start", "id", ccc.id)
+	rawResult := C.get_tx_num(C.uint64_t(ccc.id))
 	defer func() {
 		C.free_c_chars(rawResult)
 	}()
-	log.Debug("ccc get_tx_num end", "id", ccc.ID)
+	log.Debug("ccc get_tx_num end", "id", ccc.id)
 
 	result := &WrappedTxNum{}
 	if err := json.Unmarshal([]byte(C.GoString(rawResult)), result); err != nil {
-		return false, 0, fmt.Errorf("fail to json unmarshal get_tx_num result, id: %d, err: %w", ccc.ID, err)
+		return false, 0, fmt.Errorf("fail to json unmarshal get_tx_num result, id: %d, err: %w", ccc.id, err)
 	}
 	if result.Error != "" {
-		return false, 0, fmt.Errorf("fail to get_tx_num in CircuitCapacityChecker, id: %d, err: %w", ccc.ID, result.Error)
+		return false, 0, fmt.Errorf("fail to get_tx_num in CircuitCapacityChecker, id: %d, err: %w", ccc.id, result.Error)
 	}
 
 	return result.TxNum == uint64(expected), result.TxNum, nil
@@ -181,19 +186,19 @@ func (ccc *CircuitCapacityChecker) SetLightMode(lightMode bool) error {
 	ccc.Lock()
 	defer ccc.Unlock()
 
-	log.Debug("ccc set_light_mode start", "id", ccc.ID)
-	rawResult := C.set_light_mode(C.uint64_t(ccc.ID), C.bool(lightMode))
+	log.Debug("ccc set_light_mode start", "id", ccc.id)
+	rawResult := C.set_light_mode(C.uint64_t(ccc.id), C.bool(lightMode))
 	defer func() {
 		C.free_c_chars(rawResult)
 	}()
-	log.Debug("ccc set_light_mode end", "id", ccc.ID)
+	log.Debug("ccc set_light_mode end", "id", ccc.id)
 
 	result := &WrappedCommonResult{}
 	if err := json.Unmarshal([]byte(C.GoString(rawResult)), result); err != nil {
-		return fmt.Errorf("fail to json unmarshal set_light_mode result, id: %d, err: %w", ccc.ID, err)
+		return fmt.Errorf("fail to json unmarshal set_light_mode result, id: %d, err: %w", ccc.id, err)
 	}
 	if result.Error != "" {
-		return fmt.Errorf("fail to set_light_mode in CircuitCapacityChecker, id: %d, err: %w", ccc.ID, result.Error)
+		return fmt.Errorf("fail to set_light_mode in CircuitCapacityChecker, id: %d, err: %w", ccc.id, result.Error)
 	}
 
 	return nil
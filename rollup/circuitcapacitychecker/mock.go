@@ -9,18 +9,23 @@ import (
 )
 
 type CircuitCapacityChecker struct {
-	ID        uint64
+	id        uint64
 	countdown int
 	nextError *error
 }
 
 // NewCircuitCapacityChecker creates a new CircuitCapacityChecker
 func NewCircuitCapacityChecker(lightMode bool) *CircuitCapacityChecker {
-	ccc := &CircuitCapacityChecker{ID: rand.Uint64()}
+	ccc := &CircuitCapacityChecker{id: rand.Uint64()}
 	ccc.SetLightMode(lightMode)
 	return ccc
 }
 
+// ID returns the ID of the CircuitCapacityChecker
+func (ccc *CircuitCapacityChecker) ID() uint64 {
+	return ccc.id
+}
+
 // Reset resets a ccc, but need to do nothing in mock_ccc.
 func (ccc *CircuitCapacityChecker) Reset() {
 }
@@ -0,0 +1,203 @@
+package circuitcapacitychecker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+
+	"github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/log"
+	"github.com/scroll-tech/go-ethereum/rpc"
+)
+
+// RemoteCircuitCapacityChecker is a circuit capacity checker that runs in a separate process and is called
+// over RPC, see RPCService, so that a crash of libzkp does not bring down the node.
+//
+// If the checker process is unreachable, or restarts and loses the transactions applied since the last reset,
+// the checker fails with ErrCheckerUnavailable until it is reset, and the next reset creates a new checker in
+// the restarted process.
+type RemoteCircuitCapacityChecker struct {
+	mu        sync.Mutex
+	id        uint64
+	config    RemoteConfig
+	lightMode bool
+
+	client   *rpc.Client
+	remoteID *uint64 // ID of the checker in the checker process, nil if not created yet
+	stale    bool    // whether applied transactions were lost since the last reset
+}
+
+// NewRemoteCircuitCapacityChecker creates a new RemoteCircuitCapacityChecker. It connects to the
+// checker process on first use.
+func NewRemoteCircuitCapacityChecker(config RemoteConfig, lightMode bool) *RemoteCircuitCapacityChecker {
+	if config.Timeout == 0 {
+		config.Timeout = DefaultRemoteTimeout
+	}
+	return &RemoteCircuitCapacityChecker{id: rand.Uint64(), config: config, lightMode: lightMode}
+}
+
+// ID returns the ID of the RemoteCircuitCapacityChecker, which differs from the ID of the checker in the checker process.
+func (ccc *RemoteCircuitCapacityChecker) ID() uint64 {
+	return ccc.id
+}
+
+// call calls a method of the checker process. If the connection fails or the process no longer knows
+// the checker, the checker is dropped and marked stale, and the error wraps ErrCheckerUnavailable.
+func (ccc *RemoteCircuitCapacityChecker) call(result interface{}, method string, args ...interface{}) error {
+	ctx, cancel := context.WithTimeout(context.Background(), ccc.config.Timeout)
+	defer cancel()
+
+	if ccc.client == nil {
+		client, err := rpc.DialContext(ctx, ccc.config.Endpoint)
+		if err != nil {
+			return fmt.Errorf("%w: failed to dial, endpoint: %s, err: %v", ErrCheckerUnavailable, ccc.config.Endpoint, err)
+		}
+		ccc.client = client
+	}
+	if ccc.remoteID == nil && method != RPCNamespace+"_newChecker" {
+		var remoteID uint64
+		if err := ccc.call(&remoteID, RPCNamespace+"_newChecker", ccc.lightMode); err != nil {
+			return err
+		}
+		ccc.remoteID = &remoteID
+		log.Info("new remote CircuitCapacityChecker", "id", ccc.id, "remoteID", remoteID, "endpoint", ccc.config.Endpoint)
+	}
+	if ccc.remoteID != nil {
+		args = append([]interface{}{*ccc.remoteID}, args...)
+	}
+
+	err := fromRPCError(ccc.client.CallContext(ctx, result, method, args...))
+	var rpcErr rpc.Error
+	switch {
+	case err == nil:
+	case errors.Is(err, errUnknownChecker):
+		ccc.remoteID = nil
+		ccc.stale = true
+		err = fmt.Errorf("%w: %v", ErrCheckerUnavailable, err)
+	case !errors.As(err, &rpcErr) && !errors.Is(err, ErrBlockRowConsumptionOverflow) && !errors.Is(err, ErrUnknown) && !errors.Is(err, ErrCheckerUnavailable):
+		// the connection failed or timed out, the checker process may have crashed
+		ccc.client.Close()
+		ccc.client = nil
+		ccc.remoteID = nil
+		ccc.stale = true
+		err = fmt.Errorf("%w: %v", ErrCheckerUnavailable, err)
+	}
+	return err
+}
+
+// Reset resets the checker.
+func (ccc *RemoteCircuitCapacityChecker) Reset() {
+	ccc.mu.Lock()
+	defer ccc.mu.Unlock()
+
+	ccc.stale = false
+	if ccc.remoteID == nil {
+		// a new checker is created on first use
+		return
+	}
+	if err := ccc.call(nil, RPCNamespace+"_reset"); err != nil {
+		log.Warn("failed to reset remote CircuitCapacityChecker", "id", ccc.id, "err", err)
+		ccc.remoteID = nil
+	}
+	ccc.stale = false
+}
+
+// ApplyTransaction appends a tx's wrapped BlockTrace into the checker, and returns the accumulated RowConsumption.
+func (ccc *RemoteCircuitCapacityChecker) ApplyTransaction(traces *types.BlockTrace) (*types.RowConsumption, error) {
+	ccc.mu.Lock()
+	defer ccc.mu.Unlock()
+
+	if len(traces.Transactions) != 1 {
+		log.Error("malformatted BlockTrace in ApplyTransaction", "id", ccc.id, "len(traces.Transactions)", len(traces.Transactions))
+		return nil, ErrUnknown
+	}
+	if ccc.stale {
+		log.Error("remote CircuitCapacityChecker lost the applied transactions, it needs to be reset", "id", ccc.id, "TxHash", traces.Transactions[0].TxHash)
+		return nil, ErrCheckerUnavailable
+	}
+
+	rc := new(types.RowConsumption)
+	if err := ccc.call(rc, RPCNamespace+"_applyTransaction", traces); err != nil {
+		if errors.Is(err, ErrBlockRowConsumptionOverflow) {
+			return nil, err
+		}
+		log.Error("fail to apply_tx in remote CircuitCapacityChecker", "id", ccc.id, "TxHash", traces.Transactions[0].TxHash, "err", err)
+		if errors.Is(err, ErrCheckerUnavailable) {
+			return nil, ErrCheckerUnavailable
+		}
+		return nil, ErrUnknown
+	}
+	return rc, nil
+}
+
+// ApplyBlock gets a block's RowConsumption.
+func (ccc *RemoteCircuitCapacityChecker) ApplyBlock(traces *types.BlockTrace) (*types.RowConsumption, error) {
+	ccc.mu.Lock()
+	defer ccc.mu.Unlock()
+
+	rc := new(types.RowConsumption)
+	if err := ccc.call(rc, RPCNamespace+"_applyBlock", traces); err != nil {
+		if errors.Is(err, ErrBlockRowConsumptionOverflow) {
+			return nil, err
+		}
+		log.Error("fail to apply_block in remote CircuitCapacityChecker", "id", ccc.id, "blockNumber", traces.Header.Number, "blockHash", traces.Header.Hash(), "err", err)
+		if errors.Is(err, ErrCheckerUnavailable) {
+			return nil, ErrCheckerUnavailable
+		}
+		return nil, ErrUnknown
+	}
+	return rc, nil
+}
+
+// CheckTxNum compares whether the number of applied transactions matches the expected.
+func (ccc *RemoteCircuitCapacityChecker) CheckTxNum(expected int) (bool, uint64, error) {
+	ccc.mu.Lock()
+	defer ccc.mu.Unlock()
+
+	if ccc.stale {
+		return false, 0, fmt.Errorf("%w: remote CircuitCapacityChecker lost the applied transactions, id: %d", ErrCheckerUnavailable, ccc.id)
+	}
+	var result CheckTxNumResult
+	if err := ccc.call(&result, RPCNamespace+"_checkTxNum", expected); err != nil {
+		return false, 0, fmt.Errorf("fail to get_tx_num in remote CircuitCapacityChecker, id: %d, err: %w", ccc.id, err)
+	}
+	return result.Match, result.TxNum, nil
+}
+
+// SetLightMode sets the light mode of the checker.
+func (ccc *RemoteCircuitCapacityChecker) SetLightMode(lightMode bool) error {
+	ccc.mu.Lock()
+	defer ccc.mu.Unlock()
+
+	ccc.lightMode = lightMode
+	if ccc.remoteID == nil {
+		// the light mode is passed when the checker is created
+		return nil
+	}
+	if err := ccc.call(nil, RPCNamespace+"_setLightMode", lightMode); err != nil {
+		return fmt.Errorf("fail to set_light_mode in remote CircuitCapacityChecker, id: %d, err: %w", ccc.id, err)
+	}
+	return nil
+}
+
+// Close releases the checker in the checker process and closes the connection.
+func (ccc *RemoteCircuitCapacityChecker) Close() {
+	ccc.mu.Lock()
+	defer ccc.mu.Unlock()
+
+	if ccc.client == nil {
+		return
+	}
+	if ccc.remoteID != nil {
+		if err := ccc.call(nil, RPCNamespace+"_closeChecker"); err != nil {
+			log.Debug("failed to close remote CircuitCapacityChecker", "id", ccc.id, "err", err)
+		}
+	}
+	if ccc.client != nil {
+		ccc.client.Close()
+		ccc.client = nil
+	}
+	ccc.remoteID = nil
+}
@@ -0,0 +1,275 @@
+package circuitcapacitychecker
+
+import (
+	"context"
+	"encoding/json"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/rpc"
+)
+
+// testChecker counts the applied transactions and fails with err once they exceed maxTxs.
+type testChecker struct {
+	mu     sync.Mutex
+	id     uint64
+	txs    uint64
+	maxTxs uint64
+	delay  *int64 // delay of ApplyTransaction in nanoseconds
+}
+
+func (c *testChecker) ID() uint64 { return c.id }
+
+func (c *testChecker) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.txs = 0
+}
+
+func (c *testChecker) ApplyTransaction(traces *types.BlockTrace) (*types.RowConsumption, error) {
+	time.Sleep(time.Duration(atomic.LoadInt64(c.delay)))
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.txs == c.maxTxs {
+		return nil, &RowConsumptionOverflowError{RowConsumption: types.RowConsumption{{Name: "test", RowNumber: c.txs + 1}}}
+	}
+	c.txs++
+	return &types.RowConsumption{{Name: "test", RowNumber: c.txs}}, nil
+}
+
+func (c *testChecker) ApplyBlock(traces *types.BlockTrace) (*types.RowConsumption, error) {
+	return &types.RowConsumption{{Name: "test", RowNumber: uint64(len(traces.Transactions))}}, nil
+}
+
+func (c *testChecker) CheckTxNum(expected int) (bool, uint64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.txs == uint64(expected), c.txs, nil
+}
+
+func (c *testChecker) SetLightMode(lightMode bool) error { return nil }
+
+// restartableServer serves an RPCService over HTTP and can replace it with a new one, as if the
+// checker process restarted.
+type restartableServer struct {
+	server     atomic.Value // *rpc.Server
+	newChecker func(lightMode bool) Checker
+}
+
+func newRestartableServer(t *testing.T, newChecker func(lightMode bool) Checker) (*restartableServer, string) {
+	s := &restartableServer{newChecker: newChecker}
+	s.restart(t)
+	httpServer := httptest.NewServer(s)
+	t.Cleanup(httpServer.Close)
+	return s, httpServer.URL
+}
+
+func (s *restartableServer) restart(t *testing.T) {
+	server := rpc.NewServer()
+	require.NoError(t, server.RegisterName(RPCNamespace, NewRPCService(s.newChecker)))
+	s.server.Store(server)
+}
+
+func (s *restartableServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.server.Load().(*rpc.Server).ServeHTTP(w, r)
+}
+
+// checkEquivalence applies the transactions and the block of each trace to both checkers and
+// requires the same results.
+func checkEquivalence(t *testing.T, local, remote Checker, traces []*types.BlockTrace) {
+	for _, trace := range traces {
+		local.Reset()
+		remote.Reset()
+		for i := range trace.ExecutionResults {
+			tx := &types.BlockTrace{
+				ChainID:          trace.ChainID,
+				Coinbase:         trace.Coinbase,
+				Header:           trace.Header,
+				Transactions:     trace.Transactions[i : i+1],
+				ExecutionResults: trace.ExecutionResults[i : i+1],
+				StorageTrace:     trace.StorageTrace,
+			}
+			if len(trace.TxStorageTraces) > i {
+				tx.TxStorageTraces = trace.TxStorageTraces[i : i+1]
+			}
+			expected, expectedErr := local.ApplyTransaction(tx)
+			rc, err := remote.ApplyTransaction(tx)
+			assert.Equal(t, expectedErr, err)
+			assert.Equal(t, expected, rc)
+
+			expectedMatch, expectedTxNum, expectedErr := local.CheckTxNum(i + 1)
+			match, txNum, err := remote.CheckTxNum(i + 1)
+			assert.Equal(t, expectedErr == nil, err == nil)
+			assert.Equal(t, expectedMatch, match)
+			assert.Equal(t, expectedTxNum, txNum)
+		}
+
+		local.Reset()
+		remote.Reset()
+		expected, expectedErr := local.ApplyBlock(trace)
+		rc, err := remote.ApplyBlock(trace)
+		assert.Equal(t, expectedErr, err)
+		assert.Equal(t, expected, rc)
+	}
+}
+
+func readBlockTraceFromJSON(t *testing.T, filename string) *types.BlockTrace {
+	data, err := os.ReadFile(filename)
+	require.NoError(t, err)
+
+	trace := &types.BlockTrace{}
+	require.NoError(t, json.Unmarshal(data, trace))
+	return trace
+}
+
+func TestRemoteCircuitCapacityCheckerEquivalence(t *testing.T) {
+	var traces []*types.BlockTrace
+	for _, filename := range []string{"blockTrace_03.json", "blockTrace_04.json", "blockTrace_05.json"} {
+		traces = append(traces, readBlockTraceFromJSON(t, "../rollup_sync_service/testdata/"+filename))
+	}
+	_, endpoint := newRestartableServer(t, func(lightMode bool) Checker {
+		return NewCircuitCapacityChecker(lightMode)
+	})
+
+	// the in-process checker, i.e. libzkp if built with the circuit_capacity_checker tag
+	remote := NewRemoteCircuitCapacityChecker(RemoteConfig{Endpoint: endpoint}, true)
+	defer remote.Close()
+	checkEquivalence(t, NewCircuitCapacityChecker(true), remote, traces)
+
+	// a checker with overflows
+	delay := int64(0)
+	newChecker := func(lightMode bool) Checker { return &testChecker{id: rand.Uint64(), maxTxs: 3, delay: &delay} }
+	_, endpoint = newRestartableServer(t, newChecker)
+	remote = NewRemoteCircuitCapacityChecker(RemoteConfig{Endpoint: endpoint}, true)
+	defer remote.Close()
+	checkEquivalence(t, newChecker(true), remote, traces)
+}
+
+func TestRemoteCircuitCapacityCheckerFailures(t *testing.T) {
+	trace := readBlockTraceFromJSON(t, "../rollup_sync_service/testdata/blockTrace_05.json")
+	tx := &types.BlockTrace{Header: trace.Header, Transactions: trace.Transactions[:1], ExecutionResults: trace.ExecutionResults[:1]}
+
+	delay := int64(0)
+	server, endpoint := newRestartableServer(t, func(lightMode bool) Checker {
+		return &testChecker{id: rand.Uint64(), maxTxs: 100, delay: &delay}
+	})
+	remote := NewRemoteCircuitCapacityChecker(RemoteConfig{Endpoint: endpoint, Timeout: 200 * time.Millisecond}, true)
+	defer remote.Close()
+
+	rc, err := remote.ApplyTransaction(tx)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), (*rc)[0].RowNumber)
+
+	// the checker process restarted and lost the applied transaction
+	server.restart(t)
+	_, err = remote.ApplyTransaction(tx)
+	assert.Equal(t, ErrCheckerUnavailable, err)
+	_, err = remote.ApplyTransaction(tx)
+	assert.Equal(t, ErrCheckerUnavailable, err)
+	_, _, err = remote.CheckTxNum(1)
+	assert.Error(t, err)
+
+	// a reset creates a new checker
+	remote.Reset()
+	rc, err = remote.ApplyTransaction(tx)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), (*rc)[0].RowNumber)
+
+	// a call that times out fails until the next reset
+	atomic.StoreInt64(&delay, int64(time.Second))
+	start := time.Now()
+	_, err = remote.ApplyTransaction(tx)
+	assert.Equal(t, ErrCheckerUnavailable, err)
+	assert.Less(t, time.Since(start), time.Second)
+	atomic.StoreInt64(&delay, 0)
+	_, err = remote.ApplyTransaction(tx)
+	assert.Equal(t, ErrCheckerUnavailable, err)
+
+	remote.Reset()
+	rc, err = remote.ApplyTransaction(tx)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), (*rc)[0].RowNumber)
+	match, txNum, err := remote.CheckTxNum(1)
+	require.NoError(t, err)
+	assert.True(t, match)
+	assert.Equal(t, uint64(1), txNum)
+
+	// an unreachable checker process
+	unreachable := NewRemoteCircuitCapacityChecker(RemoteConfig{Endpoint: "http://127.0.0.1:1", Timeout: 200 * time.Millisecond}, true)
+	_, err = unreachable.ApplyBlock(trace)
+	assert.Equal(t, ErrCheckerUnavailable, err)
+	_, err = unreachable.ApplyTransaction(tx)
+	assert.Equal(t, ErrCheckerUnavailable, err)
+	_, _, err = unreachable.CheckTxNum(0)
+	assert.ErrorIs(t, err, ErrCheckerUnavailable)
+
+	// a failure of the checker itself is not an unavailable checker
+	remote.Reset()
+	_, err = remote.ApplyTransaction(&types.BlockTrace{Header: trace.Header})
+	assert.Equal(t, ErrUnknown, err)
+}
+
+func TestRPCServiceReusesClosedCheckers(t *testing.T) {
+	trace := readBlockTraceFromJSON(t, "../rollup_sync_service/testdata/blockTrace_05.json")
+	tx := &types.BlockTrace{Header: trace.Header, Transactions: trace.Transactions[:1], ExecutionResults: trace.ExecutionResults[:1]}
+
+	delay := int64(0)
+	var created int32
+	_, endpoint := newRestartableServer(t, func(lightMode bool) Checker {
+		atomic.AddInt32(&created, 1)
+		return &testChecker{id: rand.Uint64(), maxTxs: 100, delay: &delay}
+	})
+
+	remote := NewRemoteCircuitCapacityChecker(RemoteConfig{Endpoint: endpoint}, true)
+	_, err := remote.ApplyTransaction(tx)
+	require.NoError(t, err)
+	remote.Close()
+
+	// the closed checker is reset and reused
+	remote = NewRemoteCircuitCapacityChecker(RemoteConfig{Endpoint: endpoint}, true)
+	defer remote.Close()
+	rc, err := remote.ApplyTransaction(tx)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), (*rc)[0].RowNumber)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&created))
+
+	// checkers in use are not reused
+	other := NewRemoteCircuitCapacityChecker(RemoteConfig{Endpoint: endpoint}, true)
+	defer other.Close()
+	_, err = other.ApplyTransaction(tx)
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&created))
+}
+
+func TestSupervisor(t *testing.T) {
+	s, err := NewSupervisor(context.Background(), []string{"sh", "-c", "exit 1"})
+	require.NoError(t, err)
+	s.minRestartDelay = 10 * time.Millisecond
+	s.Start()
+	require.Eventually(t, func() bool { return s.Restarts() >= 2 }, 5*time.Second, 10*time.Millisecond)
+	s.Stop()
+
+	// stopping kills the running process
+	s, err = NewSupervisor(context.Background(), []string{"sleep", "60"})
+	require.NoError(t, err)
+	s.Start()
+	time.Sleep(100 * time.Millisecond)
+	start := time.Now()
+	s.Stop()
+	assert.Less(t, time.Since(start), 5*time.Second)
+	assert.Equal(t, uint64(0), s.Restarts())
+
+	_, err = NewSupervisor(context.Background(), nil)
+	assert.Error(t, err)
+}
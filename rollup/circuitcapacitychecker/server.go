@@ -0,0 +1,210 @@
+package circuitcapacitychecker
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/log"
+	"github.com/scroll-tech/go-ethereum/rpc"
+)
+
+// RPCNamespace is the namespace of the circuit capacity checker RPC API.
+const RPCNamespace = "ccc"
+
+// Error codes of the circuit capacity checker RPC API.
+const (
+	errCodeUnknown        = -39000
+	errCodeOverflow       = -39001
+	errCodeUnknownChecker = -39002
+)
+
+// errUnknownChecker is returned by a remote checker that does not know the checker ID, e.g. after it restarted.
+var errUnknownChecker = errors.New("unknown circuit capacity checker")
+
+// rpcError is a circuit capacity checker error sent over RPC.
+type rpcError struct {
+	code int
+	err  error
+	data interface{}
+}
+
+func (e *rpcError) Error() string          { return e.err.Error() }
+func (e *rpcError) ErrorCode() int         { return e.code }
+func (e *rpcError) ErrorData() interface{} { return e.data }
+
+func toRPCError(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, ErrBlockRowConsumptionOverflow):
+		// send the row consumption that overflowed, if known
+		var overflowErr *RowConsumptionOverflowError
+		if errors.As(err, &overflowErr) {
+			return &rpcError{code: errCodeOverflow, err: err, data: overflowErr.RowConsumption}
+		}
+		return &rpcError{code: errCodeOverflow, err: err}
+	case errors.Is(err, errUnknownChecker):
+		return &rpcError{code: errCodeUnknownChecker, err: err}
+	default:
+		return &rpcError{code: errCodeUnknown, err: err}
+	}
+}
+
+// fromRPCError converts an error returned by the RPC API back into the error of the checker.
+func fromRPCError(err error) error {
+	var rpcErr rpc.Error
+	if !errors.As(err, &rpcErr) {
+		return err
+	}
+	switch rpcErr.ErrorCode() {
+	case errCodeOverflow:
+		var dataErr rpc.DataError
+		if errors.As(err, &dataErr) && dataErr.ErrorData() != nil {
+			// the error data is decoded as a generic JSON value, decode it again into the row consumption
+			var rc types.RowConsumption
+			if data, err := json.Marshal(dataErr.ErrorData()); err == nil && json.Unmarshal(data, &rc) == nil {
+				return &RowConsumptionOverflowError{RowConsumption: rc}
+			}
+		}
+		return ErrBlockRowConsumptionOverflow
+	case errCodeUnknownChecker:
+		return errUnknownChecker
+	case errCodeUnknown:
+		if rpcErr.Error() == ErrUnknown.Error() {
+			return ErrUnknown
+		}
+	}
+	return err
+}
+
+// CheckTxNumResult is the result of ccc_checkTxNum.
+type CheckTxNumResult struct {
+	Match bool   `json:"match"`
+	TxNum uint64 `json:"txNum"`
+}
+
+// RPCService serves circuit capacity checkers over RPC in the "ccc" namespace, so that the checker can run
+// in a separate process. A client creates a checker with ccc_newChecker and passes the returned ID to the
+// other methods. Since libzkp cannot free a checker, closed checkers are reused for new ones.
+type RPCService struct {
+	mu         sync.Mutex
+	newChecker func(lightMode bool) Checker
+	checkers   map[uint64]Checker
+	idle       []Checker // closed checkers
+}
+
+// NewRPCService creates a new RPCService whose checkers are created by newChecker.
+func NewRPCService(newChecker func(lightMode bool) Checker) *RPCService {
+	return &RPCService{newChecker: newChecker, checkers: make(map[uint64]Checker)}
+}
+
+func (s *RPCService) checker(id uint64) (Checker, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ccc, ok := s.checkers[id]
+	if !ok {
+		return nil, toRPCError(fmt.Errorf("%w: %d", errUnknownChecker, id))
+	}
+	return ccc, nil
+}
+
+// NewChecker creates a new checker, or reuses a closed one, and returns its ID.
+func (s *RPCService) NewChecker(lightMode bool) uint64 {
+	ccc := s.reuseChecker(lightMode)
+	if ccc == nil {
+		ccc = s.newChecker(lightMode)
+		log.Info("new CircuitCapacityChecker in RPCService", "id", ccc.ID(), "lightMode", lightMode)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.checkers[ccc.ID()] = ccc
+	return ccc.ID()
+}
+
+// reuseChecker resets a closed checker for reuse, nil if there is none.
+func (s *RPCService) reuseChecker(lightMode bool) Checker {
+	s.mu.Lock()
+	if len(s.idle) == 0 {
+		s.mu.Unlock()
+		return nil
+	}
+	ccc := s.idle[len(s.idle)-1]
+	s.idle = s.idle[:len(s.idle)-1]
+	s.mu.Unlock()
+
+	ccc.Reset()
+	if err := ccc.SetLightMode(lightMode); err != nil {
+		log.Warn("failed to reuse CircuitCapacityChecker in RPCService", "id", ccc.ID(), "err", err)
+		return nil
+	}
+	log.Debug("reuse CircuitCapacityChecker in RPCService", "id", ccc.ID(), "lightMode", lightMode)
+	return ccc
+}
+
+// CloseChecker releases a checker, which is kept for reuse.
+func (s *RPCService) CloseChecker(id uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if ccc, ok := s.checkers[id]; ok {
+		delete(s.checkers, id)
+		s.idle = append(s.idle, ccc)
+	}
+}
+
+// Reset resets a checker.
+func (s *RPCService) Reset(id uint64) error {
+	ccc, err := s.checker(id)
+	if err != nil {
+		return err
+	}
+	ccc.Reset()
+	return nil
+}
+
+// ApplyTransaction appends a tx's wrapped BlockTrace into a checker, and returns the accumulated RowConsumption.
+func (s *RPCService) ApplyTransaction(id uint64, traces *types.BlockTrace) (*types.RowConsumption, error) {
+	ccc, err := s.checker(id)
+	if err != nil {
+		return nil, err
+	}
+	rc, err := ccc.ApplyTransaction(traces)
+	return rc, toRPCError(err)
+}
+
+// ApplyBlock gets a block's RowConsumption.
+func (s *RPCService) ApplyBlock(id uint64, traces *types.BlockTrace) (*types.RowConsumption, error) {
+	ccc, err := s.checker(id)
+	if err != nil {
+		return nil, err
+	}
+	rc, err := ccc.ApplyBlock(traces)
+	return rc, toRPCError(err)
+}
+
+// CheckTxNum compares whether the number of transactions applied to a checker matches the expected.
+func (s *RPCService) CheckTxNum(id uint64, expected int) (*CheckTxNumResult, error) {
+	ccc, err := s.checker(id)
+	if err != nil {
+		return nil, err
+	}
+	match, txNum, err := ccc.CheckTxNum(expected)
+	if err != nil {
+		return nil, toRPCError(err)
+	}
+	return &CheckTxNumResult{Match: match, TxNum: txNum}, nil
+}
+
+// SetLightMode sets the light mode of a checker.
+func (s *RPCService) SetLightMode(id uint64, lightMode bool) error {
+	ccc, err := s.checker(id)
+	if err != nil {
+		return err
+	}
+	return toRPCError(ccc.SetLightMode(lightMode))
+}
@@ -0,0 +1,112 @@
+package circuitcapacitychecker
+
+import (
+	"sort"
+
+	"github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/metrics"
+)
+
+// UpdateRowConsumptionMetrics records the rows of each sub-circuit of a block, and their share of the row limit
+// maxRows in permille, in the ccc/subcircuit/<name>/rows and ccc/subcircuit/<name>/share histograms.
+// The share is only recorded if the row limit is known.
+func UpdateRowConsumptionMetrics(rc *types.RowConsumption, maxRows uint64) {
+	if !metrics.Enabled || rc == nil {
+		return
+	}
+	sampler := func() metrics.Sample { return metrics.NewExpDecaySample(1028, 0.015) }
+	for _, usage := range *rc {
+		metrics.GetOrRegisterHistogramLazy("ccc/subcircuit/"+usage.Name+"/rows", nil, sampler).Update(int64(usage.RowNumber))
+		if maxRows != 0 {
+			metrics.GetOrRegisterHistogramLazy("ccc/subcircuit/"+usage.Name+"/share", nil, sampler).Update(int64(rowShare(usage.RowNumber, maxRows)))
+		}
+	}
+}
+
+// rowShare returns the share of rows in maxRows in permille.
+func rowShare(rows, maxRows uint64) uint64 {
+	if maxRows == 0 {
+		return 0
+	}
+	return rows * 1000 / maxRows
+}
+
+// SubCircuitStats aggregates the rows of a sub-circuit over a range of blocks.
+type SubCircuitStats struct {
+	Name         string `json:"name"`
+	TotalRows    uint64 `json:"totalRows"`
+	AverageRows  uint64 `json:"averageRows"`  // average rows per block with row consumption
+	MaxRows      uint64 `json:"maxRows"`      // most rows in a block
+	MaxRowsBlock uint64 `json:"maxRowsBlock"` // number of the block with the most rows
+	MaxShare     uint64 `json:"maxShare"`     // most rows in a block, in permille of the row limit
+	Bottleneck   uint64 `json:"bottleneck"`   // number of blocks in which the sub-circuit has the most rows
+}
+
+// RowConsumptionStats aggregates the row consumption of a range of blocks.
+type RowConsumptionStats struct {
+	FromBlock            uint64            `json:"fromBlock"`
+	ToBlock              uint64            `json:"toBlock"`
+	Blocks               uint64            `json:"blocks"`        // number of blocks with row consumption
+	MissingBlocks        uint64            `json:"missingBlocks"` // number of blocks without row consumption
+	MaxRowsPerSubCircuit uint64            `json:"maxRowsPerSubCircuit"`
+	SubCircuits          []SubCircuitStats `json:"subCircuits"` // sorted by name
+}
+
+// RowConsumptionAggregator aggregates the row consumption of blocks into RowConsumptionStats.
+type RowConsumptionAggregator struct {
+	stats       RowConsumptionStats
+	subCircuits map[string]*SubCircuitStats
+}
+
+// NewRowConsumptionAggregator creates an aggregator for the blocks from fromBlock to toBlock. The shares of
+// the row limit maxRows are 0 if the limit is 0.
+func NewRowConsumptionAggregator(fromBlock, toBlock, maxRows uint64) *RowConsumptionAggregator {
+	return &RowConsumptionAggregator{
+		stats:       RowConsumptionStats{FromBlock: fromBlock, ToBlock: toBlock, MaxRowsPerSubCircuit: maxRows},
+		subCircuits: make(map[string]*SubCircuitStats),
+	}
+}
+
+// Add adds the row consumption of a block, nil if the block has none.
+func (a *RowConsumptionAggregator) Add(number uint64, rc *types.RowConsumption) {
+	if rc == nil {
+		a.stats.MissingBlocks++
+		return
+	}
+	a.stats.Blocks++
+
+	var bottleneck *SubCircuitStats
+	var bottleneckRows uint64
+	for _, usage := range *rc {
+		s, ok := a.subCircuits[usage.Name]
+		if !ok {
+			s = &SubCircuitStats{Name: usage.Name}
+			a.subCircuits[usage.Name] = s
+		}
+		s.TotalRows += usage.RowNumber
+		if usage.RowNumber > s.MaxRows {
+			s.MaxRows = usage.RowNumber
+			s.MaxRowsBlock = number
+		}
+		if bottleneck == nil || usage.RowNumber > bottleneckRows {
+			bottleneck, bottleneckRows = s, usage.RowNumber
+		}
+	}
+	if bottleneck != nil {
+		bottleneck.Bottleneck++
+	}
+}
+
+// Stats returns the aggregated row consumption.
+func (a *RowConsumptionAggregator) Stats() *RowConsumptionStats {
+	stats := a.stats
+	stats.SubCircuits = make([]SubCircuitStats, 0, len(a.subCircuits))
+	for _, s := range a.subCircuits {
+		cpy := *s
+		cpy.AverageRows = cpy.TotalRows / stats.Blocks
+		cpy.MaxShare = rowShare(cpy.MaxRows, stats.MaxRowsPerSubCircuit)
+		stats.SubCircuits = append(stats.SubCircuits, cpy)
+	}
+	sort.Slice(stats.SubCircuits, func(i, j int) bool { return stats.SubCircuits[i].Name < stats.SubCircuits[j].Name })
+	return &stats
+}
@@ -0,0 +1,56 @@
+package circuitcapacitychecker
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/scroll-tech/go-ethereum/core/types"
+)
+
+func TestRowConsumptionAggregator(t *testing.T) {
+	a := NewRowConsumptionAggregator(10, 13, 1000)
+	a.Add(10, &types.RowConsumption{{Name: "evm", RowNumber: 100}, {Name: "keccak", RowNumber: 50}})
+	a.Add(11, nil)
+	a.Add(12, &types.RowConsumption{{Name: "evm", RowNumber: 20}, {Name: "keccak", RowNumber: 300}})
+	a.Add(13, &types.RowConsumption{{Name: "evm", RowNumber: 60}, {Name: "keccak", RowNumber: 10}})
+
+	stats := a.Stats()
+	assert.Equal(t, uint64(10), stats.FromBlock)
+	assert.Equal(t, uint64(13), stats.ToBlock)
+	assert.Equal(t, uint64(3), stats.Blocks)
+	assert.Equal(t, uint64(1), stats.MissingBlocks)
+	assert.Equal(t, uint64(1000), stats.MaxRowsPerSubCircuit)
+	assert.Equal(t, []SubCircuitStats{
+		{Name: "evm", TotalRows: 180, AverageRows: 60, MaxRows: 100, MaxRowsBlock: 10, MaxShare: 100, Bottleneck: 2},
+		{Name: "keccak", TotalRows: 360, AverageRows: 120, MaxRows: 300, MaxRowsBlock: 12, MaxShare: 300, Bottleneck: 1},
+	}, stats.SubCircuits)
+}
+
+func TestRowConsumptionAggregatorEmpty(t *testing.T) {
+	a := NewRowConsumptionAggregator(1, 2, DefaultMaxRows)
+	a.Add(1, nil)
+	a.Add(2, nil)
+
+	stats := a.Stats()
+	assert.Equal(t, uint64(0), stats.Blocks)
+	assert.Equal(t, uint64(2), stats.MissingBlocks)
+	assert.Empty(t, stats.SubCircuits)
+}
+
+func TestRowShare(t *testing.T) {
+	assert.Equal(t, uint64(0), rowShare(10, 0))
+	assert.Equal(t, uint64(500), rowShare(50, 100))
+	assert.Equal(t, uint64(1200), rowShare(120, 100))
+}
+
+func TestOverflowSubCircuit(t *testing.T) {
+	err := &RowConsumptionOverflowError{RowConsumption: types.RowConsumption{{Name: "evm", RowNumber: 10}, {Name: "keccak", RowNumber: 20}}}
+	assert.ErrorIs(t, err, ErrBlockRowConsumptionOverflow)
+	assert.Equal(t, "block row consumption overflow in keccak", err.Error())
+	assert.Equal(t, "keccak", OverflowSubCircuit(fmt.Errorf("failed to apply tx, err: %w", err)))
+
+	assert.Equal(t, "", OverflowSubCircuit(ErrBlockRowConsumptionOverflow))
+	assert.Equal(t, "", OverflowSubCircuit(nil))
+}
@@ -0,0 +1,107 @@
+package circuitcapacitychecker
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/scroll-tech/go-ethereum/log"
+)
+
+const (
+	// minRestartDelay is the delay before restarting a checker process that exited.
+	minRestartDelay = time.Second
+
+	// maxRestartDelay is the maximum delay before restarting a checker process that keeps exiting.
+	maxRestartDelay = time.Minute
+
+	// stableRunTime is the run time after which a checker process is considered stable, resetting the restart delay.
+	stableRunTime = time.Minute
+)
+
+// Supervisor runs the checker process of a RemoteCircuitCapacityChecker and restarts it if it exits,
+// e.g. when libzkp crashes. Restarts are delayed with an exponential backoff while the process keeps exiting.
+type Supervisor struct {
+	command []string
+
+	ctx      context.Context
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+	restarts uint64
+
+	minRestartDelay time.Duration
+}
+
+// NewSupervisor creates a new Supervisor that runs command.
+func NewSupervisor(ctx context.Context, command []string) (*Supervisor, error) {
+	if len(command) == 0 {
+		return nil, errors.New("empty circuit capacity checker command")
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	return &Supervisor{command: command, ctx: ctx, cancel: cancel, minRestartDelay: minRestartDelay}, nil
+}
+
+// Start starts the checker process.
+func (s *Supervisor) Start() {
+	log.Info("Starting circuit capacity checker supervisor", "command", s.command)
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.loop()
+	}()
+}
+
+// Stop kills the checker process and waits for the supervisor to exit.
+func (s *Supervisor) Stop() {
+	log.Info("Stopping circuit capacity checker supervisor")
+	s.cancel()
+	s.wg.Wait()
+	log.Info("Circuit capacity checker supervisor stopped")
+}
+
+// Restarts returns the number of times the checker process was restarted.
+func (s *Supervisor) Restarts() uint64 {
+	return atomic.LoadUint64(&s.restarts)
+}
+
+func (s *Supervisor) loop() {
+	delay := s.minRestartDelay
+	for {
+		start := time.Now()
+		err := s.run()
+		if s.ctx.Err() != nil {
+			return
+		}
+		if time.Since(start) > stableRunTime {
+			delay = s.minRestartDelay
+		}
+		log.Error("Circuit capacity checker process exited, restarting", "command", s.command, "err", err, "delay", delay)
+
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+		atomic.AddUint64(&s.restarts, 1)
+		if delay *= 2; delay > maxRestartDelay {
+			delay = maxRestartDelay
+		}
+	}
+}
+
+// run runs the checker process until it exits or the supervisor is stopped.
+func (s *Supervisor) run() error {
+	cmd := exec.CommandContext(s.ctx, s.command[0], s.command[1:]...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	log.Info("Started circuit capacity checker process", "pid", cmd.Process.Pid)
+	return cmd.Wait()
+}
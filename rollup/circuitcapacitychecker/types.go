@@ -2,6 +2,7 @@ package circuitcapacitychecker
 
 import (
 	"errors"
+	"fmt"
 
 	"github.com/scroll-tech/go-ethereum/core/types"
 )
@@ -9,8 +10,50 @@ import (
 var (
 	ErrUnknown                     = errors.New("unknown circuit capacity checker error")
 	ErrBlockRowConsumptionOverflow = errors.New("block row consumption overflow")
+
+	// ErrCheckerUnavailable is returned if the checker could not check a tx or block at all, e.g. because
+	// the checker process is unreachable or lost the applied transactions. It says nothing about the tx.
+	ErrCheckerUnavailable = errors.New("circuit capacity checker unavailable")
 )
 
+// RowConsumptionOverflowError is returned when the rows of a sub-circuit exceed its limit. It carries
+// the row consumption that overflowed, so that callers can tell which sub-circuit caused the overflow.
+type RowConsumptionOverflowError struct {
+	RowConsumption types.RowConsumption
+}
+
+func (e *RowConsumptionOverflowError) Error() string {
+	if name := e.SubCircuit(); name != "" {
+		return fmt.Sprintf("%v in %s", ErrBlockRowConsumptionOverflow, name)
+	}
+	return ErrBlockRowConsumptionOverflow.Error()
+}
+
+func (e *RowConsumptionOverflowError) Unwrap() error { return ErrBlockRowConsumptionOverflow }
+
+// SubCircuit returns the name of the sub-circuit with the most rows, which is the one that overflowed
+// since all sub-circuits have the same row limit, or an empty string if the row consumption is unknown.
+func (e *RowConsumptionOverflowError) SubCircuit() string {
+	var name string
+	var rows uint64
+	for _, usage := range e.RowConsumption {
+		if name == "" || usage.RowNumber > rows {
+			name, rows = usage.Name, usage.RowNumber
+		}
+	}
+	return name
+}
+
+// OverflowSubCircuit returns the name of the sub-circuit that caused a row consumption overflow,
+// or an empty string if err does not carry the row consumption.
+func OverflowSubCircuit(err error) string {
+	var overflowErr *RowConsumptionOverflowError
+	if errors.As(err, &overflowErr) {
+		return overflowErr.SubCircuit()
+	}
+	return ""
+}
+
 type WrappedCommonResult struct {
 	Error string `json:"error,omitempty"`
 }
@@ -0,0 +1,36 @@
+package proposer
+
+import (
+	"time"
+)
+
+// Config contains the limits under which the proposer groups L2 blocks into chunks and chunks into batches.
+// A zero value disables the corresponding limit.
+type Config struct {
+	MaxBlockNumPerChunk             uint64        // maximum number of blocks in a chunk, at most 255
+	MaxTxNumPerChunk                uint64        // maximum number of transactions in a chunk
+	MaxL1CommitGasPerChunk          uint64        // maximum estimated L1 gas for committing a chunk
+	MaxL1CommitCalldataSizePerChunk uint64        // maximum estimated calldata size for committing a chunk
+	MaxRowConsumptionPerChunk       uint64        // maximum number of rows of any sub-circuit in a chunk
+	ChunkTimeout                    time.Duration // propose a chunk that is not full once its first block is this old
+	MaxChunkNumPerBatch             uint64        // maximum number of chunks in a batch, further limited by the codec
+	MaxL1CommitGasPerBatch          uint64        // maximum estimated L1 gas for committing a batch
+	MaxL1CommitCalldataSizePerBatch uint64        // maximum estimated calldata size for committing a batch
+	BatchTimeout                    time.Duration // propose a batch that is not full once its first block is this old
+	MaxBlobSize                     uint64        // maximum estimated blob size of a chunk or batch
+}
+
+// DefaultConfig contains the default proposer limits.
+var DefaultConfig = Config{
+	MaxBlockNumPerChunk:             100,
+	MaxTxNumPerChunk:                100,
+	MaxL1CommitGasPerChunk:          5_000_000,
+	MaxL1CommitCalldataSizePerChunk: 110_000,
+	MaxRowConsumptionPerChunk:       1_000_000,
+	ChunkTimeout:                    5 * time.Minute,
+	MaxChunkNumPerBatch:             45,
+	MaxL1CommitGasPerBatch:          5_000_000,
+	MaxL1CommitCalldataSizePerBatch: 110_000,
+	BatchTimeout:                    10 * time.Minute,
+	MaxBlobSize:                     4096 * 32, // the size of a blob
+}
@@ -0,0 +1,542 @@
+package proposer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/scroll-tech/go-ethereum/core/rawdb"
+	"github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/ethdb"
+	"github.com/scroll-tech/go-ethereum/log"
+	"github.com/scroll-tech/go-ethereum/metrics"
+	"github.com/scroll-tech/go-ethereum/params"
+
+	"github.com/scroll-tech/go-ethereum/rollup/types/encoding"
+	_ "github.com/scroll-tech/go-ethereum/rollup/types/encoding/codecv0" // register codecv0
+	_ "github.com/scroll-tech/go-ethereum/rollup/types/encoding/codecv1" // register codecv1
+	_ "github.com/scroll-tech/go-ethereum/rollup/types/encoding/codecv2" // register codecv2
+)
+
+const (
+	// defaultProposeInterval is the frequency at which we try to propose new chunks and batches.
+	defaultProposeInterval = 10 * time.Second
+
+	// maxBlockNumPerChunk is the number of blocks that fits into the block count field of an encoded chunk.
+	maxBlockNumPerChunk = 255
+)
+
+var (
+	proposedChunkCounter = metrics.NewRegisteredCounter("rollup/proposer/chunk", nil)
+	proposedBatchCounter = metrics.NewRegisteredCounter("rollup/proposer/batch", nil)
+	chunkTimeoutCounter  = metrics.NewRegisteredCounter("rollup/proposer/chunk/timeout", nil)
+	batchTimeoutCounter  = metrics.NewRegisteredCounter("rollup/proposer/batch/timeout", nil)
+	chunkSplitCounter    = metrics.NewRegisteredCounter("rollup/proposer/chunk/split", nil)
+	batchSplitCounter    = metrics.NewRegisteredCounter("rollup/proposer/batch/split", nil)
+
+	// oversizedBlockGauge is the number of a block that cannot be committed even in a batch of its own.
+	// The proposer stops at this block until the operator intervenes, 0 if there is none.
+	oversizedBlockGauge = metrics.NewRegisteredGauge("rollup/proposer/oversized_block", nil)
+)
+
+// errOversizedBlock is returned if a block exceeds the limits of its codec even in a batch of its own.
+var errOversizedBlock = errors.New("block cannot be committed even in a batch of its own")
+
+// BlockChain is the subset of core.BlockChain used by the proposer.
+type BlockChain interface {
+	Config() *params.ChainConfig
+	CurrentBlock() *types.Block
+	GetBlockByNumber(number uint64) *types.Block
+}
+
+// Proposer groups the blocks of the local canonical chain into chunks and batches under
+// the configured limits, and stores the proposals in the database.
+// Proposals are final, they are not rolled back if the local chain reorgs.
+type Proposer struct {
+	ctx         context.Context
+	cancel      context.CancelFunc
+	config      Config
+	chainConfig *params.ChainConfig
+	db          ethdb.Database
+	bc          BlockChain
+	lastChunk   *rawdb.ProposedChunk
+	lastBatch   *rawdb.ProposedBatch
+	now         func() time.Time
+
+	// oversizedBlock is the number of the block the proposer stopped at, see errOversizedBlock.
+	oversizedBlock *uint64
+}
+
+// NewProposer creates a new proposer. On the first start it stores the genesis chunk and batch,
+// otherwise it continues after the latest proposals in the database.
+func NewProposer(ctx context.Context, config Config, db ethdb.Database, bc BlockChain) (*Proposer, error) {
+	ctx, cancel := context.WithCancel(ctx)
+
+	p := &Proposer{
+		ctx:         ctx,
+		cancel:      cancel,
+		config:      config,
+		chainConfig: bc.Config(),
+		db:          db,
+		bc:          bc,
+		now:         time.Now,
+	}
+
+	lastChunkIndex := rawdb.ReadLastProposedChunkIndex(db)
+	lastBatchIndex := rawdb.ReadLastProposedBatchIndex(db)
+	if lastChunkIndex == nil || lastBatchIndex == nil {
+		if err := p.proposeGenesis(); err != nil {
+			cancel()
+			return nil, fmt.Errorf("failed to propose genesis batch, err: %w", err)
+		}
+		return p, nil
+	}
+
+	p.lastChunk = rawdb.ReadProposedChunk(db, *lastChunkIndex)
+	p.lastBatch = rawdb.ReadProposedBatch(db, *lastBatchIndex)
+	if p.lastChunk == nil || p.lastBatch == nil {
+		cancel()
+		return nil, fmt.Errorf("missing latest proposals in database, chunk index: %d, batch index: %d", *lastChunkIndex, *lastBatchIndex)
+	}
+	return p, nil
+}
+
+func (p *Proposer) Start() {
+	if p == nil {
+		return
+	}
+
+	log.Info("Starting chunk and batch proposer", "last chunk index", p.lastChunk.Index, "last batch index", p.lastBatch.Index, "last proposed block", p.lastChunk.EndBlockNumber)
+
+	go func() {
+		ticker := time.NewTicker(defaultProposeInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-p.ctx.Done():
+				return
+			case <-ticker.C:
+				p.propose()
+			}
+		}
+	}()
+}
+
+func (p *Proposer) Stop() {
+	if p == nil {
+		return
+	}
+
+	log.Info("Stopping chunk and batch proposer")
+
+	if p.cancel != nil {
+		p.cancel()
+	}
+}
+
+// propose proposes as many chunks and batches as the current chain allows.
+func (p *Proposer) propose() {
+	// no chunk can be proposed past an oversized block, it was reported when it was found
+	for p.oversizedBlock == nil && p.ctx.Err() == nil {
+		proposed, err := p.proposeChunk()
+		if errors.Is(err, errOversizedBlock) {
+			log.Error("Stopping chunk and batch proposer at a block that cannot be committed, the codec limits must be raised", "err", err)
+			break
+		}
+		if err != nil {
+			log.Error("failed to propose chunk", "err", err)
+			return
+		}
+		if !proposed {
+			break
+		}
+	}
+	for p.ctx.Err() == nil {
+		proposed, err := p.proposeBatch()
+		if err != nil {
+			log.Error("failed to propose batch", "err", err)
+			return
+		}
+		if !proposed {
+			break
+		}
+	}
+}
+
+// proposeGenesis stores the genesis chunk and batch, which only contain the genesis block.
+func (p *Proposer) proposeGenesis() error {
+	genesis := p.bc.GetBlockByNumber(0)
+	if genesis == nil {
+		return fmt.Errorf("missing genesis block")
+	}
+	chunk := &encoding.Chunk{Blocks: []*encoding.Block{{Header: genesis.Header(), Transactions: encoding.TxsToTxsData(genesis.Transactions())}}}
+	codec, err := encoding.CodecFromVersion(encoding.CodecV0)
+	if err != nil {
+		return err
+	}
+	chunkHash, err := codec.ChunkHash(chunk, 0)
+	if err != nil {
+		return fmt.Errorf("failed to hash genesis chunk, err: %w", err)
+	}
+	daBatch, err := codec.NewDABatch(&encoding.Batch{Index: 0, Chunks: []*encoding.Chunk{chunk}})
+	if err != nil {
+		return fmt.Errorf("failed to create genesis batch, err: %w", err)
+	}
+
+	p.writeChunk(&rawdb.ProposedChunk{Index: 0, Hash: chunkHash, CodecVersion: uint64(encoding.CodecV0)})
+	p.writeBatch(&rawdb.ProposedBatch{Index: 0, Hash: daBatch.Hash(), Header: daBatch.Encode(), CodecVersion: uint64(encoding.CodecV0)})
+	return nil
+}
+
+// proposeChunk proposes the next chunk if enough blocks are available or if the chunk timed out.
+// A chunk ends before the first block that would exceed a limit, and at the last block before
+// a change of the codec version.
+func (p *Proposer) proposeChunk() (bool, error) {
+	start := p.lastChunk.EndBlockNumber + 1
+	head := p.bc.CurrentBlock().NumberU64()
+	if start > head {
+		return false, nil
+	}
+
+	codecVersion := encoding.CodecVersionFromConfig(p.chainConfig, new(big.Int).SetUint64(start))
+	codec, err := encoding.CodecFromVersion(codecVersion)
+	if err != nil {
+		return false, err
+	}
+
+	maxBlockNum := uint64(maxBlockNumPerChunk)
+	if p.config.MaxBlockNumPerChunk != 0 && p.config.MaxBlockNumPerChunk < maxBlockNum {
+		maxBlockNum = p.config.MaxBlockNumPerChunk
+	}
+
+	chunk := &encoding.Chunk{}
+	full := false
+	for number := start; number <= head; number++ {
+		if encoding.CodecVersionFromConfig(p.chainConfig, new(big.Int).SetUint64(number)) != codecVersion {
+			full = true
+			break
+		}
+
+		block, err := p.getBlock(number, p.config.MaxRowConsumptionPerChunk != 0)
+		if err != nil {
+			return false, err
+		}
+		if block == nil {
+			// the row consumption of the block is not available yet
+			break
+		}
+
+		chunk.Blocks = append(chunk.Blocks, block)
+		exceeds, err := p.chunkExceedsLimits(codec, chunk)
+		if err != nil {
+			return false, err
+		}
+		if !exceeds {
+			if uint64(len(chunk.Blocks)) == maxBlockNum {
+				full = true
+				break
+			}
+			continue
+		}
+		if len(chunk.Blocks) == 1 {
+			log.Warn("Block exceeds chunk limits, proposing it as a single-block chunk", "number", number)
+		} else {
+			chunk.Blocks = chunk.Blocks[:len(chunk.Blocks)-1]
+		}
+		full = true
+		break
+	}
+
+	if len(chunk.Blocks) == 0 {
+		return false, nil
+	}
+	if !full {
+		if !p.timedOut(chunk.Blocks[0].Header.Time, p.config.ChunkTimeout) {
+			return false, nil
+		}
+		chunkTimeoutCounter.Inc(1)
+	}
+
+	totalL1MessagePoppedBefore := p.lastChunk.TotalL1MessagePopped
+	// the configured limits are estimates, a chunk must also fit into a batch of its own under the codec
+	// limits, otherwise no batch can ever contain it. Split it until it does.
+	for {
+		_, err := codec.NewDABatch(&encoding.Batch{Index: p.lastBatch.Index + 1, TotalL1MessagePoppedBefore: totalL1MessagePoppedBefore, Chunks: []*encoding.Chunk{chunk}})
+		if err == nil {
+			break
+		}
+		if len(chunk.Blocks) == 1 {
+			p.oversizedBlock = &start
+			oversizedBlockGauge.Update(int64(start))
+			return false, fmt.Errorf("%w, number: %d, codec version: %d, err: %v", errOversizedBlock, start, codecVersion, err)
+		}
+		log.Warn("Chunk exceeds the codec limits, splitting it", "start block", start, "blocks", len(chunk.Blocks), "err", err)
+		chunk.Blocks = chunk.Blocks[:len(chunk.Blocks)/2]
+		chunkSplitCounter.Inc(1)
+	}
+
+	chunkHash, err := codec.ChunkHash(chunk, totalL1MessagePoppedBefore)
+	if err != nil {
+		return false, fmt.Errorf("failed to hash chunk, err: %w", err)
+	}
+
+	p.writeChunk(&rawdb.ProposedChunk{
+		Index:                      p.lastChunk.Index + 1,
+		StartBlockNumber:           start,
+		EndBlockNumber:             start + uint64(len(chunk.Blocks)) - 1,
+		TotalL1MessagePoppedBefore: totalL1MessagePoppedBefore,
+		TotalL1MessagePopped:       totalL1MessagePoppedBefore + chunk.NumL1Messages(totalL1MessagePoppedBefore),
+		Hash:                       chunkHash,
+		CodecVersion:               uint64(codecVersion),
+	})
+	proposedChunkCounter.Inc(1)
+	log.Debug("Proposed chunk", "index", p.lastChunk.Index, "start block", p.lastChunk.StartBlockNumber, "end block", p.lastChunk.EndBlockNumber, "hash", p.lastChunk.Hash.Hex())
+	return true, nil
+}
+
+// proposeBatch proposes the next batch if enough chunks are available or if the batch timed out.
+// A batch ends before the first chunk that would exceed a limit, and at the last chunk before
+// a change of the codec version.
+func (p *Proposer) proposeBatch() (bool, error) {
+	start := p.lastBatch.EndChunkIndex + 1
+	if start > p.lastChunk.Index {
+		return false, nil
+	}
+
+	firstChunk := rawdb.ReadProposedChunk(p.db, start)
+	if firstChunk == nil {
+		return false, fmt.Errorf("missing proposed chunk, index: %d", start)
+	}
+	codec, err := encoding.CodecFromVersion(encoding.CodecVersion(firstChunk.CodecVersion))
+	if err != nil {
+		return false, err
+	}
+
+	maxChunkNum := codec.MaxNumChunks()
+	if p.config.MaxChunkNumPerBatch != 0 && (maxChunkNum == 0 || p.config.MaxChunkNumPerBatch < maxChunkNum) {
+		maxChunkNum = p.config.MaxChunkNumPerBatch
+	}
+
+	batch := &encoding.Batch{
+		Index:                      p.lastBatch.Index + 1,
+		TotalL1MessagePoppedBefore: p.lastBatch.TotalL1MessagePopped,
+		ParentBatchHash:            p.lastBatch.Hash,
+	}
+	var chunks []*rawdb.ProposedChunk
+	full := false
+	for index := start; index <= p.lastChunk.Index; index++ {
+		proposedChunk := rawdb.ReadProposedChunk(p.db, index)
+		if proposedChunk == nil {
+			return false, fmt.Errorf("missing proposed chunk, index: %d", index)
+		}
+		if proposedChunk.CodecVersion != firstChunk.CodecVersion {
+			full = true
+			break
+		}
+
+		chunk, err := p.getChunk(proposedChunk)
+		if err != nil {
+			return false, err
+		}
+
+		batch.Chunks = append(batch.Chunks, chunk)
+		chunks = append(chunks, proposedChunk)
+		exceeds, err := p.batchExceedsLimits(codec, batch)
+		if err != nil {
+			return false, err
+		}
+		if !exceeds {
+			if uint64(len(batch.Chunks)) == maxChunkNum {
+				full = true
+				break
+			}
+			continue
+		}
+		if len(batch.Chunks) == 1 {
+			log.Warn("Chunk exceeds batch limits, proposing it as a single-chunk batch", "index", index)
+		} else {
+			batch.Chunks = batch.Chunks[:len(batch.Chunks)-1]
+			chunks = chunks[:len(chunks)-1]
+		}
+		full = true
+		break
+	}
+
+	if !full {
+		if !p.timedOut(batch.Chunks[0].Blocks[0].Header.Time, p.config.BatchTimeout) {
+			return false, nil
+		}
+		batchTimeoutCounter.Inc(1)
+	}
+
+	// each chunk fits into a batch of its own, so a batch over the codec limits can be split
+	daBatch, err := codec.NewDABatch(batch)
+	for err != nil && len(batch.Chunks) > 1 {
+		log.Warn("Batch exceeds the codec limits, splitting it", "index", batch.Index, "chunks", len(batch.Chunks), "err", err)
+		batch.Chunks = batch.Chunks[:len(batch.Chunks)/2]
+		chunks = chunks[:len(batch.Chunks)]
+		batchSplitCounter.Inc(1)
+		daBatch, err = codec.NewDABatch(batch)
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to create batch, index: %d, err: %w", batch.Index, err)
+	}
+
+	lastChunk := chunks[len(chunks)-1]
+	p.writeBatch(&rawdb.ProposedBatch{
+		Index:                      batch.Index,
+		StartChunkIndex:            firstChunk.Index,
+		EndChunkIndex:              lastChunk.Index,
+		StartBlockNumber:           firstChunk.StartBlockNumber,
+		EndBlockNumber:             lastChunk.EndBlockNumber,
+		TotalL1MessagePoppedBefore: batch.TotalL1MessagePoppedBefore,
+		TotalL1MessagePopped:       lastChunk.TotalL1MessagePopped,
+		ParentBatchHash:            batch.ParentBatchHash,
+		Hash:                       daBatch.Hash(),
+		Header:                     daBatch.Encode(),
+		CodecVersion:               firstChunk.CodecVersion,
+	})
+	proposedBatchCounter.Inc(1)
+	log.Info("Proposed batch", "index", batch.Index, "chunks", len(chunks), "start block", firstChunk.StartBlockNumber, "end block", lastChunk.EndBlockNumber, "hash", p.lastBatch.Hash.Hex())
+	return true, nil
+}
+
+func (p *Proposer) chunkExceedsLimits(codec encoding.Codec, chunk *encoding.Chunk) (bool, error) {
+	if p.config.MaxTxNumPerChunk != 0 && chunk.NumTransactions() > p.config.MaxTxNumPerChunk {
+		return true, nil
+	}
+	if p.config.MaxRowConsumptionPerChunk != 0 {
+		rows, err := chunk.CrcMax()
+		if err != nil {
+			return false, err
+		}
+		if rows > p.config.MaxRowConsumptionPerChunk {
+			return true, nil
+		}
+	}
+	if p.config.MaxL1CommitGasPerChunk != 0 {
+		gas, err := codec.EstimateChunkL1CommitGas(chunk)
+		if err != nil {
+			return false, fmt.Errorf("failed to estimate chunk L1 commit gas, err: %w", err)
+		}
+		if gas > p.config.MaxL1CommitGasPerChunk {
+			return true, nil
+		}
+	}
+	if p.config.MaxL1CommitCalldataSizePerChunk != 0 {
+		size, err := codec.EstimateChunkL1CommitCalldataSize(chunk)
+		if err != nil {
+			return false, fmt.Errorf("failed to estimate chunk L1 commit calldata size, err: %w", err)
+		}
+		if size > p.config.MaxL1CommitCalldataSizePerChunk {
+			return true, nil
+		}
+	}
+	if p.config.MaxBlobSize != 0 {
+		size, err := codec.EstimateChunkL1CommitBlobSize(chunk)
+		if err != nil {
+			return false, fmt.Errorf("failed to estimate chunk L1 commit blob size, err: %w", err)
+		}
+		if size > p.config.MaxBlobSize {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (p *Proposer) batchExceedsLimits(codec encoding.Codec, batch *encoding.Batch) (bool, error) {
+	if p.config.MaxL1CommitGasPerBatch != 0 {
+		gas, err := codec.EstimateBatchL1CommitGas(batch)
+		if err != nil {
+			return false, fmt.Errorf("failed to estimate batch L1 commit gas, err: %w", err)
+		}
+		if gas > p.config.MaxL1CommitGasPerBatch {
+			return true, nil
+		}
+	}
+	if p.config.MaxL1CommitCalldataSizePerBatch != 0 {
+		size, err := codec.EstimateBatchL1CommitCalldataSize(batch)
+		if err != nil {
+			return false, fmt.Errorf("failed to estimate batch L1 commit calldata size, err: %w", err)
+		}
+		if size > p.config.MaxL1CommitCalldataSizePerBatch {
+			return true, nil
+		}
+	}
+	if p.config.MaxBlobSize != 0 {
+		size, err := codec.EstimateBatchL1CommitBlobSize(batch)
+		if err != nil {
+			return false, fmt.Errorf("failed to estimate batch L1 commit blob size, err: %w", err)
+		}
+		if size > p.config.MaxBlobSize {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// getBlock returns the block with the given number for encoding. If withRowConsumption is set,
+// it returns nil if the row consumption of the block is not available.
+func (p *Proposer) getBlock(number uint64, withRowConsumption bool) (*encoding.Block, error) {
+	block := p.bc.GetBlockByNumber(number)
+	if block == nil {
+		return nil, fmt.Errorf("failed to get block by number: %d", number)
+	}
+	var rowConsumption *types.RowConsumption
+	if withRowConsumption {
+		rowConsumption = rawdb.ReadBlockRowConsumption(p.db, block.Hash())
+		if rowConsumption == nil {
+			log.Debug("Waiting for row consumption of block", "number", number, "hash", block.Hash().Hex())
+			return nil, nil
+		}
+	}
+	return &encoding.Block{
+		Header:         block.Header(),
+		Transactions:   encoding.TxsToTxsData(block.Transactions()),
+		RowConsumption: rowConsumption,
+	}, nil
+}
+
+// getChunk reconstructs a proposed chunk from the local chain.
+func (p *Proposer) getChunk(proposedChunk *rawdb.ProposedChunk) (*encoding.Chunk, error) {
+	chunk := &encoding.Chunk{Blocks: make([]*encoding.Block, 0, proposedChunk.EndBlockNumber-proposedChunk.StartBlockNumber+1)}
+	for number := proposedChunk.StartBlockNumber; number <= proposedChunk.EndBlockNumber; number++ {
+		block, err := p.getBlock(number, false)
+		if err != nil {
+			return nil, err
+		}
+		chunk.Blocks = append(chunk.Blocks, block)
+	}
+	return chunk, nil
+}
+
+// timedOut reports whether a chunk or batch starting with a block of the given timestamp timed out.
+func (p *Proposer) timedOut(blockTime uint64, timeout time.Duration) bool {
+	if timeout == 0 {
+		return false
+	}
+	return time.Unix(int64(blockTime), 0).Add(timeout).Before(p.now())
+}
+
+func (p *Proposer) writeChunk(chunk *rawdb.ProposedChunk) {
+	batch := p.db.NewBatch()
+	rawdb.WriteProposedChunk(batch, chunk)
+	rawdb.WriteLastProposedChunkIndex(batch, chunk.Index)
+	if err := batch.Write(); err != nil {
+		log.Crit("failed to store proposed chunk", "index", chunk.Index, "err", err)
+	}
+	p.lastChunk = chunk
+}
+
+func (p *Proposer) writeBatch(proposedBatch *rawdb.ProposedBatch) {
+	batch := p.db.NewBatch()
+	rawdb.WriteProposedBatch(batch, proposedBatch)
+	rawdb.WriteLastProposedBatchIndex(batch, proposedBatch.Index)
+	if err := batch.Write(); err != nil {
+		log.Crit("failed to store proposed batch", "index", proposedBatch.Index, "err", err)
+	}
+	p.lastBatch = proposedBatch
+}
@@ -0,0 +1,243 @@
+package proposer
+
+import (
+	"context"
+	"crypto/rand"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/core/rawdb"
+	"github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/params"
+	"github.com/scroll-tech/go-ethereum/rollup/types/encoding"
+)
+
+// testChain is an in-memory chain of blocks that each include one L1 message.
+type testChain struct {
+	config *params.ChainConfig
+	blocks []*types.Block
+}
+
+func newTestChain(config *params.ChainConfig, numBlocks int) *testChain {
+	bc := &testChain{config: config}
+	bc.blocks = append(bc.blocks, types.NewBlockWithHeader(&types.Header{Number: big.NewInt(0), BaseFee: big.NewInt(1000), GasLimit: 10000000}))
+	bc.addBlocks(numBlocks)
+	return bc
+}
+
+func (bc *testChain) addBlocks(numBlocks int) {
+	to := common.HexToAddress("0x1234")
+	for i := 0; i < numBlocks; i++ {
+		number := uint64(len(bc.blocks))
+		header := &types.Header{Number: new(big.Int).SetUint64(number), Time: 1700000000 + number, BaseFee: big.NewInt(1000), GasLimit: 10000000}
+		l1Message := types.NewTx(&types.L1MessageTx{QueueIndex: number - 1, Gas: 100000, To: &to, Value: common.Big0, Sender: to})
+		bc.blocks = append(bc.blocks, types.NewBlockWithHeader(header).WithBody([]*types.Transaction{l1Message}, nil))
+	}
+}
+
+func (bc *testChain) Config() *params.ChainConfig { return bc.config }
+
+func (bc *testChain) CurrentBlock() *types.Block { return bc.blocks[len(bc.blocks)-1] }
+
+func (bc *testChain) GetBlockByNumber(number uint64) *types.Block {
+	if number >= uint64(len(bc.blocks)) {
+		return nil
+	}
+	return bc.blocks[number]
+}
+
+func TestProposeChunksAndBatches(t *testing.T) {
+	db := rawdb.NewMemoryDatabase()
+	bc := newTestChain(&params.ChainConfig{BernoulliBlock: big.NewInt(11)}, 20)
+	config := Config{MaxBlockNumPerChunk: 4, MaxChunkNumPerBatch: 3, ChunkTimeout: time.Minute, BatchTimeout: time.Minute}
+
+	p, err := NewProposer(context.Background(), config, db, bc)
+	require.NoError(t, err)
+	p.now = func() time.Time { return time.Unix(1700000000, 0) }
+
+	genesisBatch := rawdb.ReadProposedBatch(db, 0)
+	require.NotNil(t, genesisBatch)
+	assert.Equal(t, uint64(0), genesisBatch.EndBlockNumber)
+
+	p.propose()
+
+	// the chunk before the Bernoulli fork ends early, the last blocks wait for more blocks or the timeout
+	expectedChunks := [][2]uint64{{0, 0}, {1, 4}, {5, 8}, {9, 10}, {11, 14}, {15, 18}}
+	require.Equal(t, uint64(len(expectedChunks)-1), *rawdb.ReadLastProposedChunkIndex(db))
+	for i, blocks := range expectedChunks {
+		chunk := rawdb.ReadProposedChunk(db, uint64(i))
+		require.NotNil(t, chunk)
+		assert.Equal(t, blocks[0], chunk.StartBlockNumber)
+		assert.Equal(t, blocks[1], chunk.EndBlockNumber)
+		assert.Equal(t, blocks[1], chunk.TotalL1MessagePopped)
+		if i >= 4 {
+			assert.Equal(t, uint64(encoding.CodecV1), chunk.CodecVersion)
+		} else {
+			assert.Equal(t, uint64(encoding.CodecV0), chunk.CodecVersion)
+		}
+	}
+
+	// the first batch is full, the Bernoulli chunks wait for the timeout
+	require.Equal(t, uint64(1), *rawdb.ReadLastProposedBatchIndex(db))
+	batch := rawdb.ReadProposedBatch(db, 1)
+	require.NotNil(t, batch)
+	assert.Equal(t, uint64(1), batch.StartChunkIndex)
+	assert.Equal(t, uint64(3), batch.EndChunkIndex)
+	assert.Equal(t, uint64(10), batch.EndBlockNumber)
+	assert.Equal(t, uint64(10), batch.TotalL1MessagePopped)
+	assert.Equal(t, genesisBatch.Hash, batch.ParentBatchHash)
+
+	codec, err := encoding.CodecFromVersion(encoding.CodecV0)
+	require.NoError(t, err)
+	daBatch, err := codec.NewDABatchFromBytes(batch.Header)
+	require.NoError(t, err)
+	assert.Equal(t, batch.Hash, daBatch.Hash())
+
+	// after the timeout, the remaining blocks and chunks are proposed
+	p.now = func() time.Time { return time.Unix(1700000000, 0).Add(time.Hour) }
+	p.propose()
+
+	chunk := rawdb.ReadProposedChunk(db, 6)
+	require.NotNil(t, chunk)
+	assert.Equal(t, uint64(19), chunk.StartBlockNumber)
+	assert.Equal(t, uint64(20), chunk.EndBlockNumber)
+
+	batch = rawdb.ReadProposedBatch(db, 2)
+	require.NotNil(t, batch)
+	assert.Equal(t, uint64(4), batch.StartChunkIndex)
+	assert.Equal(t, uint64(6), batch.EndChunkIndex)
+	assert.Equal(t, uint64(10), batch.TotalL1MessagePoppedBefore)
+	assert.Equal(t, uint64(20), batch.TotalL1MessagePopped)
+	assert.Equal(t, uint64(encoding.CodecV1), batch.CodecVersion)
+	assert.Equal(t, rawdb.ReadProposedBatch(db, 1).Hash, batch.ParentBatchHash)
+
+	// a restarted proposer continues after the latest proposals
+	bc.addBlocks(4)
+	p, err = NewProposer(context.Background(), config, db, bc)
+	require.NoError(t, err)
+	p.propose()
+
+	chunk = rawdb.ReadProposedChunk(db, 7)
+	require.NotNil(t, chunk)
+	assert.Equal(t, uint64(21), chunk.StartBlockNumber)
+	assert.Equal(t, uint64(24), chunk.EndBlockNumber)
+	assert.Equal(t, uint64(20), chunk.TotalL1MessagePoppedBefore)
+}
+
+func TestProposeChunksRowConsumption(t *testing.T) {
+	db := rawdb.NewMemoryDatabase()
+	bc := newTestChain(&params.ChainConfig{}, 5)
+	config := Config{MaxRowConsumptionPerChunk: 100}
+
+	for _, block := range bc.blocks[1:4] {
+		rawdb.WriteBlockRowConsumption(db, block.Hash(), &types.RowConsumption{{Name: "mpt", RowNumber: 40}, {Name: "keccak", RowNumber: 10}})
+	}
+
+	p, err := NewProposer(context.Background(), config, db, bc)
+	require.NoError(t, err)
+
+	proposed, err := p.proposeChunk()
+	require.NoError(t, err)
+	assert.True(t, proposed)
+	chunk := rawdb.ReadProposedChunk(db, 1)
+	require.NotNil(t, chunk)
+	assert.Equal(t, uint64(1), chunk.StartBlockNumber)
+	assert.Equal(t, uint64(2), chunk.EndBlockNumber)
+
+	// the chunk is not proposed while the row consumption of the next block is missing
+	proposed, err = p.proposeChunk()
+	require.NoError(t, err)
+	assert.False(t, proposed)
+
+	rawdb.WriteBlockRowConsumption(db, bc.blocks[4].Hash(), &types.RowConsumption{{Name: "mpt", RowNumber: 70}})
+	proposed, err = p.proposeChunk()
+	require.NoError(t, err)
+	assert.True(t, proposed)
+	chunk = rawdb.ReadProposedChunk(db, 2)
+	require.NotNil(t, chunk)
+	assert.Equal(t, uint64(3), chunk.StartBlockNumber)
+	assert.Equal(t, uint64(3), chunk.EndBlockNumber)
+}
+
+func TestProposeBatchesL1CommitGas(t *testing.T) {
+	db := rawdb.NewMemoryDatabase()
+	bc := newTestChain(&params.ChainConfig{}, 12)
+
+	codec, err := encoding.CodecFromVersion(encoding.CodecV0)
+	require.NoError(t, err)
+	p, err := NewProposer(context.Background(), Config{MaxBlockNumPerChunk: 2}, db, bc)
+	require.NoError(t, err)
+	chunk1, err := p.getChunk(&rawdb.ProposedChunk{StartBlockNumber: 1, EndBlockNumber: 2})
+	require.NoError(t, err)
+	chunk2, err := p.getChunk(&rawdb.ProposedChunk{StartBlockNumber: 3, EndBlockNumber: 4})
+	require.NoError(t, err)
+	twoChunksGas, err := codec.EstimateBatchL1CommitGas(&encoding.Batch{Chunks: []*encoding.Chunk{chunk1, chunk2}})
+	require.NoError(t, err)
+
+	// only two chunks fit into a batch
+	p.config.MaxL1CommitGasPerBatch = twoChunksGas
+	p.propose()
+
+	require.Equal(t, uint64(6), *rawdb.ReadLastProposedChunkIndex(db))
+	// the last two chunks wait for another chunk since the batch timeout is disabled
+	require.Equal(t, uint64(2), *rawdb.ReadLastProposedBatchIndex(db))
+	for i := uint64(1); i <= 2; i++ {
+		batch := rawdb.ReadProposedBatch(db, i)
+		require.NotNil(t, batch)
+		assert.Equal(t, 2*i-1, batch.StartChunkIndex)
+		assert.Equal(t, 2*i, batch.EndChunkIndex)
+	}
+}
+
+func TestProposeOversizedBlocks(t *testing.T) {
+	db := rawdb.NewMemoryDatabase()
+	bc := newTestChain(&params.ChainConfig{BernoulliBlock: big.NewInt(0)}, 10)
+	// blocks 2 and 3 only fit into a blob one at a time, block 6 does not fit at all
+	to := common.HexToAddress("0x5678")
+	for number, size := range map[int]int{2: 70000, 3: 70000, 6: 130000} {
+		data := make([]byte, size)
+		_, err := rand.Read(data)
+		require.NoError(t, err)
+		tx := types.NewTx(&types.LegacyTx{Nonce: uint64(number), Gas: 10000000, To: &to, Data: data})
+		block := bc.blocks[number]
+		bc.blocks[number] = block.WithBody(append(block.Transactions(), tx), nil)
+	}
+	config := Config{MaxBlockNumPerChunk: 4, MaxChunkNumPerBatch: 3, BatchTimeout: time.Minute}
+
+	p, err := NewProposer(context.Background(), config, db, bc)
+	require.NoError(t, err)
+	p.now = func() time.Time { return time.Unix(1700000000, 0).Add(time.Hour) }
+	p.propose()
+
+	// chunks over the blob limit are split, the proposer stops at the oversized block
+	expectedChunks := [][2]uint64{{1, 2}, {3, 4}, {5, 5}}
+	require.Equal(t, uint64(len(expectedChunks)), *rawdb.ReadLastProposedChunkIndex(db))
+	for i, blocks := range expectedChunks {
+		chunk := rawdb.ReadProposedChunk(db, uint64(i+1))
+		require.NotNil(t, chunk)
+		assert.Equal(t, blocks[0], chunk.StartBlockNumber)
+		assert.Equal(t, blocks[1], chunk.EndBlockNumber)
+	}
+	require.NotNil(t, p.oversizedBlock)
+	assert.Equal(t, uint64(6), *p.oversizedBlock)
+
+	// batches over the blob limit are split as well
+	expectedBatches := [][2]uint64{{1, 1}, {2, 3}}
+	require.Equal(t, uint64(len(expectedBatches)), *rawdb.ReadLastProposedBatchIndex(db))
+	for i, chunks := range expectedBatches {
+		batch := rawdb.ReadProposedBatch(db, uint64(i+1))
+		require.NotNil(t, batch)
+		assert.Equal(t, chunks[0], batch.StartChunkIndex)
+		assert.Equal(t, chunks[1], batch.EndChunkIndex)
+	}
+
+	// the proposer does not retry the oversized block
+	bc.addBlocks(4)
+	p.propose()
+	assert.Equal(t, uint64(len(expectedChunks)), *rawdb.ReadLastProposedChunkIndex(db))
+}
@@ -0,0 +1,216 @@
+package rollup_sync_service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/core/rawdb"
+	"github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/log"
+	"github.com/scroll-tech/go-ethereum/metrics"
+	"github.com/scroll-tech/go-ethereum/rollup/types/encoding"
+)
+
+var (
+	batchValidationTimer = metrics.NewRegisteredTimer("rollup/sync/batch/validation", nil)
+)
+
+// batchValidationTask tracks a finalized batch through the validation pipeline.
+type batchValidationTask struct {
+	event *L1FinalizeBatchEvent
+	prev  *batchValidationTask // the task of the parent batch, nil if the parent batch is already stored
+
+	chunks               []*encoding.Chunk
+	totalL1MessagePopped uint64 // total number of L1 messages popped before and in this batch
+	verification         *BatchVerification
+	err                  error
+
+	ready chan struct{} // closed once totalL1MessagePopped is known, or on failure
+	done  chan struct{} // closed once the batch is verified, or on failure
+}
+
+// validateAndFinalizeBatches validates finalized batches and stores them in index order.
+//
+// The batches are processed in a pipeline: the chunks of up to defaultMaxConcurrentBatchValidations
+// batches are rebuilt from local blocks and their batch hashes are computed concurrently, while the
+// results are checked and stored in the order of the events. A batch hash depends on its parent batch,
+// so the parent batch hash is taken from the preceding event. This is safe because a batch is only
+// stored after its local hash was found to be equal to the hash finalized on L1.
+func (s *RollupSyncService) validateAndFinalizeBatches(events []*L1FinalizeBatchEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(s.ctx)
+	var wg sync.WaitGroup
+	defer func() {
+		cancel()
+		wg.Wait()
+	}()
+
+	tasks := make([]*batchValidationTask, len(events))
+	for i, event := range events {
+		tasks[i] = &batchValidationTask{event: event, ready: make(chan struct{}), done: make(chan struct{})}
+		if i > 0 && events[i-1].BatchIndex.Uint64()+1 == event.BatchIndex.Uint64() {
+			tasks[i].prev = tasks[i-1]
+		}
+	}
+
+	// Tasks acquire a slot in event order and release it once stored, which bounds the memory used by
+	// rebuilt chunks. A task only waits for its predecessor, which always holds a slot, so this cannot deadlock.
+	slots := make(chan struct{}, defaultMaxConcurrentBatchValidations)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for _, task := range tasks {
+			select {
+			case slots <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			wg.Add(1)
+			go func(task *batchValidationTask) {
+				defer wg.Done()
+				s.runBatchValidationTask(ctx, task)
+			}(task)
+		}
+	}()
+
+	for _, task := range tasks {
+		select {
+		case <-task.done:
+		case <-ctx.Done():
+			log.Info("Context canceled", "reason", ctx.Err())
+			return ctx.Err()
+		}
+		batchIndex := task.event.BatchIndex.Uint64()
+		if task.err != nil {
+			return fmt.Errorf("fatal: validateBatch failed: finalize event: %v, err: %w", task.event, task.err)
+		}
+
+		checkBatchVerification(task.verification, task.chunks, s.stack)
+
+		// the blob is only available for batches committed after Bernoulli,
+		// and only if it was retrieved before the beacon node pruned it.
+		if blob := rawdb.ReadBatchBlob(s.db, batchIndex); blob != nil {
+			if err := validateBatchBlob(task.event, task.verification.DABatch, blob, s.stack); err != nil {
+				return fmt.Errorf("fatal: validateBatchBlob failed: finalize event: %v, err: %w", task.event, err)
+			}
+		}
+
+		endBlock := task.verification.EndBlockNumber
+		rawdb.WriteFinalizedL2BlockNumber(s.db, endBlock)
+		rawdb.WriteFinalizedBatchMeta(s.db, batchIndex, task.verification.FinalizedBatchMeta)
+		rawdb.DeleteBatchBlob(s.db, batchIndex)
+		rawdb.WriteLastFinalizedBatchIndex(s.db, batchIndex)
+		s.lastFinalizedBatchIndex = batchIndex
+
+		if batchIndex%100 == 0 {
+			log.Info("finalized batch progress", "batch index", batchIndex, "finalized l2 block height", endBlock)
+		}
+
+		// release the rebuilt chunks of the stored batch and its slot
+		task.chunks = nil
+		<-slots
+	}
+	return nil
+}
+
+// runBatchValidationTask rebuilds the chunks of a finalized batch from local blocks and verifies the batch
+// against its finalize event.
+func (s *RollupSyncService) runBatchValidationTask(ctx context.Context, task *batchValidationTask) {
+	defer close(task.done)
+
+	start := time.Now()
+	parentBatchMeta, err := s.prepareBatchValidationTask(ctx, task)
+	if err != nil {
+		task.err = err
+		close(task.ready)
+		return
+	}
+	close(task.ready)
+
+	task.verification, task.err = VerifyBatch(task.event, parentBatchMeta, task.chunks, s.bc.Config())
+	batchValidationTimer.UpdateSince(start)
+}
+
+// prepareBatchValidationTask rebuilds the chunks of a batch and determines the metadata of its parent batch.
+func (s *RollupSyncService) prepareBatchValidationTask(ctx context.Context, task *batchValidationTask) (*rawdb.FinalizedBatchMeta, error) {
+	batchIndex := task.event.BatchIndex.Uint64()
+	chunks, err := s.getLocalChunksForBatch(ctx, batchIndex)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get local node info, batch index: %v, err: %w", batchIndex, err)
+	}
+	task.chunks = chunks
+
+	// get metadata of parent batch: default to genesis batch metadata.
+	parentBatchMeta := &rawdb.FinalizedBatchMeta{}
+	if prev := task.prev; prev != nil {
+		select {
+		case <-prev.ready:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		// if the parent task failed, this batch is discarded together with it
+		parentBatchMeta = &rawdb.FinalizedBatchMeta{
+			BatchHash:            prev.event.BatchHash,
+			TotalL1MessagePopped: prev.totalL1MessagePopped,
+		}
+	} else if batchIndex > 0 {
+		parentBatchMeta = rawdb.ReadFinalizedBatchMeta(s.db, batchIndex-1)
+		if parentBatchMeta == nil {
+			return nil, fmt.Errorf("failed to get parent batch meta, batch index: %v", batchIndex)
+		}
+	}
+
+	task.totalL1MessagePopped = parentBatchMeta.TotalL1MessagePopped
+	for _, chunk := range chunks {
+		task.totalL1MessagePopped += chunk.NumL1Messages(task.totalL1MessagePopped)
+	}
+	return parentBatchMeta, nil
+}
+
+// prefetchCommitBatchTxs concurrently retrieves the transactions that emitted the CommitBatch events in logs.
+// Transactions that cannot be retrieved are skipped, they are fetched again when their event is processed.
+func (s *RollupSyncService) prefetchCommitBatchTxs(logs []types.Log) map[common.Hash]*types.Transaction {
+	var (
+		mu   sync.Mutex
+		wg   sync.WaitGroup
+		txs  = make(map[common.Hash]*types.Transaction)
+		seen = make(map[common.Hash]bool)
+		sem  = make(chan struct{}, defaultMaxConcurrentCommitTxFetches)
+	)
+	for i := range logs {
+		vLog := &logs[i]
+		if len(vLog.Topics) < 2 || vLog.Topics[0] != s.l1CommitBatchEventSignature || seen[vLog.TxHash] {
+			continue
+		}
+		// the genesis batch is not committed by a commitBatch transaction
+		if vLog.Topics[1] == (common.Hash{}) {
+			continue
+		}
+		seen[vLog.TxHash] = true
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			tx, err := s.getCommitBatchTx(vLog)
+			if err != nil {
+				log.Debug("failed to prefetch commit batch transaction", "tx hash", vLog.TxHash.Hex(), "err", err)
+				return
+			}
+			mu.Lock()
+			txs[vLog.TxHash] = tx
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	return txs
+}
@@ -0,0 +1,154 @@
+package rollup_sync_service
+
+import (
+	"context"
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/core"
+	"github.com/scroll-tech/go-ethereum/core/rawdb"
+	"github.com/scroll-tech/go-ethereum/core/state"
+	"github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/node"
+	"github.com/scroll-tech/go-ethereum/params"
+)
+
+// testChain is an in-memory chain of blocks that each include one L1 message, with an empty state.
+type testChain struct {
+	config *params.ChainConfig
+	blocks []*types.Block
+}
+
+func newTestChain(numBlocks int) *testChain {
+	bc := &testChain{config: &params.ChainConfig{}}
+	bc.blocks = append(bc.blocks, types.NewBlockWithHeader(&types.Header{Number: big.NewInt(0), BaseFee: big.NewInt(1000), GasLimit: 10000000}))
+	to := common.HexToAddress("0x1234")
+	for number := uint64(1); number <= uint64(numBlocks); number++ {
+		header := &types.Header{Number: new(big.Int).SetUint64(number), Time: 1700000000 + number, BaseFee: big.NewInt(1000), GasLimit: 10000000}
+		l1Message := types.NewTx(&types.L1MessageTx{QueueIndex: number - 1, Gas: 100000, To: &to, Value: common.Big0, Sender: to})
+		bc.blocks = append(bc.blocks, types.NewBlockWithHeader(header).WithBody([]*types.Transaction{l1Message}, nil))
+	}
+	return bc
+}
+
+func (bc *testChain) Config() *params.ChainConfig { return bc.config }
+
+func (bc *testChain) CurrentBlock() *types.Block { return bc.blocks[len(bc.blocks)-1] }
+
+func (bc *testChain) GetBlockByNumber(number uint64) *types.Block {
+	if number >= uint64(len(bc.blocks)) {
+		return nil
+	}
+	return bc.blocks[number]
+}
+
+func (bc *testChain) StateAt(root common.Hash) (*state.StateDB, error) {
+	return state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+}
+
+func TestValidateAndFinalizeBatches(t *testing.T) {
+	scrollChainABI, err := scrollChainMetaData.GetAbi()
+	require.NoError(t, err)
+
+	// batch 0 is the genesis block, every following batch has one chunk per block
+	const numBatches = 3 * defaultMaxConcurrentBatchValidations
+	bc := newTestChain(2 * numBatches)
+	db := rawdb.NewMemoryDatabase()
+	rawdb.WriteBatchChunkRanges(db, 0, []*rawdb.ChunkBlockRange{{StartBlockNumber: 0, EndBlockNumber: 0}})
+	for i := uint64(1); i <= numBatches; i++ {
+		rawdb.WriteBatchChunkRanges(db, i, []*rawdb.ChunkBlockRange{{StartBlockNumber: 2*i - 1, EndBlockNumber: 2*i - 1}, {StartBlockNumber: 2 * i, EndBlockNumber: 2 * i}})
+	}
+
+	// compute the expected batches one by one
+	var logs []types.Log
+	expected := make([]*rawdb.FinalizedBatchMeta, numBatches+1)
+	parentBatchMeta := &rawdb.FinalizedBatchMeta{}
+	for i := uint64(0); i <= numBatches; i++ {
+		chunks, err := readChunksFromChain(bc, rawdb.ReadBatchChunkRanges(db, i))
+		require.NoError(t, err)
+		event := &L1FinalizeBatchEvent{BatchIndex: new(big.Int).SetUint64(i)}
+		v, err := VerifyBatch(event, parentBatchMeta, chunks, bc.Config())
+		require.NoError(t, err)
+		expected[i] = v.FinalizedBatchMeta
+		parentBatchMeta = v.FinalizedBatchMeta
+
+		logs = append(logs, types.Log{
+			Topics: []common.Hash{scrollChainABI.Events["FinalizeBatch"].ID, common.BigToHash(event.BatchIndex), v.L2BatchHash},
+			Data:   append(v.L2StateRoot.Bytes(), v.L2WithdrawRoot.Bytes()...),
+		})
+	}
+	assert.Equal(t, uint64(2*numBatches), expected[numBatches].TotalL1MessagePopped)
+
+	service := &RollupSyncService{
+		ctx:                           context.Background(),
+		db:                            db,
+		bc:                            bc,
+		scrollChainABI:                scrollChainABI,
+		l1FinalizeBatchEventSignature: scrollChainABI.Events["FinalizeBatch"].ID,
+	}
+
+	// the batches after a batch that cannot be rebuilt are not stored
+	const failedBatch = numBatches - 2
+	rawdb.DeleteBatchChunkRanges(db, failedBatch)
+	assert.Error(t, service.parseAndUpdateRollupEventLogs(logs, 100))
+	assert.Equal(t, uint64(failedBatch-1), service.lastFinalizedBatchIndex)
+	for i := uint64(0); i <= numBatches; i++ {
+		if i < failedBatch {
+			assert.Equal(t, expected[i], rawdb.ReadFinalizedBatchMeta(db, i))
+		} else {
+			assert.Nil(t, rawdb.ReadFinalizedBatchMeta(db, i))
+		}
+	}
+	assert.Equal(t, uint64(2*(failedBatch-1)), *rawdb.ReadFinalizedL2BlockNumber(db))
+
+	// the remaining batches are stored once they can be rebuilt
+	rawdb.WriteBatchChunkRanges(db, failedBatch, []*rawdb.ChunkBlockRange{{StartBlockNumber: 2*failedBatch - 1, EndBlockNumber: 2*failedBatch - 1}, {StartBlockNumber: 2 * failedBatch, EndBlockNumber: 2 * failedBatch}})
+	require.NoError(t, service.parseAndUpdateRollupEventLogs(logs[failedBatch:], 200))
+	assert.Equal(t, uint64(numBatches), service.lastFinalizedBatchIndex)
+	for i := uint64(0); i <= numBatches; i++ {
+		assert.Equal(t, expected[i], rawdb.ReadFinalizedBatchMeta(db, i))
+	}
+	assert.Equal(t, uint64(2*numBatches), *rawdb.ReadFinalizedL2BlockNumber(db))
+	assert.Equal(t, uint64(numBatches), *rawdb.ReadLastFinalizedBatchIndex(db))
+}
+
+func TestPrefetchCommitBatchTxs(t *testing.T) {
+	genesisConfig := &params.ChainConfig{
+		Scroll: params.ScrollConfig{
+			L1Config: &params.L1Config{
+				L1ChainId:          11155111,
+				ScrollChainAddress: common.HexToAddress("0x2D567EcE699Eabe5afCd141eDB7A4f2D0D6ce8a0"),
+			},
+		},
+	}
+	rlpData, err := os.ReadFile("./testdata/commitBatch_codecv0.rlp")
+	require.NoError(t, err)
+	stack, err := node.New(&node.DefaultConfig)
+	require.NoError(t, err)
+	defer stack.Close()
+	service, err := NewRollupSyncService(context.Background(), genesisConfig, rawdb.NewMemoryDatabase(), &mockEthClient{commitBatchRLP: rlpData}, &core.BlockChain{}, stack)
+	require.NoError(t, err)
+
+	commitLog := func(batchIndex uint64, txHash common.Hash) types.Log {
+		return types.Log{
+			Topics: []common.Hash{service.l1CommitBatchEventSignature, common.BigToHash(new(big.Int).SetUint64(batchIndex)), {}},
+			TxHash: txHash,
+		}
+	}
+	logs := []types.Log{
+		commitLog(0, common.HexToHash("0x01")),
+		commitLog(1, common.HexToHash("0x02")),
+		commitLog(2, common.HexToHash("0x03")),
+		commitLog(3, common.HexToHash("0x03")),
+	}
+
+	txs := service.prefetchCommitBatchTxs(logs)
+	assert.Len(t, txs, 2)
+	assert.Contains(t, txs, common.HexToHash("0x02"))
+	assert.Contains(t, txs, common.HexToHash("0x03"))
+}
@@ -0,0 +1,187 @@
+package rollup_sync_service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/common/hexutil"
+	"github.com/scroll-tech/go-ethereum/crypto/kzg4844"
+)
+
+const (
+	beaconNodeGenesisEndpoint      = "/eth/v1/beacon/genesis"
+	beaconNodeSpecEndpoint         = "/eth/v1/config/spec"
+	beaconNodeBlobSidecarsEndpoint = "/eth/v1/beacon/blob_sidecars"
+
+	// defaultBeaconNodeRequestTimeout is the timeout of a single request to the beacon node API.
+	defaultBeaconNodeRequestTimeout = 15 * time.Second
+
+	// blobRetentionPeriod is how long beacon nodes keep blob sidecars, 4096 epochs of 32 slots of 12 seconds.
+	blobRetentionPeriod = 4096 * 32 * 12 * time.Second
+)
+
+var (
+	// errBlobNotFound is returned when the blob sidecars of a slot do not contain the requested blob,
+	// e.g. because the beacon node has already pruned them.
+	errBlobNotFound = errors.New("blob not found")
+
+	// errBlobMismatch is returned when the blob posted to L1 differs from the blob of the local batch.
+	errBlobMismatch = errors.New("blob mismatch")
+)
+
+// BlobClient retrieves the blobs that were posted to L1 by commit batch transactions.
+type BlobClient interface {
+	// GetBlobByVersionedHashAndBlockTime returns the blob with the given versioned hash,
+	// included in the L1 block with the given timestamp. Implementations must verify
+	// the returned blob against the versioned hash.
+	GetBlobByVersionedHashAndBlockTime(ctx context.Context, versionedHash common.Hash, blockTime uint64) (*kzg4844.Blob, error)
+}
+
+// BeaconNodeClient is a BlobClient that fetches blob sidecars from an L1 beacon node API.
+type BeaconNodeClient struct {
+	apiEndpoint    string
+	client         *http.Client
+	genesisTime    uint64
+	secondsPerSlot uint64
+}
+
+// NewBeaconNodeClient initializes a new BeaconNodeClient. It queries the genesis time and
+// the slot duration of the beacon chain, these are needed to map L1 block times to slots.
+func NewBeaconNodeClient(ctx context.Context, apiEndpoint string) (*BeaconNodeClient, error) {
+	c := &BeaconNodeClient{
+		apiEndpoint: apiEndpoint,
+		client:      &http.Client{Timeout: defaultBeaconNodeRequestTimeout},
+	}
+
+	var genesisResp struct {
+		Data struct {
+			GenesisTime string `json:"genesis_time"`
+		} `json:"data"`
+	}
+	if err := c.get(ctx, beaconNodeGenesisEndpoint, &genesisResp); err != nil {
+		return nil, fmt.Errorf("failed to query beacon node genesis, err: %w", err)
+	}
+	genesisTime, err := strconv.ParseUint(genesisResp.Data.GenesisTime, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse genesis time, value: %v, err: %w", genesisResp.Data.GenesisTime, err)
+	}
+
+	var specResp struct {
+		Data struct {
+			SecondsPerSlot string `json:"SECONDS_PER_SLOT"`
+		} `json:"data"`
+	}
+	if err := c.get(ctx, beaconNodeSpecEndpoint, &specResp); err != nil {
+		return nil, fmt.Errorf("failed to query beacon node spec, err: %w", err)
+	}
+	secondsPerSlot, err := strconv.ParseUint(specResp.Data.SecondsPerSlot, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse seconds per slot, value: %v, err: %w", specResp.Data.SecondsPerSlot, err)
+	}
+	if secondsPerSlot == 0 {
+		return nil, errors.New("invalid beacon node spec: seconds per slot is 0")
+	}
+
+	c.genesisTime = genesisTime
+	c.secondsPerSlot = secondsPerSlot
+	return c, nil
+}
+
+// blobSidecar is the JSON representation of a blob sidecar returned by the beacon node API.
+type blobSidecar struct {
+	Index         string        `json:"index"`
+	Blob          hexutil.Bytes `json:"blob"`
+	KZGCommitment hexutil.Bytes `json:"kzg_commitment"`
+	KZGProof      hexutil.Bytes `json:"kzg_proof"`
+}
+
+// GetBlobByVersionedHashAndBlockTime implements BlobClient.
+func (c *BeaconNodeClient) GetBlobByVersionedHashAndBlockTime(ctx context.Context, versionedHash common.Hash, blockTime uint64) (*kzg4844.Blob, error) {
+	if blockTime < c.genesisTime {
+		return nil, fmt.Errorf("block time %v is before beacon chain genesis time %v", blockTime, c.genesisTime)
+	}
+	slot := (blockTime - c.genesisTime) / c.secondsPerSlot
+
+	var resp struct {
+		Data []*blobSidecar `json:"data"`
+	}
+	path, err := url.JoinPath(beaconNodeBlobSidecarsEndpoint, strconv.FormatUint(slot, 10))
+	if err != nil {
+		return nil, fmt.Errorf("failed to join path, err: %w", err)
+	}
+	if err := c.get(ctx, path, &resp); err != nil {
+		return nil, fmt.Errorf("failed to query blob sidecars, slot: %v, err: %w", slot, err)
+	}
+
+	for _, sidecar := range resp.Data {
+		if len(sidecar.KZGCommitment) != len(kzg4844.Commitment{}) {
+			return nil, fmt.Errorf("invalid kzg commitment length in blob sidecar, slot: %v, index: %v, length: %v", slot, sidecar.Index, len(sidecar.KZGCommitment))
+		}
+		var commitment kzg4844.Commitment
+		copy(commitment[:], sidecar.KZGCommitment)
+
+		if kzg4844.CalcBlobHashV1(sha256.New(), &commitment) != versionedHash {
+			continue
+		}
+
+		return verifyBlobSidecar(sidecar, commitment)
+	}
+
+	return nil, fmt.Errorf("%w: versioned hash: %v, slot: %v", errBlobNotFound, versionedHash.Hex(), slot)
+}
+
+// verifyBlobSidecar checks the KZG proof of a blob sidecar against its commitment.
+func verifyBlobSidecar(sidecar *blobSidecar, commitment kzg4844.Commitment) (*kzg4844.Blob, error) {
+	var blob kzg4844.Blob
+	if len(sidecar.Blob) != len(blob) {
+		return nil, fmt.Errorf("invalid blob length in blob sidecar, index: %v, length: %v", sidecar.Index, len(sidecar.Blob))
+	}
+	copy(blob[:], sidecar.Blob)
+
+	var proof kzg4844.Proof
+	if len(sidecar.KZGProof) != len(proof) {
+		return nil, fmt.Errorf("invalid kzg proof length in blob sidecar, index: %v, length: %v", sidecar.Index, len(sidecar.KZGProof))
+	}
+	copy(proof[:], sidecar.KZGProof)
+
+	if err := kzg4844.VerifyBlobProof(&blob, commitment, proof); err != nil {
+		return nil, fmt.Errorf("failed to verify blob proof, index: %v, err: %w", sidecar.Index, err)
+	}
+	return &blob, nil
+}
+
+// get sends a GET request to the beacon node API and decodes the JSON response into out.
+func (c *BeaconNodeClient) get(ctx context.Context, path string, out interface{}) error {
+	fullURL, err := url.JoinPath(c.apiEndpoint, path)
+	if err != nil {
+		return fmt.Errorf("failed to join path, err: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fullURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request, err: %w", err)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("cannot do request, err: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("beacon node request failed, url: %v, status code: %v", fullURL, resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response, url: %v, err: %w", fullURL, err)
+	}
+	return nil
+}
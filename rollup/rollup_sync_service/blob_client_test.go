@@ -0,0 +1,165 @@
+package rollup_sync_service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/common/hexutil"
+	"github.com/scroll-tech/go-ethereum/core/rawdb"
+	"github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/crypto/kzg4844"
+	"github.com/scroll-tech/go-ethereum/rollup/types/encoding"
+	"github.com/scroll-tech/go-ethereum/rollup/types/encoding/codecv0"
+	"github.com/scroll-tech/go-ethereum/rollup/types/encoding/codecv1"
+)
+
+// newMockBeaconNode starts a beacon node API mock that serves the given sidecar at slot 10.
+func newMockBeaconNode(t *testing.T, sidecar *blobSidecar) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc(beaconNodeGenesisEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data":{"genesis_time":"1000"}}`))
+	})
+	mux.HandleFunc(beaconNodeSpecEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data":{"SECONDS_PER_SLOT":"12"}}`))
+	})
+	mux.HandleFunc(beaconNodeBlobSidecarsEndpoint+"/10", func(w http.ResponseWriter, r *http.Request) {
+		resp := struct {
+			Data []*blobSidecar `json:"data"`
+		}{Data: []*blobSidecar{sidecar}}
+		require.NoError(t, json.NewEncoder(w).Encode(resp))
+	})
+	return httptest.NewServer(mux)
+}
+
+func TestBeaconNodeClient(t *testing.T) {
+	block := readBlockFromJSON(t, "./testdata/blockTrace_02.json")
+	chunks := []*encoding.Chunk{{Blocks: []*encoding.Block{block}}}
+
+	blob, versionedHash, _, err := codecv1.ConstructBlobPayload(chunks)
+	require.NoError(t, err)
+	commitment, err := kzg4844.BlobToCommitment(blob)
+	require.NoError(t, err)
+	proof, err := kzg4844.ComputeBlobProof(blob, commitment)
+	require.NoError(t, err)
+	assert.Equal(t, versionedHash, common.Hash(kzg4844.CalcBlobHashV1(sha256.New(), &commitment)))
+
+	sidecar := &blobSidecar{
+		Index:         "0",
+		Blob:          hexutil.Bytes(blob[:]),
+		KZGCommitment: hexutil.Bytes(commitment[:]),
+		KZGProof:      hexutil.Bytes(proof[:]),
+	}
+	server := newMockBeaconNode(t, sidecar)
+	defer server.Close()
+
+	client, err := NewBeaconNodeClient(context.Background(), server.URL)
+	require.NoError(t, err)
+
+	// slot 10 starts at 1000 + 10 * 12
+	got, err := client.GetBlobByVersionedHashAndBlockTime(context.Background(), versionedHash, 1125)
+	require.NoError(t, err)
+	assert.Equal(t, *blob, *got)
+
+	// the blob of the commit transaction is not part of the slot
+	_, err = client.GetBlobByVersionedHashAndBlockTime(context.Background(), common.HexToHash("0x01"), 1120)
+	assert.ErrorIs(t, err, errBlobNotFound)
+
+	// an endpoint that does not serve the sidecars is an error, not a missing blob
+	_, err = client.GetBlobByVersionedHashAndBlockTime(context.Background(), versionedHash, 1140)
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, errBlobNotFound)
+
+	// the sidecar has an invalid proof
+	sidecar.KZGProof = hexutil.Bytes(commitment[:])
+	_, err = client.GetBlobByVersionedHashAndBlockTime(context.Background(), versionedHash, 1120)
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, errBlobNotFound)
+}
+
+func TestValidateBatchBlob(t *testing.T) {
+	block := readBlockFromJSON(t, "./testdata/blockTrace_02.json")
+	chunks := []*encoding.Chunk{{Blocks: []*encoding.Block{block}}}
+
+	blob, _, _, err := codecv1.ConstructBlobPayload(chunks)
+	require.NoError(t, err)
+
+	batch := &encoding.Batch{Index: 1, Chunks: chunks}
+	event := &L1FinalizeBatchEvent{BatchIndex: big.NewInt(1)}
+	daBatch, err := codecv1.NewDABatch(batch)
+	require.NoError(t, err)
+	assert.NoError(t, validateBatchBlob(event, daBatch, blob, nil))
+
+	// the blob of another batch has a different versioned hash
+	otherBlock := readBlockFromJSON(t, "./testdata/blockTrace_03.json")
+	otherBlob, _, _, err := codecv1.ConstructBlobPayload([]*encoding.Chunk{{Blocks: []*encoding.Block{otherBlock}}})
+	require.NoError(t, err)
+	assert.ErrorIs(t, compareBatchBlob(event, daBatch, otherBlob), errBlobMismatch)
+
+	// codecv0 batches are committed without blobs
+	daBatchV0, err := codecv0.NewDABatch(batch)
+	require.NoError(t, err)
+	assert.Error(t, validateBatchBlob(event, daBatchV0, blob, nil))
+}
+
+// mockBlobClient serves the blobs it holds by versioned hash.
+type mockBlobClient struct {
+	blobs map[common.Hash]*kzg4844.Blob
+}
+
+func (m *mockBlobClient) GetBlobByVersionedHashAndBlockTime(ctx context.Context, versionedHash common.Hash, blockTime uint64) (*kzg4844.Blob, error) {
+	if blob, ok := m.blobs[versionedHash]; ok {
+		return blob, nil
+	}
+	return nil, errBlobNotFound
+}
+
+// mockBlobTimeEthClient serves L1 headers with the given timestamp.
+type mockBlobTimeEthClient struct {
+	mockEthClient
+	time uint64
+}
+
+func (m *mockBlobTimeEthClient) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	return &types.Header{Number: new(big.Int).Set(number), Time: m.time}, nil
+}
+
+func TestFetchAndStoreBatchBlob(t *testing.T) {
+	block := readBlockFromJSON(t, "./testdata/blockTrace_02.json")
+	blob, versionedHash, _, err := codecv1.ConstructBlobPayload([]*encoding.Chunk{{Blocks: []*encoding.Block{block}}})
+	require.NoError(t, err)
+
+	l1Client := &mockBlobTimeEthClient{time: uint64(time.Now().Unix())}
+	service := &RollupSyncService{
+		ctx:        context.Background(),
+		client:     &L1Client{client: l1Client},
+		blobClient: &mockBlobClient{blobs: map[common.Hash]*kzg4844.Blob{versionedHash: blob}},
+		db:         rawdb.NewMemoryDatabase(),
+	}
+	newCommitTx := func(hash common.Hash) *types.Transaction {
+		return types.NewTx(&types.BlobTx{BlobHashes: []common.Hash{hash}})
+	}
+	vLog := &types.Log{BlockNumber: 10}
+
+	require.NoError(t, service.fetchAndStoreBatchBlob(1, vLog, newCommitTx(versionedHash)))
+	assert.Equal(t, blob, rawdb.ReadBatchBlob(service.db, 1))
+
+	// a missing blob is retried later
+	err = service.fetchAndStoreBatchBlob(2, vLog, newCommitTx(common.HexToHash("0x01")))
+	assert.ErrorIs(t, err, errBlobNotFound)
+	assert.Nil(t, rawdb.ReadBatchBlob(service.db, 2))
+
+	// unless it is past the retention period
+	l1Client.time = uint64(time.Now().Add(-blobRetentionPeriod - time.Hour).Unix())
+	assert.NoError(t, service.fetchAndStoreBatchBlob(2, vLog, newCommitTx(common.HexToHash("0x01"))))
+	assert.Nil(t, rawdb.ReadBatchBlob(service.db, 2))
+}
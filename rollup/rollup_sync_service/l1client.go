@@ -55,13 +55,9 @@ func newL1Client(ctx context.Context, l1Client sync_service.EthClient, l1ChainId
 	return &client, nil
 }
 
-// fetcRollupEventsInRange retrieves and parses commit/revert/finalize rollup events between block numbers: [from, to].
-func (c *L1Client) fetchRollupEventsInRange(from, to uint64) ([]types.Log, error) {
-	log.Trace("L1Client fetchRollupEventsInRange", "fromBlock", from, "toBlock", to)
-
+// rollupEventsQuery returns the filter query that matches all commit/revert/finalize rollup events.
+func (c *L1Client) rollupEventsQuery() ethereum.FilterQuery {
 	query := ethereum.FilterQuery{
-		FromBlock: big.NewInt(int64(from)), // inclusive
-		ToBlock:   big.NewInt(int64(to)),   // inclusive
 		Addresses: []common.Address{
 			c.scrollChainAddress,
 		},
@@ -71,6 +67,16 @@ func (c *L1Client) fetchRollupEventsInRange(from, to uint64) ([]types.Log, error
 	query.Topics[0][0] = c.l1CommitBatchEventSignature
 	query.Topics[0][1] = c.l1RevertBatchEventSignature
 	query.Topics[0][2] = c.l1FinalizeBatchEventSignature
+	return query
+}
+
+// fetcRollupEventsInRange retrieves and parses commit/revert/finalize rollup events between block numbers: [from, to].
+func (c *L1Client) fetchRollupEventsInRange(from, to uint64) ([]types.Log, error) {
+	log.Trace("L1Client fetchRollupEventsInRange", "fromBlock", from, "toBlock", to)
+
+	query := c.rollupEventsQuery()
+	query.FromBlock = big.NewInt(int64(from)) // inclusive
+	query.ToBlock = big.NewInt(int64(to))     // inclusive
 
 	logs, err := c.client.FilterLogs(c.ctx, query)
 	if err != nil {
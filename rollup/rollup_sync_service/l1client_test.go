@@ -12,6 +12,7 @@ import (
 	"github.com/scroll-tech/go-ethereum/common"
 	"github.com/scroll-tech/go-ethereum/core/types"
 	"github.com/scroll-tech/go-ethereum/rlp"
+	"github.com/scroll-tech/go-ethereum/rpc"
 )
 
 func TestL1Client(t *testing.T) {
@@ -57,8 +58,20 @@ func (m *mockEthClient) HeaderByNumber(ctx context.Context, number *big.Int) (*t
 	}, nil
 }
 
+func (m *mockEthClient) BlockHashByNumber(ctx context.Context, number *big.Int) (common.Hash, error) {
+	header, err := m.HeaderByNumber(ctx, number)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return header.Hash(), nil
+}
+
 func (m *mockEthClient) SubscribeFilterLogs(ctx context.Context, query ethereum.FilterQuery, ch chan<- types.Log) (ethereum.Subscription, error) {
-	return nil, nil
+	return nil, rpc.ErrNotificationsUnsupported
+}
+
+func (m *mockEthClient) SubscribeNewHead(ctx context.Context, ch chan<- *types.Header) (ethereum.Subscription, error) {
+	return nil, rpc.ErrNotificationsUnsupported
 }
 
 func (m *mockEthClient) TransactionByHash(ctx context.Context, txHash common.Hash) (*types.Transaction, bool, error) {
@@ -72,3 +85,7 @@ func (m *mockEthClient) TransactionByHash(ctx context.Context, txHash common.Has
 func (m *mockEthClient) BlockByHash(ctx context.Context, hash common.Hash) (*types.Block, error) {
 	return nil, nil
 }
+
+func (m *mockEthClient) BlockReceiptsByHash(ctx context.Context, blockHash common.Hash) ([]*types.Receipt, error) {
+	return nil, nil
+}
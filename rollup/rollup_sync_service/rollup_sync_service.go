@@ -2,8 +2,11 @@ package rollup_sync_service
 
 import (
 	"context"
+	"crypto/sha256"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"math/big"
 	"os"
 	"reflect"
 	"time"
@@ -13,17 +16,18 @@ import (
 	"github.com/scroll-tech/go-ethereum/core"
 	"github.com/scroll-tech/go-ethereum/core/rawdb"
 	"github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/crypto/kzg4844"
 	"github.com/scroll-tech/go-ethereum/ethdb"
 	"github.com/scroll-tech/go-ethereum/log"
+	"github.com/scroll-tech/go-ethereum/metrics"
 	"github.com/scroll-tech/go-ethereum/node"
 	"github.com/scroll-tech/go-ethereum/params"
 
-	"github.com/scroll-tech/go-ethereum/rollup/rcfg"
 	"github.com/scroll-tech/go-ethereum/rollup/sync_service"
 	"github.com/scroll-tech/go-ethereum/rollup/types/encoding"
-	"github.com/scroll-tech/go-ethereum/rollup/types/encoding/codecv0"
-	"github.com/scroll-tech/go-ethereum/rollup/types/encoding/codecv1"
-	"github.com/scroll-tech/go-ethereum/rollup/withdrawtrie"
+	_ "github.com/scroll-tech/go-ethereum/rollup/types/encoding/codecv0" // register codecv0
+	_ "github.com/scroll-tech/go-ethereum/rollup/types/encoding/codecv1" // register codecv1
+	_ "github.com/scroll-tech/go-ethereum/rollup/types/encoding/codecv2" // register codecv2
 )
 
 const (
@@ -43,6 +47,17 @@ const (
 
 	// defaultLogInterval is the frequency at which we print the latestProcessedBlock.
 	defaultLogInterval = 5 * time.Minute
+
+	// defaultMaxConcurrentBatchValidations is the maximum number of finalized batches that are
+	// rebuilt from local blocks and hashed concurrently, including validated batches waiting to be stored.
+	defaultMaxConcurrentBatchValidations = 8
+
+	// defaultMaxConcurrentCommitTxFetches is the maximum number of concurrent requests for commit batch transactions.
+	defaultMaxConcurrentCommitTxFetches = 8
+)
+
+var (
+	l1ReorgCounter = metrics.NewRegisteredCounter("rollup/sync/l1/reorg", nil)
 )
 
 // RollupSyncService collects ScrollChain batch commit/revert/finalize events and stores metadata into db.
@@ -50,14 +65,20 @@ type RollupSyncService struct {
 	ctx                           context.Context
 	cancel                        context.CancelFunc
 	client                        *L1Client
+	blobClient                    BlobClient
 	db                            ethdb.Database
 	latestProcessedBlock          uint64
+	lastCommittedBatchIndex       uint64
+	lastFinalizedBatchIndex       uint64
 	scrollChainABI                *abi.ABI
 	l1CommitBatchEventSignature   common.Hash
 	l1RevertBatchEventSignature   common.Hash
 	l1FinalizeBatchEventSignature common.Hash
-	bc                            *core.BlockChain
+	bc                            BlockChain
 	stack                         *node.Node
+
+	// commitBatchTxs holds the commit batch transactions prefetched for the logs being processed.
+	commitBatchTxs map[common.Hash]*types.Transaction
 }
 
 func NewRollupSyncService(ctx context.Context, genesisConfig *params.ChainConfig, db ethdb.Database, l1Client sync_service.EthClient, bc *core.BlockChain, stack *node.Node) (*RollupSyncService, error) {
@@ -81,6 +102,16 @@ func NewRollupSyncService(ctx context.Context, genesisConfig *params.ChainConfig
 		return nil, fmt.Errorf("failed to initialize l1 client: %w", err)
 	}
 
+	// The blob client is optional, blobs of committed batches are only verified if a beacon node endpoint is configured.
+	var blobClient BlobClient
+	if beaconEndpoint := stack.Config().L1BeaconEndpoint; beaconEndpoint != "" {
+		beaconNodeClient, err := NewBeaconNodeClient(ctx, beaconEndpoint)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize beacon node client: %w", err)
+		}
+		blobClient = beaconNodeClient
+	}
+
 	// Initialize the latestProcessedBlock with the block just before the L1 deployment block.
 	// This serves as a default value when there's no L1 rollup events synced in the database.
 	var latestProcessedBlock uint64
@@ -94,14 +125,24 @@ func NewRollupSyncService(ctx context.Context, genesisConfig *params.ChainConfig
 		latestProcessedBlock = *block
 	}
 
+	// restore the latest batch indices from the last reorg checkpoint
+	var lastCommittedBatchIndex, lastFinalizedBatchIndex uint64
+	if checkpoints := rawdb.ReadRollupEventSyncCheckpoints(db); len(checkpoints) > 0 {
+		lastCommittedBatchIndex = checkpoints[len(checkpoints)-1].LastCommittedBatchIndex
+		lastFinalizedBatchIndex = checkpoints[len(checkpoints)-1].LastFinalizedBatchIndex
+	}
+
 	ctx, cancel := context.WithCancel(ctx)
 
 	service := RollupSyncService{
 		ctx:                           ctx,
 		cancel:                        cancel,
 		client:                        client,
+		blobClient:                    blobClient,
 		db:                            db,
 		latestProcessedBlock:          latestProcessedBlock,
+		lastCommittedBatchIndex:       lastCommittedBatchIndex,
+		lastFinalizedBatchIndex:       lastFinalizedBatchIndex,
 		scrollChainABI:                scrollChainABI,
 		l1CommitBatchEventSignature:   scrollChainABI.Events["CommitBatch"].ID,
 		l1RevertBatchEventSignature:   scrollChainABI.Events["RevertBatch"].ID,
@@ -120,9 +161,16 @@ func (s *RollupSyncService) Start() {
 
 	log.Info("Starting rollup event sync background service", "latest processed block", s.latestProcessedBlock)
 
+	// use push notifications if the L1 endpoint supports them, poll otherwise
+	notifier := sync_service.NewL1Notifier(s.ctx, s.client.client, s.client.rollupEventsQuery(), defaultSyncInterval)
+	notifier.Start()
+
 	go func() {
-		syncTicker := time.NewTicker(defaultSyncInterval)
-		defer syncTicker.Stop()
+		// batches committed before the block number index existed are indexed before new events are
+		// processed, so that reverts cannot race with the backfill
+		if indexed := rawdb.IndexBatchEndBlockNumbers(s.db); indexed > 0 {
+			log.Info("Indexed committed batches by end block number", "batches", indexed)
+		}
 
 		logTicker := time.NewTicker(defaultLogInterval)
 		defer logTicker.Stop()
@@ -131,7 +179,7 @@ func (s *RollupSyncService) Start() {
 			select {
 			case <-s.ctx.Done():
 				return
-			case <-syncTicker.C:
+			case <-notifier.C():
 				s.fetchRollupEvents()
 			case <-logTicker.C:
 				log.Info("Sync rollup events progress update", "latestProcessedBlock", s.latestProcessedBlock)
@@ -161,6 +209,12 @@ func (s *RollupSyncService) fetchRollupEvents() {
 
 	log.Trace("Sync service fetch rollup events", "latest processed block", s.latestProcessedBlock, "latest confirmed", latestConfirmed)
 
+	// roll back batches from reorged L1 blocks before fetching new events
+	if err := s.detectAndHandleL1Reorg(); err != nil {
+		log.Warn("failed to check L1 reorg", "err", err)
+		return
+	}
+
 	// query in batches
 	for from := s.latestProcessedBlock + 1; from <= latestConfirmed; from += defaultFetchBlockRange {
 		if s.ctx.Err() != nil {
@@ -183,12 +237,25 @@ func (s *RollupSyncService) fetchRollupEvents() {
 			log.Error("failed to parse and update rollup event logs", "err", err)
 			return
 		}
+		s.writeRollupEventSyncCheckpoint(to)
 
 		s.latestProcessedBlock = to
 	}
 }
 
 func (s *RollupSyncService) parseAndUpdateRollupEventLogs(logs []types.Log, endBlockNumber uint64) error {
+	s.commitBatchTxs = s.prefetchCommitBatchTxs(logs)
+	defer func() { s.commitBatchTxs = nil }()
+
+	// Finalized batches are collected and validated concurrently, either before
+	// a revert event that depends on the last finalized batch, or at the end.
+	var finalizeEvents []*L1FinalizeBatchEvent
+	finalizeBatches := func() error {
+		events := finalizeEvents
+		finalizeEvents = nil
+		return s.validateAndFinalizeBatches(events)
+	}
+
 	for _, vLog := range logs {
 		switch vLog.Topics[0] {
 		case s.l1CommitBatchEventSignature:
@@ -199,11 +266,39 @@ func (s *RollupSyncService) parseAndUpdateRollupEventLogs(logs []types.Log, endB
 			batchIndex := event.BatchIndex.Uint64()
 			log.Trace("found new CommitBatch event", "batch index", batchIndex)
 
-			chunkBlockRanges, err := s.getChunkRanges(batchIndex, &vLog)
+			var commitTx *types.Transaction
+			if batchIndex > 0 {
+				var err error
+				if commitTx, err = s.getCommitBatchTx(&vLog); err != nil {
+					return fmt.Errorf("failed to get commit batch tx, batch index: %v, err: %w", batchIndex, err)
+				}
+			}
+			chunkBlockRanges, err := s.getChunkRanges(batchIndex, commitTx)
 			if err != nil {
 				return fmt.Errorf("failed to get chunk ranges, batch index: %v, err: %w", batchIndex, err)
 			}
 			rawdb.WriteBatchChunkRanges(s.db, batchIndex, chunkBlockRanges)
+			rawdb.WriteCommittedBatchMeta(s.db, batchIndex, &rawdb.CommittedBatchMeta{
+				BatchHash:     event.BatchHash,
+				L1BlockNumber: vLog.BlockNumber,
+				L1TxHash:      vLog.TxHash,
+			})
+			if len(chunkBlockRanges) > 0 {
+				endBlock := chunkBlockRanges[len(chunkBlockRanges)-1].EndBlockNumber
+				rawdb.WriteBatchEndBlockNumber(s.db, endBlock, batchIndex)
+				if batchIndex > s.lastCommittedBatchIndex {
+					rawdb.WriteCommittedL2BlockNumber(s.db, endBlock)
+				}
+			}
+			if batchIndex > s.lastCommittedBatchIndex {
+				s.lastCommittedBatchIndex = batchIndex
+			}
+
+			if s.blobClient != nil && batchIndex > 0 {
+				if err := s.fetchAndStoreBatchBlob(batchIndex, &vLog, commitTx); err != nil {
+					return fmt.Errorf("failed to fetch batch blob, batch index: %v, err: %w", batchIndex, err)
+				}
+			}
 
 		case s.l1RevertBatchEventSignature:
 			event := &L1RevertBatchEvent{}
@@ -213,38 +308,41 @@ func (s *RollupSyncService) parseAndUpdateRollupEventLogs(logs []types.Log, endB
 			batchIndex := event.BatchIndex.Uint64()
 			log.Trace("found new RevertBatch event", "batch index", batchIndex)
 
-			rawdb.DeleteBatchChunkRanges(s.db, batchIndex)
-
-		case s.l1FinalizeBatchEventSignature:
-			event := &L1FinalizeBatchEvent{}
-			if err := UnpackLog(s.scrollChainABI, event, "FinalizeBatch", vLog); err != nil {
-				return fmt.Errorf("failed to unpack finalized rollup event log, err: %w", err)
+			if err := finalizeBatches(); err != nil {
+				return err
 			}
-			batchIndex := event.BatchIndex.Uint64()
-			log.Trace("found new FinalizeBatch event", "batch index", batchIndex)
 
-			parentBatchMeta, chunks, err := s.getLocalInfoForBatch(batchIndex)
-			if err != nil {
-				return fmt.Errorf("failed to get local node info, batch index: %v, err: %w", batchIndex, err)
-			}
+			deleteCommittedBatch(s.db, s.db, batchIndex)
 
-			endBlock, finalizedBatchMeta, err := validateBatch(event, parentBatchMeta, chunks, s.bc.Config(), s.stack)
-			if err != nil {
-				return fmt.Errorf("fatal: validateBatch failed: finalize event: %v, err: %w", event, err)
+			// reverts always include the latest committed batch, so the committed head
+			// moves back to the last batch that is still committed.
+			if batchIndex <= s.lastCommittedBatchIndex {
+				var finalizedL2BlockNumber uint64
+				if number := rawdb.ReadFinalizedL2BlockNumber(s.db); number != nil {
+					finalizedL2BlockNumber = *number
+				}
+				lastCommittedBatchIndex, committedL2BlockNumber := lastCommittedBatchBefore(s.db, batchIndex, s.lastFinalizedBatchIndex, finalizedL2BlockNumber)
+				rawdb.WriteCommittedL2BlockNumber(s.db, committedL2BlockNumber)
+				s.lastCommittedBatchIndex = lastCommittedBatchIndex
 			}
 
-			rawdb.WriteFinalizedL2BlockNumber(s.db, endBlock)
-			rawdb.WriteFinalizedBatchMeta(s.db, batchIndex, finalizedBatchMeta)
-
-			if batchIndex%100 == 0 {
-				log.Info("finalized batch progress", "batch index", batchIndex, "finalized l2 block height", endBlock)
+		case s.l1FinalizeBatchEventSignature:
+			event := &L1FinalizeBatchEvent{}
+			if err := UnpackLog(s.scrollChainABI, event, "FinalizeBatch", vLog); err != nil {
+				return fmt.Errorf("failed to unpack finalized rollup event log, err: %w", err)
 			}
+			log.Trace("found new FinalizeBatch event", "batch index", event.BatchIndex.Uint64())
+			finalizeEvents = append(finalizeEvents, event)
 
 		default:
 			return fmt.Errorf("unknown event, topic: %v, tx hash: %v", vLog.Topics[0].Hex(), vLog.TxHash.Hex())
 		}
 	}
 
+	if err := finalizeBatches(); err != nil {
+		return err
+	}
+
 	// note: the batch updates above are idempotent, if we crash
 	// before this line and reexecute the previous steps, we will
 	// get the same result.
@@ -252,19 +350,137 @@ func (s *RollupSyncService) parseAndUpdateRollupEventLogs(logs []types.Log, endB
 	return nil
 }
 
-func (s *RollupSyncService) getLocalInfoForBatch(batchIndex uint64) (*rawdb.FinalizedBatchMeta, []*encoding.Chunk, error) {
+// writeRollupEventSyncCheckpoint records the hash of the last processed L1 block together with the
+// current batch indices, so that we can detect and roll back an L1 reorg later.
+func (s *RollupSyncService) writeRollupEventSyncCheckpoint(l1BlockNumber uint64) {
+	hash, err := sync_service.L1BlockHash(s.ctx, s.client.client, l1BlockNumber)
+	if err != nil {
+		// a missing checkpoint only makes reorg detection less precise
+		log.Warn("failed to get L1 block hash for rollup event sync checkpoint", "number", l1BlockNumber, "err", err)
+		return
+	}
+
+	var finalizedL2BlockNumber uint64
+	if number := rawdb.ReadFinalizedL2BlockNumber(s.db); number != nil {
+		finalizedL2BlockNumber = *number
+	}
+
+	batchWriter := s.db.NewBatch()
+	rawdb.WriteRollupEventSyncCheckpoint(batchWriter, &rawdb.RollupEventSyncCheckpoint{
+		L1BlockNumber:           l1BlockNumber,
+		L1BlockHash:             hash,
+		LastCommittedBatchIndex: s.lastCommittedBatchIndex,
+		LastFinalizedBatchIndex: s.lastFinalizedBatchIndex,
+		FinalizedL2BlockNumber:  finalizedL2BlockNumber,
+	})
+	checkpoints := rawdb.ReadRollupEventSyncCheckpoints(s.db)
+	for len(checkpoints) >= sync_service.DefaultNumL1SyncCheckpoints {
+		rawdb.DeleteRollupEventSyncCheckpoint(batchWriter, checkpoints[0].L1BlockNumber)
+		checkpoints = checkpoints[1:]
+	}
+	if err := batchWriter.Write(); err != nil {
+		log.Crit("failed to write rollup event sync checkpoint", "err", err)
+	}
+}
+
+// detectAndHandleL1Reorg checks whether the latest checkpointed L1 block is still canonical.
+// If not, it finds the fork point and deletes all batch data written from the reorged blocks.
+// The events after the fork point are then re-processed from the new canonical L1 chain.
+// Note: Batches reverted by a reorged RevertBatch event cannot be restored, as their chunk
+// ranges were written by a CommitBatch event before the reverted block.
+func (s *RollupSyncService) detectAndHandleL1Reorg() error {
+	checkpoints := rawdb.ReadRollupEventSyncCheckpoints(s.db)
+	if len(checkpoints) == 0 {
+		return nil
+	}
+
+	latest := checkpoints[len(checkpoints)-1]
+	hash, err := sync_service.L1BlockHash(s.ctx, s.client.client, latest.L1BlockNumber)
+	if err != nil {
+		return err
+	}
+	if hash == latest.L1BlockHash {
+		return nil
+	}
+
+	numbers := make([]uint64, len(checkpoints))
+	hashes := make([]common.Hash, len(checkpoints))
+	for i, checkpoint := range checkpoints {
+		numbers[i] = checkpoint.L1BlockNumber
+		hashes[i] = checkpoint.L1BlockHash
+	}
+	forkIndex, err := sync_service.FindL1ForkPoint(s.ctx, s.client.client, numbers, hashes)
+	if errors.Is(err, sync_service.ErrL1ReorgTooDeep) {
+		log.Error("L1 reorg is deeper than the tracked checkpoints, manual intervention required", "oldest checkpoint", checkpoints[0].L1BlockNumber, "latest checkpoint", latest.L1BlockNumber)
+	}
+	if err != nil {
+		return err
+	}
+	forkPoint := checkpoints[forkIndex]
+
+	log.Warn("L1 reorg detected, rolling back rollup events", "fork point", forkPoint.L1BlockNumber, "latest processed block", s.latestProcessedBlock,
+		"last committed batch", s.lastCommittedBatchIndex, "last finalized batch", s.lastFinalizedBatchIndex,
+		"fork point last committed batch", forkPoint.LastCommittedBatchIndex, "fork point last finalized batch", forkPoint.LastFinalizedBatchIndex)
+
+	batchWriter := s.db.NewBatch()
+	for batchIndex := forkPoint.LastCommittedBatchIndex + 1; batchIndex <= s.lastCommittedBatchIndex; batchIndex++ {
+		deleteCommittedBatch(s.db, batchWriter, batchIndex)
+	}
+	for batchIndex := forkPoint.LastFinalizedBatchIndex + 1; batchIndex <= s.lastFinalizedBatchIndex; batchIndex++ {
+		rawdb.DeleteFinalizedBatchMeta(batchWriter, batchIndex)
+	}
+	rawdb.WriteLastFinalizedBatchIndex(batchWriter, forkPoint.LastFinalizedBatchIndex)
+	_, committedL2BlockNumber := lastCommittedBatchBefore(s.db, forkPoint.LastCommittedBatchIndex+1, forkPoint.LastFinalizedBatchIndex, forkPoint.FinalizedL2BlockNumber)
+	rawdb.WriteCommittedL2BlockNumber(batchWriter, committedL2BlockNumber)
+	rawdb.WriteFinalizedL2BlockNumber(batchWriter, forkPoint.FinalizedL2BlockNumber)
+	for _, checkpoint := range checkpoints[forkIndex+1:] {
+		rawdb.DeleteRollupEventSyncCheckpoint(batchWriter, checkpoint.L1BlockNumber)
+	}
+	rawdb.WriteRollupEventSyncedL1BlockNumber(batchWriter, forkPoint.L1BlockNumber)
+
+	if err := batchWriter.Write(); err != nil {
+		log.Crit("failed to roll back rollup events", "err", err)
+	}
+
+	l1ReorgCounter.Inc(1)
+	s.lastCommittedBatchIndex = forkPoint.LastCommittedBatchIndex
+	s.lastFinalizedBatchIndex = forkPoint.LastFinalizedBatchIndex
+	s.latestProcessedBlock = forkPoint.L1BlockNumber
+	return nil
+}
+
+// deleteCommittedBatch removes the data stored for a committed batch that was reverted or reorged out.
+func deleteCommittedBatch(db ethdb.Reader, writer ethdb.KeyValueWriter, batchIndex uint64) {
+	if chunkBlockRanges := rawdb.ReadBatchChunkRanges(db, batchIndex); len(chunkBlockRanges) > 0 {
+		rawdb.DeleteBatchEndBlockNumber(writer, chunkBlockRanges[len(chunkBlockRanges)-1].EndBlockNumber)
+	}
+	rawdb.DeleteBatchChunkRanges(writer, batchIndex)
+	rawdb.DeleteCommittedBatchMeta(writer, batchIndex)
+	rawdb.DeleteBatchBlob(writer, batchIndex)
+}
+
+// lastCommittedBatchBefore returns the index and the last L2 block of the latest batch before batchIndex
+// that is still committed. Finalized batches cannot be reverted, so the search stops at the last finalized
+// batch and falls back to the finalized L2 block if its chunk ranges are not available.
+func lastCommittedBatchBefore(db ethdb.Reader, batchIndex, lastFinalizedBatchIndex, finalizedL2BlockNumber uint64) (uint64, uint64) {
+	for index := batchIndex; index > lastFinalizedBatchIndex; index-- {
+		if chunkBlockRanges := rawdb.ReadBatchChunkRanges(db, index-1); len(chunkBlockRanges) > 0 {
+			return index - 1, chunkBlockRanges[len(chunkBlockRanges)-1].EndBlockNumber
+		}
+	}
+	return lastFinalizedBatchIndex, finalizedL2BlockNumber
+}
+
+// getLocalChunksForBatch rebuilds the chunks of a committed batch from local blocks,
+// waiting for the local node to sync up to the end of the batch if necessary.
+func (s *RollupSyncService) getLocalChunksForBatch(ctx context.Context, batchIndex uint64) ([]*encoding.Chunk, error) {
 	chunkBlockRanges := rawdb.ReadBatchChunkRanges(s.db, batchIndex)
 	if len(chunkBlockRanges) == 0 {
-		return nil, nil, fmt.Errorf("failed to get batch chunk ranges, empty chunk block ranges")
+		return nil, fmt.Errorf("failed to get batch chunk ranges, empty chunk block ranges")
 	}
 
 	endBlockNumber := chunkBlockRanges[len(chunkBlockRanges)-1].EndBlockNumber
 	for i := 0; i < defaultMaxRetries; i++ {
-		if s.ctx.Err() != nil {
-			log.Info("Context canceled", "reason", s.ctx.Err())
-			return nil, nil, s.ctx.Err()
-		}
-
 		localSyncedBlockHeight := s.bc.CurrentBlock().Number().Uint64()
 		if localSyncedBlockHeight >= endBlockNumber {
 			break // ready to proceed, exit retry loop
@@ -272,48 +488,67 @@ func (s *RollupSyncService) getLocalInfoForBatch(batchIndex uint64) (*rawdb.Fina
 
 		log.Debug("local node is not synced up to the required block height, waiting for next retry",
 			"retries", i+1, "local synced block height", localSyncedBlockHeight, "required end block number", endBlockNumber)
-		time.Sleep(defaultGetBlockInRangeRetryDelay)
+		select {
+		case <-ctx.Done():
+			log.Info("Context canceled", "reason", ctx.Err())
+			return nil, ctx.Err()
+		case <-time.After(defaultGetBlockInRangeRetryDelay):
+		}
 	}
 
 	localSyncedBlockHeight := s.bc.CurrentBlock().Number().Uint64()
 	if localSyncedBlockHeight < endBlockNumber {
-		return nil, nil, fmt.Errorf("local node is not synced up to the required block height: %v, local synced block height: %v", endBlockNumber, localSyncedBlockHeight)
+		return nil, fmt.Errorf("local node is not synced up to the required block height: %v, local synced block height: %v", endBlockNumber, localSyncedBlockHeight)
 	}
 
-	chunks := make([]*encoding.Chunk, len(chunkBlockRanges))
-	for i, cr := range chunkBlockRanges {
-		chunks[i] = &encoding.Chunk{Blocks: make([]*encoding.Block, cr.EndBlockNumber-cr.StartBlockNumber+1)}
-		for j := cr.StartBlockNumber; j <= cr.EndBlockNumber; j++ {
-			block := s.bc.GetBlockByNumber(j)
-			if block == nil {
-				return nil, nil, fmt.Errorf("failed to get block by number: %v", i)
-			}
-			txData := encoding.TxsToTxsData(block.Transactions())
-			state, err := s.bc.StateAt(block.Root())
-			if err != nil {
-				return nil, nil, fmt.Errorf("failed to get block state, block: %v, err: %w", block.Hash().Hex(), err)
-			}
-			withdrawRoot := withdrawtrie.ReadWTRSlot(rcfg.L2MessageQueueAddress, state)
-			chunks[i].Blocks[j-cr.StartBlockNumber] = &encoding.Block{
-				Header:       block.Header(),
-				Transactions: txData,
-				WithdrawRoot: withdrawRoot,
-			}
-		}
+	return readChunksFromChain(s.bc, chunkBlockRanges)
+}
+
+// getChunkRanges returns the block ranges of the chunks of a batch, tx is the commit batch transaction, nil for the genesis batch.
+func (s *RollupSyncService) getChunkRanges(batchIndex uint64, tx *types.Transaction) ([]*rawdb.ChunkBlockRange, error) {
+	if batchIndex == 0 {
+		return []*rawdb.ChunkBlockRange{{StartBlockNumber: 0, EndBlockNumber: 0}}, nil
+	}
+
+	return s.decodeChunkBlockRanges(tx.Data())
+}
+
+// fetchAndStoreBatchBlob retrieves the blob posted by the commit batch transaction tx, verifies it against
+// the blob versioned hash of the transaction, and stores it until the batch is finalized.
+// A blob that is not available yet is an error, so that the events are processed again later. Only the
+// blobs of L1 blocks older than the blob retention period are skipped, since they cannot be retrieved anymore.
+func (s *RollupSyncService) fetchAndStoreBatchBlob(batchIndex uint64, vLog *types.Log, tx *types.Transaction) error {
+	// codecv0 batches do not carry blobs
+	blobHashes := tx.BlobHashes()
+	if len(blobHashes) == 0 {
+		return nil
+	}
+	if len(blobHashes) != 1 {
+		return fmt.Errorf("unexpected number of blobs in commit batch transaction, tx hash: %v, blobs: %v", vLog.TxHash.Hex(), len(blobHashes))
+	}
+
+	header, err := s.client.client.HeaderByNumber(s.ctx, new(big.Int).SetUint64(vLog.BlockNumber))
+	if err != nil {
+		return fmt.Errorf("failed to get L1 header, block number: %v, err: %w", vLog.BlockNumber, err)
 	}
 
-	// get metadata of parent batch: default to genesis batch metadata.
-	parentBatchMeta := &rawdb.FinalizedBatchMeta{}
-	if batchIndex > 0 {
-		parentBatchMeta = rawdb.ReadFinalizedBatchMeta(s.db, batchIndex-1)
+	blob, err := s.blobClient.GetBlobByVersionedHashAndBlockTime(s.ctx, blobHashes[0], header.Time)
+	if errors.Is(err, errBlobNotFound) && time.Since(time.Unix(int64(header.Time), 0)) > blobRetentionPeriod {
+		log.Warn("Blob of committed batch is past the retention period, skipping blob verification", "batch index", batchIndex, "tx hash", vLog.TxHash.Hex(), "err", err)
+		return nil
+	}
+	if err != nil {
+		return err
 	}
 
-	return parentBatchMeta, chunks, nil
+	rawdb.WriteBatchBlob(s.db, batchIndex, blob)
+	return nil
 }
 
-func (s *RollupSyncService) getChunkRanges(batchIndex uint64, vLog *types.Log) ([]*rawdb.ChunkBlockRange, error) {
-	if batchIndex == 0 {
-		return []*rawdb.ChunkBlockRange{{StartBlockNumber: 0, EndBlockNumber: 0}}, nil
+// getCommitBatchTx retrieves the commit batch transaction that emitted the provided log.
+func (s *RollupSyncService) getCommitBatchTx(vLog *types.Log) (*types.Transaction, error) {
+	if tx, ok := s.commitBatchTxs[vLog.TxHash]; ok {
+		return tx, nil
 	}
 
 	tx, _, err := s.client.client.TransactionByHash(s.ctx, vLog.TxHash)
@@ -338,35 +573,14 @@ func (s *RollupSyncService) getChunkRanges(batchIndex uint64, vLog *types.Log) (
 		}
 	}
 
-	return s.decodeChunkBlockRanges(tx.Data())
+	return tx, nil
 }
 
 // decodeChunkBlockRanges decodes chunks in a batch based on the commit batch transaction's calldata.
 func (s *RollupSyncService) decodeChunkBlockRanges(txData []byte) ([]*rawdb.ChunkBlockRange, error) {
-	const methodIDLength = 4
-	if len(txData) < methodIDLength {
-		return nil, fmt.Errorf("transaction data is too short, length of tx data: %v, minimum length required: %v", len(txData), methodIDLength)
-	}
-
-	method, err := s.scrollChainABI.MethodById(txData[:methodIDLength])
-	if err != nil {
-		return nil, fmt.Errorf("failed to get method by ID, ID: %v, err: %w", txData[:methodIDLength], err)
-	}
-
-	values, err := method.Inputs.Unpack(txData[methodIDLength:])
+	args, err := decodeCommitBatchArgs(s.scrollChainABI, txData)
 	if err != nil {
-		return nil, fmt.Errorf("failed to unpack transaction data using ABI, tx data: %v, err: %w", txData, err)
-	}
-
-	type commitBatchArgs struct {
-		Version                uint8
-		ParentBatchHeader      []byte
-		Chunks                 [][]byte
-		SkippedL1MessageBitmap []byte
-	}
-	var args commitBatchArgs
-	if err = method.Inputs.Copy(&args, values); err != nil {
-		return nil, fmt.Errorf("failed to decode calldata into commitBatch args, values: %+v, err: %w", values, err)
+		return nil, err
 	}
 
 	return decodeBlockRangesFromEncodedChunks(encoding.CodecVersion(args.Version), args.Chunks)
@@ -374,65 +588,35 @@ func (s *RollupSyncService) decodeChunkBlockRanges(txData []byte) ([]*rawdb.Chun
 
 // validateBatch verifies the consistency between the L1 contract and L2 node data.
 // The function will terminate the node and exit if any consistency check fails.
-// It returns the number of the end block, a finalized batch meta data, and an error if any.
-func validateBatch(event *L1FinalizeBatchEvent, parentBatchMeta *rawdb.FinalizedBatchMeta, chunks []*encoding.Chunk, chainCfg *params.ChainConfig, stack *node.Node) (uint64, *rawdb.FinalizedBatchMeta, error) {
-	if len(chunks) == 0 {
-		return 0, nil, fmt.Errorf("invalid argument: length of chunks is 0, batch index: %v", event.BatchIndex.Uint64())
-	}
-
-	startChunk := chunks[0]
-	if len(startChunk.Blocks) == 0 {
-		return 0, nil, fmt.Errorf("invalid argument: block count of start chunk is 0, batch index: %v", event.BatchIndex.Uint64())
+// It returns the number of the end block, a finalized batch meta data, the local DA batch, and an error if any.
+func validateBatch(event *L1FinalizeBatchEvent, parentBatchMeta *rawdb.FinalizedBatchMeta, chunks []*encoding.Chunk, chainCfg *params.ChainConfig, stack *node.Node) (uint64, *rawdb.FinalizedBatchMeta, encoding.DABatch, error) {
+	v, err := VerifyBatch(event, parentBatchMeta, chunks, chainCfg)
+	if err != nil {
+		return 0, nil, nil, err
 	}
-	startBlock := startChunk.Blocks[0]
 
-	endChunk := chunks[len(chunks)-1]
-	if len(endChunk.Blocks) == 0 {
-		return 0, nil, fmt.Errorf("invalid argument: block count of end chunk is 0, batch index: %v", event.BatchIndex.Uint64())
-	}
-	endBlock := endChunk.Blocks[len(endChunk.Blocks)-1]
+	checkBatchVerification(v, chunks, stack)
+	return v.EndBlockNumber, v.FinalizedBatchMeta, v.DABatch, nil
+}
 
-	localStateRoot := endBlock.Header.Root
-	if localStateRoot != event.StateRoot {
-		log.Error("State root mismatch", "batch index", event.BatchIndex.Uint64(), "start block", startBlock.Header.Number.Uint64(), "end block", endBlock.Header.Number.Uint64(), "parent batch hash", parentBatchMeta.BatchHash.Hex(), "l1 finalized state root", event.StateRoot.Hex(), "l2 state root", localStateRoot.Hex())
+// checkBatchVerification terminates the node and exits if the local batch does not match the batch finalized on L1.
+func checkBatchVerification(v *BatchVerification, chunks []*encoding.Chunk, stack *node.Node) {
+	if v.L2StateRoot != v.L1StateRoot {
+		log.Error("State root mismatch", "batch index", v.BatchIndex, "start block", v.StartBlockNumber, "end block", v.EndBlockNumber, "parent batch hash", v.ParentBatchMeta.BatchHash.Hex(), "l1 finalized state root", v.L1StateRoot.Hex(), "l2 state root", v.L2StateRoot.Hex())
 		stack.Close()
 		os.Exit(1)
 	}
 
-	localWithdrawRoot := endBlock.WithdrawRoot
-	if localWithdrawRoot != event.WithdrawRoot {
-		log.Error("Withdraw root mismatch", "batch index", event.BatchIndex.Uint64(), "start block", startBlock.Header.Number.Uint64(), "end block", endBlock.Header.Number.Uint64(), "parent batch hash", parentBatchMeta.BatchHash.Hex(), "l1 finalized withdraw root", event.WithdrawRoot.Hex(), "l2 withdraw root", localWithdrawRoot.Hex())
+	if v.L2WithdrawRoot != v.L1WithdrawRoot {
+		log.Error("Withdraw root mismatch", "batch index", v.BatchIndex, "start block", v.StartBlockNumber, "end block", v.EndBlockNumber, "parent batch hash", v.ParentBatchMeta.BatchHash.Hex(), "l1 finalized withdraw root", v.L1WithdrawRoot.Hex(), "l2 withdraw root", v.L2WithdrawRoot.Hex())
 		stack.Close()
 		os.Exit(1)
 	}
 
-	// Note: All params of batch are calculated locally based on the block data.
-	batch := &encoding.Batch{
-		Index:                      event.BatchIndex.Uint64(),
-		TotalL1MessagePoppedBefore: parentBatchMeta.TotalL1MessagePopped,
-		ParentBatchHash:            parentBatchMeta.BatchHash,
-		Chunks:                     chunks,
-	}
-
-	var localBatchHash common.Hash
-	if startBlock.Header.Number.Uint64() == 0 || !chainCfg.IsBernoulli(startBlock.Header.Number) { // codecv0: genesis batch or batches before Bernoulli
-		daBatch, err := codecv0.NewDABatch(batch)
-		if err != nil {
-			return 0, nil, fmt.Errorf("failed to create codecv0 DA batch, batch index: %v, err: %w", event.BatchIndex.Uint64(), err)
-		}
-		localBatchHash = daBatch.Hash()
-	} else {
-		daBatch, err := codecv1.NewDABatch(batch)
-		if err != nil {
-			return 0, nil, fmt.Errorf("failed to create codecv1 DA batch, batch index: %v, err: %w", event.BatchIndex.Uint64(), err)
-		}
-		localBatchHash = daBatch.Hash()
-	}
-
 	// Note: If the batch headers match, this ensures the consistency of blocks and transactions
 	// (including skipped transactions) between L1 and L2.
-	if localBatchHash != event.BatchHash {
-		log.Error("Batch hash mismatch", "batch index", event.BatchIndex.Uint64(), "start block", startBlock.Header.Number.Uint64(), "end block", endBlock.Header.Number.Uint64(), "parent batch hash", parentBatchMeta.BatchHash.Hex(), "parent TotalL1MessagePopped", parentBatchMeta.TotalL1MessagePopped, "l1 finalized batch hash", event.BatchHash.Hex(), "l2 batch hash", localBatchHash.Hex())
+	if v.L2BatchHash != v.L1BatchHash {
+		log.Error("Batch hash mismatch", "batch index", v.BatchIndex, "start block", v.StartBlockNumber, "end block", v.EndBlockNumber, "parent batch hash", v.ParentBatchMeta.BatchHash.Hex(), "parent TotalL1MessagePopped", v.ParentBatchMeta.TotalL1MessagePopped, "l1 finalized batch hash", v.L1BatchHash.Hex(), "l2 batch hash", v.L2BatchHash.Hex())
 		chunksJson, err := json.Marshal(chunks)
 		if err != nil {
 			log.Error("marshal chunks failed", "err", err)
@@ -441,71 +625,61 @@ func validateBatch(event *L1FinalizeBatchEvent, parentBatchMeta *rawdb.Finalized
 		stack.Close()
 		os.Exit(1)
 	}
+}
+
+// validateBatchBlob verifies that the blob posted to L1 for a batch matches the blob payload of the local DA batch.
+// The function will terminate the node and exit if the check fails.
+func validateBatchBlob(event *L1FinalizeBatchEvent, daBatch encoding.DABatch, blob *kzg4844.Blob, stack *node.Node) error {
+	err := compareBatchBlob(event, daBatch, blob)
+	if errors.Is(err, errBlobMismatch) {
+		log.Error("Blob mismatch", "batch index", event.BatchIndex.Uint64(), "l1 finalized batch hash", event.BatchHash.Hex(), "l2 batch hash", daBatch.Hash().Hex(), "err", err)
+		stack.Close()
+		os.Exit(1)
+	}
+	return err
+}
 
-	totalL1MessagePopped := parentBatchMeta.TotalL1MessagePopped
-	for _, chunk := range chunks {
-		totalL1MessagePopped += chunk.NumL1Messages(totalL1MessagePopped)
+// compareBatchBlob compares the blob posted to L1 for a batch with the blob payload of the local DA batch,
+// it returns errBlobMismatch if they differ. The L1 blob was already checked against its commitment when it
+// was fetched, so the blobs are compared byte by byte.
+func compareBatchBlob(event *L1FinalizeBatchEvent, daBatch encoding.DABatch, blob *kzg4844.Blob) error {
+	localBlob := daBatch.Blob()
+	if localBlob == nil {
+		return fmt.Errorf("local DA batch has no blob, batch index: %v", event.BatchIndex.Uint64())
+	}
+	if *localBlob != *blob {
+		return fmt.Errorf("%w, batch index: %v, l1 versioned hash: %v, l2 versioned hash: %v", errBlobMismatch, event.BatchIndex.Uint64(), blobVersionedHash(blob), blobVersionedHash(localBlob))
 	}
-	finalizedBatchMeta := &rawdb.FinalizedBatchMeta{
-		BatchHash:            localBatchHash,
-		TotalL1MessagePopped: totalL1MessagePopped,
-		StateRoot:            localStateRoot,
-		WithdrawRoot:         localWithdrawRoot,
+	return nil
+}
+
+// blobVersionedHash returns the versioned hash of the KZG commitment of a blob for logs, or the error of
+// computing the commitment.
+func blobVersionedHash(blob *kzg4844.Blob) string {
+	commitment, err := kzg4844.BlobToCommitment(blob)
+	if err != nil {
+		return err.Error()
 	}
-	return endBlock.Header.Number.Uint64(), finalizedBatchMeta, nil
+	return common.Hash(kzg4844.CalcBlobHashV1(sha256.New(), &commitment)).Hex()
 }
 
 // decodeBlockRangesFromEncodedChunks decodes the provided chunks into a list of block ranges.
 func decodeBlockRangesFromEncodedChunks(codecVersion encoding.CodecVersion, chunks [][]byte) ([]*rawdb.ChunkBlockRange, error) {
-	var chunkBlockRanges []*rawdb.ChunkBlockRange
-	for _, chunk := range chunks {
-		if len(chunk) < 1 {
-			return nil, fmt.Errorf("invalid chunk, length is less than 1")
-		}
-
-		numBlocks := int(chunk[0])
-
-		switch codecVersion {
-		case encoding.CodecV0:
-			if len(chunk) < 1+numBlocks*60 {
-				return nil, fmt.Errorf("invalid chunk byte length, expected: %v, got: %v", 1+numBlocks*60, len(chunk))
-			}
-			daBlocks := make([]*codecv0.DABlock, numBlocks)
-			for i := 0; i < numBlocks; i++ {
-				startIdx := 1 + i*60 // add 1 to skip numBlocks byte
-				endIdx := startIdx + 60
-				daBlock, err := codecv0.DecodeDABlock(chunk[startIdx:endIdx])
-				if err != nil {
-					return nil, err
-				}
-				daBlocks[i] = daBlock
-			}
+	codec, err := encoding.CodecFromVersion(codecVersion)
+	if err != nil {
+		return nil, err
+	}
 
-			chunkBlockRanges = append(chunkBlockRanges, &rawdb.ChunkBlockRange{
-				StartBlockNumber: daBlocks[0].BlockNumber,
-				EndBlockNumber:   daBlocks[len(daBlocks)-1].BlockNumber,
-			})
-		case encoding.CodecV1:
-			if len(chunk) != 1+numBlocks*60 {
-				return nil, fmt.Errorf("invalid chunk byte length, expected: %v, got: %v", 1+numBlocks*60, len(chunk))
-			}
-			daBlocks := make([]*codecv1.DABlock, numBlocks)
-			for i := 0; i < numBlocks; i++ {
-				startIdx := 1 + i*60 // add 1 to skip numBlocks byte
-				endIdx := startIdx + 60
-				daBlock, err := codecv1.DecodeDABlock(chunk[startIdx:endIdx])
-				if err != nil {
-					return nil, err
-				}
-				daBlocks[i] = daBlock
-			}
+	daChunks, err := codec.DecodeDAChunks(chunks)
+	if err != nil {
+		return nil, err
+	}
 
-			chunkBlockRanges = append(chunkBlockRanges, &rawdb.ChunkBlockRange{
-				StartBlockNumber: daBlocks[0].BlockNumber,
-				EndBlockNumber:   daBlocks[len(daBlocks)-1].BlockNumber,
-			})
-		default:
-			return nil, fmt.Errorf("unexpected batch version %v", codecVersion)
+	chunkBlockRanges := make([]*rawdb.ChunkBlockRange, len(daChunks))
+	for i, chunk := range daChunks {
+		chunkBlockRanges[i] = &rawdb.ChunkBlockRange{
+			StartBlockNumber: chunk.Blocks[0].Header.Number.Uint64(),
+			EndBlockNumber:   chunk.Blocks[len(chunk.Blocks)-1].Header.Number.Uint64(),
 		}
 	}
 	return chunkBlockRanges, nil
@@ -20,6 +20,7 @@ import (
 	"github.com/scroll-tech/go-ethereum/node"
 	"github.com/scroll-tech/go-ethereum/params"
 
+	"github.com/scroll-tech/go-ethereum/rollup/sync_service"
 	"github.com/scroll-tech/go-ethereum/rollup/types/encoding"
 )
 
@@ -191,7 +192,9 @@ func TestGetChunkRangesCodecv0(t *testing.T) {
 	vLog := &types.Log{
 		TxHash: common.HexToHash("0x0"),
 	}
-	ranges, err := service.getChunkRanges(1, vLog)
+	tx, err := service.getCommitBatchTx(vLog)
+	require.NoError(t, err)
+	ranges, err := service.getChunkRanges(1, tx)
 	require.NoError(t, err)
 
 	expectedRanges := []*rawdb.ChunkBlockRange{
@@ -243,7 +246,9 @@ func TestGetChunkRangesCodecv1(t *testing.T) {
 	vLog := &types.Log{
 		TxHash: common.HexToHash("0x1"),
 	}
-	ranges, err := service.getChunkRanges(1, vLog)
+	tx, err := service.getCommitBatchTx(vLog)
+	require.NoError(t, err)
+	ranges, err := service.getChunkRanges(1, tx)
 	require.NoError(t, err)
 
 	expectedRanges := []*rawdb.ChunkBlockRange{
@@ -279,7 +284,7 @@ func TestValidateBatchCodecv0(t *testing.T) {
 		WithdrawRoot: chunk3.Blocks[len(chunk3.Blocks)-1].WithdrawRoot,
 	}
 
-	endBlock1, finalizedBatchMeta1, err := validateBatch(event1, parentBatchMeta1, []*encoding.Chunk{chunk1, chunk2, chunk3}, &params.ChainConfig{}, nil)
+	endBlock1, finalizedBatchMeta1, _, err := validateBatch(event1, parentBatchMeta1, []*encoding.Chunk{chunk1, chunk2, chunk3}, &params.ChainConfig{}, nil)
 	assert.NoError(t, err)
 	assert.Equal(t, uint64(13), endBlock1)
 
@@ -299,7 +304,7 @@ func TestValidateBatchCodecv0(t *testing.T) {
 		StateRoot:    chunk4.Blocks[len(chunk4.Blocks)-1].Header.Root,
 		WithdrawRoot: chunk4.Blocks[len(chunk4.Blocks)-1].WithdrawRoot,
 	}
-	endBlock2, finalizedBatchMeta2, err := validateBatch(event2, parentBatchMeta2, []*encoding.Chunk{chunk4}, &params.ChainConfig{}, nil)
+	endBlock2, finalizedBatchMeta2, _, err := validateBatch(event2, parentBatchMeta2, []*encoding.Chunk{chunk4}, &params.ChainConfig{}, nil)
 	assert.NoError(t, err)
 	assert.Equal(t, uint64(17), endBlock2)
 
@@ -330,7 +335,7 @@ func TestValidateBatchCodecv1(t *testing.T) {
 		WithdrawRoot: chunk3.Blocks[len(chunk3.Blocks)-1].WithdrawRoot,
 	}
 
-	endBlock1, finalizedBatchMeta1, err := validateBatch(event1, parentBatchMeta1, []*encoding.Chunk{chunk1, chunk2, chunk3}, &params.ChainConfig{BernoulliBlock: big.NewInt(0)}, nil)
+	endBlock1, finalizedBatchMeta1, _, err := validateBatch(event1, parentBatchMeta1, []*encoding.Chunk{chunk1, chunk2, chunk3}, &params.ChainConfig{BernoulliBlock: big.NewInt(0)}, nil)
 	assert.NoError(t, err)
 	assert.Equal(t, uint64(13), endBlock1)
 
@@ -350,7 +355,7 @@ func TestValidateBatchCodecv1(t *testing.T) {
 		StateRoot:    chunk4.Blocks[len(chunk4.Blocks)-1].Header.Root,
 		WithdrawRoot: chunk4.Blocks[len(chunk4.Blocks)-1].WithdrawRoot,
 	}
-	endBlock2, finalizedBatchMeta2, err := validateBatch(event2, parentBatchMeta2, []*encoding.Chunk{chunk4}, &params.ChainConfig{BernoulliBlock: big.NewInt(0)}, nil)
+	endBlock2, finalizedBatchMeta2, _, err := validateBatch(event2, parentBatchMeta2, []*encoding.Chunk{chunk4}, &params.ChainConfig{BernoulliBlock: big.NewInt(0)}, nil)
 	assert.NoError(t, err)
 	assert.Equal(t, uint64(17), endBlock2)
 
@@ -381,7 +386,7 @@ func TestValidateBatchFromCodecv0ToCodecv1(t *testing.T) {
 		WithdrawRoot: chunk3.Blocks[len(chunk3.Blocks)-1].WithdrawRoot,
 	}
 
-	endBlock1, finalizedBatchMeta1, err := validateBatch(event1, parentBatchMeta1, []*encoding.Chunk{chunk1, chunk2, chunk3}, &params.ChainConfig{BernoulliBlock: big.NewInt(16)}, nil)
+	endBlock1, finalizedBatchMeta1, _, err := validateBatch(event1, parentBatchMeta1, []*encoding.Chunk{chunk1, chunk2, chunk3}, &params.ChainConfig{BernoulliBlock: big.NewInt(16)}, nil)
 	assert.NoError(t, err)
 	assert.Equal(t, uint64(13), endBlock1)
 
@@ -401,7 +406,7 @@ func TestValidateBatchFromCodecv0ToCodecv1(t *testing.T) {
 		StateRoot:    chunk4.Blocks[len(chunk4.Blocks)-1].Header.Root,
 		WithdrawRoot: chunk4.Blocks[len(chunk4.Blocks)-1].WithdrawRoot,
 	}
-	endBlock2, finalizedBatchMeta2, err := validateBatch(event2, parentBatchMeta2, []*encoding.Chunk{chunk4}, &params.ChainConfig{BernoulliBlock: big.NewInt(16)}, nil)
+	endBlock2, finalizedBatchMeta2, _, err := validateBatch(event2, parentBatchMeta2, []*encoding.Chunk{chunk4}, &params.ChainConfig{BernoulliBlock: big.NewInt(16)}, nil)
 	assert.NoError(t, err)
 	assert.Equal(t, uint64(17), endBlock2)
 
@@ -422,3 +427,123 @@ func readBlockFromJSON(t *testing.T, filename string) *encoding.Block {
 	assert.NoError(t, json.Unmarshal(data, block))
 	return block
 }
+
+// mockReorgEthClient serves L1 headers whose content depends on the L1 fork they belong to.
+type mockReorgEthClient struct {
+	mockEthClient
+	reorgedFrom uint64 // blocks at or after this height are on the new fork, 0 means no reorg
+}
+
+func (m *mockReorgEthClient) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	header := &types.Header{Number: new(big.Int).Set(number)}
+	if m.reorgedFrom != 0 && number.Uint64() >= m.reorgedFrom {
+		header.Extra = []byte("reorged")
+	}
+	return header, nil
+}
+
+func (m *mockReorgEthClient) BlockHashByNumber(ctx context.Context, number *big.Int) (common.Hash, error) {
+	header, _ := m.HeaderByNumber(ctx, number)
+	return header.Hash(), nil
+}
+
+func TestDetectAndHandleL1Reorg(t *testing.T) {
+	db := rawdb.NewDatabase(memorydb.New())
+	l1Client := &mockReorgEthClient{}
+	service := &RollupSyncService{
+		ctx:    context.Background(),
+		client: &L1Client{client: l1Client},
+		db:     db,
+	}
+
+	// L1 block 10: batch 1 committed
+	rawdb.WriteBatchChunkRanges(db, 1, []*rawdb.ChunkBlockRange{{StartBlockNumber: 1, EndBlockNumber: 10}})
+	service.lastCommittedBatchIndex = 1
+	service.writeRollupEventSyncCheckpoint(10)
+
+	// L1 block 20: batch 2 committed, batch 1 finalized
+	rawdb.WriteBatchChunkRanges(db, 2, []*rawdb.ChunkBlockRange{{StartBlockNumber: 11, EndBlockNumber: 20}})
+	rawdb.WriteFinalizedBatchMeta(db, 1, &rawdb.FinalizedBatchMeta{BatchHash: common.HexToHash("0x1")})
+	rawdb.WriteFinalizedL2BlockNumber(db, 10)
+	service.lastCommittedBatchIndex = 2
+	service.lastFinalizedBatchIndex = 1
+	service.writeRollupEventSyncCheckpoint(20)
+
+	// L1 block 30: batch 3 committed, batch 2 finalized
+	rawdb.WriteBatchChunkRanges(db, 3, []*rawdb.ChunkBlockRange{{StartBlockNumber: 21, EndBlockNumber: 30}})
+	rawdb.WriteBatchEndBlockNumber(db, 30, 3)
+	rawdb.WriteFinalizedBatchMeta(db, 2, &rawdb.FinalizedBatchMeta{BatchHash: common.HexToHash("0x2")})
+	rawdb.WriteFinalizedL2BlockNumber(db, 20)
+	service.lastCommittedBatchIndex = 3
+	service.lastFinalizedBatchIndex = 2
+	service.writeRollupEventSyncCheckpoint(30)
+	rawdb.WriteRollupEventSyncedL1BlockNumber(db, 30)
+	service.latestProcessedBlock = 30
+
+	// no reorg
+	require.NoError(t, service.detectAndHandleL1Reorg())
+	assert.Equal(t, uint64(30), service.latestProcessedBlock)
+	assert.Len(t, rawdb.ReadRollupEventSyncCheckpoints(db), 3)
+
+	// L1 reorg starting at block 25
+	l1Client.reorgedFrom = 25
+	require.NoError(t, service.detectAndHandleL1Reorg())
+
+	assert.Equal(t, uint64(20), service.latestProcessedBlock)
+	assert.Equal(t, uint64(20), *rawdb.ReadRollupEventSyncedL1BlockNumber(db))
+	assert.Equal(t, uint64(2), service.lastCommittedBatchIndex)
+	assert.Equal(t, uint64(1), service.lastFinalizedBatchIndex)
+	assert.NotNil(t, rawdb.ReadBatchChunkRanges(db, 2))
+	assert.Nil(t, rawdb.ReadBatchChunkRanges(db, 3))
+	assert.NotNil(t, rawdb.ReadFinalizedBatchMeta(db, 1))
+	assert.Nil(t, rawdb.ReadFinalizedBatchMeta(db, 2))
+	assert.Equal(t, uint64(10), *rawdb.ReadFinalizedL2BlockNumber(db))
+	assert.Equal(t, uint64(1), *rawdb.ReadLastFinalizedBatchIndex(db))
+	assert.Equal(t, uint64(20), *rawdb.ReadCommittedL2BlockNumber(db))
+	assert.Nil(t, rawdb.ReadBatchIndexByBlockNumber(db, 25))
+	assert.Len(t, rawdb.ReadRollupEventSyncCheckpoints(db), 2)
+
+	// L1 reorg deeper than all checkpoints
+	l1Client.reorgedFrom = 1
+	assert.ErrorIs(t, service.detectAndHandleL1Reorg(), sync_service.ErrL1ReorgTooDeep)
+}
+
+func TestRevertBatchRollsBackCommittedHead(t *testing.T) {
+	scrollChainABI, err := scrollChainMetaData.GetAbi()
+	require.NoError(t, err)
+
+	db := rawdb.NewDatabase(memorydb.New())
+	service := &RollupSyncService{
+		ctx:                         context.Background(),
+		db:                          db,
+		scrollChainABI:              scrollChainABI,
+		l1RevertBatchEventSignature: scrollChainABI.Events["RevertBatch"].ID,
+		lastCommittedBatchIndex:     3,
+		lastFinalizedBatchIndex:     1,
+	}
+
+	for i := uint64(1); i <= 3; i++ {
+		rawdb.WriteBatchChunkRanges(db, i, []*rawdb.ChunkBlockRange{{StartBlockNumber: 10*i - 9, EndBlockNumber: 10 * i}})
+		rawdb.WriteCommittedBatchMeta(db, i, &rawdb.CommittedBatchMeta{BatchHash: common.BigToHash(new(big.Int).SetUint64(i)), L1BlockNumber: 100 + i})
+		rawdb.WriteBatchEndBlockNumber(db, 10*i, i)
+	}
+	rawdb.WriteCommittedL2BlockNumber(db, 30)
+	rawdb.WriteFinalizedL2BlockNumber(db, 10)
+
+	revertLog := func(batchIndex uint64) types.Log {
+		return types.Log{
+			Data:   []byte{},
+			Topics: []common.Hash{service.l1RevertBatchEventSignature, common.BigToHash(new(big.Int).SetUint64(batchIndex)), common.BigToHash(new(big.Int).SetUint64(batchIndex))},
+		}
+	}
+
+	// batches 2 and 3 are reverted in one transaction
+	require.NoError(t, service.parseAndUpdateRollupEventLogs([]types.Log{revertLog(2), revertLog(3)}, 200))
+
+	assert.Equal(t, uint64(1), service.lastCommittedBatchIndex)
+	assert.Equal(t, uint64(10), *rawdb.ReadCommittedL2BlockNumber(db))
+	assert.NotNil(t, rawdb.ReadCommittedBatchMeta(db, 1))
+	assert.Nil(t, rawdb.ReadCommittedBatchMeta(db, 2))
+	assert.Nil(t, rawdb.ReadCommittedBatchMeta(db, 3))
+	assert.Nil(t, rawdb.ReadBatchIndexByBlockNumber(db, 11))
+}
@@ -0,0 +1,311 @@
+package rollup_sync_service
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/scroll-tech/go-ethereum/accounts/abi"
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/common/hexutil"
+	"github.com/scroll-tech/go-ethereum/core/rawdb"
+	"github.com/scroll-tech/go-ethereum/core/state"
+	"github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/params"
+	"github.com/scroll-tech/go-ethereum/rlp"
+	"github.com/scroll-tech/go-ethereum/rollup/rcfg"
+	"github.com/scroll-tech/go-ethereum/rollup/types/encoding"
+	"github.com/scroll-tech/go-ethereum/rollup/withdrawtrie"
+)
+
+// BlockChain is the subset of the local chain used to rebuild batches from L2 blocks.
+type BlockChain interface {
+	Config() *params.ChainConfig
+	CurrentBlock() *types.Block
+	GetBlockByNumber(number uint64) *types.Block
+	StateAt(root common.Hash) (*state.StateDB, error)
+}
+
+// BatchVerification is the result of recomputing a finalized batch from local L2 blocks.
+type BatchVerification struct {
+	BatchIndex       uint64
+	StartBlockNumber uint64
+	EndBlockNumber   uint64
+	ParentBatchMeta  *rawdb.FinalizedBatchMeta // metadata of the parent batch the local batch builds on
+
+	L1StateRoot    common.Hash
+	L2StateRoot    common.Hash
+	L1WithdrawRoot common.Hash
+	L2WithdrawRoot common.Hash
+	L1BatchHash    common.Hash
+	L2BatchHash    common.Hash
+
+	FinalizedBatchMeta *rawdb.FinalizedBatchMeta // metadata of the local batch
+	DABatch            encoding.DABatch          // the local DA batch
+}
+
+// Match reports whether the local batch matches the batch finalized on L1.
+func (v *BatchVerification) Match() bool {
+	return v.L1StateRoot == v.L2StateRoot && v.L1WithdrawRoot == v.L2WithdrawRoot && v.L1BatchHash == v.L2BatchHash
+}
+
+// VerifyBatch recomputes a batch from the provided chunks of local blocks and compares it with the
+// finalize event emitted on L1. Mismatches are reported in the result, an error is only returned
+// if the local batch cannot be computed.
+func VerifyBatch(event *L1FinalizeBatchEvent, parentBatchMeta *rawdb.FinalizedBatchMeta, chunks []*encoding.Chunk, chainCfg *params.ChainConfig) (*BatchVerification, error) {
+	if len(chunks) == 0 {
+		return nil, fmt.Errorf("invalid argument: length of chunks is 0, batch index: %v", event.BatchIndex.Uint64())
+	}
+
+	startChunk := chunks[0]
+	if len(startChunk.Blocks) == 0 {
+		return nil, fmt.Errorf("invalid argument: block count of start chunk is 0, batch index: %v", event.BatchIndex.Uint64())
+	}
+	startBlock := startChunk.Blocks[0]
+
+	endChunk := chunks[len(chunks)-1]
+	if len(endChunk.Blocks) == 0 {
+		return nil, fmt.Errorf("invalid argument: block count of end chunk is 0, batch index: %v", event.BatchIndex.Uint64())
+	}
+	endBlock := endChunk.Blocks[len(endChunk.Blocks)-1]
+
+	// Note: All params of batch are calculated locally based on the block data.
+	batch := &encoding.Batch{
+		Index:                      event.BatchIndex.Uint64(),
+		TotalL1MessagePoppedBefore: parentBatchMeta.TotalL1MessagePopped,
+		ParentBatchHash:            parentBatchMeta.BatchHash,
+		Chunks:                     chunks,
+	}
+
+	codec, err := encoding.CodecFromConfig(chainCfg, startBlock.Header.Number)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get codec, batch index: %v, err: %w", event.BatchIndex.Uint64(), err)
+	}
+	daBatch, err := codec.NewDABatch(batch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create codecv%d DA batch, batch index: %v, err: %w", codec.Version(), event.BatchIndex.Uint64(), err)
+	}
+
+	totalL1MessagePopped := parentBatchMeta.TotalL1MessagePopped
+	for _, chunk := range chunks {
+		totalL1MessagePopped += chunk.NumL1Messages(totalL1MessagePopped)
+	}
+
+	return &BatchVerification{
+		BatchIndex:       event.BatchIndex.Uint64(),
+		StartBlockNumber: startBlock.Header.Number.Uint64(),
+		EndBlockNumber:   endBlock.Header.Number.Uint64(),
+		ParentBatchMeta:  parentBatchMeta,
+		L1StateRoot:      event.StateRoot,
+		L2StateRoot:      endBlock.Header.Root,
+		L1WithdrawRoot:   event.WithdrawRoot,
+		L2WithdrawRoot:   endBlock.WithdrawRoot,
+		L1BatchHash:      event.BatchHash,
+		L2BatchHash:      daBatch.Hash(),
+		FinalizedBatchMeta: &rawdb.FinalizedBatchMeta{
+			BatchHash:            daBatch.Hash(),
+			TotalL1MessagePopped: totalL1MessagePopped,
+			StateRoot:            endBlock.Header.Root,
+			WithdrawRoot:         endBlock.WithdrawRoot,
+		},
+		DABatch: daBatch,
+	}, nil
+}
+
+// VerifyBatchOffline recomputes a finalized batch from the local chain without L1 access.
+// Instead of the chunk ranges and parent batch metadata stored by the rollup sync service,
+// it uses the calldata of the commit batch transaction. The calldata may be nil for the genesis batch.
+func VerifyBatchOffline(bc BlockChain, commitTxData []byte, event *L1FinalizeBatchEvent) (*BatchVerification, error) {
+	batchIndex := event.BatchIndex.Uint64()
+	if batchIndex == 0 {
+		chunks, err := readChunksFromChain(bc, []*rawdb.ChunkBlockRange{{StartBlockNumber: 0, EndBlockNumber: 0}})
+		if err != nil {
+			return nil, err
+		}
+		return VerifyBatch(event, &rawdb.FinalizedBatchMeta{}, chunks, bc.Config())
+	}
+
+	scrollChainABI, err := scrollChainMetaData.GetAbi()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get scroll chain abi: %w", err)
+	}
+	args, err := decodeCommitBatchArgs(scrollChainABI, commitTxData)
+	if err != nil {
+		return nil, err
+	}
+
+	parentBatchIndex, parentBatchMeta, err := parentBatchMetaFromHeader(args.ParentBatchHeader)
+	if err != nil {
+		return nil, err
+	}
+	if parentBatchIndex+1 != batchIndex {
+		return nil, fmt.Errorf("commit batch transaction does not belong to the batch, batch index: %v, parent batch index: %v", batchIndex, parentBatchIndex)
+	}
+
+	chunkBlockRanges, err := decodeBlockRangesFromEncodedChunks(encoding.CodecVersion(args.Version), args.Chunks)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode chunk block ranges, batch index: %v, err: %w", batchIndex, err)
+	}
+	if endBlockNumber := chunkBlockRanges[len(chunkBlockRanges)-1].EndBlockNumber; bc.CurrentBlock().NumberU64() < endBlockNumber {
+		return nil, fmt.Errorf("local chain is not synced up to the required block height: %v, local block height: %v", endBlockNumber, bc.CurrentBlock().NumberU64())
+	}
+
+	chunks, err := readChunksFromChain(bc, chunkBlockRanges)
+	if err != nil {
+		return nil, err
+	}
+	return VerifyBatch(event, parentBatchMeta, chunks, bc.Config())
+}
+
+// readChunksFromChain builds the chunks of a batch from the local blocks in the provided ranges.
+func readChunksFromChain(bc BlockChain, chunkBlockRanges []*rawdb.ChunkBlockRange) ([]*encoding.Chunk, error) {
+	chunks := make([]*encoding.Chunk, len(chunkBlockRanges))
+	for i, cr := range chunkBlockRanges {
+		chunks[i] = &encoding.Chunk{Blocks: make([]*encoding.Block, cr.EndBlockNumber-cr.StartBlockNumber+1)}
+		for j := cr.StartBlockNumber; j <= cr.EndBlockNumber; j++ {
+			block := bc.GetBlockByNumber(j)
+			if block == nil {
+				return nil, fmt.Errorf("failed to get block by number: %v", j)
+			}
+			txData := encoding.TxsToTxsData(block.Transactions())
+			state, err := bc.StateAt(block.Root())
+			if err != nil {
+				return nil, fmt.Errorf("failed to get block state, block: %v, err: %w", block.Hash().Hex(), err)
+			}
+			withdrawRoot := withdrawtrie.ReadWTRSlot(rcfg.L2MessageQueueAddress, state)
+			chunks[i].Blocks[j-cr.StartBlockNumber] = &encoding.Block{
+				Header:       block.Header(),
+				Transactions: txData,
+				WithdrawRoot: withdrawRoot,
+			}
+		}
+	}
+	return chunks, nil
+}
+
+// commitBatchArgs are the arguments of the commitBatch method of the ScrollChain contract.
+type commitBatchArgs struct {
+	Version                uint8
+	ParentBatchHeader      []byte
+	Chunks                 [][]byte
+	SkippedL1MessageBitmap []byte
+}
+
+// decodeCommitBatchArgs decodes the calldata of a commit batch transaction.
+func decodeCommitBatchArgs(scrollChainABI *abi.ABI, txData []byte) (*commitBatchArgs, error) {
+	const methodIDLength = 4
+	if len(txData) < methodIDLength {
+		return nil, fmt.Errorf("transaction data is too short, length of tx data: %v, minimum length required: %v", len(txData), methodIDLength)
+	}
+
+	method, err := scrollChainABI.MethodById(txData[:methodIDLength])
+	if err != nil {
+		return nil, fmt.Errorf("failed to get method by ID, ID: %v, err: %w", txData[:methodIDLength], err)
+	}
+
+	values, err := method.Inputs.Unpack(txData[methodIDLength:])
+	if err != nil {
+		return nil, fmt.Errorf("failed to unpack transaction data using ABI, tx data: %v, err: %w", txData, err)
+	}
+
+	var args commitBatchArgs
+	if err = method.Inputs.Copy(&args, values); err != nil {
+		return nil, fmt.Errorf("failed to decode calldata into commitBatch args, values: %+v, err: %w", values, err)
+	}
+	return &args, nil
+}
+
+// parentBatchMetaFromHeader decodes the index, the hash and the total number of popped L1 messages of a batch
+// from its encoded header. All codec versions share the header layout up to the total number of popped L1 messages:
+// version (1 byte), batch index (8 bytes), L1 messages popped (8 bytes), total L1 messages popped (8 bytes).
+func parentBatchMetaFromHeader(header []byte) (uint64, *rawdb.FinalizedBatchMeta, error) {
+	if len(header) < 25 {
+		return 0, nil, fmt.Errorf("batch header is too short, length: %v", len(header))
+	}
+
+	codec, err := encoding.CodecFromVersion(encoding.CodecVersion(header[0]))
+	if err != nil {
+		return 0, nil, err
+	}
+	daBatch, err := codec.NewDABatchFromBytes(header)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to decode codecv%d batch header, err: %w", codec.Version(), err)
+	}
+
+	meta := &rawdb.FinalizedBatchMeta{
+		BatchHash:            daBatch.Hash(),
+		TotalL1MessagePopped: binary.BigEndian.Uint64(header[17:25]),
+	}
+	return binary.BigEndian.Uint64(header[1:9]), meta, nil
+}
+
+// ReadCommitBatchTxData reads the calldata of a commit batch transaction from a file. The file contains either
+// the JSON transaction object returned by eth_getTransactionByHash or the RLP-encoded transaction.
+func ReadCommitBatchTxData(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		var tx struct {
+			Input *hexutil.Bytes `json:"input"`
+		}
+		if err := json.Unmarshal(trimmed, &tx); err != nil {
+			return nil, fmt.Errorf("failed to decode commit batch transaction JSON, err: %w", err)
+		}
+		if tx.Input == nil {
+			return nil, fmt.Errorf("missing input in commit batch transaction JSON")
+		}
+		return *tx.Input, nil
+	}
+
+	var tx types.Transaction
+	if err := rlp.DecodeBytes(data, &tx); err != nil {
+		return nil, fmt.Errorf("failed to decode commit batch transaction RLP, err: %w", err)
+	}
+	return tx.Data(), nil
+}
+
+// ReadFinalizeBatchEvent reads the FinalizeBatch event of a batch from a file. The file contains either a single
+// JSON log object or the JSON array of logs returned by eth_getLogs.
+func ReadFinalizeBatchEvent(path string, batchIndex uint64) (*L1FinalizeBatchEvent, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var logs []types.Log
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		if err := json.Unmarshal(trimmed, &logs); err != nil {
+			return nil, fmt.Errorf("failed to decode finalize batch event logs, err: %w", err)
+		}
+	} else {
+		var vLog types.Log
+		if err := json.Unmarshal(trimmed, &vLog); err != nil {
+			return nil, fmt.Errorf("failed to decode finalize batch event log, err: %w", err)
+		}
+		logs = append(logs, vLog)
+	}
+
+	scrollChainABI, err := scrollChainMetaData.GetAbi()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get scroll chain abi: %w", err)
+	}
+	finalizeBatchEventSignature := scrollChainABI.Events["FinalizeBatch"].ID
+	for _, vLog := range logs {
+		if len(vLog.Topics) == 0 || vLog.Topics[0] != finalizeBatchEventSignature {
+			continue
+		}
+		event := &L1FinalizeBatchEvent{}
+		if err := UnpackLog(scrollChainABI, event, "FinalizeBatch", vLog); err != nil {
+			return nil, fmt.Errorf("failed to unpack finalized rollup event log, err: %w", err)
+		}
+		if event.BatchIndex.Uint64() == batchIndex {
+			return event, nil
+		}
+	}
+	return nil, fmt.Errorf("no FinalizeBatch event found for batch index %v", batchIndex)
+}
@@ -0,0 +1,82 @@
+package rollup_sync_service
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/core/rawdb"
+	"github.com/scroll-tech/go-ethereum/crypto"
+	"github.com/scroll-tech/go-ethereum/params"
+	"github.com/scroll-tech/go-ethereum/rollup/types/encoding"
+)
+
+func TestVerifyBatchReportsMismatch(t *testing.T) {
+	block1 := readBlockFromJSON(t, "./testdata/blockTrace_02.json")
+	block2 := readBlockFromJSON(t, "./testdata/blockTrace_03.json")
+	chunks := []*encoding.Chunk{{Blocks: []*encoding.Block{block1}}, {Blocks: []*encoding.Block{block2}}}
+
+	event := &L1FinalizeBatchEvent{
+		BatchIndex:   big.NewInt(0),
+		BatchHash:    common.HexToHash("0x01"),
+		StateRoot:    block2.Header.Root,
+		WithdrawRoot: common.HexToHash("0x02"),
+	}
+
+	v, err := VerifyBatch(event, &rawdb.FinalizedBatchMeta{}, chunks, &params.ChainConfig{})
+	require.NoError(t, err)
+	assert.False(t, v.Match())
+	assert.Equal(t, block1.Header.Number.Uint64(), v.StartBlockNumber)
+	assert.Equal(t, block2.Header.Number.Uint64(), v.EndBlockNumber)
+	assert.Equal(t, v.L1StateRoot, v.L2StateRoot)
+	assert.Equal(t, block2.WithdrawRoot, v.L2WithdrawRoot)
+	assert.Equal(t, v.DABatch.Hash(), v.L2BatchHash)
+	assert.NotEqual(t, v.L1BatchHash, v.L2BatchHash)
+
+	event.BatchHash = v.L2BatchHash
+	event.WithdrawRoot = v.L2WithdrawRoot
+	v, err = VerifyBatch(event, &rawdb.FinalizedBatchMeta{}, chunks, &params.ChainConfig{})
+	require.NoError(t, err)
+	assert.True(t, v.Match())
+}
+
+func TestReadCommitBatchTxData(t *testing.T) {
+	scrollChainABI, err := scrollChainMetaData.GetAbi()
+	require.NoError(t, err)
+
+	txData, err := ReadCommitBatchTxData("./testdata/commitBatch_input_codecv0.json")
+	require.NoError(t, err)
+	args, err := decodeCommitBatchArgs(scrollChainABI, txData)
+	require.NoError(t, err)
+	assert.Equal(t, uint8(encoding.CodecV0), args.Version)
+
+	parentBatchIndex, parentBatchMeta, err := parentBatchMetaFromHeader(args.ParentBatchHeader)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(129579), parentBatchIndex)
+	assert.Equal(t, crypto.Keccak256Hash(args.ParentBatchHeader), parentBatchMeta.BatchHash)
+	assert.Equal(t, uint64(246893), parentBatchMeta.TotalL1MessagePopped)
+
+	txData, err = ReadCommitBatchTxData("./testdata/commitBatch_codecv1.rlp")
+	require.NoError(t, err)
+	args, err = decodeCommitBatchArgs(scrollChainABI, txData)
+	require.NoError(t, err)
+	assert.Equal(t, uint8(encoding.CodecV1), args.Version)
+
+	_, _, err = parentBatchMetaFromHeader(args.ParentBatchHeader[:24])
+	assert.Error(t, err)
+}
+
+func TestReadFinalizeBatchEvent(t *testing.T) {
+	event, err := ReadFinalizeBatchEvent("./testdata/finalizeBatch_logs.json", 129580)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(129580), event.BatchIndex.Uint64())
+	assert.Equal(t, crypto.Keccak256Hash([]byte("batch")), event.BatchHash)
+	assert.Equal(t, crypto.Keccak256Hash([]byte("state")), event.StateRoot)
+	assert.Equal(t, crypto.Keccak256Hash([]byte("withdraw")), event.WithdrawRoot)
+
+	_, err = ReadFinalizeBatchEvent("./testdata/finalizeBatch_logs.json", 129579)
+	assert.Error(t, err)
+}
@@ -6,13 +6,18 @@ import (
 	"fmt"
 	"math/big"
 
+	"github.com/scroll-tech/go-ethereum"
 	"github.com/scroll-tech/go-ethereum/accounts/abi/bind"
 	"github.com/scroll-tech/go-ethereum/common"
 	"github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/crypto"
 	"github.com/scroll-tech/go-ethereum/log"
 	"github.com/scroll-tech/go-ethereum/rpc"
 )
 
+// queueTransactionEventSignature is the topic of QueueTransaction events emitted by L1MessageQueue.
+var queueTransactionEventSignature = crypto.Keccak256Hash([]byte("QueueTransaction(address,address,uint256,uint64,uint256,bytes)"))
+
 // BridgeClient is a wrapper around EthClient that adds
 // methods for conveniently collecting L1 messages.
 type BridgeClient struct {
@@ -20,6 +25,9 @@ type BridgeClient struct {
 	confirmations         rpc.BlockNumber
 	l1MessageQueueAddress common.Address
 	filterer              *L1MessageQueueFilterer
+
+	// verifier checks the fetched logs against the L1 header chain, nil if verification is disabled
+	verifier *messageVerifier
 }
 
 func newBridgeClient(ctx context.Context, l1Client EthClient, l1ChainId uint64, confirmations rpc.BlockNumber, l1MessageQueueAddress common.Address) (*BridgeClient, error) {
@@ -67,10 +75,12 @@ func (c *BridgeClient) fetchMessagesInRange(ctx context.Context, from, to uint64
 	}
 
 	var msgs []types.L1MessageTx
+	var logs []types.Log
 
 	for it.Next() {
 		event := it.Event
 		log.Trace("Received new L1 QueueTransaction event", "event", event)
+		logs = append(logs, event.Raw)
 
 		if !event.GasLimit.IsUint64() {
 			return nil, fmt.Errorf("invalid QueueTransaction event: QueueIndex = %v, GasLimit = %v", event.QueueIndex, event.GasLimit)
@@ -90,9 +100,23 @@ func (c *BridgeClient) fetchMessagesInRange(ctx context.Context, from, to uint64
 		return nil, err
 	}
 
+	if c.verifier != nil {
+		if err := c.verifier.verifyRange(ctx, from, to, logs); err != nil {
+			return nil, err
+		}
+	}
+
 	return msgs, nil
 }
 
+// queueTransactionQuery returns the filter query that matches all QueueTransaction events.
+func (c *BridgeClient) queueTransactionQuery() ethereum.FilterQuery {
+	return ethereum.FilterQuery{
+		Addresses: []common.Address{c.l1MessageQueueAddress},
+		Topics:    [][]common.Hash{{queueTransactionEventSignature}},
+	}
+}
+
 func (c *BridgeClient) getLatestConfirmedBlockNumber(ctx context.Context) (uint64, error) {
 	// confirmation based on "safe" or "finalized" block tag
 	if c.confirmations == rpc.SafeBlockNumber || c.confirmations == rpc.FinalizedBlockNumber {
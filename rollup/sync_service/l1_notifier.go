@@ -0,0 +1,140 @@
+package sync_service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/scroll-tech/go-ethereum"
+	"github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/log"
+	"github.com/scroll-tech/go-ethereum/rpc"
+)
+
+// DefaultResubscribeInterval is the delay between attempts to re-establish broken L1 subscriptions.
+const DefaultResubscribeInterval = 30 * time.Second
+
+// L1Notifier notifies its consumer whenever new L1 data might be available.
+//
+// If the L1 endpoint supports subscriptions (e.g. a websocket endpoint), notifications are
+// pushed on every new L1 head and on every log matching the query. Otherwise, or while the
+// subscriptions are down, L1Notifier falls back to polling at a fixed interval. A notification
+// is always sent right after (re)subscribing, so that the consumer can fill the gap with
+// eth_getLogs. Notifications are coalesced, the consumer never falls behind.
+type L1Notifier struct {
+	ctx                 context.Context
+	client              EthClient
+	query               ethereum.FilterQuery
+	pollInterval        time.Duration
+	resubscribeInterval time.Duration
+	ch                  chan struct{}
+}
+
+// NewL1Notifier creates a new L1Notifier, call Start to start sending notifications.
+func NewL1Notifier(ctx context.Context, client EthClient, query ethereum.FilterQuery, pollInterval time.Duration) *L1Notifier {
+	return &L1Notifier{
+		ctx:                 ctx,
+		client:              client,
+		query:               query,
+		pollInterval:        pollInterval,
+		resubscribeInterval: DefaultResubscribeInterval,
+		ch:                  make(chan struct{}, 1),
+	}
+}
+
+// Start starts the notifier in the background, it stops when its context is canceled.
+func (n *L1Notifier) Start() {
+	go n.loop()
+}
+
+// C returns the channel on which notifications are delivered.
+func (n *L1Notifier) C() <-chan struct{} {
+	return n.ch
+}
+
+func (n *L1Notifier) notify() {
+	select {
+	case n.ch <- struct{}{}:
+	default:
+		// a notification is already pending
+	}
+}
+
+func (n *L1Notifier) loop() {
+	pollTicker := time.NewTicker(n.pollInterval)
+	defer pollTicker.Stop()
+
+	for {
+		err := n.runSubscriptions()
+		if n.ctx.Err() != nil {
+			return
+		}
+
+		// a nil channel blocks forever, i.e. we keep polling if subscriptions are not supported
+		var resubscribe <-chan time.Time
+		if errors.Is(err, rpc.ErrNotificationsUnsupported) {
+			log.Info("L1 endpoint does not support subscriptions, polling for new L1 events", "interval", n.pollInterval)
+		} else {
+			log.Warn("L1 subscription failed, polling for new L1 events", "interval", n.pollInterval, "err", err, "retry in", n.resubscribeInterval)
+			resubscribe = time.After(n.resubscribeInterval)
+		}
+
+		if !n.poll(pollTicker, resubscribe) {
+			return
+		}
+	}
+}
+
+// poll sends a notification on every tick until it is time to resubscribe.
+// It returns false if the context was canceled.
+func (n *L1Notifier) poll(pollTicker *time.Ticker, resubscribe <-chan time.Time) bool {
+	for {
+		select {
+		case <-n.ctx.Done():
+			return false
+		case <-pollTicker.C:
+			n.notify()
+		case <-resubscribe:
+			return true
+		}
+	}
+}
+
+// runSubscriptions subscribes to new L1 heads and matching logs, and forwards them as
+// notifications. It blocks until either of the subscriptions fails.
+func (n *L1Notifier) runSubscriptions() error {
+	headCh := make(chan *types.Header, 16)
+	headSub, err := n.client.SubscribeNewHead(n.ctx, headCh)
+	if err != nil {
+		return err
+	}
+	defer headSub.Unsubscribe()
+
+	logCh := make(chan types.Log, 16)
+	logSub, err := n.client.SubscribeFilterLogs(n.ctx, n.query, logCh)
+	if err != nil {
+		return err
+	}
+	defer logSub.Unsubscribe()
+
+	log.Info("Subscribed to new L1 heads and logs", "addresses", n.query.Addresses)
+
+	// fill the gap since the last poll or the last subscription
+	n.notify()
+
+	for {
+		select {
+		case <-n.ctx.Done():
+			return n.ctx.Err()
+		case <-headCh:
+			n.notify()
+		case <-logCh:
+			n.notify()
+		case err := <-headSub.Err():
+			return fmt.Errorf("new head subscription failed: %w", err)
+		case err := <-logSub.Err():
+			return fmt.Errorf("log subscription failed: %w", err)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package sync_service
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scroll-tech/go-ethereum"
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/event"
+	"github.com/scroll-tech/go-ethereum/rpc"
+)
+
+// mockSubscribingEthClient supports subscriptions, these fail when a value is sent on fail.
+type mockSubscribingEthClient struct {
+	EthClient
+	unsupported bool
+	heads       chan *types.Header
+	fail        chan error
+	subscribed  chan struct{}
+}
+
+func (m *mockSubscribingEthClient) SubscribeNewHead(ctx context.Context, ch chan<- *types.Header) (ethereum.Subscription, error) {
+	if m.unsupported {
+		return nil, rpc.ErrNotificationsUnsupported
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		m.subscribed <- struct{}{}
+		for {
+			select {
+			case <-quit:
+				return nil
+			case err := <-m.fail:
+				return err
+			case head := <-m.heads:
+				ch <- head
+			}
+		}
+	}), nil
+}
+
+func (m *mockSubscribingEthClient) SubscribeFilterLogs(ctx context.Context, query ethereum.FilterQuery, ch chan<- types.Log) (ethereum.Subscription, error) {
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		<-quit
+		return nil
+	}), nil
+}
+
+func expectNotification(t *testing.T, n *L1Notifier) {
+	select {
+	case <-n.C():
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for notification")
+	}
+}
+
+func TestL1NotifierSubscriptions(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	client := &mockSubscribingEthClient{
+		heads:      make(chan *types.Header),
+		fail:       make(chan error),
+		subscribed: make(chan struct{}, 1),
+	}
+	n := NewL1Notifier(ctx, client, ethereum.FilterQuery{}, time.Hour)
+	n.resubscribeInterval = 10 * time.Millisecond
+	n.Start()
+
+	// notified right after subscribing to fill the gap
+	<-client.subscribed
+	expectNotification(t, n)
+
+	// notified on new heads
+	client.heads <- &types.Header{Number: big.NewInt(1)}
+	expectNotification(t, n)
+
+	// notified again after resubscribing
+	client.fail <- errors.New("connection lost")
+	<-client.subscribed
+	expectNotification(t, n)
+}
+
+func TestL1NotifierPolling(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	client := &mockSubscribingEthClient{unsupported: true}
+	n := NewL1Notifier(ctx, client, ethereum.FilterQuery{}, 10*time.Millisecond)
+	n.Start()
+
+	expectNotification(t, n)
+	expectNotification(t, n)
+}
+
+func TestQueueTransactionEventSignature(t *testing.T) {
+	abi, err := L1MessageQueueMetaData.GetAbi()
+	require.NoError(t, err)
+	assert.Equal(t, abi.Events["QueueTransaction"].ID, queueTransactionEventSignature)
+	assert.Equal(t, common.HexToHash("0x69cfcb8e6d4192b8aba9902243912587f37e550d75c1fa801491fce26717f37e"), queueTransactionEventSignature)
+}
@@ -0,0 +1,265 @@
+package sync_service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/core/rawdb"
+	"github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/ethdb"
+	"github.com/scroll-tech/go-ethereum/log"
+	"github.com/scroll-tech/go-ethereum/rlp"
+	"github.com/scroll-tech/go-ethereum/trie"
+)
+
+// defaultMaxCatchUpHeaders is the maximum number of L1 headers before a range that are verified per call,
+// when the latest verified L1 block is far behind the range, e.g. after enabling verification.
+const defaultMaxCatchUpHeaders = 1000
+
+var (
+	// ErrL1MessageVerificationFailed is returned if the L1 endpoint returned QueueTransaction logs
+	// that are not committed to by the verified L1 header chain.
+	ErrL1MessageVerificationFailed = errors.New("L1 message verification failed")
+
+	// errL1HeadersCatchingUp is returned while the verified L1 header chain is catching up with a range.
+	errL1HeadersCatchingUp = errors.New("verified L1 header chain is catching up")
+)
+
+// messageVerifier checks QueueTransaction logs returned by the L1 endpoint against the L1 header
+// chain, anchored by a trusted L1 checkpoint.
+//
+// Each header of a range must extend the latest verified header through its parent hash. For
+// each block that contains QueueTransaction logs, or whose logs bloom says that it might, we
+// fetch all receipts, check them against the receipts root of the header, and require that the
+// returned logs are exactly the QueueTransaction logs in the receipts. This way the L1 endpoint
+// can neither inject nor withhold L1 messages.
+type messageVerifier struct {
+	client                EthClient
+	db                    ethdb.Database
+	l1MessageQueueAddress common.Address
+
+	// checkpoint is the trusted L1 block configured by the user.
+	checkpoint rawdb.VerifiedL1Block
+
+	// anchor is the latest verified L1 block. It is persisted when the sync progress is flushed.
+	anchor rawdb.VerifiedL1Block
+
+	// maxCatchUpHeaders bounds the L1 headers verified per call before the start of a range.
+	maxCatchUpHeaders uint64
+}
+
+func newMessageVerifier(client EthClient, db ethdb.Database, l1MessageQueueAddress common.Address, checkpoint rawdb.VerifiedL1Block) *messageVerifier {
+	v := &messageVerifier{
+		client:                client,
+		db:                    db,
+		l1MessageQueueAddress: l1MessageQueueAddress,
+		checkpoint:            checkpoint,
+		maxCatchUpHeaders:     defaultMaxCatchUpHeaders,
+	}
+	v.reset()
+	return v
+}
+
+// reset restores the latest verified L1 block from the database, discarding unflushed progress.
+func (v *messageVerifier) reset() {
+	v.anchor = v.checkpoint
+	if stored := rawdb.ReadLastVerifiedL1Block(v.db); stored != nil && stored.Number >= v.checkpoint.Number {
+		v.anchor = *stored
+	}
+}
+
+// write persists the latest verified L1 block if it matches the flushed sync progress.
+func (v *messageVerifier) write(db ethdb.KeyValueWriter, l1BlockNumber uint64) {
+	if v.anchor.Number == l1BlockNumber {
+		rawdb.WriteLastVerifiedL1Block(db, &v.anchor)
+	}
+}
+
+// verifiedHash returns the verified hash of the given L1 block, if it is the latest verified L1 block.
+func (v *messageVerifier) verifiedHash(l1BlockNumber uint64) (common.Hash, bool) {
+	if v.anchor.Number != l1BlockNumber {
+		return common.Hash{}, false
+	}
+	return v.anchor.Hash, true
+}
+
+// rollback moves the latest verified L1 block back to the fork point of an L1 reorg.
+func (v *messageVerifier) rollback(db ethdb.KeyValueWriter, forkPointNumber uint64, forkPointHash common.Hash) {
+	if forkPointNumber < v.checkpoint.Number {
+		log.Error("L1 reorg goes beyond the trusted L1 checkpoint", "fork point", forkPointNumber, "checkpoint", v.checkpoint.Number)
+		v.anchor = v.checkpoint
+	} else if v.anchor.Number > forkPointNumber {
+		v.anchor = rawdb.VerifiedL1Block{Number: forkPointNumber, Hash: forkPointHash}
+	}
+	rawdb.WriteLastVerifiedL1Block(db, &v.anchor)
+}
+
+// verifyRange verifies the QueueTransaction logs returned for the L1 blocks [from, to].
+// On success, the latest verified L1 block is advanced to `to`.
+//
+// The header chain must reach the range first. This covers the blocks processed before verification
+// was enabled, and is done at most maxCatchUpHeaders headers per call. The catch-up progress is persisted
+// right away, since it only covers blocks whose messages are already synced. errL1HeadersCatchingUp is
+// returned until the header chain reaches the range.
+func (v *messageVerifier) verifyRange(ctx context.Context, from, to uint64, logs []types.Log) error {
+	if v.anchor.Number >= from {
+		return fmt.Errorf("latest verified L1 block %v is not before the range start %v", v.anchor.Number, from)
+	}
+
+	if from > v.anchor.Number+1 {
+		catchUpTo := from - 1
+		if catchUpTo-v.anchor.Number > v.maxCatchUpHeaders {
+			catchUpTo = v.anchor.Number + v.maxCatchUpHeaders
+		}
+		log.Info("Verifying L1 headers since the latest verified L1 block", "verified", v.anchor.Number, "to", catchUpTo, "from", from)
+		headers, err := v.extendHeaderChain(ctx, catchUpTo)
+		if err != nil {
+			return err
+		}
+		v.anchor = rawdb.VerifiedL1Block{Number: catchUpTo, Hash: headers[len(headers)-1].Hash()}
+		rawdb.WriteLastVerifiedL1Block(v.db, &v.anchor)
+		if catchUpTo < from-1 {
+			return fmt.Errorf("%w: verified L1 headers up to %v, range starts at %v", errL1HeadersCatchingUp, catchUpTo, from)
+		}
+	}
+
+	headers, err := v.extendHeaderChain(ctx, to)
+	if err != nil {
+		return err
+	}
+
+	logsByBlock := make(map[uint64][]types.Log)
+	for _, l := range logs {
+		if l.BlockNumber < from || l.BlockNumber > to {
+			return fmt.Errorf("%w: log of L1 block %v is outside of the range [%v, %v]", ErrL1MessageVerificationFailed, l.BlockNumber, from, to)
+		}
+		logsByBlock[l.BlockNumber] = append(logsByBlock[l.BlockNumber], l)
+	}
+
+	for _, header := range headers {
+		number := header.Number.Uint64()
+		blockLogs := logsByBlock[number]
+		if len(blockLogs) == 0 && !v.mayContainMessages(header.Bloom) {
+			continue
+		}
+		if err := v.verifyBlockLogs(ctx, header, blockLogs); err != nil {
+			return err
+		}
+	}
+
+	v.anchor = rawdb.VerifiedL1Block{Number: to, Hash: headers[len(headers)-1].Hash()}
+	return nil
+}
+
+// extendHeaderChain returns the L1 headers after the latest verified L1 block up to `to`, after checking
+// that each of them extends the previous one.
+func (v *messageVerifier) extendHeaderChain(ctx context.Context, to uint64) ([]*types.Header, error) {
+	headers := make([]*types.Header, 0, to-v.anchor.Number)
+	parentHash := v.anchor.Hash
+	for number := v.anchor.Number + 1; number <= to; number++ {
+		header, err := v.client.HeaderByNumber(ctx, new(big.Int).SetUint64(number))
+		if err != nil {
+			return nil, fmt.Errorf("failed to get L1 header, block number: %v, err: %w", number, err)
+		}
+		if header.Number == nil || header.Number.Uint64() != number {
+			return nil, fmt.Errorf("unexpected L1 header, expected block number: %v, got: %v", number, header.Number)
+		}
+		if header.ParentHash != parentHash {
+			return nil, fmt.Errorf("%w: L1 block %v does not extend the verified header chain, parent hash: %v, expected: %v", ErrL1MessageVerificationFailed, number, header.ParentHash.Hex(), parentHash.Hex())
+		}
+		parentHash = header.Hash()
+		headers = append(headers, header)
+	}
+	return headers, nil
+}
+
+// mayContainMessages checks the logs bloom of an L1 block for QueueTransaction logs.
+func (v *messageVerifier) mayContainMessages(bloom types.Bloom) bool {
+	return types.BloomLookup(bloom, v.l1MessageQueueAddress) && types.BloomLookup(bloom, queueTransactionEventSignature)
+}
+
+// verifyBlockLogs checks that the given logs are exactly the QueueTransaction logs of an L1 block.
+func (v *messageVerifier) verifyBlockLogs(ctx context.Context, header *types.Header, logs []types.Log) error {
+	number := header.Number.Uint64()
+	hash := header.Hash()
+
+	receipts, err := v.client.BlockReceiptsByHash(ctx, hash)
+	if err != nil {
+		return fmt.Errorf("failed to get L1 block receipts, block number: %v, err: %w", number, err)
+	}
+	if root := types.DeriveSha(consensusReceipts(receipts), trie.NewStackTrie(nil)); root != header.ReceiptHash {
+		return fmt.Errorf("%w: receipts of L1 block %v do not match the receipts root, got: %v, expected: %v", ErrL1MessageVerificationFailed, number, root.Hex(), header.ReceiptHash.Hex())
+	}
+
+	var expected []*types.Log
+	for _, receipt := range receipts {
+		for _, l := range receipt.Logs {
+			if l.Address == v.l1MessageQueueAddress && len(l.Topics) > 0 && l.Topics[0] == queueTransactionEventSignature {
+				expected = append(expected, l)
+			}
+		}
+	}
+	if len(logs) != len(expected) {
+		return fmt.Errorf("%w: got %v QueueTransaction logs for L1 block %v, receipts contain %v", ErrL1MessageVerificationFailed, len(logs), number, len(expected))
+	}
+	for i := range logs {
+		if logs[i].BlockHash != hash || !consensusLogEqual(&logs[i], expected[i]) {
+			return fmt.Errorf("%w: QueueTransaction log %v of L1 block %v does not match the receipts", ErrL1MessageVerificationFailed, i, number)
+		}
+	}
+	return nil
+}
+
+// consensusLogEqual compares the log fields that are committed to by the receipts root.
+func consensusLogEqual(a, b *types.Log) bool {
+	if a.Address != b.Address || len(a.Topics) != len(b.Topics) || !bytes.Equal(a.Data, b.Data) {
+		return false
+	}
+	for i := range a.Topics {
+		if a.Topics[i] != b.Topics[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// consensusReceipts implements types.DerivableList for L1 receipts. Unlike types.Receipts,
+// it encodes typed receipts of all EIP-2718 transaction types, including the ones that
+// do not exist on L2.
+type consensusReceipts []*types.Receipt
+
+type consensusReceiptRLP struct {
+	PostStateOrStatus []byte
+	CumulativeGasUsed uint64
+	Bloom             types.Bloom
+	Logs              []*types.Log
+}
+
+// Len returns the number of receipts in this list.
+func (rs consensusReceipts) Len() int { return len(rs) }
+
+// EncodeIndex encodes the i'th receipt to w.
+func (rs consensusReceipts) EncodeIndex(i int, w *bytes.Buffer) {
+	r := rs[i]
+	data := &consensusReceiptRLP{
+		PostStateOrStatus: r.PostState,
+		CumulativeGasUsed: r.CumulativeGasUsed,
+		Bloom:             r.Bloom,
+		Logs:              r.Logs,
+	}
+	if len(r.PostState) == 0 {
+		if r.Status == types.ReceiptStatusFailed {
+			data.PostStateOrStatus = []byte{}
+		} else {
+			data.PostStateOrStatus = []byte{0x01}
+		}
+	}
+	if r.Type != types.LegacyTxType {
+		w.WriteByte(r.Type)
+	}
+	rlp.Encode(w, data)
+}
@@ -0,0 +1,139 @@
+package sync_service
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scroll-tech/go-ethereum"
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/core/rawdb"
+	"github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/trie"
+)
+
+// mockL1Chain serves headers and receipts of a small L1 chain.
+type mockL1Chain struct {
+	EthClient
+	headers  map[uint64]*types.Header
+	receipts map[common.Hash][]*types.Receipt
+}
+
+func (m *mockL1Chain) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	header, ok := m.headers[number.Uint64()]
+	if !ok {
+		return nil, ethereum.NotFound
+	}
+	return header, nil
+}
+
+func (m *mockL1Chain) BlockReceiptsByHash(ctx context.Context, blockHash common.Hash) ([]*types.Receipt, error) {
+	return m.receipts[blockHash], nil
+}
+
+func newMockL1Chain(t *testing.T, l1MessageQueueAddress common.Address) (*mockL1Chain, types.Log) {
+	chain := &mockL1Chain{headers: make(map[uint64]*types.Header), receipts: make(map[common.Hash][]*types.Receipt)}
+
+	msgLog := &types.Log{
+		Address: l1MessageQueueAddress,
+		Topics:  []common.Hash{queueTransactionEventSignature, common.HexToHash("0x01"), common.HexToHash("0x02")},
+		Data:    []byte{1, 2, 3},
+	}
+	receipts := []*types.Receipt{
+		{Type: types.DynamicFeeTxType, Status: types.ReceiptStatusSuccessful, CumulativeGasUsed: 21000, Logs: []*types.Log{msgLog}},
+		// EIP-7702 transactions do not exist on L2, but their receipts must still be encoded correctly
+		{Type: 0x04, Status: types.ReceiptStatusFailed, CumulativeGasUsed: 50000},
+	}
+	for _, r := range receipts {
+		r.Bloom = types.CreateBloom(types.Receipts{r})
+	}
+
+	// types.Receipts and consensusReceipts agree on the transaction types known on L2
+	assert.Equal(t, types.DeriveSha(types.Receipts(receipts[:1]), trie.NewStackTrie(nil)), types.DeriveSha(consensusReceipts(receipts[:1]), trie.NewStackTrie(nil)))
+
+	parentHash := common.Hash{}
+	for number := uint64(10); number <= 12; number++ {
+		header := &types.Header{ParentHash: parentHash, Number: new(big.Int).SetUint64(number), Difficulty: common.Big0, ReceiptHash: types.EmptyRootHash}
+		if number == 12 {
+			header.ReceiptHash = types.DeriveSha(consensusReceipts(receipts), trie.NewStackTrie(nil))
+			header.Bloom = types.CreateBloom(receipts)
+			chain.receipts[header.Hash()] = receipts
+		}
+		chain.headers[number] = header
+		parentHash = header.Hash()
+	}
+
+	returned := *msgLog
+	returned.BlockNumber = 12
+	returned.BlockHash = chain.headers[12].Hash()
+	return chain, returned
+}
+
+func TestMessageVerifier(t *testing.T) {
+	l1MessageQueueAddress := common.HexToAddress("0x1234")
+	chain, msgLog := newMockL1Chain(t, l1MessageQueueAddress)
+	checkpoint := rawdb.VerifiedL1Block{Number: 10, Hash: chain.headers[10].Hash()}
+
+	db := rawdb.NewMemoryDatabase()
+	v := newMessageVerifier(chain, db, l1MessageQueueAddress, checkpoint)
+
+	// the L1 endpoint withholds an L1 message
+	err := v.verifyRange(context.Background(), 11, 12, nil)
+	assert.ErrorIs(t, err, ErrL1MessageVerificationFailed)
+
+	// the L1 endpoint injects a modified L1 message
+	injected := msgLog
+	injected.Data = []byte{1, 2, 4}
+	err = v.verifyRange(context.Background(), 11, 12, []types.Log{injected})
+	assert.ErrorIs(t, err, ErrL1MessageVerificationFailed)
+
+	// the L1 endpoint returns the actual L1 message
+	require.NoError(t, v.verifyRange(context.Background(), 11, 12, []types.Log{msgLog}))
+	hash, ok := v.verifiedHash(12)
+	assert.True(t, ok)
+	assert.Equal(t, chain.headers[12].Hash(), hash)
+
+	// progress is only kept once written
+	v.reset()
+	assert.Equal(t, checkpoint, v.anchor)
+	require.NoError(t, v.verifyRange(context.Background(), 11, 12, []types.Log{msgLog}))
+	v.write(db, 12)
+	v.reset()
+	assert.Equal(t, uint64(12), v.anchor.Number)
+
+	// the header chain does not descend from the checkpoint
+	v = newMessageVerifier(chain, rawdb.NewMemoryDatabase(), l1MessageQueueAddress, rawdb.VerifiedL1Block{Number: 10, Hash: common.Hash{1}})
+	err = v.verifyRange(context.Background(), 11, 12, []types.Log{msgLog})
+	assert.ErrorIs(t, err, ErrL1MessageVerificationFailed)
+}
+
+func TestMessageVerifierCatchUp(t *testing.T) {
+	l1MessageQueueAddress := common.HexToAddress("0x1234")
+	chain, _ := newMockL1Chain(t, l1MessageQueueAddress)
+	for number := uint64(13); number <= 16; number++ {
+		chain.headers[number] = &types.Header{ParentHash: chain.headers[number-1].Hash(), Number: new(big.Int).SetUint64(number), Difficulty: common.Big0, ReceiptHash: types.EmptyRootHash}
+	}
+	checkpoint := rawdb.VerifiedL1Block{Number: 10, Hash: chain.headers[10].Hash()}
+
+	db := rawdb.NewMemoryDatabase()
+	v := newMessageVerifier(chain, db, l1MessageQueueAddress, checkpoint)
+	v.maxCatchUpHeaders = 2
+
+	// the headers before the range are verified a few at a time, and the progress is kept across polls
+	err := v.verifyRange(context.Background(), 16, 16, nil)
+	assert.ErrorIs(t, err, errL1HeadersCatchingUp)
+	v.reset()
+	assert.Equal(t, rawdb.VerifiedL1Block{Number: 12, Hash: chain.headers[12].Hash()}, v.anchor)
+	err = v.verifyRange(context.Background(), 16, 16, nil)
+	assert.ErrorIs(t, err, errL1HeadersCatchingUp)
+	assert.Equal(t, uint64(14), v.anchor.Number)
+
+	// the last headers before the range are verified along with the range
+	require.NoError(t, v.verifyRange(context.Background(), 16, 16, nil))
+	hash, ok := v.verifiedHash(16)
+	assert.True(t, ok)
+	assert.Equal(t, chain.headers[16].Hash(), hash)
+}
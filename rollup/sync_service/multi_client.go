@@ -0,0 +1,434 @@
+package sync_service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/scroll-tech/go-ethereum"
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/log"
+	"github.com/scroll-tech/go-ethereum/metrics"
+	"github.com/scroll-tech/go-ethereum/rpc"
+)
+
+const (
+	// maxHealthScore is the health score of an endpoint that has not failed recently.
+	maxHealthScore = 100
+
+	// healthScoreReward is added to the health score of an endpoint after each successful request.
+	healthScoreReward = 5
+
+	// healthScorePenalty is subtracted from the health score of an endpoint after each failed request.
+	healthScorePenalty = 25
+)
+
+var (
+	multiClientFailoverCounter     = metrics.NewRegisteredCounter("rollup/l1/client/failover", nil)
+	multiClientHedgedCounter       = metrics.NewRegisteredCounter("rollup/l1/client/hedged", nil)
+	multiClientLogsMismatchCounter = metrics.NewRegisteredCounter("rollup/l1/client/logs/mismatch", nil)
+
+	// ErrL1LogsMismatch is returned if two L1 endpoints returned different results for the same eth_getLogs query.
+	ErrL1LogsMismatch = errors.New("L1 endpoints returned different logs")
+)
+
+// MultiClientConfig contains the configuration of MultiClient.
+type MultiClientConfig struct {
+	// HedgeDelay is the time to wait for a response before sending the same request
+	// to the next endpoint. Zero disables hedged requests.
+	HedgeDelay time.Duration
+
+	// CrossCheckLogs makes FilterLogs query two different endpoints and only accept
+	// the result if both endpoints agree.
+	CrossCheckLogs bool
+}
+
+// MultiClientEndpoint is an L1 endpoint used by MultiClient.
+type MultiClientEndpoint struct {
+	URL    string
+	Client EthClient
+}
+
+type multiClientEndpoint struct {
+	MultiClientEndpoint
+	index int
+	score int // health score in [0, maxHealthScore]
+}
+
+// MultiClient is an EthClient that spreads requests over several L1 endpoints.
+//
+// Each endpoint has a health score that increases on successful requests and decreases on
+// failed ones. Requests are sent to the healthiest endpoint first and fail over to the next
+// endpoint on errors. If hedging is enabled, a request that did not complete within the hedge
+// delay is also sent to the next endpoint, and the first successful response wins.
+type MultiClient struct {
+	config    MultiClientConfig
+	mu        sync.Mutex
+	endpoints []*multiClientEndpoint
+}
+
+// NewMultiClient creates a new MultiClient from the given endpoints, listed in order of preference.
+func NewMultiClient(endpoints []MultiClientEndpoint, config MultiClientConfig) (*MultiClient, error) {
+	if len(endpoints) == 0 {
+		return nil, errors.New("must pass at least one L1 endpoint to MultiClient")
+	}
+	if config.CrossCheckLogs && len(endpoints) < 2 {
+		return nil, fmt.Errorf("cross-checking logs requires at least 2 L1 endpoints, got %v", len(endpoints))
+	}
+
+	c := &MultiClient{config: config}
+	for i, e := range endpoints {
+		c.endpoints = append(c.endpoints, &multiClientEndpoint{
+			MultiClientEndpoint: e,
+			index:               i,
+			score:               maxHealthScore,
+		})
+	}
+	return c, nil
+}
+
+// ordered returns the endpoints ordered by decreasing health score, ties are broken by preference.
+func (c *MultiClient) ordered() []*multiClientEndpoint {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	endpoints := make([]*multiClientEndpoint, len(c.endpoints))
+	copy(endpoints, c.endpoints)
+	sort.SliceStable(endpoints, func(i, j int) bool {
+		if endpoints[i].score != endpoints[j].score {
+			return endpoints[i].score > endpoints[j].score
+		}
+		return endpoints[i].index < endpoints[j].index
+	})
+	return endpoints
+}
+
+// record updates the health score of an endpoint after a request.
+func (c *MultiClient) record(e *multiClientEndpoint, method string, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err == nil || errors.Is(err, ethereum.NotFound) || errors.Is(err, rpc.ErrNotificationsUnsupported) {
+		e.score += healthScoreReward
+		if e.score > maxHealthScore {
+			e.score = maxHealthScore
+		}
+		return
+	}
+
+	e.score -= healthScorePenalty
+	if e.score < 0 {
+		e.score = 0
+	}
+	log.Debug("L1 endpoint request failed", "endpoint", e.URL, "method", method, "score", e.score, "err", err)
+}
+
+// HealthScores returns the current health score of each endpoint, keyed by URL.
+func (c *MultiClient) HealthScores() map[string]int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	scores := make(map[string]int, len(c.endpoints))
+	for _, e := range c.endpoints {
+		scores[e.URL] = e.score
+	}
+	return scores
+}
+
+type multiClientResult struct {
+	endpoint *multiClientEndpoint
+	value    interface{}
+	err      error
+}
+
+// call sends the request to the healthiest endpoint and fails over to the next endpoints on errors.
+// If hedge is true and hedging is enabled, slow requests are also sent to the next endpoint.
+func (c *MultiClient) call(ctx context.Context, method string, hedge bool, fn func(ctx context.Context, client EthClient) (interface{}, error)) (interface{}, error) {
+	endpoints := c.ordered()
+
+	callCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// buffered so that requests that lose the race do not block
+	results := make(chan multiClientResult, len(endpoints))
+	next, pending := 0, 0
+	start := func() {
+		e := endpoints[next]
+		next++
+		pending++
+		go func() {
+			value, err := fn(callCtx, e.Client)
+			results <- multiClientResult{endpoint: e, value: value, err: err}
+		}()
+	}
+
+	var hedgeTimer *time.Timer
+	var hedgeCh <-chan time.Time
+	resetHedgeTimer := func() {
+		if !hedge || c.config.HedgeDelay == 0 || next >= len(endpoints) {
+			hedgeCh = nil
+			return
+		}
+		if hedgeTimer == nil {
+			hedgeTimer = time.NewTimer(c.config.HedgeDelay)
+		} else {
+			if !hedgeTimer.Stop() {
+				// drain the channel in case the timer fired but was not received
+				select {
+				case <-hedgeTimer.C:
+				default:
+				}
+			}
+			hedgeTimer.Reset(c.config.HedgeDelay)
+		}
+		hedgeCh = hedgeTimer.C
+	}
+	defer func() {
+		if hedgeTimer != nil {
+			hedgeTimer.Stop()
+		}
+	}()
+
+	start()
+	resetHedgeTimer()
+
+	var errs []error
+	for pending > 0 {
+		select {
+		case <-hedgeCh:
+			multiClientHedgedCounter.Inc(1)
+			start()
+			resetHedgeTimer()
+
+		case res := <-results:
+			pending--
+
+			// requests canceled by the caller do not say anything about the endpoint health
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			c.record(res.endpoint, method, res.err)
+
+			if res.err == nil {
+				return res.value, nil
+			}
+			errs = append(errs, fmt.Errorf("%v: %w", res.endpoint.URL, res.err))
+
+			if pending == 0 && next < len(endpoints) {
+				multiClientFailoverCounter.Inc(1)
+				start()
+				resetHedgeTimer()
+			}
+		}
+	}
+
+	// if all endpoints agree that the request is not supported or the data is not there, pass that on
+	if allErrorsAre(errs, ethereum.NotFound) {
+		return nil, ethereum.NotFound
+	}
+	if allErrorsAre(errs, rpc.ErrNotificationsUnsupported) {
+		return nil, rpc.ErrNotificationsUnsupported
+	}
+	return nil, fmt.Errorf("%v failed on all L1 endpoints: %w", method, errors.Join(errs...))
+}
+
+func allErrorsAre(errs []error, target error) bool {
+	for _, err := range errs {
+		if !errors.Is(err, target) {
+			return false
+		}
+	}
+	return len(errs) > 0
+}
+
+// BlockNumber implements EthClient.
+func (c *MultiClient) BlockNumber(ctx context.Context) (uint64, error) {
+	res, err := c.call(ctx, "BlockNumber", true, func(ctx context.Context, client EthClient) (interface{}, error) {
+		return client.BlockNumber(ctx)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return res.(uint64), nil
+}
+
+// ChainID implements EthClient. It queries all reachable endpoints and fails if they disagree.
+func (c *MultiClient) ChainID(ctx context.Context) (*big.Int, error) {
+	var chainID *big.Int
+	var errs []error
+	for _, e := range c.ordered() {
+		got, err := e.Client.ChainID(ctx)
+		c.record(e, "ChainID", err)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%v: %w", e.URL, err))
+			continue
+		}
+		if chainID != nil && chainID.Cmp(got) != 0 {
+			return nil, fmt.Errorf("L1 endpoints are on different chains, chain IDs: %v and %v (%v)", chainID, got, e.URL)
+		}
+		chainID = got
+	}
+	if chainID == nil {
+		return nil, fmt.Errorf("ChainID failed on all L1 endpoints: %w", errors.Join(errs...))
+	}
+	return chainID, nil
+}
+
+// FilterLogs implements EthClient. If CrossCheckLogs is enabled, the result is only
+// returned if two endpoints agree on it, otherwise ErrL1LogsMismatch is returned.
+func (c *MultiClient) FilterLogs(ctx context.Context, q ethereum.FilterQuery) ([]types.Log, error) {
+	if c.config.CrossCheckLogs {
+		return c.crossCheckedFilterLogs(ctx, q)
+	}
+	res, err := c.call(ctx, "FilterLogs", true, func(ctx context.Context, client EthClient) (interface{}, error) {
+		return client.FilterLogs(ctx, q)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res.([]types.Log), nil
+}
+
+func (c *MultiClient) crossCheckedFilterLogs(ctx context.Context, q ethereum.FilterQuery) ([]types.Log, error) {
+	var (
+		results [][]types.Log
+		urls    []string
+		errs    []error
+	)
+	for _, e := range c.ordered() {
+		logs, err := e.Client.FilterLogs(ctx, q)
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		c.record(e, "FilterLogs", err)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%v: %w", e.URL, err))
+			continue
+		}
+		results = append(results, logs)
+		urls = append(urls, e.URL)
+		if len(results) == 2 {
+			break
+		}
+	}
+	if len(results) < 2 {
+		return nil, fmt.Errorf("not enough L1 endpoints available to cross-check logs: %w", errors.Join(errs...))
+	}
+
+	if !logsEqual(results[0], results[1]) {
+		multiClientLogsMismatchCounter.Inc(1)
+		log.Warn("L1 endpoints returned different logs", "endpoints", urls, "fromBlock", q.FromBlock, "toBlock", q.ToBlock, "numLogs", []int{len(results[0]), len(results[1])})
+		return nil, fmt.Errorf("%w, endpoints: %v, fromBlock: %v, toBlock: %v", ErrL1LogsMismatch, urls, q.FromBlock, q.ToBlock)
+	}
+	return results[0], nil
+}
+
+func logsEqual(a, b []types.Log) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		x, y := a[i], b[i]
+		if x.Address != y.Address || x.BlockNumber != y.BlockNumber || x.BlockHash != y.BlockHash ||
+			x.TxHash != y.TxHash || x.TxIndex != y.TxIndex || x.Index != y.Index || x.Removed != y.Removed ||
+			len(x.Topics) != len(y.Topics) || !bytes.Equal(x.Data, y.Data) {
+			return false
+		}
+		for j := range x.Topics {
+			if x.Topics[j] != y.Topics[j] {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// HeaderByNumber implements EthClient.
+func (c *MultiClient) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	res, err := c.call(ctx, "HeaderByNumber", true, func(ctx context.Context, client EthClient) (interface{}, error) {
+		return client.HeaderByNumber(ctx, number)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res.(*types.Header), nil
+}
+
+// BlockHashByNumber implements EthClient.
+func (c *MultiClient) BlockHashByNumber(ctx context.Context, number *big.Int) (common.Hash, error) {
+	res, err := c.call(ctx, "BlockHashByNumber", true, func(ctx context.Context, client EthClient) (interface{}, error) {
+		return client.BlockHashByNumber(ctx, number)
+	})
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return res.(common.Hash), nil
+}
+
+// SubscribeFilterLogs implements EthClient. Subscriptions are never hedged.
+func (c *MultiClient) SubscribeFilterLogs(ctx context.Context, query ethereum.FilterQuery, ch chan<- types.Log) (ethereum.Subscription, error) {
+	res, err := c.call(ctx, "SubscribeFilterLogs", false, func(ctx context.Context, client EthClient) (interface{}, error) {
+		return client.SubscribeFilterLogs(ctx, query, ch)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res.(ethereum.Subscription), nil
+}
+
+// SubscribeNewHead implements EthClient. Subscriptions are never hedged.
+func (c *MultiClient) SubscribeNewHead(ctx context.Context, ch chan<- *types.Header) (ethereum.Subscription, error) {
+	res, err := c.call(ctx, "SubscribeNewHead", false, func(ctx context.Context, client EthClient) (interface{}, error) {
+		return client.SubscribeNewHead(ctx, ch)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res.(ethereum.Subscription), nil
+}
+
+type transactionByHashResult struct {
+	tx        *types.Transaction
+	isPending bool
+}
+
+// TransactionByHash implements EthClient.
+func (c *MultiClient) TransactionByHash(ctx context.Context, txHash common.Hash) (*types.Transaction, bool, error) {
+	res, err := c.call(ctx, "TransactionByHash", true, func(ctx context.Context, client EthClient) (interface{}, error) {
+		tx, isPending, err := client.TransactionByHash(ctx, txHash)
+		return transactionByHashResult{tx: tx, isPending: isPending}, err
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	r := res.(transactionByHashResult)
+	return r.tx, r.isPending, nil
+}
+
+// BlockByHash implements EthClient.
+func (c *MultiClient) BlockByHash(ctx context.Context, hash common.Hash) (*types.Block, error) {
+	res, err := c.call(ctx, "BlockByHash", true, func(ctx context.Context, client EthClient) (interface{}, error) {
+		return client.BlockByHash(ctx, hash)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res.(*types.Block), nil
+}
+
+// BlockReceiptsByHash implements EthClient.
+func (c *MultiClient) BlockReceiptsByHash(ctx context.Context, blockHash common.Hash) ([]*types.Receipt, error) {
+	res, err := c.call(ctx, "BlockReceiptsByHash", true, func(ctx context.Context, client EthClient) (interface{}, error) {
+		return client.BlockReceiptsByHash(ctx, blockHash)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res.([]*types.Receipt), nil
+}
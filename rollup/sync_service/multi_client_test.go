@@ -0,0 +1,124 @@
+package sync_service
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scroll-tech/go-ethereum"
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/core/types"
+)
+
+// mockEndpointClient answers BlockNumber and FilterLogs after the given delay, or fails if err is set.
+type mockEndpointClient struct {
+	EthClient
+	blockNumber uint64
+	chainID     int64
+	logs        []types.Log
+	delay       time.Duration
+	err         error
+	calls       int
+}
+
+func (m *mockEndpointClient) BlockNumber(ctx context.Context) (uint64, error) {
+	m.calls++
+	select {
+	case <-time.After(m.delay):
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+	return m.blockNumber, m.err
+}
+
+func (m *mockEndpointClient) ChainID(ctx context.Context) (*big.Int, error) {
+	return big.NewInt(m.chainID), m.err
+}
+
+func (m *mockEndpointClient) FilterLogs(ctx context.Context, q ethereum.FilterQuery) ([]types.Log, error) {
+	m.calls++
+	return m.logs, m.err
+}
+
+func newTestMultiClient(t *testing.T, config MultiClientConfig, clients ...*mockEndpointClient) *MultiClient {
+	var endpoints []MultiClientEndpoint
+	for i, client := range clients {
+		endpoints = append(endpoints, MultiClientEndpoint{URL: string(rune('a' + i)), Client: client})
+	}
+	c, err := NewMultiClient(endpoints, config)
+	require.NoError(t, err)
+	return c
+}
+
+func TestMultiClientFailover(t *testing.T) {
+	primary := &mockEndpointClient{err: errors.New("connection refused")}
+	secondary := &mockEndpointClient{blockNumber: 42}
+	c := newTestMultiClient(t, MultiClientConfig{}, primary, secondary)
+
+	number, err := c.BlockNumber(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, uint64(42), number)
+	assert.Equal(t, map[string]int{"a": maxHealthScore - healthScorePenalty, "b": maxHealthScore}, c.HealthScores())
+
+	// the unhealthy endpoint is no longer tried first
+	number, err = c.BlockNumber(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, uint64(42), number)
+	assert.Equal(t, 1, primary.calls)
+	assert.Equal(t, 2, secondary.calls)
+
+	// all endpoints fail
+	secondary.err = errors.New("internal error")
+	_, err = c.BlockNumber(context.Background())
+	assert.Error(t, err)
+}
+
+func TestMultiClientHedging(t *testing.T) {
+	slow := &mockEndpointClient{blockNumber: 1, delay: time.Minute}
+	fast := &mockEndpointClient{blockNumber: 2}
+	c := newTestMultiClient(t, MultiClientConfig{HedgeDelay: 10 * time.Millisecond}, slow, fast)
+
+	number, err := c.BlockNumber(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), number)
+}
+
+func TestMultiClientChainID(t *testing.T) {
+	c := newTestMultiClient(t, MultiClientConfig{}, &mockEndpointClient{chainID: 1}, &mockEndpointClient{chainID: 1})
+	chainID, err := c.ChainID(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), chainID.Int64())
+
+	c = newTestMultiClient(t, MultiClientConfig{}, &mockEndpointClient{chainID: 1}, &mockEndpointClient{chainID: 5})
+	_, err = c.ChainID(context.Background())
+	assert.Error(t, err)
+}
+
+func TestMultiClientCrossCheckLogs(t *testing.T) {
+	_, err := NewMultiClient([]MultiClientEndpoint{{URL: "a", Client: &mockEndpointClient{}}}, MultiClientConfig{CrossCheckLogs: true})
+	assert.Error(t, err)
+
+	logs := []types.Log{{Address: common.HexToAddress("0x01"), BlockNumber: 10, Data: []byte{1, 2, 3}}}
+	a := &mockEndpointClient{logs: logs}
+	b := &mockEndpointClient{logs: logs}
+	c := newTestMultiClient(t, MultiClientConfig{CrossCheckLogs: true}, a, b)
+
+	got, err := c.FilterLogs(context.Background(), ethereum.FilterQuery{})
+	require.NoError(t, err)
+	assert.Equal(t, logs, got)
+
+	// the second endpoint omits a log
+	b.logs = nil
+	_, err = c.FilterLogs(context.Background(), ethereum.FilterQuery{})
+	assert.ErrorIs(t, err, ErrL1LogsMismatch)
+
+	// the second endpoint returns different log data
+	b.logs = []types.Log{{Address: common.HexToAddress("0x01"), BlockNumber: 10, Data: []byte{1, 2, 4}}}
+	_, err = c.FilterLogs(context.Background(), ethereum.FilterQuery{})
+	assert.ErrorIs(t, err, ErrL1LogsMismatch)
+}
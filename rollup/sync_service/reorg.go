@@ -0,0 +1,49 @@
+package sync_service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/scroll-tech/go-ethereum/common"
+)
+
+// DefaultNumL1SyncCheckpoints is the number of L1 block checkpoints that we keep for reorg detection.
+// New checkpoints are written after each processed range, so this covers at least the last
+// DefaultNumL1SyncCheckpoints L1 blocks, which is well beyond the depth of any realistic L1 reorg.
+const DefaultNumL1SyncCheckpoints = 128
+
+// ErrL1ReorgTooDeep is returned if none of the tracked L1 checkpoints is part of the canonical L1 chain.
+var ErrL1ReorgTooDeep = errors.New("L1 reorg is deeper than the tracked checkpoints")
+
+// L1BlockHash returns the hash of the canonical L1 block with the given number, as reported by the L1 node.
+// The hash is not recomputed from the header, since L1 headers can have fields that this client does not know.
+func L1BlockHash(ctx context.Context, client EthClient, number uint64) (common.Hash, error) {
+	hash, err := client.BlockHashByNumber(ctx, new(big.Int).SetUint64(number))
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("failed to get L1 block hash, block number: %v, err: %w", number, err)
+	}
+	return hash, nil
+}
+
+// FindL1ForkPoint walks back the provided L1 checkpoints, given as block numbers and block hashes
+// in ascending order, and returns the index of the latest one that is still part of the canonical
+// L1 chain. It returns ErrL1ReorgTooDeep if none of them is.
+func FindL1ForkPoint(ctx context.Context, client EthClient, numbers []uint64, hashes []common.Hash) (int, error) {
+	if len(numbers) != len(hashes) {
+		return -1, fmt.Errorf("invalid checkpoints, %v block numbers but %v block hashes", len(numbers), len(hashes))
+	}
+
+	for i := len(numbers) - 1; i >= 0; i-- {
+		hash, err := L1BlockHash(ctx, client, numbers[i])
+		if err != nil {
+			return -1, err
+		}
+		if hash == hashes[i] {
+			return i, nil
+		}
+	}
+
+	return -1, ErrL1ReorgTooDeep
+}
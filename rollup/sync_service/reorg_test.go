@@ -0,0 +1,47 @@
+package sync_service
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scroll-tech/go-ethereum"
+	"github.com/scroll-tech/go-ethereum/common"
+)
+
+// mockBlockHashClient serves the block hashes reported by an L1 node.
+type mockBlockHashClient struct {
+	EthClient
+	hashes map[uint64]common.Hash
+}
+
+func (m *mockBlockHashClient) BlockHashByNumber(ctx context.Context, number *big.Int) (common.Hash, error) {
+	hash, ok := m.hashes[number.Uint64()]
+	if !ok {
+		return common.Hash{}, ethereum.NotFound
+	}
+	return hash, nil
+}
+
+func TestFindL1ForkPoint(t *testing.T) {
+	client := &mockBlockHashClient{hashes: map[uint64]common.Hash{10: {10}, 20: {20}, 30: {31}}}
+	numbers := []uint64{10, 20, 30}
+
+	// the hashes reported by the node are compared as they are
+	hash, err := L1BlockHash(context.Background(), client, 20)
+	require.NoError(t, err)
+	assert.Equal(t, common.Hash{20}, hash)
+
+	index, err := FindL1ForkPoint(context.Background(), client, numbers, []common.Hash{{10}, {20}, {30}})
+	require.NoError(t, err)
+	assert.Equal(t, 1, index)
+
+	_, err = FindL1ForkPoint(context.Background(), client, numbers, []common.Hash{{11}, {21}, {30}})
+	assert.ErrorIs(t, err, ErrL1ReorgTooDeep)
+
+	_, err = L1BlockHash(context.Background(), client, 40)
+	assert.ErrorIs(t, err, ethereum.NotFound)
+}
@@ -0,0 +1,146 @@
+package sync_service
+
+import (
+	"fmt"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/core/rawdb"
+	"github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/ethdb"
+	"github.com/scroll-tech/go-ethereum/log"
+	"github.com/scroll-tech/go-ethereum/rlp"
+)
+
+// L1MessageVerification is the result of checking the L1 messages stored in the database.
+type L1MessageVerification struct {
+	NumL1Messages           uint64 // number of stored L1 messages
+	HighestSyncedQueueIndex uint64
+
+	// FirstInvalidQueueIndex is the lowest queue index whose message is missing, corrupted or
+	// inconsistent with the L2 chain, nil if all messages up to the highest synced queue index are valid.
+	FirstInvalidQueueIndex *uint64
+
+	Gaps           [][2]uint64 // ranges of missing queue indices, inclusive
+	Corrupted      []uint64    // queue indices whose stored message cannot be decoded or has a different queue index
+	HashMismatches []uint64    // queue indices included in L2 blocks whose recomputed transaction hash is not found
+}
+
+func (v *L1MessageVerification) invalid(queueIndex uint64) {
+	if v.FirstInvalidQueueIndex == nil || queueIndex < *v.FirstInvalidQueueIndex {
+		v.FirstInvalidQueueIndex = &queueIndex
+	}
+}
+
+// VerifyL1Messages checks that the stored L1 messages have contiguous queue indices from 0 up to the
+// highest synced queue index, and that the recomputed hashes of the messages that the head block has
+// already included match the L1 message transactions of the L2 chain.
+func VerifyL1Messages(db ethdb.Database) (*L1MessageVerification, error) {
+	v := &L1MessageVerification{HighestSyncedQueueIndex: rawdb.ReadHighestSyncedQueueIndex(db)}
+
+	// messages before this queue index must be included in the L2 chain, they can only be
+	// checked if the transaction index covers the whole chain
+	var numIncluded uint64
+	tail := rawdb.ReadTxIndexTail(db)
+	if headHash := rawdb.ReadHeadBlockHash(db); headHash != (common.Hash{}) && (tail == nil || *tail == 0) {
+		if queueIndex := rawdb.ReadFirstQueueIndexNotInL2Block(db, headHash); queueIndex != nil {
+			numIncluded = *queueIndex
+		}
+	}
+
+	it := rawdb.IterateL1MessagesFrom(db, 0)
+	defer it.Release()
+
+	var next uint64
+	for it.Next() {
+		queueIndex := it.QueueIndex()
+		v.NumL1Messages++
+		if queueIndex != next {
+			v.Gaps = append(v.Gaps, [2]uint64{next, queueIndex - 1})
+			v.invalid(next)
+		}
+		next = queueIndex + 1
+
+		var msg types.L1MessageTx
+		if err := rlp.DecodeBytes(it.L1MessageRLP(), &msg); err != nil || msg.QueueIndex != queueIndex {
+			v.Corrupted = append(v.Corrupted, queueIndex)
+			v.invalid(queueIndex)
+			continue
+		}
+		if queueIndex < numIncluded {
+			if blockNumber := rawdb.ReadTxLookupEntry(db, types.NewTx(&msg).Hash()); blockNumber == nil {
+				v.HashMismatches = append(v.HashMismatches, queueIndex)
+				v.invalid(queueIndex)
+			}
+		}
+	}
+	if err := it.Error(); err != nil {
+		return nil, fmt.Errorf("failed to iterate L1 messages, err: %w", err)
+	}
+
+	// the messages up to the highest synced queue index must all be present
+	if next <= v.HighestSyncedQueueIndex && (v.NumL1Messages > 0 || v.HighestSyncedQueueIndex > 0) {
+		v.Gaps = append(v.Gaps, [2]uint64{next, v.HighestSyncedQueueIndex})
+		v.invalid(next)
+	}
+	return v, nil
+}
+
+// ResetL1MessageSync rolls back the L1 message sync so that the L1 messages from queue index
+// firstQueueIndex onwards are synced from L1 again. The sync restarts after the latest checkpoint
+// that precedes these messages, or after the L1 deployment block if there is none. It returns
+// the L1 block number after which the sync restarts.
+func ResetL1MessageSync(db ethdb.Database, firstQueueIndex uint64, l1DeploymentBlock uint64) uint64 {
+	var forkPoint *rawdb.L1MessageSyncCheckpoint
+	checkpoints := rawdb.ReadL1MessageSyncCheckpoints(db)
+	for _, checkpoint := range checkpoints {
+		if checkpoint.NumL1Messages <= firstQueueIndex {
+			forkPoint = checkpoint
+		}
+	}
+	restartBlock := l1DeploymentBlock
+	if forkPoint != nil {
+		restartBlock = forkPoint.L1BlockNumber
+	}
+
+	batchWriter := db.NewBatch()
+
+	// the stored messages may have gaps, so delete all of them rather than up to the highest synced queue index
+	it := rawdb.IterateL1MessagesFrom(db, firstQueueIndex)
+	var numDeleted int
+	for it.Next() {
+		rawdb.DeleteL1Message(batchWriter, it.QueueIndex())
+		numDeleted++
+	}
+	it.Release()
+	if err := it.Error(); err != nil {
+		log.Crit("Failed to iterate L1 messages", "err", err)
+	}
+
+	if firstQueueIndex > 0 {
+		rawdb.WriteHighestSyncedQueueIndex(batchWriter, firstQueueIndex-1)
+	} else {
+		rawdb.WriteHighestSyncedQueueIndex(batchWriter, 0)
+	}
+	for _, checkpoint := range checkpoints {
+		if checkpoint.L1BlockNumber > restartBlock {
+			rawdb.DeleteL1MessageSyncCheckpoint(batchWriter, checkpoint.L1BlockNumber)
+		}
+	}
+	rawdb.WriteSyncedL1BlockNumber(batchWriter, restartBlock)
+
+	// message verification must resume from a block at or before the restart block
+	if verified := rawdb.ReadLastVerifiedL1Block(db); verified != nil && verified.Number > restartBlock {
+		if forkPoint != nil {
+			rawdb.WriteLastVerifiedL1Block(batchWriter, &rawdb.VerifiedL1Block{Number: forkPoint.L1BlockNumber, Hash: forkPoint.L1BlockHash})
+		} else {
+			rawdb.DeleteLastVerifiedL1Block(batchWriter)
+		}
+	}
+
+	if err := batchWriter.Write(); err != nil {
+		log.Crit("Failed to reset L1 message sync", "err", err)
+	}
+
+	log.Info("Reset L1 message sync", "first queue index", firstQueueIndex, "deleted messages", numDeleted, "restart after L1 block", restartBlock)
+	return restartBlock
+}
@@ -0,0 +1,105 @@
+package sync_service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/core/rawdb"
+	"github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/ethdb"
+)
+
+func newTestL1Message(queueIndex uint64) types.L1MessageTx {
+	to := common.HexToAddress("0x1234")
+	return types.L1MessageTx{QueueIndex: queueIndex, Gas: 100000, To: &to, Value: common.Big1, Data: []byte{byte(queueIndex)}, Sender: to}
+}
+
+// writeTestL1Messages stores the L1 messages [0, numL1Messages), of which the first numIncluded are included in the head block.
+func writeTestL1Messages(db ethdb.Database, numL1Messages, numIncluded uint64) {
+	var hashes []common.Hash
+	for i := uint64(0); i < numL1Messages; i++ {
+		msg := newTestL1Message(i)
+		rawdb.WriteL1Message(db, msg)
+		if i < numIncluded {
+			hashes = append(hashes, types.NewTx(&msg).Hash())
+		}
+	}
+	headHash := common.HexToHash("0xabcd")
+	rawdb.WriteHeadBlockHash(db, headHash)
+	rawdb.WriteFirstQueueIndexNotInL2Block(db, headHash, numIncluded)
+	rawdb.WriteTxLookupEntries(db, 1, hashes)
+}
+
+func TestVerifyL1Messages(t *testing.T) {
+	db := rawdb.NewMemoryDatabase()
+	v, err := VerifyL1Messages(db)
+	require.NoError(t, err)
+	assert.Nil(t, v.FirstInvalidQueueIndex)
+
+	writeTestL1Messages(db, 20, 10)
+	v, err = VerifyL1Messages(db)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(20), v.NumL1Messages)
+	assert.Equal(t, uint64(19), v.HighestSyncedQueueIndex)
+	assert.Nil(t, v.FirstInvalidQueueIndex)
+
+	// a message included in the L2 chain that differs from the stored one
+	modified := newTestL1Message(8)
+	modified.Gas++
+	rawdb.WriteL1Message(db, modified)
+	// a message that cannot be decoded
+	require.NoError(t, db.Put(rawdb.L1MessageKey(12), []byte{0x01, 0x02}))
+	// a gap and missing messages up to the highest synced queue index
+	rawdb.DeleteL1Message(db, 15)
+	rawdb.DeleteL1Message(db, 16)
+	rawdb.DeleteL1Message(db, 19)
+	rawdb.WriteHighestSyncedQueueIndex(db, 19)
+
+	v, err = VerifyL1Messages(db)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(17), v.NumL1Messages)
+	assert.Equal(t, []uint64{8}, v.HashMismatches)
+	assert.Equal(t, []uint64{12}, v.Corrupted)
+	assert.Equal(t, [][2]uint64{{15, 16}, {19, 19}}, v.Gaps)
+	require.NotNil(t, v.FirstInvalidQueueIndex)
+	assert.Equal(t, uint64(8), *v.FirstInvalidQueueIndex)
+}
+
+func TestResetL1MessageSync(t *testing.T) {
+	db := rawdb.NewMemoryDatabase()
+	writeTestL1Messages(db, 20, 0)
+	rawdb.WriteSyncedL1BlockNumber(db, 130)
+	rawdb.WriteLastVerifiedL1Block(db, &rawdb.VerifiedL1Block{Number: 130, Hash: common.Hash{130}})
+	for i, numL1Messages := range []uint64{5, 10, 15, 20} {
+		number := uint64(100 + 10*i)
+		rawdb.WriteL1MessageSyncCheckpoint(db, &rawdb.L1MessageSyncCheckpoint{L1BlockNumber: number, L1BlockHash: common.Hash{byte(number)}, NumL1Messages: numL1Messages})
+	}
+	rawdb.DeleteL1Message(db, 13)
+
+	// the sync restarts after the latest checkpoint before the gap
+	assert.Equal(t, uint64(110), ResetL1MessageSync(db, 13, 50))
+	assert.Equal(t, uint64(110), *rawdb.ReadSyncedL1BlockNumber(db))
+	assert.Equal(t, uint64(12), rawdb.ReadHighestSyncedQueueIndex(db))
+	assert.NotNil(t, rawdb.ReadL1Message(db, 12))
+	for i := uint64(13); i < 20; i++ {
+		assert.Nil(t, rawdb.ReadL1Message(db, i))
+	}
+	checkpoints := rawdb.ReadL1MessageSyncCheckpoints(db)
+	require.Len(t, checkpoints, 2)
+	assert.Equal(t, uint64(110), checkpoints[1].L1BlockNumber)
+	assert.Equal(t, &rawdb.VerifiedL1Block{Number: 110, Hash: common.Hash{110}}, rawdb.ReadLastVerifiedL1Block(db))
+
+	v, err := VerifyL1Messages(db)
+	require.NoError(t, err)
+	assert.Nil(t, v.FirstInvalidQueueIndex)
+
+	// without an earlier checkpoint the sync restarts after the L1 deployment block
+	assert.Equal(t, uint64(50), ResetL1MessageSync(db, 2, 50))
+	assert.Equal(t, uint64(50), *rawdb.ReadSyncedL1BlockNumber(db))
+	assert.Equal(t, uint64(1), rawdb.ReadHighestSyncedQueueIndex(db))
+	assert.Empty(t, rawdb.ReadL1MessageSyncCheckpoints(db))
+	assert.Nil(t, rawdb.ReadLastVerifiedL1Block(db))
+}
@@ -2,10 +2,12 @@ package sync_service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"reflect"
 	"time"
 
+	"github.com/scroll-tech/go-ethereum/common"
 	"github.com/scroll-tech/go-ethereum/core"
 	"github.com/scroll-tech/go-ethereum/core/rawdb"
 	"github.com/scroll-tech/go-ethereum/ethdb"
@@ -37,7 +39,9 @@ const (
 )
 
 var (
-	l1MessageTotalCounter = metrics.NewRegisteredCounter("rollup/l1/message", nil)
+	l1MessageTotalCounter   = metrics.NewRegisteredCounter("rollup/l1/message", nil)
+	l1ReorgCounter          = metrics.NewRegisteredCounter("rollup/l1/reorg", nil)
+	l1MessageReorgedCounter = metrics.NewRegisteredCounter("rollup/l1/message/reorged", nil)
 )
 
 // SyncService collects all L1 messages and stores them in a local database.
@@ -76,6 +80,16 @@ func NewSyncService(ctx context.Context, genesisConfig *params.ChainConfig, node
 		latestProcessedBlock = *block
 	}
 
+	// verify L1 messages against the L1 header chain if a trusted L1 checkpoint is configured
+	if nodeConfig.L1CheckpointHash != (common.Hash{}) {
+		if nodeConfig.L1CheckpointNumber > latestProcessedBlock {
+			return nil, fmt.Errorf("L1 checkpoint %v is ahead of the latest synced L1 block %v", nodeConfig.L1CheckpointNumber, latestProcessedBlock)
+		}
+		checkpoint := rawdb.VerifiedL1Block{Number: nodeConfig.L1CheckpointNumber, Hash: nodeConfig.L1CheckpointHash}
+		client.verifier = newMessageVerifier(l1Client, db, genesisConfig.Scroll.L1Config.L1MessageQueueAddress, checkpoint)
+		log.Info("L1 message verification enabled", "checkpoint number", checkpoint.Number, "checkpoint hash", checkpoint.Hash.Hex())
+	}
+
 	ctx, cancel := context.WithCancel(ctx)
 
 	service := SyncService{
@@ -106,18 +120,19 @@ func (s *SyncService) Start() {
 		log.Info("L1 message initial sync completed", "latestProcessedBlock", s.latestProcessedBlock)
 	}
 
-	go func() {
-		t := time.NewTicker(s.pollInterval)
-		defer t.Stop()
+	// use push notifications if the L1 endpoint supports them, poll otherwise
+	notifier := NewL1Notifier(s.ctx, s.client.client, s.client.queueTransactionQuery(), s.pollInterval)
+	notifier.Start()
 
+	go func() {
 		for {
-			// don't wait for ticker during startup
+			// don't wait for notifications during startup
 			s.fetchMessages()
 
 			select {
 			case <-s.ctx.Done():
 				return
-			case <-t.C:
+			case <-notifier.C():
 				continue
 			}
 		}
@@ -154,8 +169,20 @@ func (s *SyncService) fetchMessages() {
 
 	log.Trace("Sync service fetchMessages", "latestProcessedBlock", s.latestProcessedBlock, "latestConfirmed", latestConfirmed)
 
+	// roll back L1 messages from reorged L1 blocks before fetching new ones
+	if err := s.detectAndHandleL1Reorg(); err != nil {
+		log.Warn("Failed to check L1 reorg", "err", err)
+		return
+	}
+
+	// discard verification progress that was not flushed in the previous round
+	if s.client.verifier != nil {
+		s.client.verifier.reset()
+	}
+
 	// keep track of next queue index we're expecting to see
 	queueIndex := rawdb.ReadHighestSyncedQueueIndex(s.db)
+	numL1Messages := s.numSyncedL1Messages()
 
 	batchWriter := s.db.NewBatch()
 	numBlocksPendingDbWrite := uint64(0)
@@ -165,6 +192,10 @@ func (s *SyncService) fetchMessages() {
 	flush := func(lastBlock uint64) {
 		// update sync progress
 		rawdb.WriteSyncedL1BlockNumber(batchWriter, lastBlock)
+		s.writeL1SyncCheckpoint(batchWriter, lastBlock, numL1Messages)
+		if s.client.verifier != nil {
+			s.client.verifier.write(batchWriter, lastBlock)
+		}
 
 		// write batch in a single transaction
 		err := batchWriter.Write()
@@ -215,6 +246,10 @@ func (s *SyncService) fetchMessages() {
 			if from > 0 {
 				flush(from - 1)
 			}
+			if errors.Is(err, errL1HeadersCatchingUp) {
+				log.Info("Waiting for L1 header verification to catch up", "fromBlock", from, "toBlock", to, "err", err)
+				return
+			}
 			log.Warn("Failed to fetch L1 messages in range", "fromBlock", from, "toBlock", to, "err", err)
 			return
 		}
@@ -234,6 +269,7 @@ func (s *SyncService) fetchMessages() {
 				log.Error("Unexpected queue index in SyncService", "expected", queueIndex, "got", msg.QueueIndex, "msg", msg)
 				return // do not flush inconsistent data to disk
 			}
+			numL1Messages = msg.QueueIndex + 1
 		}
 
 		numBlocksPendingDbWrite += to - from + 1
@@ -245,3 +281,114 @@ func (s *SyncService) fetchMessages() {
 		}
 	}
 }
+
+// numSyncedL1Messages returns the number of L1 messages stored in the database.
+func (s *SyncService) numSyncedL1Messages() uint64 {
+	highest := rawdb.ReadHighestSyncedQueueIndex(s.db)
+	if rawdb.ReadL1Message(s.db, highest) == nil {
+		return 0
+	}
+	return highest + 1
+}
+
+// writeL1SyncCheckpoint records the hash of the last processed L1 block, so that we can detect
+// if it gets reorged out later. Checkpoints beyond DefaultNumL1SyncCheckpoints are pruned.
+func (s *SyncService) writeL1SyncCheckpoint(db ethdb.KeyValueWriter, l1BlockNumber, numL1Messages uint64) {
+	if s.ctx.Err() != nil {
+		return
+	}
+
+	var hash common.Hash
+	if s.client.verifier != nil {
+		// only checkpoint verified blocks, these become trusted anchors when rolling back a reorg
+		var ok bool
+		if hash, ok = s.client.verifier.verifiedHash(l1BlockNumber); !ok {
+			return
+		}
+	} else {
+		var err error
+		hash, err = L1BlockHash(s.ctx, s.client.client, l1BlockNumber)
+		if err != nil {
+			// a missing checkpoint only makes reorg detection less precise
+			log.Warn("Failed to get L1 block hash for sync checkpoint", "number", l1BlockNumber, "err", err)
+			return
+		}
+	}
+
+	rawdb.WriteL1MessageSyncCheckpoint(db, &rawdb.L1MessageSyncCheckpoint{
+		L1BlockNumber: l1BlockNumber,
+		L1BlockHash:   hash,
+		NumL1Messages: numL1Messages,
+	})
+
+	checkpoints := rawdb.ReadL1MessageSyncCheckpoints(s.db)
+	for len(checkpoints) >= DefaultNumL1SyncCheckpoints {
+		rawdb.DeleteL1MessageSyncCheckpoint(db, checkpoints[0].L1BlockNumber)
+		checkpoints = checkpoints[1:]
+	}
+}
+
+// detectAndHandleL1Reorg checks whether the latest checkpointed L1 block is still canonical.
+// If not, it finds the fork point and deletes all L1 messages synced from the reorged blocks.
+func (s *SyncService) detectAndHandleL1Reorg() error {
+	checkpoints := rawdb.ReadL1MessageSyncCheckpoints(s.db)
+	if len(checkpoints) == 0 {
+		return nil
+	}
+
+	latest := checkpoints[len(checkpoints)-1]
+	hash, err := L1BlockHash(s.ctx, s.client.client, latest.L1BlockNumber)
+	if err != nil {
+		return err
+	}
+	if hash == latest.L1BlockHash {
+		return nil
+	}
+
+	numbers := make([]uint64, len(checkpoints))
+	hashes := make([]common.Hash, len(checkpoints))
+	for i, checkpoint := range checkpoints {
+		numbers[i] = checkpoint.L1BlockNumber
+		hashes[i] = checkpoint.L1BlockHash
+	}
+	forkIndex, err := FindL1ForkPoint(s.ctx, s.client.client, numbers, hashes)
+	if errors.Is(err, ErrL1ReorgTooDeep) {
+		log.Error("L1 reorg is deeper than the tracked checkpoints, manual intervention required", "oldest checkpoint", checkpoints[0].L1BlockNumber, "latest checkpoint", latest.L1BlockNumber)
+	}
+	if err != nil {
+		return err
+	}
+	forkPoint := checkpoints[forkIndex]
+
+	numL1Messages := s.numSyncedL1Messages()
+	log.Warn("L1 reorg detected, rolling back L1 messages", "fork point", forkPoint.L1BlockNumber, "latest processed block", s.latestProcessedBlock, "first reorged queue index", forkPoint.NumL1Messages, "synced L1 messages", numL1Messages)
+
+	batchWriter := s.db.NewBatch()
+	for queueIndex := forkPoint.NumL1Messages; queueIndex < numL1Messages; queueIndex++ {
+		rawdb.DeleteL1Message(batchWriter, queueIndex)
+	}
+	if forkPoint.NumL1Messages > 0 {
+		rawdb.WriteHighestSyncedQueueIndex(batchWriter, forkPoint.NumL1Messages-1)
+	} else {
+		rawdb.WriteHighestSyncedQueueIndex(batchWriter, 0)
+	}
+	for _, checkpoint := range checkpoints[forkIndex+1:] {
+		rawdb.DeleteL1MessageSyncCheckpoint(batchWriter, checkpoint.L1BlockNumber)
+	}
+	rawdb.WriteSyncedL1BlockNumber(batchWriter, forkPoint.L1BlockNumber)
+	if s.client.verifier != nil {
+		s.client.verifier.rollback(batchWriter, forkPoint.L1BlockNumber, forkPoint.L1BlockHash)
+	}
+
+	if err := batchWriter.Write(); err != nil {
+		// crash on database error, no risk of inconsistency here
+		log.Crit("Failed to roll back L1 messages", "err", err)
+	}
+
+	l1ReorgCounter.Inc(1)
+	if numL1Messages > forkPoint.NumL1Messages {
+		l1MessageReorgedCounter.Inc(int64(numL1Messages - forkPoint.NumL1Messages))
+	}
+	s.latestProcessedBlock = forkPoint.L1BlockNumber
+	return nil
+}
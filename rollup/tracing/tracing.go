@@ -608,3 +608,65 @@ func (env *TraceEnv) fillBlockTrace(block *types.Block) (*types.BlockTrace, erro
 
 	return blockTrace, nil
 }
+
+// TraceEnvState is the storage trace and the zktrie proof tracers that a TraceEnv accumulates over the
+// traced transactions of a block. Restoring the state after a transaction lets the TraceEnv continue
+// as if it had traced the transaction, so that the trace of the transaction can be reused.
+type TraceEnvState struct {
+	storageTrace *types.StorageTrace
+	zkTrieTracer map[string]state.ZktrieProofTracer
+}
+
+// StorageTrace returns the storage trace of the state, which must not be modified.
+func (s *TraceEnvState) StorageTrace() *types.StorageTrace {
+	return s.storageTrace
+}
+
+// State returns a copy of the state that the TraceEnv accumulated so far.
+func (env *TraceEnv) State() *TraceEnvState {
+	return &TraceEnvState{
+		storageTrace: copyStorageTrace(env.StorageTrace),
+		zkTrieTracer: copyZkTrieTracers(env.ZkTrieTracer),
+	}
+}
+
+// SetState replaces the accumulated state of the TraceEnv with a copy of s.
+func (env *TraceEnv) SetState(s *TraceEnvState) {
+	env.StorageTrace = copyStorageTrace(s.storageTrace)
+	env.ZkTrieTracer = copyZkTrieTracers(s.zkTrieTracer)
+}
+
+// SetStateDB replaces the state that the TraceEnv traces transactions on, e.g. after the caller restored a copy of it.
+func (env *TraceEnv) SetStateDB(statedb *state.StateDB) {
+	env.state = statedb
+}
+
+// copyStorageTrace copies the maps of a storage trace, the proofs themselves are not modified once added.
+func copyStorageTrace(st *types.StorageTrace) *types.StorageTrace {
+	cpy := &types.StorageTrace{
+		RootBefore:     st.RootBefore,
+		RootAfter:      st.RootAfter,
+		Proofs:         make(map[string][]hexutil.Bytes, len(st.Proofs)),
+		StorageProofs:  make(map[string]map[string][]hexutil.Bytes, len(st.StorageProofs)),
+		DeletionProofs: append([]hexutil.Bytes(nil), st.DeletionProofs...),
+	}
+	for addr, proof := range st.Proofs {
+		cpy.Proofs[addr] = proof
+	}
+	for addr, proofs := range st.StorageProofs {
+		m := make(map[string][]hexutil.Bytes, len(proofs))
+		for key, proof := range proofs {
+			m[key] = proof
+		}
+		cpy.StorageProofs[addr] = m
+	}
+	return cpy
+}
+
+func copyZkTrieTracers(tracers map[string]state.ZktrieProofTracer) map[string]state.ZktrieProofTracer {
+	cpy := make(map[string]state.ZktrieProofTracer, len(tracers))
+	for addr, tracer := range tracers {
+		cpy[addr] = tracer.Copy()
+	}
+	return cpy
+}
@@ -0,0 +1,112 @@
+package encoding
+
+import (
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/crypto/kzg4844"
+	"github.com/scroll-tech/go-ethereum/params"
+)
+
+// DABatch is the batch header produced by a codec.
+type DABatch interface {
+	// Encode serializes the batch header into bytes.
+	Encode() []byte
+
+	// Hash computes the hash of the serialized batch header.
+	Hash() common.Hash
+
+	// Blob returns the blob of the batch, or nil if the codec does not use blobs
+	// or if the batch header was decoded from bytes.
+	Blob() *kzg4844.Blob
+}
+
+// Codec encodes and decodes batches in the format of one codec version.
+type Codec interface {
+	// Version returns the version of the codec.
+	Version() CodecVersion
+
+	// MaxNumChunks returns the maximum number of chunks in a batch, or 0 if the codec does not limit it.
+	MaxNumChunks() uint64
+
+	// NewDABatch creates a batch header from the provided batch.
+	NewDABatch(batch *Batch) (DABatch, error)
+
+	// NewDABatchFromBytes decodes a serialized batch header.
+	NewDABatchFromBytes(data []byte) (DABatch, error)
+
+	// ChunkHash computes the hash of a chunk that is committed to by the batch data hash.
+	ChunkHash(chunk *Chunk, totalL1MessagePoppedBefore uint64) (common.Hash, error)
+
+	// DecodeDAChunks decodes the block contexts of the encoded chunks of a `commitBatch` call.
+	// The headers of the returned blocks only contain the fields that are part of the block
+	// contexts, and the blocks do not contain transactions.
+	DecodeDAChunks(chunks [][]byte) ([]*Chunk, error)
+
+	// EstimateChunkL1CommitCalldataSize estimates the calldata size needed for committing a chunk to L1.
+	EstimateChunkL1CommitCalldataSize(chunk *Chunk) (uint64, error)
+
+	// EstimateChunkL1CommitGas estimates the L1 commit gas of a chunk.
+	EstimateChunkL1CommitGas(chunk *Chunk) (uint64, error)
+
+	// EstimateChunkL1CommitBlobSize estimates the blob size needed for committing a chunk to L1.
+	EstimateChunkL1CommitBlobSize(chunk *Chunk) (uint64, error)
+
+	// EstimateBatchL1CommitCalldataSize estimates the calldata size needed for committing a batch to L1.
+	EstimateBatchL1CommitCalldataSize(batch *Batch) (uint64, error)
+
+	// EstimateBatchL1CommitGas estimates the L1 commit gas of a batch.
+	EstimateBatchL1CommitGas(batch *Batch) (uint64, error)
+
+	// EstimateBatchL1CommitBlobSize estimates the blob size needed for committing a batch to L1.
+	EstimateBatchL1CommitBlobSize(batch *Batch) (uint64, error)
+}
+
+var (
+	codecsLock sync.RWMutex
+	codecs     = make(map[CodecVersion]Codec)
+)
+
+// RegisterCodec makes a codec available by its version. It is meant to be called
+// from the init function of the package that implements the codec.
+func RegisterCodec(codec Codec) {
+	codecsLock.Lock()
+	defer codecsLock.Unlock()
+
+	if _, exists := codecs[codec.Version()]; exists {
+		panic(fmt.Sprintf("codec version %d registered twice", codec.Version()))
+	}
+	codecs[codec.Version()] = codec
+}
+
+// CodecFromVersion returns the registered codec of the given version.
+func CodecFromVersion(version CodecVersion) (Codec, error) {
+	codecsLock.RLock()
+	defer codecsLock.RUnlock()
+
+	codec, ok := codecs[version]
+	if !ok {
+		return nil, fmt.Errorf("unsupported codec version: %d", version)
+	}
+	return codec, nil
+}
+
+// CodecVersionFromConfig returns the codec version used for batches starting at the given L2 block.
+func CodecVersionFromConfig(chainCfg *params.ChainConfig, startBlockNumber *big.Int) CodecVersion {
+	// the genesis batch always uses codecv0
+	switch {
+	case startBlockNumber.Sign() == 0 || !chainCfg.IsBernoulli(startBlockNumber):
+		return CodecV0
+	case !chainCfg.IsDarwin(startBlockNumber):
+		return CodecV1
+	default:
+		return CodecV2
+	}
+}
+
+// CodecFromConfig returns the registered codec used for batches starting at the given L2 block.
+func CodecFromConfig(chainCfg *params.ChainConfig, startBlockNumber *big.Int) (Codec, error) {
+	return CodecFromVersion(CodecVersionFromConfig(chainCfg, startBlockNumber))
+}
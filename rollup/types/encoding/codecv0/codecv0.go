@@ -12,6 +12,7 @@ import (
 	"github.com/scroll-tech/go-ethereum/common"
 	"github.com/scroll-tech/go-ethereum/core/types"
 	"github.com/scroll-tech/go-ethereum/crypto"
+	"github.com/scroll-tech/go-ethereum/crypto/kzg4844"
 	"github.com/scroll-tech/go-ethereum/rollup/types/encoding"
 )
 
@@ -297,6 +298,65 @@ func (b *DABatch) Hash() common.Hash {
 	return crypto.Keccak256Hash(bytes)
 }
 
+// Blob returns nil, codecv0 batches are committed without blobs.
+func (b *DABatch) Blob() *kzg4844.Blob {
+	return nil
+}
+
+// DecodeDAChunk decodes a DAChunk from the block contexts in its encoding.
+// Note: This function leaves the transactions empty.
+func DecodeDAChunk(data []byte) (*DAChunk, error) {
+	if len(data) < 1 {
+		return nil, errors.New("chunk encoding is empty")
+	}
+
+	numBlocks := int(data[0])
+	if numBlocks == 0 {
+		return nil, errors.New("chunk has no blocks")
+	}
+	// the block contexts are followed by the L2 transactions
+	if len(data) < 1+numBlocks*60 {
+		return nil, fmt.Errorf("invalid chunk encoding length, expected at least %d bytes but got %d", 1+numBlocks*60, len(data))
+	}
+
+	chunk := &DAChunk{
+		Blocks:       make([]*DABlock, numBlocks),
+		Transactions: make([][]*types.TransactionData, numBlocks),
+	}
+	for i := 0; i < numBlocks; i++ {
+		startIdx := 1 + i*60 // add 1 to skip numBlocks byte
+		block, err := DecodeDABlock(data[startIdx : startIdx+60])
+		if err != nil {
+			return nil, err
+		}
+		chunk.Blocks[i] = block
+	}
+
+	return chunk, nil
+}
+
+// ToChunk rebuilds an encoding.Chunk from the DAChunk. The block headers only contain the
+// fields that are part of the block contexts.
+func (c *DAChunk) ToChunk() *encoding.Chunk {
+	chunk := &encoding.Chunk{Blocks: make([]*encoding.Block, len(c.Blocks))}
+	for i, block := range c.Blocks {
+		var txs []*types.TransactionData
+		if i < len(c.Transactions) {
+			txs = c.Transactions[i]
+		}
+		chunk.Blocks[i] = &encoding.Block{
+			Header: &types.Header{
+				Number:   new(big.Int).SetUint64(block.BlockNumber),
+				Time:     block.Timestamp,
+				BaseFee:  block.BaseFee,
+				GasLimit: block.GasLimit,
+			},
+			Transactions: txs,
+		}
+	}
+	return chunk
+}
+
 // DecodeFromCalldata attempts to decode a DABatch and an array of DAChunks from the provided calldata byte slice.
 func DecodeFromCalldata(data []byte) (*DABatch, []*DAChunk, error) {
 	// TODO: implement this function.
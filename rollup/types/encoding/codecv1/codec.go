@@ -0,0 +1,81 @@
+package codecv1
+
+import (
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/rollup/types/encoding"
+)
+
+func init() {
+	encoding.RegisterCodec(codec{})
+}
+
+// codec implements encoding.Codec for codecv1.
+type codec struct{}
+
+func (codec) Version() encoding.CodecVersion {
+	return encoding.CodecV1
+}
+
+func (codec) MaxNumChunks() uint64 {
+	return uint64(MaxNumChunks)
+}
+
+func (codec) NewDABatch(batch *encoding.Batch) (encoding.DABatch, error) {
+	daBatch, err := NewDABatch(batch)
+	if err != nil {
+		return nil, err
+	}
+	return daBatch, nil
+}
+
+func (codec) NewDABatchFromBytes(data []byte) (encoding.DABatch, error) {
+	daBatch, err := NewDABatchFromBytes(data)
+	if err != nil {
+		return nil, err
+	}
+	return daBatch, nil
+}
+
+func (codec) ChunkHash(chunk *encoding.Chunk, totalL1MessagePoppedBefore uint64) (common.Hash, error) {
+	daChunk, err := NewDAChunk(chunk, totalL1MessagePoppedBefore)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return daChunk.Hash()
+}
+
+func (codec) DecodeDAChunks(chunks [][]byte) ([]*encoding.Chunk, error) {
+	decoded := make([]*encoding.Chunk, len(chunks))
+	for i, chunk := range chunks {
+		daChunk, err := DecodeDAChunk(chunk)
+		if err != nil {
+			return nil, err
+		}
+		decoded[i] = daChunk.ToChunk()
+	}
+	return decoded, nil
+}
+
+func (codec) EstimateChunkL1CommitCalldataSize(chunk *encoding.Chunk) (uint64, error) {
+	return EstimateChunkL1CommitCalldataSize(chunk)
+}
+
+func (codec) EstimateChunkL1CommitGas(chunk *encoding.Chunk) (uint64, error) {
+	return EstimateChunkL1CommitGas(chunk)
+}
+
+func (codec) EstimateChunkL1CommitBlobSize(chunk *encoding.Chunk) (uint64, error) {
+	return EstimateChunkL1CommitBlobSize(chunk)
+}
+
+func (codec) EstimateBatchL1CommitCalldataSize(batch *encoding.Batch) (uint64, error) {
+	return EstimateBatchL1CommitCalldataSize(batch)
+}
+
+func (codec) EstimateBatchL1CommitGas(batch *encoding.Batch) (uint64, error) {
+	return EstimateBatchL1CommitGas(batch)
+}
+
+func (codec) EstimateBatchL1CommitBlobSize(batch *encoding.Batch) (uint64, error) {
+	return EstimateBatchL1CommitBlobSize(batch)
+}
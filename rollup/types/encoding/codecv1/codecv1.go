@@ -1,6 +1,7 @@
 package codecv1
 
 import (
+	"bytes"
 	"crypto/sha256"
 	"encoding/binary"
 	"encoding/hex"
@@ -16,7 +17,9 @@ import (
 	"github.com/scroll-tech/go-ethereum/crypto"
 	"github.com/scroll-tech/go-ethereum/crypto/kzg4844"
 	"github.com/scroll-tech/go-ethereum/log"
+	"github.com/scroll-tech/go-ethereum/rlp"
 	"github.com/scroll-tech/go-ethereum/rollup/types/encoding"
+	"github.com/scroll-tech/go-ethereum/rollup/types/encoding/codecv0"
 )
 
 var (
@@ -26,6 +29,12 @@ var (
 	// BlobDataProofArgs defines the argument types for `_blobDataProof` in `finalizeBatchWithProof4844`.
 	BlobDataProofArgs abi.Arguments
 
+	// CommitBatchArgs defines the argument types for `commitBatch`.
+	CommitBatchArgs abi.Arguments
+
+	// commitBatchMethodID is the method ID of `commitBatch(uint8,bytes,bytes[],bytes)`.
+	commitBatchMethodID = crypto.Keccak256([]byte("commitBatch(uint8,bytes,bytes[],bytes)"))[:4]
+
 	// MaxNumChunks is the maximum number of chunks that a batch can contain.
 	MaxNumChunks int = 15
 )
@@ -41,8 +50,11 @@ func init() {
 	// initialize arguments
 	bytes32Type, err1 := abi.NewType("bytes32", "bytes32", nil)
 	bytes48Type, err2 := abi.NewType("bytes48", "bytes48", nil)
-	if err1 != nil || err2 != nil {
-		log.Crit("Failed to initialize abi types", "err1", err1, "err2", err2)
+	uint8Type, err3 := abi.NewType("uint8", "uint8", nil)
+	bytesType, err4 := abi.NewType("bytes", "bytes", nil)
+	bytesArrayType, err5 := abi.NewType("bytes[]", "bytes[]", nil)
+	if err1 != nil || err2 != nil || err3 != nil || err4 != nil || err5 != nil {
+		log.Crit("Failed to initialize abi types", "err1", err1, "err2", err2, "err3", err3, "err4", err4, "err5", err5)
 	}
 
 	BlobDataProofArgs = abi.Arguments{
@@ -51,6 +63,13 @@ func init() {
 		{Type: bytes48Type, Name: "commitment"},
 		{Type: bytes48Type, Name: "proof"},
 	}
+
+	CommitBatchArgs = abi.Arguments{
+		{Type: uint8Type, Name: "version"},
+		{Type: bytesType, Name: "parentBatchHeader"},
+		{Type: bytesArrayType, Name: "chunks"},
+		{Type: bytesType, Name: "skippedL1MessageBitmap"},
+	}
 }
 
 // CodecV1Version denotes the version of the codec.
@@ -244,7 +263,7 @@ func NewDABatch(batch *encoding.Batch) (*DABatch, error) {
 	}
 
 	// blob payload
-	blob, blobVersionedHash, z, err := constructBlobPayload(batch.Chunks)
+	blob, blobVersionedHash, z, err := ConstructBlobPayload(batch.Chunks)
 	if err != nil {
 		return nil, err
 	}
@@ -290,8 +309,8 @@ func computeBatchDataHash(chunks []*encoding.Chunk, totalL1MessagePoppedBefore u
 	return dataHash, nil
 }
 
-// constructBlobPayload constructs the 4844 blob payload.
-func constructBlobPayload(chunks []*encoding.Chunk) (*kzg4844.Blob, common.Hash, *kzg4844.Point, error) {
+// ConstructBlobPayload constructs the 4844 blob payload.
+func ConstructBlobPayload(chunks []*encoding.Chunk) (*kzg4844.Blob, common.Hash, *kzg4844.Point, error) {
 	// metadata consists of num_chunks (2 bytes) and chunki_size (4 bytes per chunk)
 	metadataLength := 2 + MaxNumChunks*4
 
@@ -349,7 +368,7 @@ func constructBlobPayload(chunks []*encoding.Chunk) (*kzg4844.Blob, common.Hash,
 	copy(challengePreimage[0:], hash[:])
 
 	// convert raw data to BLSFieldElements
-	blob, err := makeBlobCanonical(blobBytes)
+	blob, err := MakeBlobCanonical(blobBytes)
 	if err != nil {
 		return nil, common.Hash{}, nil, err
 	}
@@ -377,8 +396,8 @@ func constructBlobPayload(chunks []*encoding.Chunk) (*kzg4844.Blob, common.Hash,
 	return blob, blobVersionedHash, &z, nil
 }
 
-// makeBlobCanonical converts the raw blob data into the canonical blob representation of 4096 BLSFieldElements.
-func makeBlobCanonical(blobBytes []byte) (*kzg4844.Blob, error) {
+// MakeBlobCanonical converts the raw blob data into the canonical blob representation of 4096 BLSFieldElements.
+func MakeBlobCanonical(blobBytes []byte) (*kzg4844.Blob, error) {
 	// blob contains 131072 bytes but we can only utilize 31/32 of these
 	if len(blobBytes) > 126976 {
 		return nil, fmt.Errorf("oversized batch payload")
@@ -477,9 +496,351 @@ func (b *DABatch) Blob() *kzg4844.Blob {
 }
 
 // DecodeFromCalldata attempts to decode a DABatch and an array of DAChunks from the provided calldata byte slice.
+// The calldata is the input of a `commitBatch` transaction, including the method ID.
+// Note: The transactions are not part of the calldata, use DecodeTxsFromBlob to decode them.
+// DataHash and BlobVersionedHash are left empty, as the former requires the L1 message hashes
+// and the latter is part of the transaction, not of the calldata.
 func DecodeFromCalldata(data []byte) (*DABatch, []*DAChunk, error) {
-	// TODO: implement this function.
-	return nil, nil, nil
+	if len(data) < len(commitBatchMethodID) {
+		return nil, nil, fmt.Errorf("insufficient calldata, expected at least %d bytes but got %d", len(commitBatchMethodID), len(data))
+	}
+	if !bytes.Equal(data[:len(commitBatchMethodID)], commitBatchMethodID) {
+		return nil, nil, fmt.Errorf("unexpected method ID: %x", data[:len(commitBatchMethodID)])
+	}
+
+	values, err := CommitBatchArgs.Unpack(data[len(commitBatchMethodID):])
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to unpack commitBatch calldata: %w", err)
+	}
+	var args struct {
+		Version                uint8
+		ParentBatchHeader      []byte
+		Chunks                 [][]byte
+		SkippedL1MessageBitmap []byte
+	}
+	if err := CommitBatchArgs.Copy(&args, values); err != nil {
+		return nil, nil, fmt.Errorf("failed to decode commitBatch calldata: %w", err)
+	}
+
+	if args.Version != CodecV1Version {
+		return nil, nil, fmt.Errorf("unexpected batch version: %d", args.Version)
+	}
+	if len(args.Chunks) == 0 {
+		return nil, nil, fmt.Errorf("too few chunks in batch")
+	}
+	if len(args.Chunks) > MaxNumChunks {
+		return nil, nil, fmt.Errorf("too many chunks in batch")
+	}
+
+	// the parent batch header can be encoded with codecv0 or codecv1,
+	// they share the layout of the fields that we need here
+	if len(args.ParentBatchHeader) < 89 {
+		return nil, nil, fmt.Errorf("insufficient data for parent batch header, expected at least 89 bytes but got %d", len(args.ParentBatchHeader))
+	}
+	parentBatchIndex := binary.BigEndian.Uint64(args.ParentBatchHeader[1:9])
+	parentTotalL1MessagePopped := binary.BigEndian.Uint64(args.ParentBatchHeader[17:25])
+
+	chunks := make([]*DAChunk, len(args.Chunks))
+	var l1MessagePopped uint64
+	for i, chunkBytes := range args.Chunks {
+		chunk, err := DecodeDAChunk(chunkBytes)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to decode chunk %d: %w", i, err)
+		}
+		for _, block := range chunk.Blocks {
+			l1MessagePopped += uint64(block.NumL1Messages)
+		}
+		chunks[i] = chunk
+	}
+
+	if expected := (l1MessagePopped + 255) / 256 * 32; uint64(len(args.SkippedL1MessageBitmap)) != expected {
+		return nil, nil, fmt.Errorf("unexpected skipped L1 message bitmap length, expected %d bytes but got %d", expected, len(args.SkippedL1MessageBitmap))
+	}
+
+	batch := &DABatch{
+		Version:                args.Version,
+		BatchIndex:             parentBatchIndex + 1,
+		L1MessagePopped:        l1MessagePopped,
+		TotalL1MessagePopped:   parentTotalL1MessagePopped + l1MessagePopped,
+		ParentBatchHash:        crypto.Keccak256Hash(args.ParentBatchHeader),
+		SkippedL1MessageBitmap: args.SkippedL1MessageBitmap,
+	}
+
+	return batch, chunks, nil
+}
+
+// DecodeDAChunk decodes a DAChunk from the block contexts in its encoding.
+// Note: This function leaves the transactions empty, they are part of the blob.
+func DecodeDAChunk(data []byte) (*DAChunk, error) {
+	if len(data) < 1 {
+		return nil, errors.New("chunk encoding is empty")
+	}
+
+	numBlocks := int(data[0])
+	if numBlocks == 0 {
+		return nil, errors.New("chunk has no blocks")
+	}
+	if len(data) != 1+numBlocks*60 {
+		return nil, fmt.Errorf("invalid chunk encoding length, expected %d bytes but got %d", 1+numBlocks*60, len(data))
+	}
+
+	chunk := &DAChunk{
+		Blocks:       make([]*DABlock, numBlocks),
+		Transactions: make([][]*types.TransactionData, numBlocks),
+	}
+	for i := 0; i < numBlocks; i++ {
+		startIdx := 1 + i*60 // add 1 to skip numBlocks byte
+		block, err := DecodeDABlock(data[startIdx : startIdx+60])
+		if err != nil {
+			return nil, err
+		}
+		if block.NumL1Messages > block.NumTransactions {
+			return nil, fmt.Errorf("block %d has more L1 messages (%d) than transactions (%d)", block.BlockNumber, block.NumL1Messages, block.NumTransactions)
+		}
+		chunk.Blocks[i] = block
+	}
+
+	return chunk, nil
+}
+
+// DecodeTxsFromBlob decodes the L2 transactions of the given chunks from the blob payload,
+// and fills in the transactions of each chunk. L1 messages are not part of the blob.
+func DecodeTxsFromBlob(blob *kzg4844.Blob, chunks []*DAChunk) error {
+	blobBytes, err := BytesFromBlobCanonical(blob)
+	if err != nil {
+		return err
+	}
+	return DecodeTxsFromBytes(blobBytes, chunks, MaxNumChunks)
+}
+
+// DecodeTxsFromBytes decodes the L2 transactions of the given chunks from the raw blob payload,
+// which consists of the metadata for maxNumChunks chunks followed by the chunk data.
+func DecodeTxsFromBytes(blobBytes []byte, chunks []*DAChunk, maxNumChunks int) error {
+	metadataLength := 2 + maxNumChunks*4
+	if len(blobBytes) < metadataLength {
+		return fmt.Errorf("insufficient blob payload, expected at least %d bytes of metadata but got %d", metadataLength, len(blobBytes))
+	}
+	numChunks := int(binary.BigEndian.Uint16(blobBytes[0:2]))
+	if numChunks != len(chunks) {
+		return fmt.Errorf("unexpected number of chunks in blob, expected %d but got %d", len(chunks), numChunks)
+	}
+
+	index := metadataLength
+	for i, chunk := range chunks {
+		chunkSize := int(binary.BigEndian.Uint32(blobBytes[2+4*i:]))
+		if index+chunkSize > len(blobBytes) {
+			return fmt.Errorf("chunk %d exceeds the blob payload, offset: %d, size: %d", i, index, chunkSize)
+		}
+		if err := chunk.decodeTxs(blobBytes[index : index+chunkSize]); err != nil {
+			return fmt.Errorf("failed to decode transactions of chunk %d: %w", i, err)
+		}
+		index += chunkSize
+	}
+
+	return nil
+}
+
+// decodeTxs decodes the L2 transactions of a chunk from its blob data.
+func (c *DAChunk) decodeTxs(data []byte) error {
+	c.Transactions = make([][]*types.TransactionData, len(c.Blocks))
+
+	for i, block := range c.Blocks {
+		numL2Transactions := int(block.NumTransactions - block.NumL1Messages)
+		txs := make(types.Transactions, 0, numL2Transactions)
+		for j := 0; j < numL2Transactions; j++ {
+			tx, size, err := decodeTx(data)
+			if err != nil {
+				return fmt.Errorf("failed to decode transaction %d of block %d: %w", j, block.BlockNumber, err)
+			}
+			txs = append(txs, tx)
+			data = data[size:]
+		}
+		c.Transactions[i] = encoding.TxsToTxsData(txs)
+	}
+
+	if len(data) != 0 {
+		return fmt.Errorf("%d unexpected bytes after the last transaction", len(data))
+	}
+	return nil
+}
+
+// decodeTx decodes the first transaction in data and returns it with its encoded size.
+func decodeTx(data []byte) (*types.Transaction, int, error) {
+	if len(data) == 0 {
+		return nil, 0, errors.New("unexpected end of data")
+	}
+
+	// typed transactions are prefixed by their type, legacy transactions are plain RLP lists
+	var prefixLength int
+	if data[0] <= 0x7f {
+		prefixLength = 1
+	}
+	_, _, rest, err := rlp.Split(data[prefixLength:])
+	if err != nil {
+		return nil, 0, err
+	}
+	size := len(data) - len(rest)
+
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(data[:size]); err != nil {
+		return nil, 0, err
+	}
+	return tx, size, nil
+}
+
+// BytesFromBlobCanonical converts the canonical blob representation into the raw blob payload.
+// It is the inverse of MakeBlobCanonical, up to the trailing zero padding.
+func BytesFromBlobCanonical(blob *kzg4844.Blob) ([]byte, error) {
+	blobBytes := make([]byte, 0, len(blob)/32*31)
+	for from := 0; from < len(blob); from += 32 {
+		if blob[from] != 0 {
+			return nil, fmt.Errorf("invalid blob, field element %d has a non-zero first byte", from/32)
+		}
+		blobBytes = append(blobBytes, blob[from+1:from+32]...)
+	}
+	return blobBytes, nil
+}
+
+// ToChunk rebuilds an encoding.Chunk from the DAChunk. The block headers only contain the
+// fields that are part of the block contexts, and the blocks only contain L2 transactions.
+func (c *DAChunk) ToChunk() *encoding.Chunk {
+	chunk := &encoding.Chunk{Blocks: make([]*encoding.Block, len(c.Blocks))}
+	for i, block := range c.Blocks {
+		var txs []*types.TransactionData
+		if i < len(c.Transactions) {
+			txs = c.Transactions[i]
+		}
+		chunk.Blocks[i] = &encoding.Block{
+			Header: &types.Header{
+				Number:   new(big.Int).SetUint64(block.BlockNumber),
+				Time:     block.Timestamp,
+				BaseFee:  block.BaseFee,
+				GasLimit: block.GasLimit,
+			},
+			Transactions: txs,
+		}
+	}
+	return chunk
+}
+
+// EstimateBlockL1CommitCalldataSize calculates the calldata size in l1 commit for this block approximately.
+// The L2 transactions are part of the blob, so only the 60 bytes BlockContext remain in the calldata.
+func EstimateBlockL1CommitCalldataSize(b *encoding.Block) (uint64, error) {
+	return 60, nil
+}
+
+// EstimateBlockL1CommitGas calculates the total L1 commit gas for this block approximately.
+func EstimateBlockL1CommitGas(b *encoding.Block) (uint64, error) {
+	var total uint64
+	var numL1Messages uint64
+	for _, txData := range b.Transactions {
+		if txData.Type == types.L1MessageTxType {
+			numL1Messages++
+		}
+	}
+
+	// 60 bytes BlockContext calldata
+	total += codecv0.CalldataNonZeroByteGas * 60
+
+	// sload
+	total += 2100 * numL1Messages // numL1Messages times cold sload in L1MessageQueue
+
+	// staticcall
+	total += 100 * numL1Messages // numL1Messages times call to L1MessageQueue
+	total += 100 * numL1Messages // numL1Messages times warm address access to L1MessageQueue
+
+	total += codecv0.GetMemoryExpansionCost(36) * numL1Messages // staticcall to proxy
+	total += 100 * numL1Messages                                // read admin in proxy
+	total += 100 * numL1Messages                                // read impl in proxy
+	total += 100 * numL1Messages                                // access impl
+	total += codecv0.GetMemoryExpansionCost(36) * numL1Messages // delegatecall to impl
+
+	return total, nil
+}
+
+// EstimateChunkL1CommitCalldataSize calculates the calldata size needed for committing a chunk to L1 approximately.
+func EstimateChunkL1CommitCalldataSize(c *encoding.Chunk) (uint64, error) {
+	return uint64(60 * len(c.Blocks)), nil
+}
+
+// EstimateChunkL1CommitGas calculates the total L1 commit gas for this chunk approximately.
+func EstimateChunkL1CommitGas(c *encoding.Chunk) (uint64, error) {
+	var totalL1Messages uint64
+	var totalL1CommitGas uint64
+	for _, block := range c.Blocks {
+		totalL1Messages += uint64(len(block.Transactions)) - block.NumL2Transactions()
+		blockL1CommitGas, err := EstimateBlockL1CommitGas(block)
+		if err != nil {
+			return 0, err
+		}
+		totalL1CommitGas += blockL1CommitGas
+	}
+
+	numBlocks := uint64(len(c.Blocks))
+	totalL1CommitGas += 100 * numBlocks                                            // numBlocks times warm sload
+	totalL1CommitGas += codecv0.CalldataNonZeroByteGas                             // numBlocks field of chunk encoding in calldata
+	totalL1CommitGas += codecv0.GetKeccak256Gas(58*numBlocks + 32*totalL1Messages) // chunk hash
+	return totalL1CommitGas, nil
+}
+
+// EstimateBatchL1CommitGas calculates the total L1 commit gas for this batch approximately.
+func EstimateBatchL1CommitGas(b *encoding.Batch) (uint64, error) {
+	var totalL1CommitGas uint64
+
+	// Add extra gas costs
+	totalL1CommitGas += 100000                         // constant to account for ops like _getAdmin, _implementation, _requireNotPaused, etc
+	totalL1CommitGas += 4 * 2100                       // 4 one-time cold sload for commitBatch
+	totalL1CommitGas += 20000                          // 1 time sstore
+	totalL1CommitGas += 21000                          // base fee for tx
+	totalL1CommitGas += codecv0.CalldataNonZeroByteGas // version in calldata
+
+	// adjusting gas:
+	// add 1 time cold sload (2100 gas) for L1MessageQueue
+	// add 1 time cold address access (2600 gas) for L1MessageQueue
+	// minus 1 time warm sload (100 gas) & 1 time warm address access (100 gas)
+	totalL1CommitGas += (2100 + 2600 - 100 - 100)
+	totalL1CommitGas += codecv0.GetKeccak256Gas(121 + 32)           // parent batch header hash, length is estimated as 121 (constant part)+ 32 (1 skippedL1MessageBitmap)
+	totalL1CommitGas += codecv0.CalldataNonZeroByteGas * (121 + 32) // parent batch header in calldata
+
+	// adjust batch data hash gas cost
+	totalL1CommitGas += codecv0.GetKeccak256Gas(uint64(32 * len(b.Chunks)))
+
+	totalL1MessagePoppedBefore := b.TotalL1MessagePoppedBefore
+
+	for _, chunk := range b.Chunks {
+		chunkL1CommitGas, err := EstimateChunkL1CommitGas(chunk)
+		if err != nil {
+			return 0, err
+		}
+		totalL1CommitGas += chunkL1CommitGas
+
+		totalL1MessagePoppedInChunk := chunk.NumL1Messages(totalL1MessagePoppedBefore)
+		totalL1MessagePoppedBefore += totalL1MessagePoppedInChunk
+
+		totalL1CommitGas += codecv0.CalldataNonZeroByteGas * (32 * (totalL1MessagePoppedInChunk + 255) / 256)
+		totalL1CommitGas += codecv0.GetKeccak256Gas(121 + 32*(totalL1MessagePoppedInChunk+255)/256)
+
+		totalL1CommitCalldataSize, err := EstimateChunkL1CommitCalldataSize(chunk)
+		if err != nil {
+			return 0, err
+		}
+		totalL1CommitGas += codecv0.GetMemoryExpansionCost(totalL1CommitCalldataSize)
+	}
+
+	return totalL1CommitGas, nil
+}
+
+// EstimateBatchL1CommitCalldataSize calculates the calldata size in l1 commit for this batch approximately.
+func EstimateBatchL1CommitCalldataSize(b *encoding.Batch) (uint64, error) {
+	var totalL1CommitCalldataSize uint64
+	for _, chunk := range b.Chunks {
+		chunkL1CommitCalldataSize, err := EstimateChunkL1CommitCalldataSize(chunk)
+		if err != nil {
+			return 0, err
+		}
+		totalL1CommitCalldataSize += chunkL1CommitCalldataSize
+	}
+	return totalL1CommitCalldataSize, nil
 }
 
 // EstimateChunkL1CommitBlobSize estimates the size of the L1 commit blob for a single chunk.
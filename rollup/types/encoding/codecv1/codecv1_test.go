@@ -0,0 +1,228 @@
+package codecv1
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/crypto"
+	"github.com/scroll-tech/go-ethereum/params"
+	"github.com/scroll-tech/go-ethereum/rollup/types/encoding"
+	"github.com/scroll-tech/go-ethereum/rollup/types/encoding/codecv0"
+)
+
+// newTestChunks creates two chunks with L1 messages and L2 transactions of all supported types.
+func newTestChunks(t *testing.T) []*encoding.Chunk {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	chainID := big.NewInt(534352)
+	signer := types.NewLondonSigner(chainID)
+	to := common.HexToAddress("0x1234")
+
+	sign := func(tx types.TxData) *types.Transaction {
+		signed, err := types.SignNewTx(key, signer, tx)
+		require.NoError(t, err)
+		return signed
+	}
+
+	newBlock := func(number uint64, txs ...*types.Transaction) *encoding.Block {
+		return &encoding.Block{
+			Header: &types.Header{
+				Number:   new(big.Int).SetUint64(number),
+				Time:     1700000000 + number,
+				BaseFee:  big.NewInt(1000),
+				GasLimit: 10000000,
+			},
+			Transactions: encoding.TxsToTxsData(txs),
+		}
+	}
+
+	l1Message := types.NewTx(&types.L1MessageTx{QueueIndex: 0, Gas: 100000, To: &to, Value: common.Big0, Data: []byte{1}, Sender: to})
+	legacy := sign(&types.LegacyTx{Nonce: 0, To: &to, Value: big.NewInt(1), Gas: 21000, GasPrice: big.NewInt(1000)})
+	accessList := sign(&types.AccessListTx{ChainID: chainID, Nonce: 1, To: &to, Gas: 30000, GasPrice: big.NewInt(1000), Data: []byte{1, 2, 3},
+		AccessList: types.AccessList{{Address: to, StorageKeys: []common.Hash{{1}}}}})
+	dynamicFee := sign(&types.DynamicFeeTx{ChainID: chainID, Nonce: 2, Gas: 60000, GasTipCap: big.NewInt(1), GasFeeCap: big.NewInt(2000), Data: make([]byte, 100)})
+
+	return []*encoding.Chunk{
+		{Blocks: []*encoding.Block{newBlock(1, l1Message, legacy), newBlock(2)}},
+		{Blocks: []*encoding.Block{newBlock(3, accessList, dynamicFee)}},
+	}
+}
+
+func TestDecodeFromCalldataAndBlob(t *testing.T) {
+	chunks := newTestChunks(t)
+
+	parent := &DABatch{Version: CodecV1Version, BatchIndex: 5, TotalL1MessagePopped: 0}
+	batch := &encoding.Batch{Index: 6, TotalL1MessagePoppedBefore: 0, ParentBatchHash: parent.Hash(), Chunks: chunks}
+	daBatch, err := NewDABatch(batch)
+	require.NoError(t, err)
+
+	var encodedChunks [][]byte
+	for _, chunk := range chunks {
+		daChunk, err := NewDAChunk(chunk, 0)
+		require.NoError(t, err)
+		encodedChunks = append(encodedChunks, daChunk.Encode())
+	}
+	args, err := CommitBatchArgs.Pack(uint8(CodecV1Version), parent.Encode(), encodedChunks, daBatch.SkippedL1MessageBitmap)
+	require.NoError(t, err)
+	calldata := append(append([]byte{}, commitBatchMethodID...), args...)
+
+	decodedBatch, decodedChunks, err := DecodeFromCalldata(calldata)
+	require.NoError(t, err)
+	assert.Equal(t, daBatch.BatchIndex, decodedBatch.BatchIndex)
+	assert.Equal(t, daBatch.L1MessagePopped, decodedBatch.L1MessagePopped)
+	assert.Equal(t, daBatch.TotalL1MessagePopped, decodedBatch.TotalL1MessagePopped)
+	assert.Equal(t, daBatch.ParentBatchHash, decodedBatch.ParentBatchHash)
+	assert.Equal(t, daBatch.SkippedL1MessageBitmap, decodedBatch.SkippedL1MessageBitmap)
+	require.Len(t, decodedChunks, len(chunks))
+
+	blob, _, _, err := ConstructBlobPayload(chunks)
+	require.NoError(t, err)
+	require.NoError(t, DecodeTxsFromBlob(blob, decodedChunks))
+
+	for i, chunk := range chunks {
+		decoded := decodedChunks[i].ToChunk()
+		require.Len(t, decoded.Blocks, len(chunk.Blocks))
+		for j, block := range chunk.Blocks {
+			decodedBlock := decoded.Blocks[j]
+			assert.Equal(t, block.Header.Number, decodedBlock.Header.Number)
+			assert.Equal(t, block.Header.Time, decodedBlock.Header.Time)
+			assert.Equal(t, block.Header.BaseFee, decodedBlock.Header.BaseFee)
+			assert.Equal(t, block.Header.GasLimit, decodedBlock.Header.GasLimit)
+
+			// L1 messages are not part of the blob
+			var l2Txs []*types.TransactionData
+			for _, tx := range block.Transactions {
+				if tx.Type != types.L1MessageTxType {
+					l2Txs = append(l2Txs, tx)
+				}
+			}
+			require.Len(t, decodedBlock.Transactions, len(l2Txs))
+			for k, tx := range l2Txs {
+				assert.Equal(t, tx.TxHash, decodedBlock.Transactions[k].TxHash)
+			}
+		}
+
+		// re-encoding the decoded chunk reproduces the blob data
+		reencoded, _, _, err := ConstructBlobPayload([]*encoding.Chunk{decoded})
+		require.NoError(t, err)
+		original, _, _, err := ConstructBlobPayload([]*encoding.Chunk{chunk})
+		require.NoError(t, err)
+		assert.Equal(t, original, reencoded)
+	}
+}
+
+func TestDecodeFromCalldataErrors(t *testing.T) {
+	_, _, err := DecodeFromCalldata([]byte{1, 2})
+	assert.Error(t, err)
+
+	_, _, err = DecodeFromCalldata([]byte{1, 2, 3, 4})
+	assert.Error(t, err)
+
+	// unsupported version
+	parent := &DABatch{Version: CodecV1Version}
+	args, err := CommitBatchArgs.Pack(uint8(0), parent.Encode(), [][]byte{{0}}, []byte{})
+	require.NoError(t, err)
+	_, _, err = DecodeFromCalldata(append(append([]byte{}, commitBatchMethodID...), args...))
+	assert.Error(t, err)
+
+	// chunk without blocks
+	args, err = CommitBatchArgs.Pack(uint8(CodecV1Version), parent.Encode(), [][]byte{{0}}, []byte{})
+	require.NoError(t, err)
+	_, _, err = DecodeFromCalldata(append(append([]byte{}, commitBatchMethodID...), args...))
+	assert.Error(t, err)
+}
+
+func TestDecodeTxsFromBlobErrors(t *testing.T) {
+	chunks := newTestChunks(t)
+	blob, _, _, err := ConstructBlobPayload(chunks)
+	require.NoError(t, err)
+
+	daChunks := make([]*DAChunk, len(chunks))
+	for i, chunk := range chunks {
+		daChunks[i], err = NewDAChunk(chunk, 0)
+		require.NoError(t, err)
+	}
+
+	// wrong number of chunks
+	assert.Error(t, DecodeTxsFromBlob(blob, daChunks[:1]))
+
+	// the block contexts claim more transactions than the blob contains
+	daChunks[1].Blocks[0].NumTransactions++
+	assert.Error(t, DecodeTxsFromBlob(blob, daChunks))
+	daChunks[1].Blocks[0].NumTransactions--
+
+	// invalid field element
+	blob[0] = 1
+	assert.Error(t, DecodeTxsFromBlob(blob, daChunks))
+}
+
+func TestCodecRegistry(t *testing.T) {
+	chainCfg := &params.ChainConfig{BernoulliBlock: big.NewInt(10)}
+
+	codec, err := encoding.CodecFromConfig(chainCfg, big.NewInt(0))
+	require.NoError(t, err)
+	assert.Equal(t, encoding.CodecV0, codec.Version())
+	codec, err = encoding.CodecFromConfig(chainCfg, big.NewInt(9))
+	require.NoError(t, err)
+	assert.Equal(t, encoding.CodecV0, codec.Version())
+	codec, err = encoding.CodecFromConfig(chainCfg, big.NewInt(10))
+	require.NoError(t, err)
+	assert.Equal(t, encoding.CodecV1, codec.Version())
+
+	_, err = encoding.CodecFromVersion(encoding.CodecVersion(100))
+	assert.Error(t, err)
+
+	chunks := newTestChunks(t)
+	batch := &encoding.Batch{Index: 1, Chunks: chunks}
+
+	daBatch, err := NewDABatch(batch)
+	require.NoError(t, err)
+	viaCodec, err := codec.NewDABatch(batch)
+	require.NoError(t, err)
+	assert.Equal(t, daBatch.Hash(), viaCodec.Hash())
+	assert.Equal(t, daBatch.Blob(), viaCodec.Blob())
+
+	decoded, err := codec.NewDABatchFromBytes(daBatch.Encode())
+	require.NoError(t, err)
+	assert.Equal(t, daBatch.Hash(), decoded.Hash())
+
+	daChunk, err := NewDAChunk(chunks[0], 0)
+	require.NoError(t, err)
+	chunkHash, err := daChunk.Hash()
+	require.NoError(t, err)
+	viaCodecHash, err := codec.ChunkHash(chunks[0], 0)
+	require.NoError(t, err)
+	assert.Equal(t, chunkHash, viaCodecHash)
+
+	decodedChunks, err := codec.DecodeDAChunks([][]byte{daChunk.Encode()})
+	require.NoError(t, err)
+	require.Len(t, decodedChunks, 1)
+	require.Len(t, decodedChunks[0].Blocks, 2)
+	assert.Equal(t, uint64(1), decodedChunks[0].Blocks[0].Header.Number.Uint64())
+	assert.Equal(t, uint64(2), decodedChunks[0].Blocks[1].Header.Number.Uint64())
+
+	blobSize, err := codec.EstimateBatchL1CommitBlobSize(batch)
+	require.NoError(t, err)
+	assert.NotZero(t, blobSize)
+
+	// codecv1 commits the L2 transactions in the blob, so the calldata is cheaper than with codecv0
+	v0, err := encoding.CodecFromVersion(encoding.CodecV0)
+	require.NoError(t, err)
+	v0Gas, err := v0.EstimateBatchL1CommitGas(batch)
+	require.NoError(t, err)
+	v1Gas, err := codec.EstimateBatchL1CommitGas(batch)
+	require.NoError(t, err)
+	assert.Less(t, v1Gas, v0Gas)
+	v0BlobSize, err := v0.EstimateBatchL1CommitBlobSize(batch)
+	require.NoError(t, err)
+	assert.Zero(t, v0BlobSize)
+
+	v0Batch, err := codecv0.NewDABatch(batch)
+	require.NoError(t, err)
+	assert.Nil(t, v0Batch.Blob())
+}
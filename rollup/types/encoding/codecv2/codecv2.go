@@ -0,0 +1,462 @@
+package codecv2
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/klauspost/compress/zstd"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/crypto"
+	"github.com/scroll-tech/go-ethereum/crypto/kzg4844"
+	"github.com/scroll-tech/go-ethereum/log"
+	"github.com/scroll-tech/go-ethereum/rollup/types/encoding"
+	"github.com/scroll-tech/go-ethereum/rollup/types/encoding/codecv1"
+)
+
+// CodecV2Version denotes the version of the codec.
+const CodecV2Version = 2
+
+// MaxNumChunks is the maximum number of chunks that a batch can contain.
+const MaxNumChunks = 45
+
+// maxBlobBytesSize is the number of bytes of a blob that can be utilized, 31 bytes of each field element.
+const maxBlobBytesSize = 4096 * 31
+
+// maxBatchBytesSize is the maximum size of the uncompressed blob payload that we are willing to decompress.
+const maxBatchBytesSize = 64 * 1024 * 1024
+
+var (
+	// zstdEncoder compresses blob payloads. The configuration is fixed, so that all nodes
+	// produce the same compressed payload, and thus the same blob, for the same batch.
+	// Note: The output also depends on the version of the zstd library, so it must only
+	// be upgraded together with a check that the existing test vectors are unchanged.
+	zstdEncoder *zstd.Encoder
+
+	// zstdDecoder decompresses blob payloads.
+	zstdDecoder *zstd.Decoder
+)
+
+func init() {
+	var err error
+	zstdEncoder, err = zstd.NewWriter(nil,
+		zstd.WithEncoderLevel(zstd.SpeedBetterCompression),
+		zstd.WithEncoderConcurrency(1),
+		zstd.WithEncoderCRC(false),
+		zstd.WithZeroFrames(true),
+		zstd.WithSingleSegment(true),
+		zstd.WithLowerEncoderMem(false),
+	)
+	if err != nil {
+		log.Crit("Failed to initialize zstd encoder", "err", err)
+	}
+
+	zstdDecoder, err = zstd.NewReader(nil,
+		zstd.WithDecoderConcurrency(1),
+		zstd.WithDecoderMaxMemory(maxBatchBytesSize),
+	)
+	if err != nil {
+		log.Crit("Failed to initialize zstd decoder", "err", err)
+	}
+}
+
+// DABlock represents a Data Availability Block.
+type DABlock = codecv1.DABlock
+
+// DAChunk groups consecutive DABlocks with their transactions.
+type DAChunk = codecv1.DAChunk
+
+// DABatch contains metadata about a batch of DAChunks.
+type DABatch struct {
+	// header
+	Version                uint8
+	BatchIndex             uint64
+	L1MessagePopped        uint64
+	TotalL1MessagePopped   uint64
+	DataHash               common.Hash
+	BlobVersionedHash      common.Hash
+	ParentBatchHash        common.Hash
+	SkippedL1MessageBitmap []byte
+
+	// blob payload
+	blob *kzg4844.Blob
+	z    *kzg4844.Point
+}
+
+// NewDABlock creates a new DABlock from the given encoding.Block and the total number of L1 messages popped before.
+func NewDABlock(block *encoding.Block, totalL1MessagePoppedBefore uint64) (*DABlock, error) {
+	return codecv1.NewDABlock(block, totalL1MessagePoppedBefore)
+}
+
+// NewDAChunk creates a new DAChunk from the given encoding.Chunk and the total number of L1 messages popped before.
+func NewDAChunk(chunk *encoding.Chunk, totalL1MessagePoppedBefore uint64) (*DAChunk, error) {
+	return codecv1.NewDAChunk(chunk, totalL1MessagePoppedBefore)
+}
+
+// NewDABatch creates a DABatch from the provided encoding.Batch.
+func NewDABatch(batch *encoding.Batch) (*DABatch, error) {
+	// this encoding can only support a fixed number of chunks per batch
+	if len(batch.Chunks) > MaxNumChunks {
+		return nil, fmt.Errorf("too many chunks in batch")
+	}
+
+	if len(batch.Chunks) == 0 {
+		return nil, fmt.Errorf("too few chunks in batch")
+	}
+
+	// batch data hash
+	dataHash, err := computeBatchDataHash(batch.Chunks, batch.TotalL1MessagePoppedBefore)
+	if err != nil {
+		return nil, err
+	}
+
+	// skipped L1 messages bitmap
+	bitmapBytes, totalL1MessagePoppedAfter, err := encoding.ConstructSkippedBitmap(batch.Index, batch.Chunks, batch.TotalL1MessagePoppedBefore)
+	if err != nil {
+		return nil, err
+	}
+
+	// blob payload
+	blob, blobVersionedHash, z, err := ConstructBlobPayload(batch.Chunks)
+	if err != nil {
+		return nil, err
+	}
+
+	daBatch := DABatch{
+		Version:                CodecV2Version,
+		BatchIndex:             batch.Index,
+		L1MessagePopped:        totalL1MessagePoppedAfter - batch.TotalL1MessagePoppedBefore,
+		TotalL1MessagePopped:   totalL1MessagePoppedAfter,
+		DataHash:               dataHash,
+		BlobVersionedHash:      blobVersionedHash,
+		ParentBatchHash:        batch.ParentBatchHash,
+		SkippedL1MessageBitmap: bitmapBytes,
+		blob:                   blob,
+		z:                      z,
+	}
+
+	return &daBatch, nil
+}
+
+// computeBatchDataHash computes the data hash of the batch.
+// Note: The batch hash and batch data hash are two different hashes,
+// the former is used for identifying a badge in the contracts,
+// the latter is used in the public input to the provers.
+func computeBatchDataHash(chunks []*encoding.Chunk, totalL1MessagePoppedBefore uint64) (common.Hash, error) {
+	var dataBytes []byte
+	totalL1MessagePoppedBeforeChunk := totalL1MessagePoppedBefore
+
+	for _, chunk := range chunks {
+		daChunk, err := NewDAChunk(chunk, totalL1MessagePoppedBeforeChunk)
+		if err != nil {
+			return common.Hash{}, err
+		}
+		totalL1MessagePoppedBeforeChunk += chunk.NumL1Messages(totalL1MessagePoppedBeforeChunk)
+		chunkHash, err := daChunk.Hash()
+		if err != nil {
+			return common.Hash{}, err
+		}
+		dataBytes = append(dataBytes, chunkHash.Bytes()...)
+	}
+
+	dataHash := crypto.Keccak256Hash(dataBytes)
+	return dataHash, nil
+}
+
+// ConstructBlobPayload constructs the 4844 blob payload.
+//
+// The uncompressed payload has the same layout as in codecv1, but with metadata for MaxNumChunks chunks.
+// The blob contains the size of the compressed payload (4 bytes) followed by the compressed payload.
+func ConstructBlobPayload(chunks []*encoding.Chunk) (*kzg4844.Blob, common.Hash, *kzg4844.Point, error) {
+	// the raw (un-padded) and uncompressed blob payload
+	batchBytes, err := constructBatchBytes(chunks)
+	if err != nil {
+		return nil, common.Hash{}, nil, err
+	}
+
+	// challenge digest preimage
+	// 1 hash for metadata, 1 hash for each chunk, 1 hash for blob versioned hash
+	challengePreimage := make([]byte, (1+MaxNumChunks+1)*32)
+
+	// challenge: compute metadata hash
+	metadataLength := 2 + MaxNumChunks*4
+	hash := crypto.Keccak256Hash(batchBytes[0:metadataLength])
+	copy(challengePreimage[0:], hash[:])
+
+	// challenge: compute chunk data hashes
+	var chunkDataHash common.Hash
+	currentChunkStartIndex := metadataLength
+	for chunkID := range chunks {
+		chunkSize := int(binary.BigEndian.Uint32(batchBytes[2+4*chunkID:]))
+		chunkDataHash = crypto.Keccak256Hash(batchBytes[currentChunkStartIndex : currentChunkStartIndex+chunkSize])
+		copy(challengePreimage[32+chunkID*32:], chunkDataHash[:])
+		currentChunkStartIndex += chunkSize
+	}
+
+	// if we have fewer than MaxNumChunks chunks, we need to add padding to the challenge preimage
+	for chunkID := len(chunks); chunkID < MaxNumChunks; chunkID++ {
+		// use the last chunk's data hash as padding
+		copy(challengePreimage[32+chunkID*32:], chunkDataHash[:])
+	}
+
+	// compress the blob payload
+	blobBytes, err := compressBatchBytes(batchBytes)
+	if err != nil {
+		return nil, common.Hash{}, nil, err
+	}
+
+	// convert raw data to BLSFieldElements
+	blob, err := codecv1.MakeBlobCanonical(blobBytes)
+	if err != nil {
+		return nil, common.Hash{}, nil, err
+	}
+
+	// compute blob versioned hash
+	c, err := kzg4844.BlobToCommitment(blob)
+	if err != nil {
+		return nil, common.Hash{}, nil, fmt.Errorf("failed to create blob commitment")
+	}
+	blobVersionedHash := kzg4844.CalcBlobHashV1(sha256.New(), &c)
+
+	// challenge: append blob versioned hash
+	copy(challengePreimage[(1+MaxNumChunks)*32:], blobVersionedHash[:])
+
+	// compute z = challenge_digest % BLS_MODULUS
+	challengeDigest := crypto.Keccak256Hash(challengePreimage)
+	pointBigInt := new(big.Int).Mod(new(big.Int).SetBytes(challengeDigest[:]), codecv1.BLSModulus)
+	pointBytes := pointBigInt.Bytes()
+
+	// the challenge point z
+	var z kzg4844.Point
+	start := 32 - len(pointBytes)
+	copy(z[start:], pointBytes)
+
+	return blob, blobVersionedHash, &z, nil
+}
+
+// constructBatchBytes constructs the uncompressed blob payload, which consists of the
+// metadata, num_chunks (2 bytes) and chunki_size (4 bytes per chunk), and the L2 transactions.
+func constructBatchBytes(chunks []*encoding.Chunk) ([]byte, error) {
+	if len(chunks) > MaxNumChunks {
+		return nil, fmt.Errorf("too many chunks in batch")
+	}
+
+	metadataLength := 2 + MaxNumChunks*4
+	batchBytes := make([]byte, metadataLength)
+
+	// blob metadata: num_chunks
+	binary.BigEndian.PutUint16(batchBytes[0:], uint16(len(chunks)))
+
+	for chunkID, chunk := range chunks {
+		currentChunkStartIndex := len(batchBytes)
+
+		for _, block := range chunk.Blocks {
+			for _, tx := range block.Transactions {
+				if tx.Type != types.L1MessageTxType {
+					// encode L2 txs into blob payload
+					rlpTxData, err := encoding.ConvertTxDataToRLPEncoding(tx)
+					if err != nil {
+						return nil, err
+					}
+					batchBytes = append(batchBytes, rlpTxData...)
+				}
+			}
+		}
+
+		// blob metadata: chunki_size
+		binary.BigEndian.PutUint32(batchBytes[2+4*chunkID:], uint32(len(batchBytes)-currentChunkStartIndex))
+	}
+
+	return batchBytes, nil
+}
+
+// compressBatchBytes compresses the uncompressed blob payload and prepends the compressed size.
+// It checks that the compressed payload decompresses into the original payload, so that we
+// never commit a blob that the provers and the followers cannot decode.
+func compressBatchBytes(batchBytes []byte) ([]byte, error) {
+	compressed := zstdEncoder.EncodeAll(batchBytes, nil)
+	if len(compressed) > maxBlobBytesSize-4 {
+		return nil, fmt.Errorf("oversized batch payload, compressed size: %d", len(compressed))
+	}
+
+	if err := checkCompressedDataCompatibility(compressed, batchBytes); err != nil {
+		return nil, err
+	}
+
+	blobBytes := make([]byte, 4+len(compressed))
+	binary.BigEndian.PutUint32(blobBytes[0:], uint32(len(compressed)))
+	copy(blobBytes[4:], compressed)
+	return blobBytes, nil
+}
+
+// checkCompressedDataCompatibility checks that the compressed payload is a single zstd frame
+// that decompresses into the uncompressed payload.
+func checkCompressedDataCompatibility(compressed, batchBytes []byte) error {
+	var header zstd.Header
+	if err := header.Decode(compressed); err != nil {
+		return fmt.Errorf("failed to decode zstd frame header: %w", err)
+	}
+	if !header.SingleSegment || header.HasCheckSum {
+		return fmt.Errorf("unexpected zstd frame header, single segment: %v, checksum: %v", header.SingleSegment, header.HasCheckSum)
+	}
+
+	decompressed, err := decompressBatchBytes(compressed)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(decompressed, batchBytes) {
+		return errors.New("compressed blob payload does not decompress into the original payload")
+	}
+	return nil
+}
+
+// decompressBatchBytes decompresses a compressed blob payload.
+func decompressBatchBytes(compressed []byte) ([]byte, error) {
+	decompressed, err := zstdDecoder.DecodeAll(compressed, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress blob payload: %w", err)
+	}
+	return decompressed, nil
+}
+
+// NewDABatchFromBytes attempts to decode the given byte slice into a DABatch.
+// Note: This function only populates the batch header, it leaves the blob-related fields empty.
+func NewDABatchFromBytes(data []byte) (*DABatch, error) {
+	if len(data) < 121 {
+		return nil, fmt.Errorf("insufficient data for DABatch, expected at least 121 bytes but got %d", len(data))
+	}
+
+	b := &DABatch{
+		Version:                data[0],
+		BatchIndex:             binary.BigEndian.Uint64(data[1:9]),
+		L1MessagePopped:        binary.BigEndian.Uint64(data[9:17]),
+		TotalL1MessagePopped:   binary.BigEndian.Uint64(data[17:25]),
+		DataHash:               common.BytesToHash(data[25:57]),
+		BlobVersionedHash:      common.BytesToHash(data[57:89]),
+		ParentBatchHash:        common.BytesToHash(data[89:121]),
+		SkippedL1MessageBitmap: data[121:],
+	}
+
+	return b, nil
+}
+
+// Encode serializes the DABatch into bytes.
+func (b *DABatch) Encode() []byte {
+	batchBytes := make([]byte, 121+len(b.SkippedL1MessageBitmap))
+	batchBytes[0] = b.Version
+	binary.BigEndian.PutUint64(batchBytes[1:], b.BatchIndex)
+	binary.BigEndian.PutUint64(batchBytes[9:], b.L1MessagePopped)
+	binary.BigEndian.PutUint64(batchBytes[17:], b.TotalL1MessagePopped)
+	copy(batchBytes[25:], b.DataHash[:])
+	copy(batchBytes[57:], b.BlobVersionedHash[:])
+	copy(batchBytes[89:], b.ParentBatchHash[:])
+	copy(batchBytes[121:], b.SkippedL1MessageBitmap[:])
+	return batchBytes
+}
+
+// Hash computes the hash of the serialized DABatch.
+func (b *DABatch) Hash() common.Hash {
+	bytes := b.Encode()
+	return crypto.Keccak256Hash(bytes)
+}
+
+// BlobDataProof computes the abi-encoded blob verification data.
+func (b *DABatch) BlobDataProof() ([]byte, error) {
+	if b.blob == nil {
+		return nil, errors.New("called BlobDataProof with empty blob")
+	}
+	if b.z == nil {
+		return nil, errors.New("called BlobDataProof with empty z")
+	}
+
+	commitment, err := kzg4844.BlobToCommitment(b.blob)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create blob commitment")
+	}
+
+	proof, y, err := kzg4844.ComputeProof(b.blob, *b.z)
+	if err != nil {
+		log.Crit("failed to create KZG proof at point", "err", err, "z", hex.EncodeToString(b.z[:]))
+	}
+
+	// Memory layout of ``_blobDataProof``:
+	// | z       | y       | kzg_commitment | kzg_proof |
+	// |---------|---------|----------------|-----------|
+	// | bytes32 | bytes32 | bytes48        | bytes48   |
+
+	values := []interface{}{*b.z, y, commitment, proof}
+	return codecv1.BlobDataProofArgs.Pack(values...)
+}
+
+// Blob returns the blob of the batch.
+func (b *DABatch) Blob() *kzg4844.Blob {
+	return b.blob
+}
+
+// DecodeTxsFromBlob decodes the L2 transactions of the given chunks from the blob payload,
+// and fills in the transactions of each chunk. L1 messages are not part of the blob.
+func DecodeTxsFromBlob(blob *kzg4844.Blob, chunks []*DAChunk) error {
+	blobBytes, err := codecv1.BytesFromBlobCanonical(blob)
+	if err != nil {
+		return err
+	}
+
+	compressedSize := int(binary.BigEndian.Uint32(blobBytes[0:4]))
+	if compressedSize > len(blobBytes)-4 {
+		return fmt.Errorf("compressed blob payload size %d exceeds the blob", compressedSize)
+	}
+
+	batchBytes, err := decompressBatchBytes(blobBytes[4 : 4+compressedSize])
+	if err != nil {
+		return err
+	}
+	return codecv1.DecodeTxsFromBytes(batchBytes, chunks, MaxNumChunks)
+}
+
+// EstimateChunkL1CommitBlobSize estimates the size of the L1 commit blob for a single chunk.
+func EstimateChunkL1CommitBlobSize(c *encoding.Chunk) (uint64, error) {
+	return estimateBlobSize([]*encoding.Chunk{c})
+}
+
+// EstimateBatchL1CommitBlobSize estimates the total size of the L1 commit blob for a batch.
+func EstimateBatchL1CommitBlobSize(b *encoding.Batch) (uint64, error) {
+	return estimateBlobSize(b.Chunks)
+}
+
+// estimateBlobSize compresses the blob payload of the given chunks, as the compressed size cannot be
+// estimated from the uncompressed size, and returns the size of the canonical (padded) blob data.
+func estimateBlobSize(chunks []*encoding.Chunk) (uint64, error) {
+	batchBytes, err := constructBatchBytes(chunks)
+	if err != nil {
+		return 0, err
+	}
+	blobDataSize := uint64(4 + len(zstdEncoder.EncodeAll(batchBytes, nil)))
+	paddedSize := ((blobDataSize + 30) / 31) * 32
+	return paddedSize, nil
+}
+
+// EstimateChunkL1CommitCalldataSize calculates the calldata size needed for committing a chunk to L1 approximately.
+func EstimateChunkL1CommitCalldataSize(c *encoding.Chunk) (uint64, error) {
+	return codecv1.EstimateChunkL1CommitCalldataSize(c)
+}
+
+// EstimateChunkL1CommitGas calculates the total L1 commit gas for this chunk approximately.
+func EstimateChunkL1CommitGas(c *encoding.Chunk) (uint64, error) {
+	return codecv1.EstimateChunkL1CommitGas(c)
+}
+
+// EstimateBatchL1CommitGas calculates the total L1 commit gas for this batch approximately.
+func EstimateBatchL1CommitGas(b *encoding.Batch) (uint64, error) {
+	return codecv1.EstimateBatchL1CommitGas(b)
+}
+
+// EstimateBatchL1CommitCalldataSize calculates the calldata size in l1 commit for this batch approximately.
+func EstimateBatchL1CommitCalldataSize(b *encoding.Batch) (uint64, error) {
+	return codecv1.EstimateBatchL1CommitCalldataSize(b)
+}
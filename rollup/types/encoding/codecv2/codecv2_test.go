@@ -0,0 +1,199 @@
+package codecv2
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/crypto"
+	"github.com/scroll-tech/go-ethereum/crypto/kzg4844"
+	"github.com/scroll-tech/go-ethereum/params"
+	"github.com/scroll-tech/go-ethereum/rollup/types/encoding"
+	"github.com/scroll-tech/go-ethereum/rollup/types/encoding/codecv1"
+)
+
+// newTestChunks creates chunks with one L1 message and numTxs similar L2 transactions per block.
+func newTestChunks(t *testing.T, numChunks, numTxs int, data []byte) []*encoding.Chunk {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	signer := types.NewLondonSigner(big.NewInt(534352))
+	to := common.HexToAddress("0x1234")
+
+	var chunks []*encoding.Chunk
+	var nonce, queueIndex uint64
+	for i := 0; i < numChunks; i++ {
+		txs := types.Transactions{types.NewTx(&types.L1MessageTx{QueueIndex: queueIndex, Gas: 100000, To: &to, Value: common.Big0, Sender: to})}
+		queueIndex++
+		for j := 0; j < numTxs; j++ {
+			tx, err := types.SignNewTx(key, signer, &types.DynamicFeeTx{ChainID: big.NewInt(534352), Nonce: nonce, To: &to, Gas: 100000, GasTipCap: big.NewInt(1), GasFeeCap: big.NewInt(2000), Data: data})
+			require.NoError(t, err)
+			nonce++
+			txs = append(txs, tx)
+		}
+		block := &encoding.Block{
+			Header:       &types.Header{Number: big.NewInt(int64(i + 1)), Time: uint64(1700000000 + i), BaseFee: big.NewInt(1000), GasLimit: 10000000},
+			Transactions: encoding.TxsToTxsData(txs),
+		}
+		chunks = append(chunks, &encoding.Chunk{Blocks: []*encoding.Block{block}})
+	}
+	return chunks
+}
+
+func TestBlobPayloadRoundTrip(t *testing.T) {
+	chunks := newTestChunks(t, 3, 20, make([]byte, 200))
+
+	blob, versionedHash, z, err := ConstructBlobPayload(chunks)
+	require.NoError(t, err)
+
+	// the payload is deterministic
+	blob2, versionedHash2, z2, err := ConstructBlobPayload(chunks)
+	require.NoError(t, err)
+	assert.Equal(t, blob, blob2)
+	assert.Equal(t, versionedHash, versionedHash2)
+	assert.Equal(t, z, z2)
+
+	daChunks := make([]*DAChunk, len(chunks))
+	for i, chunk := range chunks {
+		daChunk, err := NewDAChunk(chunk, uint64(i))
+		require.NoError(t, err)
+		daChunks[i], err = codecv1.DecodeDAChunk(daChunk.Encode())
+		require.NoError(t, err)
+	}
+	require.NoError(t, DecodeTxsFromBlob(blob, daChunks))
+	for i, chunk := range chunks {
+		var l2TxHashes []string
+		for _, tx := range chunk.Blocks[0].Transactions[1:] {
+			l2TxHashes = append(l2TxHashes, tx.TxHash)
+		}
+		var decodedTxHashes []string
+		for _, tx := range daChunks[i].Transactions[0] {
+			decodedTxHashes = append(decodedTxHashes, tx.TxHash)
+		}
+		assert.Equal(t, l2TxHashes, decodedTxHashes)
+	}
+
+	// similar transactions compress well
+	blobV1, _, _, err := codecv1.ConstructBlobPayload(chunks)
+	require.NoError(t, err)
+	assert.Less(t, 4+compressedSize(t, blob), usedBytes(blobV1)/2)
+
+	// a corrupted compressed size is detected
+	blob[1] = 0xff
+	assert.Error(t, DecodeTxsFromBlob(blob, daChunks))
+}
+
+func TestNewDABatch(t *testing.T) {
+	chunks := newTestChunks(t, 2, 5, []byte{1, 2, 3})
+	batch := &encoding.Batch{Index: 10, TotalL1MessagePoppedBefore: 0, ParentBatchHash: common.Hash{1}, Chunks: chunks}
+
+	daBatch, err := NewDABatch(batch)
+	require.NoError(t, err)
+	assert.Equal(t, uint8(CodecV2Version), daBatch.Version)
+	assert.Equal(t, uint64(2), daBatch.L1MessagePopped)
+	assert.NotNil(t, daBatch.Blob())
+
+	decoded, err := NewDABatchFromBytes(daBatch.Encode())
+	require.NoError(t, err)
+	assert.Equal(t, daBatch.Hash(), decoded.Hash())
+	assert.Equal(t, daBatch.BlobVersionedHash, decoded.BlobVersionedHash)
+
+	proof, err := daBatch.BlobDataProof()
+	require.NoError(t, err)
+	assert.Len(t, proof, 32+32+48+48)
+
+	// the data hash does not depend on the blob encoding
+	daBatchV1, err := codecv1.NewDABatch(batch)
+	require.NoError(t, err)
+	assert.Equal(t, daBatchV1.DataHash, daBatch.DataHash)
+
+	_, err = NewDABatch(&encoding.Batch{Chunks: make([]*encoding.Chunk, MaxNumChunks+1)})
+	assert.Error(t, err)
+}
+
+func TestOversizedPayload(t *testing.T) {
+	// random data does not compress
+	var chunks []*encoding.Chunk
+	for i := 0; i < 14; i++ {
+		data := make([]byte, 10000)
+		_, err := rand.Read(data)
+		require.NoError(t, err)
+		chunks = append(chunks, newTestChunks(t, 1, 1, data)...)
+	}
+
+	_, _, _, err := ConstructBlobPayload(chunks)
+	assert.Error(t, err)
+
+	// the blob is not oversized without the last chunks
+	_, _, _, err = ConstructBlobPayload(chunks[:10])
+	assert.NoError(t, err)
+}
+
+func TestEstimateBlobSize(t *testing.T) {
+	chunks := newTestChunks(t, 3, 20, make([]byte, 200))
+	batch := &encoding.Batch{Chunks: chunks}
+
+	blob, _, _, err := ConstructBlobPayload(chunks)
+	require.NoError(t, err)
+
+	estimate, err := EstimateBatchL1CommitBlobSize(batch)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(4+compressedSize(t, blob)+30)/31*32, estimate)
+
+	chunkEstimate, err := EstimateChunkL1CommitBlobSize(chunks[0])
+	require.NoError(t, err)
+	assert.Less(t, chunkEstimate, estimate)
+
+	codec, err := encoding.CodecFromVersion(encoding.CodecV2)
+	require.NoError(t, err)
+	viaCodec, err := codec.EstimateBatchL1CommitBlobSize(batch)
+	require.NoError(t, err)
+	assert.Equal(t, estimate, viaCodec)
+}
+
+func TestCodecFromConfig(t *testing.T) {
+	chainCfg := &params.ChainConfig{BernoulliBlock: big.NewInt(10), DarwinBlock: big.NewInt(20)}
+	assert.Equal(t, encoding.CodecV1, encoding.CodecVersionFromConfig(chainCfg, big.NewInt(19)))
+	assert.Equal(t, encoding.CodecV2, encoding.CodecVersionFromConfig(chainCfg, big.NewInt(20)))
+
+	codec, err := encoding.CodecFromConfig(chainCfg, big.NewInt(20))
+	require.NoError(t, err)
+	assert.Equal(t, encoding.CodecV2, codec.Version())
+}
+
+// compressedSize returns the size of the compressed payload in the blob.
+func compressedSize(t *testing.T, blob *kzg4844.Blob) int {
+	blobBytes, err := codecv1.BytesFromBlobCanonical(blob)
+	require.NoError(t, err)
+	return int(binary.BigEndian.Uint32(blobBytes[0:4]))
+}
+
+// usedBytes returns the length of the blob up to the last non-zero byte.
+func usedBytes(blob *kzg4844.Blob) int {
+	for i := len(blob) - 1; i >= 0; i-- {
+		if blob[i] != 0 {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+func TestCompressionTestVector(t *testing.T) {
+	// a fixed payload with some repetition
+	batchBytes := make([]byte, 2+MaxNumChunks*4)
+	for i := 0; i < 1000; i++ {
+		batchBytes = append(batchBytes, byte(i%7), byte(i/3), 0, 0, 0xff)
+	}
+
+	blobBytes, err := compressBatchBytes(batchBytes)
+	require.NoError(t, err)
+
+	// changes of the compressed output break the determinism of blobs across node versions
+	assert.Equal(t, "0xb5ca81bcad83dd4a92c6aa977a3fa2a3906ac6505a294266f476c6edddcdf2c1", crypto.Keccak256Hash(blobBytes).Hex())
+	assert.Len(t, blobBytes, 2233)
+}
@@ -17,6 +17,9 @@ const (
 
 	// CodecV1 represents the version 1 of the encoder and decoder.
 	CodecV1
+
+	// CodecV2 represents the version 2 of the encoder and decoder.
+	CodecV2
 )
 
 // Block represents an L2 block.
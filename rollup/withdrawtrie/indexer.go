@@ -0,0 +1,192 @@
+package withdrawtrie
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/core/rawdb"
+	"github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/crypto"
+	"github.com/scroll-tech/go-ethereum/ethdb"
+	"github.com/scroll-tech/go-ethereum/log"
+	"github.com/scroll-tech/go-ethereum/rollup/rcfg"
+)
+
+const (
+	// defaultIndexInterval is the frequency at which we index new blocks.
+	defaultIndexInterval = 5 * time.Second
+
+	// defaultProgressInterval is the number of blocks after which the indexing progress is stored while catching up.
+	defaultProgressInterval = 1000
+)
+
+// AppendMessageEventSignature is the topic of `AppendMessage(uint256 index, bytes32 messageHash)`
+// emitted by the L2MessageQueue contract.
+var AppendMessageEventSignature = crypto.Keccak256Hash([]byte("AppendMessage(uint256,bytes32)"))
+
+// BlockChain is the subset of core.BlockChain used by the indexer.
+type BlockChain interface {
+	CurrentBlock() *types.Block
+	GetBlockByNumber(number uint64) *types.Block
+	GetReceiptsByHash(hash common.Hash) types.Receipts
+}
+
+// Indexer follows the local canonical chain and appends the messages of the L2MessageQueue
+// contract to the withdraw trie stored in the database.
+type Indexer struct {
+	ctx      context.Context
+	cancel   context.CancelFunc
+	db       ethdb.Database
+	bc       BlockChain
+	progress rawdb.WithdrawTrieProgress
+}
+
+// NewIndexer creates a new withdraw trie indexer that continues after the indexing progress in the database.
+func NewIndexer(ctx context.Context, db ethdb.Database, bc BlockChain) *Indexer {
+	ctx, cancel := context.WithCancel(ctx)
+
+	ix := &Indexer{
+		ctx:    ctx,
+		cancel: cancel,
+		db:     db,
+		bc:     bc,
+	}
+	if progress := rawdb.ReadWithdrawTrieProgress(db); progress != nil {
+		ix.progress = *progress
+	} else if genesis := bc.GetBlockByNumber(0); genesis != nil {
+		ix.progress.BlockHash = genesis.Hash()
+	}
+	return ix
+}
+
+func (ix *Indexer) Start() {
+	if ix == nil {
+		return
+	}
+
+	log.Info("Starting withdraw trie indexer", "last indexed block", ix.progress.BlockNumber, "messages", ix.progress.NumMessages)
+
+	go func() {
+		ticker := time.NewTicker(defaultIndexInterval)
+		defer ticker.Stop()
+
+		for {
+			if err := ix.index(); err != nil {
+				log.Error("failed to index withdraw trie", "err", err)
+			}
+
+			select {
+			case <-ix.ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func (ix *Indexer) Stop() {
+	if ix == nil {
+		return
+	}
+
+	log.Info("Stopping withdraw trie indexer")
+
+	if ix.cancel != nil {
+		ix.cancel()
+	}
+}
+
+// index appends the messages of the blocks up to the current head.
+func (ix *Indexer) index() error {
+	ix.handleReorg()
+
+	head := ix.bc.CurrentBlock().NumberU64()
+	for number := ix.progress.BlockNumber + 1; number <= head && ix.ctx.Err() == nil; number++ {
+		block := ix.bc.GetBlockByNumber(number)
+		if block == nil {
+			break
+		}
+		if block.ParentHash() != ix.progress.BlockHash {
+			// the chain reorged while indexing, continue on the next run
+			break
+		}
+		if err := ix.indexBlock(block); err != nil {
+			rawdb.WriteWithdrawTrieProgress(ix.db, &ix.progress)
+			return fmt.Errorf("failed to index block %d, err: %w", number, err)
+		}
+		if number%defaultProgressInterval == 0 {
+			rawdb.WriteWithdrawTrieProgress(ix.db, &ix.progress)
+		}
+	}
+	rawdb.WriteWithdrawTrieProgress(ix.db, &ix.progress)
+	return nil
+}
+
+// indexBlock appends the messages of a block.
+func (ix *Indexer) indexBlock(block *types.Block) error {
+	numMessages := ix.progress.NumMessages
+	for _, receipt := range ix.bc.GetReceiptsByHash(block.Hash()) {
+		for _, vLog := range receipt.Logs {
+			if vLog.Address != rcfg.L2MessageQueueAddress || len(vLog.Topics) == 0 || vLog.Topics[0] != AppendMessageEventSignature {
+				continue
+			}
+			if len(vLog.Data) != 2*common.HashLength {
+				return fmt.Errorf("invalid AppendMessage event data, tx hash: %s, data: %x", vLog.TxHash.Hex(), vLog.Data)
+			}
+			index := new(big.Int).SetBytes(vLog.Data[:common.HashLength])
+			if !index.IsUint64() || index.Uint64() != numMessages {
+				return fmt.Errorf("unexpected withdraw message index, expected: %d, got: %v, tx hash: %s", numMessages, index, vLog.TxHash.Hex())
+			}
+			msg := &rawdb.WithdrawMessage{
+				MessageHash: common.BytesToHash(vLog.Data[common.HashLength:]),
+				BlockNumber: block.NumberU64(),
+				BlockHash:   block.Hash(),
+			}
+			if err := AppendMessage(ix.db, numMessages, msg); err != nil {
+				return err
+			}
+			numMessages++
+		}
+	}
+
+	ix.progress.BlockNumber = block.NumberU64()
+	ix.progress.BlockHash = block.Hash()
+	ix.progress.NumMessages = numMessages
+	return nil
+}
+
+// handleReorg rewinds the withdraw trie to the last message that is still in the canonical chain.
+func (ix *Indexer) handleReorg() {
+	if block := ix.bc.GetBlockByNumber(ix.progress.BlockNumber); block != nil && block.Hash() == ix.progress.BlockHash {
+		return
+	}
+
+	numMessages := ix.progress.NumMessages
+	for numMessages > 0 {
+		msg := rawdb.ReadWithdrawMessage(ix.db, numMessages-1)
+		if msg != nil {
+			if block := ix.bc.GetBlockByNumber(msg.BlockNumber); block != nil && block.Hash() == msg.BlockHash {
+				break
+			}
+		}
+		numMessages--
+	}
+
+	// all messages of the block of the last kept message are kept, continue after that block
+	progress := rawdb.WithdrawTrieProgress{NumMessages: numMessages}
+	if numMessages > 0 {
+		msg := rawdb.ReadWithdrawMessage(ix.db, numMessages-1)
+		progress.BlockNumber = msg.BlockNumber
+		progress.BlockHash = msg.BlockHash
+	} else if genesis := ix.bc.GetBlockByNumber(0); genesis != nil {
+		progress.BlockHash = genesis.Hash()
+	}
+
+	log.Warn("Rewinding withdraw trie after reorg", "old block", ix.progress.BlockNumber, "old messages", ix.progress.NumMessages, "new block", progress.BlockNumber, "new messages", progress.NumMessages)
+	Truncate(ix.db, progress.NumMessages, ix.progress.NumMessages)
+	ix.progress = progress
+	rawdb.WriteWithdrawTrieProgress(ix.db, &progress)
+}
@@ -0,0 +1,116 @@
+package withdrawtrie
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/core/rawdb"
+	"github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/rollup/rcfg"
+)
+
+// testChain is an in-memory chain whose receipts are set per block.
+type testChain struct {
+	blocks   []*types.Block
+	receipts map[common.Hash]types.Receipts
+}
+
+func newTestChain() *testChain {
+	genesis := types.NewBlockWithHeader(&types.Header{Number: big.NewInt(0)})
+	return &testChain{blocks: []*types.Block{genesis}, receipts: make(map[common.Hash]types.Receipts)}
+}
+
+// addBlock appends a block that emits an AppendMessage event for each of the message hashes.
+// The salt distinguishes blocks with the same number on different forks.
+func (bc *testChain) addBlock(firstIndex uint64, salt byte, messageHashes ...common.Hash) {
+	parent := bc.blocks[len(bc.blocks)-1]
+	header := &types.Header{ParentHash: parent.Hash(), Number: new(big.Int).Add(parent.Number(), common.Big1), Extra: []byte{salt}}
+	block := types.NewBlockWithHeader(header)
+
+	receipt := &types.Receipt{}
+	// events of other contracts are ignored
+	receipt.Logs = append(receipt.Logs, &types.Log{Address: common.HexToAddress("0x1234"), Topics: []common.Hash{AppendMessageEventSignature}})
+	for i, hash := range messageHashes {
+		index := common.BigToHash(new(big.Int).SetUint64(firstIndex + uint64(i)))
+		receipt.Logs = append(receipt.Logs, &types.Log{
+			Address: rcfg.L2MessageQueueAddress,
+			Topics:  []common.Hash{AppendMessageEventSignature},
+			Data:    append(index.Bytes(), hash.Bytes()...),
+		})
+	}
+	bc.blocks = append(bc.blocks, block)
+	bc.receipts[block.Hash()] = types.Receipts{receipt}
+}
+
+func (bc *testChain) CurrentBlock() *types.Block { return bc.blocks[len(bc.blocks)-1] }
+
+func (bc *testChain) GetBlockByNumber(number uint64) *types.Block {
+	if number >= uint64(len(bc.blocks)) {
+		return nil
+	}
+	return bc.blocks[number]
+}
+
+func (bc *testChain) GetReceiptsByHash(hash common.Hash) types.Receipts { return bc.receipts[hash] }
+
+func TestIndexer(t *testing.T) {
+	db := rawdb.NewMemoryDatabase()
+	bc := newTestChain()
+	ref := &contractTrie{}
+
+	for i := uint64(0); i < 10; i++ {
+		bc.addBlock(2*i, 0, messageHash(2*i), messageHash(2*i+1))
+		ref.append(messageHash(2 * i))
+		ref.append(messageHash(2*i + 1))
+	}
+	bc.addBlock(0, 0)
+
+	ix := NewIndexer(context.Background(), db, bc)
+	require.NoError(t, ix.index())
+	progress := rawdb.ReadWithdrawTrieProgress(db)
+	require.NotNil(t, progress)
+	assert.Equal(t, uint64(11), progress.BlockNumber)
+	assert.Equal(t, uint64(20), progress.NumMessages)
+	root, err := Root(db, progress.NumMessages)
+	require.NoError(t, err)
+	assert.Equal(t, ref.messageRoot, root)
+	assert.Equal(t, uint64(7), *rawdb.ReadWithdrawMessageIndex(db, messageHash(7)))
+
+	// reorg the last 4 blocks, the messages of the new blocks replace the old ones
+	bc.blocks = bc.blocks[:8]
+	ref = &contractTrie{}
+	for i := uint64(0); i < 14; i++ {
+		ref.append(messageHash(i))
+	}
+	bc.addBlock(14, 1, messageHash(100))
+	bc.addBlock(15, 1)
+	bc.addBlock(15, 1, messageHash(101), messageHash(102))
+	ref.append(messageHash(100))
+	ref.append(messageHash(101))
+	ref.append(messageHash(102))
+
+	// a restarted indexer continues from the stored progress
+	ix = NewIndexer(context.Background(), db, bc)
+	require.NoError(t, ix.index())
+	progress = rawdb.ReadWithdrawTrieProgress(db)
+	assert.Equal(t, uint64(10), progress.BlockNumber)
+	assert.Equal(t, bc.CurrentBlock().Hash(), progress.BlockHash)
+	assert.Equal(t, uint64(17), progress.NumMessages)
+	root, err = Root(db, progress.NumMessages)
+	require.NoError(t, err)
+	assert.Equal(t, ref.messageRoot, root)
+	assert.Nil(t, rawdb.ReadWithdrawMessageIndex(db, messageHash(15)))
+	assert.Nil(t, rawdb.ReadWithdrawMessage(db, 17))
+
+	// an unexpected message index stops indexing at the previous block
+	bc.addBlock(20, 0, messageHash(200))
+	assert.Error(t, ix.index())
+	progress = rawdb.ReadWithdrawTrieProgress(db)
+	assert.Equal(t, uint64(10), progress.BlockNumber)
+	assert.Equal(t, uint64(17), progress.NumMessages)
+}
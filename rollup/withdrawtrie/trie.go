@@ -0,0 +1,159 @@
+package withdrawtrie
+
+import (
+	"fmt"
+	"math/bits"
+	"sort"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/core/rawdb"
+	"github.com/scroll-tech/go-ethereum/crypto"
+	"github.com/scroll-tech/go-ethereum/ethdb"
+)
+
+// MaxHeight is the maximum height of the withdraw trie,
+// i.e., `MAX_TREE_HEIGHT` in contracts/src/libraries/common/AppendOnlyMerkleTree.sol
+const MaxHeight = 40
+
+// zeroHashes[h] is the hash of an empty subtree of height h.
+var zeroHashes [MaxHeight + 1]common.Hash
+
+func init() {
+	for h := 1; h <= MaxHeight; h++ {
+		zeroHashes[h] = hashPair(zeroHashes[h-1], zeroHashes[h-1])
+	}
+}
+
+func hashPair(left, right common.Hash) common.Hash {
+	return crypto.Keccak256Hash(left.Bytes(), right.Bytes())
+}
+
+// The withdraw trie is stored as the message hashes, i.e., its leaves, and the hashes of its complete
+// subtrees of height 1 and above. A subtree is complete once all of its leaves are appended, from then
+// on its hash never changes. The hash of an incomplete subtree is computed from its complete subtrees
+// and the hashes of empty subtrees, which is how the L2MessageQueue contract computes its root.
+
+// AppendMessage stores the message at the given index, which must be the number of messages already
+// appended, and the complete subtrees that it completes.
+func AppendMessage(db ethdb.Database, index uint64, msg *rawdb.WithdrawMessage) error {
+	rawdb.WriteWithdrawMessage(db, index, msg)
+	rawdb.WriteWithdrawMessageIndex(db, msg.MessageHash, index)
+
+	hash := msg.MessageHash
+	position := index
+	for height := uint8(0); position%2 == 1; height++ {
+		left, err := node(db, height, position-1)
+		if err != nil {
+			return err
+		}
+		hash = hashPair(left, hash)
+		position >>= 1
+		rawdb.WriteWithdrawTrieNode(db, height+1, position, hash)
+	}
+	return nil
+}
+
+// Truncate removes the messages from index numMessages up to oldNumMessages, and the subtrees that contain them.
+func Truncate(db ethdb.Database, numMessages, oldNumMessages uint64) {
+	for index := numMessages; index < oldNumMessages; index++ {
+		if msg := rawdb.ReadWithdrawMessage(db, index); msg != nil {
+			rawdb.DeleteWithdrawMessageIndex(db, msg.MessageHash)
+		}
+		rawdb.DeleteWithdrawMessage(db, index)
+	}
+	for height := uint8(1); height <= MaxHeight && oldNumMessages>>height > 0; height++ {
+		for position := numMessages >> height; position < oldNumMessages>>height; position++ {
+			rawdb.DeleteWithdrawTrieNode(db, height, position)
+		}
+	}
+}
+
+// Root returns the root of the withdraw trie that contains the first numMessages messages.
+func Root(db ethdb.Reader, numMessages uint64) (common.Hash, error) {
+	if numMessages == 0 {
+		return common.Hash{}, nil
+	}
+	return subtreeHash(db, treeHeight(numMessages), 0, numMessages)
+}
+
+// Proof returns the Merkle proof of the message at the given index against the root of the withdraw trie
+// that contains the first numMessages messages.
+func Proof(db ethdb.Reader, index, numMessages uint64) ([]common.Hash, error) {
+	if index >= numMessages {
+		return nil, fmt.Errorf("withdraw message index out of range, index: %d, number of messages: %d", index, numMessages)
+	}
+	height := treeHeight(numMessages)
+	proof := make([]common.Hash, height)
+	for h := uint8(0); h < height; h++ {
+		sibling, err := subtreeHash(db, h, (index>>h)^1, numMessages)
+		if err != nil {
+			return nil, err
+		}
+		proof[h] = sibling
+	}
+	return proof, nil
+}
+
+// NumMessagesAt returns how many of the first numMessages messages were appended up to and including the given block.
+func NumMessagesAt(db ethdb.Reader, blockNumber, numMessages uint64) uint64 {
+	return uint64(sort.Search(int(numMessages), func(i int) bool {
+		msg := rawdb.ReadWithdrawMessage(db, uint64(i))
+		return msg == nil || msg.BlockNumber > blockNumber
+	}))
+}
+
+// VerifyProof checks a Merkle proof of a message against a withdraw trie root,
+// i.e., `verifyMerkleProof` in contracts/src/libraries/verifier/WithdrawTrieVerifier.sol
+func VerifyProof(root, messageHash common.Hash, index uint64, proof []common.Hash) bool {
+	hash := messageHash
+	for _, item := range proof {
+		if index%2 == 0 {
+			hash = hashPair(hash, item)
+		} else {
+			hash = hashPair(item, hash)
+		}
+		index >>= 1
+	}
+	return hash == root
+}
+
+// treeHeight returns the height of the withdraw trie that contains numMessages > 0 messages.
+func treeHeight(numMessages uint64) uint8 {
+	return uint8(bits.Len64(numMessages - 1))
+}
+
+// subtreeHash returns the hash of the subtree at the given height and position, considering only the first numMessages messages.
+func subtreeHash(db ethdb.Reader, height uint8, position, numMessages uint64) (common.Hash, error) {
+	first := position << height
+	if first >= numMessages {
+		return zeroHashes[height], nil
+	}
+	if first+1<<height <= numMessages {
+		return node(db, height, position)
+	}
+	left, err := subtreeHash(db, height-1, 2*position, numMessages)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	right, err := subtreeHash(db, height-1, 2*position+1, numMessages)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return hashPair(left, right), nil
+}
+
+// node returns the hash of a complete subtree.
+func node(db ethdb.Reader, height uint8, position uint64) (common.Hash, error) {
+	if height == 0 {
+		msg := rawdb.ReadWithdrawMessage(db, position)
+		if msg == nil {
+			return common.Hash{}, fmt.Errorf("missing withdraw message, index: %d", position)
+		}
+		return msg.MessageHash, nil
+	}
+	hash := rawdb.ReadWithdrawTrieNode(db, height, position)
+	if hash == nil {
+		return common.Hash{}, fmt.Errorf("missing withdraw trie node, height: %d, position: %d", height, position)
+	}
+	return *hash, nil
+}
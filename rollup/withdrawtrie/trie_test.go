@@ -0,0 +1,103 @@
+package withdrawtrie
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/core/rawdb"
+	"github.com/scroll-tech/go-ethereum/crypto"
+)
+
+// contractTrie computes the withdraw trie root like `_appendMessageHash` in AppendOnlyMerkleTree.sol.
+type contractTrie struct {
+	nextMessageIndex uint64
+	branches         [MaxHeight + 1]common.Hash
+	messageRoot      common.Hash
+}
+
+func (t *contractTrie) append(messageHash common.Hash) {
+	index := t.nextMessageIndex
+	hash := messageHash
+	height := 0
+	for index != 0 {
+		if index%2 == 0 {
+			t.branches[height] = hash
+			hash = hashPair(hash, zeroHashes[height])
+		} else {
+			hash = hashPair(t.branches[height], hash)
+		}
+		height++
+		index >>= 1
+	}
+	t.branches[height] = hash
+	t.messageRoot = hash
+	t.nextMessageIndex++
+}
+
+func messageHash(i uint64) common.Hash {
+	return crypto.Keccak256Hash(new(big.Int).SetUint64(i).Bytes())
+}
+
+func TestWithdrawTrie(t *testing.T) {
+	db := rawdb.NewMemoryDatabase()
+	ref := &contractTrie{}
+
+	root, err := Root(db, 0)
+	require.NoError(t, err)
+	assert.Equal(t, common.Hash{}, root)
+
+	const numMessages = 70
+	for i := uint64(0); i < numMessages; i++ {
+		require.NoError(t, AppendMessage(db, i, &rawdb.WithdrawMessage{MessageHash: messageHash(i), BlockNumber: i / 3}))
+		ref.append(messageHash(i))
+
+		root, err := Root(db, i+1)
+		require.NoError(t, err)
+		require.Equal(t, ref.messageRoot, root, "root mismatch after %d messages", i+1)
+
+		for j := uint64(0); j <= i; j++ {
+			proof, err := Proof(db, j, i+1)
+			require.NoError(t, err)
+			require.True(t, VerifyProof(root, messageHash(j), j, proof), "invalid proof of message %d after %d messages", j, i+1)
+		}
+	}
+
+	// proofs are computed against earlier roots as well
+	root, err = Root(db, 5)
+	require.NoError(t, err)
+	proof, err := Proof(db, 4, 5)
+	require.NoError(t, err)
+	assert.Len(t, proof, 3)
+	assert.True(t, VerifyProof(root, messageHash(4), 4, proof))
+	assert.False(t, VerifyProof(root, messageHash(3), 4, proof))
+	_, err = Proof(db, 5, 5)
+	assert.Error(t, err)
+
+	assert.Equal(t, uint64(0), NumMessagesAt(db, 0, 0))
+	assert.Equal(t, uint64(3), NumMessagesAt(db, 0, numMessages))
+	assert.Equal(t, uint64(30), NumMessagesAt(db, 9, numMessages))
+	assert.Equal(t, uint64(numMessages), NumMessagesAt(db, 100, numMessages))
+
+	// appending different messages after a truncation gives the same root as the contract
+	const truncated = 37
+	Truncate(db, truncated, numMessages)
+	for i := uint64(truncated); i < numMessages; i++ {
+		assert.Nil(t, rawdb.ReadWithdrawMessage(db, i))
+		assert.Nil(t, rawdb.ReadWithdrawMessageIndex(db, messageHash(i)))
+	}
+	ref = &contractTrie{}
+	for i := uint64(0); i < truncated; i++ {
+		ref.append(messageHash(i))
+	}
+	for i := uint64(truncated); i < numMessages; i++ {
+		require.NoError(t, AppendMessage(db, i, &rawdb.WithdrawMessage{MessageHash: messageHash(i + 1000)}))
+		ref.append(messageHash(i + 1000))
+	}
+	root, err = Root(db, numMessages)
+	require.NoError(t, err)
+	assert.Equal(t, ref.messageRoot, root)
+}
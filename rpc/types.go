@@ -58,7 +58,7 @@ type jsonWriter interface {
 type BlockNumber int64
 
 const (
-	SafeBlockNumber      = BlockNumber(-4)
+	SafeBlockNumber      = BlockNumber(-4) // on Scroll, the last L2 block committed to L1, also accepted as "committed"
 	FinalizedBlockNumber = BlockNumber(-3)
 	PendingBlockNumber   = BlockNumber(-2)
 	LatestBlockNumber    = BlockNumber(-1)
@@ -90,7 +90,7 @@ func (bn *BlockNumber) UnmarshalJSON(data []byte) error {
 	case "finalized":
 		*bn = FinalizedBlockNumber
 		return nil
-	case "safe":
+	case "safe", "committed":
 		*bn = SafeBlockNumber
 		return nil
 	}
@@ -171,7 +171,7 @@ func (bnh *BlockNumberOrHash) UnmarshalJSON(data []byte) error {
 		bn := FinalizedBlockNumber
 		bnh.BlockNumber = &bn
 		return nil
-	case "safe":
+	case "safe", "committed":
 		bn := SafeBlockNumber
 		bnh.BlockNumber = &bn
 		return nil
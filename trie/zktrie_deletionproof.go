@@ -51,6 +51,11 @@ func (t *ProofTracer) Merge(another *ProofTracer) *ProofTracer {
 	return t
 }
 
+// Copy returns a copy of the tracer on the same trie
+func (t *ProofTracer) Copy() *ProofTracer {
+	return t.ZkTrie.NewProofTracer().Merge(t)
+}
+
 // GetDeletionProofs generate current deletionTracer and collect deletion proofs
 // which is possible to be used from all rawPaths, which enabling witness generator
 // to predict the final state root after executing any deletion